@@ -1,7 +1,8 @@
 package httpexpect
 
 import (
-	"reflect"
+	"bytes"
+	"fmt"
 )
 
 // Array provides methods to inspect attached []interface{} object
@@ -20,7 +21,7 @@ type Array struct {
 // Example:
 //  array := NewArray(t, []interface{}{"foo", 123})
 func NewArray(reporter Reporter, value []interface{}) *Array {
-	chain := makeChain(reporter)
+	chain := makeChainFor("NewArray", reporter)
 	if value == nil {
 		chain.fail("expected non-nil array value")
 	} else {
@@ -41,11 +42,19 @@ func (a *Array) Raw() []interface{} {
 
 // Path is similar to Value.Path.
 func (a *Array) Path(path string) *Value {
+	defer a.chain.leave(a.chain.failed())
 	return getPath(&a.chain, a.value, path)
 }
 
+// Pointer is similar to Value.Pointer.
+func (a *Array) Pointer(pointer string) *Value {
+	defer a.chain.leave(a.chain.failed())
+	return getPointer(&a.chain, a.value, pointer)
+}
+
 // Schema is similar to Value.Schema.
 func (a *Array) Schema(schema interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
 	checkSchema(&a.chain, a.value, schema)
 	return a
 }
@@ -56,12 +65,16 @@ func (a *Array) Schema(schema interface{}) *Array {
 //  array := NewArray(t, []interface{}{1, 2, 3})
 //  array.Length().Equal(3)
 func (a *Array) Length() *Number {
+	defer a.chain.leave(a.chain.failed())
 	return &Number{a.chain, float64(len(a.value))}
 }
 
 // Element returns a new Value object that may be used to inspect array element
 // for given index.
 //
+// Negative index counts from the end of array, i.e. -1 is the last element,
+// -2 is the one before it, and so on.
+//
 // If index is out of array bounds, Element reports failure and returns empty
 // (but non-nil) value.
 //
@@ -69,16 +82,33 @@ func (a *Array) Length() *Number {
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.Element(0).String().Equal("foo")
 //  array.Element(1).Number().Equal(123)
+//  array.Element(-1).Number().Equal(123)
 func (a *Array) Element(index int) *Value {
-	if index < 0 || index >= len(a.value) {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return &Value{a.chain, nil}
+	}
+	pos := index
+	if pos < 0 {
+		pos += len(a.value)
+	}
+	if len(a.value) == 0 {
 		a.chain.fail(
-			"\narray index out of bounds:\n  index %d\n\n  bounds [%d; %d)",
+			"\narray index out of bounds:\n  index %d\n\n  array is empty",
+			index)
+		return &Value{a.chain, nil}
+	}
+	if pos < 0 || pos >= len(a.value) {
+		a.chain.fail(
+			"\narray index out of bounds:\n  index %d\n\n  valid indexes: %d..%d"+
+				"\n\n  array length %d",
 			index,
-			0,
+			-len(a.value),
+			len(a.value)-1,
 			len(a.value))
 		return &Value{a.chain, nil}
 	}
-	return &Value{a.chain, a.value[index]}
+	return &Value{a.chain.enterIndex(pos), a.value[pos]}
 }
 
 // First returns a new Value object that may be used to inspect first element
@@ -91,11 +121,16 @@ func (a *Array) Element(index int) *Value {
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.First().String().Equal("foo")
 func (a *Array) First() *Value {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return &Value{a.chain, nil}
+	}
 	if len(a.value) < 1 {
-		a.chain.fail("\narray is empty")
+		a.chain.fail("\nexpected non-empty array in First, but got:\n%s",
+			dumpValue(a.value))
 		return &Value{a.chain, nil}
 	}
-	return &Value{a.chain, a.value[0]}
+	return &Value{a.chain.enterIndex(0), a.value[0]}
 }
 
 // Last returns a new Value object that may be used to inspect last element
@@ -108,11 +143,16 @@ func (a *Array) First() *Value {
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.Last().Number().Equal(123)
 func (a *Array) Last() *Value {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return &Value{a.chain, nil}
+	}
 	if len(a.value) < 1 {
-		a.chain.fail("\narray is empty")
+		a.chain.fail("\nexpected non-empty array in Last, but got:\n%s",
+			dumpValue(a.value))
 		return &Value{a.chain, nil}
 	}
-	return &Value{a.chain, a.value[len(a.value)-1]}
+	return &Value{a.chain.enterIndex(len(a.value) - 1), a.value[len(a.value)-1]}
 }
 
 // Iter returns a new slice of Values attached to array elements.
@@ -130,7 +170,7 @@ func (a *Array) Iter() []Value {
 	}
 	ret := []Value{}
 	for n := range a.value {
-		ret = append(ret, Value{a.chain, a.value[n]})
+		ret = append(ret, Value{a.chain.enterIndex(n), a.value[n]})
 	}
 	return ret
 }
@@ -141,6 +181,7 @@ func (a *Array) Iter() []Value {
 //  array := NewArray(t, []interface{}{})
 //  array.Empty()
 func (a *Array) Empty() *Array {
+	defer a.chain.leave(a.chain.failed())
 	return a.Equal([]interface{}{})
 }
 
@@ -150,6 +191,7 @@ func (a *Array) Empty() *Array {
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.NotEmpty()
 func (a *Array) NotEmpty() *Array {
+	defer a.chain.leave(a.chain.failed())
 	return a.NotEqual([]interface{}{})
 }
 
@@ -168,15 +210,27 @@ func (a *Array) NotEmpty() *Array {
 //  array := NewArray(t, []interface{}{123, 456})
 //  array.Equal([]int{}{123, 456})
 func (a *Array) Equal(value interface{}) *Array {
-	expected, ok := canonArray(&a.chain, value)
+	defer a.chain.leave(a.chain.failed())
+	expected, ok := canonArrayShared(&a.chain, value)
 	if !ok {
 		return a
 	}
-	if !reflect.DeepEqual(expected, a.value) {
-		a.chain.fail("\nexpected array equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
-			dumpValue(expected),
-			dumpValue(a.value),
-			diffValues(expected, a.value))
+	if !equalValues(expected, a.value) {
+		if path := diffPath(expected, a.value); path != "" {
+			a.chain.failExpected(expected, a.value,
+				"\nexpected array equal to:\n%s\n\nbut got:\n%s\n\n"+
+					"first difference at:\n %s\n\ndiff:\n%s",
+				dumpValue(expected),
+				dumpValue(a.value),
+				path,
+				diffValues(expected, a.value))
+		} else {
+			a.chain.failExpected(expected, a.value,
+				"\nexpected array equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+				dumpValue(expected),
+				dumpValue(a.value),
+				diffValues(expected, a.value))
+		}
 	}
 	return a
 }
@@ -190,11 +244,12 @@ func (a *Array) Equal(value interface{}) *Array {
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.NotEqual([]interface{}{123, "foo"})
 func (a *Array) NotEqual(value interface{}) *Array {
-	expected, ok := canonArray(&a.chain, value)
+	defer a.chain.leave(a.chain.failed())
+	expected, ok := canonArrayShared(&a.chain, value)
 	if !ok {
 		return a
 	}
-	if reflect.DeepEqual(expected, a.value) {
+	if equalValues(expected, a.value) {
 		a.chain.fail("\nexpected array not equal to:\n%s",
 			dumpValue(expected))
 	}
@@ -214,6 +269,7 @@ func (a *Array) NotEqual(value interface{}) *Array {
 //  array.Elelems("a", "b")
 //  array.Equal([]interface{}{"a", "b"})
 func (a *Array) Elements(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
 	return a.Equal(values)
 }
 
@@ -224,12 +280,14 @@ func (a *Array) Elements(values ...interface{}) *Array {
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.Contains(123, "foo")
 func (a *Array) Contains(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
 	elements, ok := canonArray(&a.chain, values)
 	if !ok {
 		return a
 	}
+	set := makeValueSet(a.value)
 	for _, e := range elements {
-		if !a.containsElement(e) {
+		if !set.contains(e) {
 			a.chain.fail("\nexpected array containing element:\n%s\n\nbut got:\n%s",
 				dumpValue(e), dumpValue(a.value))
 		}
@@ -237,6 +295,145 @@ func (a *Array) Contains(values ...interface{}) *Array {
 	return a
 }
 
+// ContainsNull succeeds if array contains at least one null element.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", nil})
+//  array.ContainsNull()
+func (a *Array) ContainsNull() *Array {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return a
+	}
+	for _, e := range a.value {
+		if e == nil {
+			return a
+		}
+	}
+	a.chain.fail("\nexpected array containing null element, but got:\n%s",
+		dumpValue(a.value))
+	return a
+}
+
+// NotContainsNull succeeds if array contains no null elements.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.NotContainsNull()
+func (a *Array) NotContainsNull() *Array {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return a
+	}
+	for i, e := range a.value {
+		if e == nil {
+			a.chain.fail(
+				"\nexpected array not containing null elements,"+
+					" but got null at index %d:\n%s", i, dumpValue(a.value))
+			return a
+		}
+	}
+	return a
+}
+
+// IsUnique succeeds if array contains no two deeply equal elements.
+// Null elements are equal to each other.
+//
+// Elements are compared using their canonical JSON representation, so
+// checking large arrays takes linear time. Failure reports indexes and
+// value of the first duplicate pair.
+//
+// Example:
+//  array := NewArray(t, []interface{}{1, 2, 3})
+//  array.IsUnique()
+func (a *Array) IsUnique() *Array {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return a
+	}
+	if i, j, ok := a.findDuplicate(a.value); ok {
+		a.chain.fail(
+			"\nexpected array with unique elements,"+
+				" but elements %d and %d are equal:\n%s", i, j, dumpValue(a.value[j]))
+	}
+	return a
+}
+
+// NotUnique succeeds if array contains at least two deeply equal elements.
+//
+// Example:
+//  array := NewArray(t, []interface{}{1, 2, 1})
+//  array.NotUnique()
+func (a *Array) NotUnique() *Array {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return a
+	}
+	if _, _, ok := a.findDuplicate(a.value); !ok {
+		a.chain.fail(
+			"\nexpected array with duplicate elements, but all elements are unique:\n%s",
+			dumpValue(a.value))
+	}
+	return a
+}
+
+// IsUniqueBy succeeds if array elements have unique values at given path.
+//
+// Path has the same syntax as in Object.ContainsPath, e.g. "id" or
+// "owner.id". Every element should contain the path, otherwise failure
+// is reported. Values are compared like in IsUnique.
+//
+// Example:
+//  array := NewArray(t, []interface{}{
+//      map[string]interface{}{"id": 1, "name": "foo"},
+//      map[string]interface{}{"id": 2, "name": "foo"},
+//  })
+//  array.IsUniqueBy("id")
+func (a *Array) IsUniqueBy(path string) *Array {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return a
+	}
+	keys, err := splitKeyPath(path)
+	if err != nil {
+		a.chain.failUsage("\nunexpected invalid path passed to IsUniqueBy:\n %q\n\nerror:\n %s",
+			path, err.Error())
+		return a
+	}
+	values := make([]interface{}, 0, len(a.value))
+	for i, e := range a.value {
+		lk := lookupKeyPath(e, keys)
+		if lk.found != len(keys) {
+			a.chain.fail(
+				"\nexpected every array element containing path:\n %q"+
+					"\n\nbut element %d doesn't:\n%s", path, i, dumpValue(e))
+			return a
+		}
+		values = append(values, lk.node)
+	}
+	if i, j, ok := a.findDuplicate(values); ok {
+		a.chain.fail(
+			"\nexpected array elements with unique values at path:\n %q"+
+				"\n\nbut elements %d and %d have equal values:\n%s",
+			path, i, j, dumpValue(values[j]))
+	}
+	return a
+}
+
+// findDuplicate returns indexes of the first pair of equal values.
+func (a *Array) findDuplicate(values []interface{}) (int, int, bool) {
+	seen := make(map[string]int, len(values))
+	var buf bytes.Buffer
+	for j, v := range values {
+		key := valueKey(&buf, v)
+		if i, ok := seen[key]; ok {
+			return i, j, true
+		}
+		seen[key] = j
+	}
+	return 0, 0, false
+}
+
 // NotContains succeeds if array contains none of given elements.
 // Before comparison, array and all elements are converted to canonical form.
 //
@@ -245,12 +442,14 @@ func (a *Array) Contains(values ...interface{}) *Array {
 //  array.NotContains("bar")         // success
 //  array.NotContains("bar", "foo")  // failure (array contains "foo")
 func (a *Array) NotContains(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
 	elements, ok := canonArray(&a.chain, values)
 	if !ok {
 		return a
 	}
+	set := makeValueSet(a.value)
 	for _, e := range elements {
-		if a.containsElement(e) {
+		if set.contains(e) {
 			a.chain.fail("\nexpected array not containing element:\n%s\n\nbut got:\n%s",
 				dumpValue(e), dumpValue(a.value))
 		}
@@ -258,6 +457,201 @@ func (a *Array) NotContains(values ...interface{}) *Array {
 	return a
 }
 
+// NotContainsAll succeeds if array contains none of given elements.
+// Before comparison, array and all elements are converted to canonical form.
+//
+// Unlike NotContains, it reports single failure listing all given elements
+// that are present in array.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.NotContainsAll("bar", 456)
+func (a *Array) NotContainsAll(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	elements, ok := canonArray(&a.chain, values)
+	if !ok {
+		return a
+	}
+	set := makeValueSet(a.value)
+	seen := makeValueSet(nil)
+	var present []interface{}
+	for _, e := range elements {
+		if set.contains(e) && seen.add(e) {
+			present = append(present, e)
+		}
+	}
+	if len(present) != 0 {
+		a.chain.fail("\nexpected array not containing elements:\n%s\n\n"+
+			"but got array with %d of them:\n%s\n\narray:\n%s",
+			dumpValue(elements), len(present), dumpElements(present),
+			dumpValue(a.value))
+	}
+	return a
+}
+
+// ContainsSubsequence succeeds if array contains given elements in given
+// order, possibly with other elements interleaved.
+// Before comparison, array and all elements are converted to canonical form.
+//
+// On failure, it reports how many elements were matched and the index of
+// the last matched element.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"start", "progress", "progress", "done"})
+//  array.ContainsSubsequence("start", "done")
+func (a *Array) ContainsSubsequence(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	if values == nil {
+		values = []interface{}{}
+	}
+	elements, ok := canonArray(&a.chain, values)
+	if !ok {
+		return a
+	}
+	indexes := findSubsequence(a.value, elements)
+	if len(indexes) == len(elements) {
+		return a
+	}
+	k := len(indexes)
+	if k == 0 {
+		a.chain.fail("\nexpected array containing subsequence:\n%s\n\n"+
+			"but matched 0 of %d element(s), first element not found:\n%s"+
+			"\n\narray:\n%s",
+			dumpValue(elements), len(elements), dumpValue(elements[0]),
+			dumpValue(a.value))
+	} else {
+		a.chain.fail("\nexpected array containing subsequence:\n%s\n\n"+
+			"but matched %d of %d element(s), stuck after index %d"+
+			" while looking for:\n%s\n\narray:\n%s",
+			dumpValue(elements), k, len(elements), indexes[k-1],
+			dumpValue(elements[k]), dumpValue(a.value))
+	}
+	return a
+}
+
+// NotContainsSubsequence succeeds if array doesn't contain given elements
+// in given order, even with other elements interleaved.
+// Before comparison, array and all elements are converted to canonical form.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"start", "done"})
+//  array.NotContainsSubsequence("done", "start")
+func (a *Array) NotContainsSubsequence(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	if values == nil {
+		values = []interface{}{}
+	}
+	elements, ok := canonArray(&a.chain, values)
+	if !ok {
+		return a
+	}
+	if indexes := findSubsequence(a.value, elements); len(indexes) == len(elements) {
+		a.chain.fail("\nexpected array not containing subsequence:\n%s\n\n"+
+			"but it was found at indexes %v:\n%s",
+			dumpValue(elements), indexes, dumpValue(a.value))
+	}
+	return a
+}
+
+// ContainsContiguous succeeds if array contains given elements in given
+// order as an adjacent run, without other elements interleaved.
+// Before comparison, array and all elements are converted to canonical form.
+//
+// On failure, it reports the longest matching run found.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"a", "b", "c", "d"})
+//  array.ContainsContiguous("b", "c")
+func (a *Array) ContainsContiguous(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	if values == nil {
+		values = []interface{}{}
+	}
+	elements, ok := canonArray(&a.chain, values)
+	if !ok {
+		return a
+	}
+	bestStart, bestLen := 0, 0
+	for start := 0; start < len(a.value); start++ {
+		n := 0
+		for n < len(elements) && start+n < len(a.value) &&
+			equalValues(elements[n], a.value[start+n]) {
+			n++
+		}
+		if n > bestLen {
+			bestStart, bestLen = start, n
+		}
+		if bestLen == len(elements) {
+			return a
+		}
+	}
+	if len(elements) == 0 {
+		return a
+	}
+	if bestLen == 0 {
+		a.chain.fail("\nexpected array containing contiguous run:\n%s\n\n"+
+			"but matched 0 of %d element(s), first element not found:\n%s"+
+			"\n\narray:\n%s",
+			dumpValue(elements), len(elements), dumpValue(elements[0]),
+			dumpValue(a.value))
+	} else {
+		a.chain.fail("\nexpected array containing contiguous run:\n%s\n\n"+
+			"but longest matching run has %d of %d element(s),"+
+			" starting at index %d\n\narray:\n%s",
+			dumpValue(elements), bestLen, len(elements), bestStart,
+			dumpValue(a.value))
+	}
+	return a
+}
+
+// findSubsequence greedily matches elements against values in order and
+// returns indexes of matched values.
+func findSubsequence(values, elements []interface{}) []int {
+	indexes := []int{}
+	i := 0
+	for _, e := range elements {
+		for i < len(values) && !equalValues(e, values[i]) {
+			i++
+		}
+		if i == len(values) {
+			break
+		}
+		indexes = append(indexes, i)
+		i++
+	}
+	return indexes
+}
+
+// IndexOf returns a new Number object that may be used to inspect index
+// of the first array element equal to given value. Before comparison, array
+// and value are converted to canonical form, and elements are compared
+// deeply, so value may be e.g. an object or nested array.
+//
+// If array contains no such element, IndexOf reports failure.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", "bar", "foo"})
+//  array.IndexOf("foo").Equal(0)
+//  array.IndexOf("bar").Equal(1)
+func (a *Array) IndexOf(value interface{}) *Number {
+	defer a.chain.leave(a.chain.failed())
+	if a.chain.failed() {
+		return &Number{a.chain, 0}
+	}
+	expected, ok := canonValue(&a.chain, value)
+	if !ok {
+		return &Number{a.chain, 0}
+	}
+	for i, v := range a.value {
+		if equalValues(expected, v) {
+			return &Number{a.chain, float64(i)}
+		}
+	}
+	a.chain.fail("\nexpected array containing element:\n%s\n\nbut got:\n%s",
+		dumpValue(expected), dumpValue(a.value))
+	return &Number{a.chain, 0}
+}
+
 // ContainsOnly succeeds if array contains all given elements, in any order, and only
 // them. Before comparison, array and all elements are converted to canonical form.
 //
@@ -269,6 +663,7 @@ func (a *Array) NotContains(values ...interface{}) *Array {
 //  array.ContainsOnly("a", "b")
 //  array.ContainsOnly("b", "a")
 func (a *Array) ContainsOnly(values ...interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
 	elements, ok := canonArray(&a.chain, values)
 	if !ok {
 		return a
@@ -280,8 +675,9 @@ func (a *Array) ContainsOnly(values ...interface{}) *Array {
 			len(a.value), dumpValue(a.value))
 		return a
 	}
+	set := makeValueSet(a.value)
 	for _, e := range elements {
-		if !a.containsElement(e) {
+		if !set.contains(e) {
 			a.chain.fail("\nexpected array containing element:\n%s\n\nbut got:\n%s",
 				dumpValue(e), dumpValue(a.value))
 		}
@@ -289,11 +685,170 @@ func (a *Array) ContainsOnly(values ...interface{}) *Array {
 	return a
 }
 
-func (a *Array) containsElement(expected interface{}) bool {
-	for _, e := range a.value {
-		if reflect.DeepEqual(expected, e) {
-			return true
+// IsSubsetOf succeeds if every element of array is present in given Go slice.
+// Before comparison, array and value are converted to canonical form.
+//
+// Duplicates are ignored, i.e. array and value are treated as sets. value may
+// be the Raw() of another Array, e.g. obtained from another response.
+//
+// Example:
+//  array := NewArray(t, []interface{}{1, 2})
+//  array.IsSubsetOf([]interface{}{1, 2, 3})
+//
+//  orders := e.GET("/orders").Expect().JSON().Path("$..id").Array()
+//  summary := e.GET("/orders/summary").Expect().JSON().Path("$..id").Array()
+//  orders.IsSubsetOf(summary.Raw())
+func (a *Array) IsSubsetOf(value interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	values, ok := canonArray(&a.chain, value)
+	if !ok {
+		return a
+	}
+	if extra := setDifference(a.value, values); len(extra) != 0 {
+		a.chain.fail("\nexpected array being subset of:\n%s\n\n"+
+			"but got array with %d extra element(s):\n%s",
+			dumpValue(values), len(extra), dumpElements(extra))
+	}
+	return a
+}
+
+// IsSupersetOf succeeds if every element of given Go slice is present in array.
+// Before comparison, array and value are converted to canonical form.
+//
+// Duplicates are ignored, i.e. array and value are treated as sets. value may
+// be the Raw() of another Array, e.g. obtained from another response.
+//
+// Example:
+//  array := NewArray(t, []interface{}{1, 2, 3})
+//  array.IsSupersetOf([]interface{}{1, 2})
+func (a *Array) IsSupersetOf(value interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	values, ok := canonArray(&a.chain, value)
+	if !ok {
+		return a
+	}
+	if missing := setDifference(values, a.value); len(missing) != 0 {
+		a.chain.fail("\nexpected array being superset of:\n%s\n\n"+
+			"but got array with %d missing element(s):\n%s",
+			dumpValue(values), len(missing), dumpElements(missing))
+	}
+	return a
+}
+
+// EqualUnordered succeeds if array contains the same elements as given Go
+// slice, in any order. Before comparison, array and value are converted to
+// canonical form.
+//
+// Unlike IsSubsetOf and IsSupersetOf, duplicates are counted, i.e. array
+// and value are treated as multisets.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", "bar", "foo"})
+//  array.EqualUnordered([]interface{}{"foo", "foo", "bar"})  // success
+//  array.EqualUnordered([]interface{}{"foo", "bar"})         // failure
+func (a *Array) EqualUnordered(value interface{}) *Array {
+	defer a.chain.leave(a.chain.failed())
+	values, ok := canonArray(&a.chain, value)
+	if !ok {
+		return a
+	}
+	missing, extra := multisetDifference(values, a.value)
+	if len(missing) != 0 || len(extra) != 0 {
+		a.chain.fail("\nexpected array equal (in any order) to:\n%s\n\n"+
+			"but got:\n%s\n\nmissing %d element(s):\n%s\n\nextra %d element(s):\n%s",
+			dumpValue(values), dumpValue(a.value),
+			len(missing), dumpElements(missing),
+			len(extra), dumpElements(extra))
+	}
+	return a
+}
+
+// maxDumpElements limits number of elements printed in failures of set
+// operations.
+const maxDumpElements = 10
+
+func dumpElements(elements []interface{}) string {
+	if len(elements) == 0 {
+		return dumpValue([]interface{}{})
+	}
+	if len(elements) <= maxDumpElements {
+		return dumpValue(elements)
+	}
+	return fmt.Sprintf("%s\n (and %d more)",
+		dumpValue(elements[:maxDumpElements]), len(elements)-maxDumpElements)
+}
+
+// valueKey returns canonical JSON encoding of value in canonical form,
+// so that equal values have equal keys. buf is used as scratch space.
+func valueKey(buf *bytes.Buffer, v interface{}) string {
+	buf.Reset()
+	if err := writeCanonicalJSON(buf, v, ""); err != nil {
+		buf.WriteString(fmt.Sprintf("%#v", v))
+	}
+	return buf.String()
+}
+
+// valueSet holds number of occurrences of every value, by valueKey.
+type valueSet struct {
+	buf    bytes.Buffer
+	counts map[string]int
+}
+
+func makeValueSet(values []interface{}) *valueSet {
+	s := &valueSet{counts: make(map[string]int, len(values))}
+	for _, v := range values {
+		s.counts[valueKey(&s.buf, v)]++
+	}
+	return s
+}
+
+func (s *valueSet) contains(v interface{}) bool {
+	return s.counts[valueKey(&s.buf, v)] != 0
+}
+
+// take decrements number of occurrences of value, if it's present.
+func (s *valueSet) take(v interface{}) bool {
+	key := valueKey(&s.buf, v)
+	if s.counts[key] == 0 {
+		return false
+	}
+	s.counts[key]--
+	return true
+}
+
+// add increments number of occurrences of value and reports whether
+// it was not present before.
+func (s *valueSet) add(v interface{}) bool {
+	key := valueKey(&s.buf, v)
+	s.counts[key]++
+	return s.counts[key] == 1
+}
+
+// setDifference returns unique elements of a that are not present in b.
+func setDifference(a, b []interface{}) []interface{} {
+	excluded := makeValueSet(b)
+	var ret []interface{}
+	for _, e := range a {
+		if !excluded.contains(e) && excluded.add(e) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// multisetDifference returns elements of expected not matched in actual,
+// and elements of actual not matched in expected, counting duplicates.
+func multisetDifference(expected, actual []interface{}) (missing, extra []interface{}) {
+	unmatched := makeValueSet(actual)
+	for _, e := range expected {
+		if !unmatched.take(e) {
+			missing = append(missing, e)
+		}
+	}
+	for _, v := range actual {
+		if unmatched.take(v) {
+			extra = append(extra, v)
 		}
 	}
-	return false
+	return missing, extra
 }
@@ -1,7 +1,10 @@
 package httpexpect
 
 import (
+	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,7 +19,13 @@ func TestArrayFailed(t *testing.T) {
 	value.chain.assertFailed(t)
 
 	value.Path("$").chain.assertFailed(t)
+	value.Pointer("").chain.assertFailed(t)
 	value.Schema("")
+	value.ContainsNull()
+	value.NotContainsNull()
+	value.IsUnique()
+	value.NotUnique()
+	value.IsUniqueBy("id")
 
 	assert.False(t, value.Length() == nil)
 	assert.False(t, value.Element(0) == nil)
@@ -36,6 +45,12 @@ func TestArrayFailed(t *testing.T) {
 	value.Contains("foo")
 	value.NotContains("foo")
 	value.ContainsOnly("foo")
+	value.IsSubsetOf([]interface{}{"foo"})
+	value.IsSupersetOf([]interface{}{"foo"})
+	value.EqualUnordered([]interface{}{"foo"})
+	value.ContainsSubsequence("foo")
+	value.NotContainsSubsequence("foo")
+	value.ContainsContiguous("foo")
 }
 
 func TestArrayGetters(t *testing.T) {
@@ -53,6 +68,10 @@ func TestArrayGetters(t *testing.T) {
 	value.chain.assertOK(t)
 	value.chain.reset()
 
+	assert.Equal(t, a[0], value.Pointer("/0").Raw())
+	value.chain.assertOK(t)
+	value.chain.reset()
+
 	value.Schema(`{"type": "array"}`)
 	value.chain.assertOK(t)
 	value.chain.reset()
@@ -72,6 +91,15 @@ func TestArrayGetters(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 
+	assert.Equal(t, 123.0, value.Element(-1).Raw())
+	assert.Equal(t, "foo", value.Element(-2).Raw())
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	assert.Equal(t, nil, value.Element(-3).Raw())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
 	it := value.Iter()
 	assert.Equal(t, 2, len(it))
 	assert.Equal(t, "foo", it[0].Raw())
@@ -85,6 +113,79 @@ func TestArrayGetters(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestArrayElementEmpty(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{})
+
+	for _, index := range []int{0, 1, -1} {
+		value.Element(index).chain.assertFailed(t)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	}
+
+	value.First().chain.assertFailed(t)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Last().chain.assertFailed(t)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestArrayElementSingle(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo"})
+
+	assert.Equal(t, "foo", value.Element(0).Raw())
+	assert.Equal(t, "foo", value.Element(-1).Raw())
+	assert.Equal(t, "foo", value.First().Raw())
+	assert.Equal(t, "foo", value.Last().Raw())
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	for _, index := range []int{1, -2} {
+		value.Element(index).chain.assertFailed(t)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	}
+}
+
+func TestArrayElementMessage(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", "bar", "baz"})
+
+	value.Element(5)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "index 5")
+	assert.Contains(t, reporter.message, "array length 3")
+	value.chain.reset()
+
+	value.Element(-4)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "index -4")
+	assert.Contains(t, reporter.message, "valid indexes: -3..2")
+	value.chain.reset()
+
+	value = NewArray(reporter, []interface{}{"foo"})
+
+	value.Element(1)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "valid indexes: -1..0")
+	value.chain.reset()
+
+	value = NewArray(reporter, []interface{}{})
+
+	value.Element(0)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "index 0")
+	assert.Contains(t, reporter.message, "array is empty")
+	assert.NotContains(t, reporter.message, "valid indexes")
+	value.chain.reset()
+}
+
 func TestArrayEmpty(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -322,6 +423,189 @@ func TestArrayContains(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestArrayContainsSubsequence(t *testing.T) {
+	events := []interface{}{
+		"start",
+		map[string]interface{}{"progress": 10},
+		"tick",
+		map[string]interface{}{"progress": 50},
+		"done",
+	}
+
+	cases := []struct {
+		name   string
+		values []interface{}
+		ok     bool
+	}{
+		{"empty", []interface{}{}, true},
+		{"single", []interface{}{"tick"}, true},
+		{"interleaved", []interface{}{"start", "done"}, true},
+		{"canonical", []interface{}{
+			"start", map[string]int{"progress": 50}, "done"}, true},
+		{"whole", events, true},
+		{"wrong order", []interface{}{"done", "start"}, false},
+		{"missing", []interface{}{"start", "error"}, false},
+		{"repeated", []interface{}{"tick", "tick"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			value := NewArray(reporter, events)
+
+			value.ContainsSubsequence(tc.values...)
+			if tc.ok {
+				value.chain.assertOK(t)
+			} else {
+				value.chain.assertFailed(t)
+			}
+			value.chain.reset()
+
+			value.NotContainsSubsequence(tc.values...)
+			if tc.ok {
+				value.chain.assertFailed(t)
+			} else {
+				value.chain.assertOK(t)
+			}
+			value.chain.reset()
+		})
+	}
+
+	t.Run("progress", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		value := NewArray(reporter, events)
+
+		value.ContainsSubsequence("start", "tick", "start", "done")
+		value.chain.assertFailed(t)
+		assert.Contains(t, reporter.message,
+			"matched 2 of 4 element(s), stuck after index 2")
+		value.chain.reset()
+
+		value.ContainsSubsequence("error", "done")
+		value.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "matched 0 of 2 element(s)")
+		value.chain.reset()
+
+		value.NotContainsSubsequence("start", "done")
+		value.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "found at indexes [0 4]")
+		value.chain.reset()
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		value := NewArray(newMockReporter(t), events)
+
+		value.ContainsSubsequence(func() {})
+		value.chain.assertFailed(t)
+		value.chain.reset()
+
+		value.NotContainsSubsequence(func() {})
+		value.chain.assertFailed(t)
+	})
+}
+
+func TestArrayContainsContiguous(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"a", "b", 1, "c", "b", 1, "d"})
+
+	value.ContainsContiguous("b", 1, "d")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsContiguous("a", "b", 1.0)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsContiguous()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsContiguous("a", "c")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message,
+		"longest matching run has 1 of 2 element(s), starting at index 0")
+	value.chain.reset()
+
+	value.ContainsContiguous("b", 1, "c", "d")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message,
+		"longest matching run has 3 of 4 element(s), starting at index 1")
+	value.chain.reset()
+
+	value.ContainsContiguous("x")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "matched 0 of 1 element(s)")
+	value.chain.reset()
+
+	value.ContainsContiguous("d", "e")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "1 of 2 element(s), starting at index 6")
+	value.chain.reset()
+}
+
+func TestArrayNotContainsAll(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", 123, "foo"})
+
+	value.NotContainsAll("bar", 456)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotContainsAll("bar", "foo", 123, 456, "foo")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "but got array with 2 of them")
+	assert.Contains(t, reporter.message, `"foo"`)
+	assert.Contains(t, reporter.message, "123")
+
+	value.NotContainsAll(func() {})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestArrayIndexOf(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{
+		"foo",
+		123,
+		"foo",
+		map[string]interface{}{"a": 1.0, "b": []interface{}{"x"}},
+		map[string]interface{}{"a": 1.0, "b": []interface{}{"x"}},
+	})
+
+	value.IndexOf("foo").Equal(0)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IndexOf(123).Equal(1)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IndexOf(map[string]interface{}{"b": []string{"x"}, "a": 1}).Equal(3)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IndexOf("bar").Equal(0)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.IndexOf(map[string]interface{}{"a": 2}).Equal(0)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.IndexOf(func() {})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewArray(reporter, []interface{}{}).IndexOf("foo").chain.assertFailed(t)
+}
+
 func TestArrayContainsOnly(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -452,3 +736,367 @@ func TestArrayConvertContains(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestArraySubsetSuperset(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{1, 2, 2, "foo"})
+
+	value.IsSubsetOf([]interface{}{"foo", 3, 2, 1})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsSubsetOf([]int{1, 2})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "1 extra element(s)")
+	assert.Contains(t, reporter.message, `"foo"`)
+
+	value.IsSupersetOf([]interface{}{"foo", 1, 1})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsSupersetOf([]interface{}{1, 3, 4, 3})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "2 missing element(s)")
+
+	other := NewArray(reporter, []interface{}{1.0, 2.0, "foo", "bar"})
+
+	value.IsSubsetOf(other.Raw())
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	other.IsSupersetOf(value.Raw())
+	other.chain.assertOK(t)
+	other.chain.reset()
+
+	value.IsSubsetOf(func() {})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestArraySubsetManyElements(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	var elements []interface{}
+	for i := 0; i < 25; i++ {
+		elements = append(elements, i)
+	}
+
+	value := NewArray(reporter, elements)
+
+	value.IsSubsetOf([]interface{}{})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "25 extra element(s)")
+	assert.Contains(t, reporter.message, "(and 15 more)")
+	assert.NotContains(t, reporter.message, "\n   10,")
+}
+
+func TestArrayEqualUnordered(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", "bar", "foo"})
+
+	value.EqualUnordered([]interface{}{"foo", "foo", "bar"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualUnordered([]string{"bar", "foo", "foo"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualUnordered([]interface{}{"foo", "bar"})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "missing 0 element(s)")
+	assert.Contains(t, reporter.message, "extra 1 element(s)")
+
+	value.EqualUnordered([]interface{}{"foo", "bar", "bar"})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "missing 1 element(s)")
+	assert.Contains(t, reporter.message, "extra 1 element(s)")
+
+	value.EqualUnordered([]interface{}{"foo", "bar", "foo", "baz"})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewArray(reporter, []interface{}{}).EqualUnordered([]interface{}{}).
+		chain.assertOK(t)
+
+	objects := NewArray(reporter, []interface{}{
+		map[string]interface{}{"a": 1, "b": []interface{}{2, 3}},
+		map[string]interface{}{"a": 1},
+		1,
+	})
+
+	objects.EqualUnordered([]interface{}{
+		1.0,
+		map[string]interface{}{"a": 1.0},
+		map[string]interface{}{"b": []int{2, 3}, "a": 1},
+	})
+	objects.chain.assertOK(t)
+	objects.chain.reset()
+
+	objects.EqualUnordered([]interface{}{
+		1,
+		map[string]interface{}{"a": 1},
+		map[string]interface{}{"a": 1, "b": []int{3, 2}},
+	})
+	objects.chain.assertFailed(t)
+	objects.chain.reset()
+}
+
+func TestArrayContainsNull(t *testing.T) {
+	cases := []struct {
+		name         string
+		array        []interface{}
+		containsNull bool
+	}{
+		{"empty", []interface{}{}, false},
+		{"without null", []interface{}{"", 0.0, false}, false},
+		{"with null", []interface{}{"foo", nil}, true},
+		{"only null", []interface{}{nil}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			value := NewArray(reporter, tc.array)
+
+			value.ContainsNull()
+			if tc.containsNull {
+				value.chain.assertOK(t)
+			} else {
+				value.chain.assertFailed(t)
+			}
+			value.chain.reset()
+
+			value.NotContainsNull()
+			if tc.containsNull {
+				value.chain.assertFailed(t)
+				assert.Contains(t, reporter.message, "but got null at index")
+			} else {
+				value.chain.assertOK(t)
+			}
+		})
+	}
+}
+
+func TestArrayIsUnique(t *testing.T) {
+	cases := []struct {
+		name   string
+		array  []interface{}
+		unique bool
+	}{
+		{"empty", []interface{}{}, true},
+		{"unique", []interface{}{1, "1", true, nil, []interface{}{1}}, true},
+		{"numbers", []interface{}{1, 2, 1.0}, false},
+		{"nulls", []interface{}{nil, "x", nil}, false},
+		{"objects", []interface{}{
+			map[string]interface{}{"a": 1, "b": []interface{}{"x"}},
+			map[string]interface{}{"b": []interface{}{"x"}, "a": 1.0},
+		}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			value := NewArray(reporter, tc.array)
+
+			value.IsUnique()
+			if tc.unique {
+				value.chain.assertOK(t)
+			} else {
+				value.chain.assertFailed(t)
+			}
+			value.chain.reset()
+
+			value.NotUnique()
+			if tc.unique {
+				value.chain.assertFailed(t)
+			} else {
+				value.chain.assertOK(t)
+			}
+		})
+	}
+
+	reporter := newMockReporter(t)
+
+	NewArray(reporter, []interface{}{"a", "b", "c", "b", "a"}).IsUnique()
+	assert.Contains(t, reporter.message, "elements 1 and 3 are equal")
+	assert.Contains(t, reporter.message, `"b"`)
+}
+
+func TestArrayIsUniqueBy(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{
+		map[string]interface{}{"id": 1, "owner": map[string]interface{}{"id": 7}},
+		map[string]interface{}{"id": 2, "owner": map[string]interface{}{"id": 7}},
+		map[string]interface{}{"id": 3, "owner": map[string]interface{}{"id": nil}},
+	})
+
+	value.IsUniqueBy("id")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsUniqueBy("owner.id")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "elements 0 and 1 have equal values")
+	value.chain.reset()
+
+	value.IsUniqueBy("name")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "element 0 doesn't")
+	value.chain.reset()
+
+	value.IsUniqueBy("items[")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestArrayIsUniqueLarge(t *testing.T) {
+	const n = 10000
+
+	elements := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		elements = append(elements, map[string]interface{}{
+			"id":   i,
+			"name": fmt.Sprintf("item-%d", i),
+		})
+	}
+
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, elements)
+
+	start := time.Now()
+
+	value.IsUnique()
+	value.IsUniqueBy("id")
+	value.chain.assertOK(t)
+
+	elements[n-1] = elements[0]
+
+	value = NewArray(reporter, elements)
+	value.IsUnique()
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, fmt.Sprintf("elements 0 and %d", n-1))
+
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestArrayEqualDiffPath(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{
+		map[string]interface{}{"id": 1, "name": "foo"},
+		map[string]interface{}{"id": 2, "name": "bar"},
+	})
+
+	value.Equal(value.Raw())
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotEqual(value.Raw())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal([]interface{}{
+		map[string]interface{}{"id": 1, "name": "foo"},
+		map[string]interface{}{"id": 2, "name": "baz"},
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "first difference at:\n [1].name")
+
+	value.Equal([]interface{}{"foo"})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.NotContains(t, reporter.message, "first difference at")
+}
+
+func TestArrayEqualShared(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{1, "foo"})
+
+	value.Equal([]interface{}{1, "foo"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal([]interface{}{1.0, "foo"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal([]interface{}{1.0, "\xff"})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal([]interface{}(nil))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqual([]interface{}{math.NaN()})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func makeBenchArray(n int) []interface{} {
+	arr := make([]interface{}, n)
+	for i := range arr {
+		arr[i] = map[string]interface{}{
+			"id":   i,
+			"name": fmt.Sprintf("item%d", i),
+			"tags": []interface{}{"a", "b"},
+		}
+	}
+	return arr
+}
+
+func BenchmarkArrayEqual(b *testing.B) {
+	array := NewArray(NopReporter{}, makeBenchArray(100000))
+	other := NewArray(NopReporter{}, makeBenchArray(100000))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		array.Equal(other.Raw())
+	}
+
+	if array.chain.failed() {
+		b.Fatal("unexpected failure")
+	}
+}
+
+func BenchmarkArrayContains(b *testing.B) {
+	array := NewArray(NopReporter{}, makeBenchArray(100000))
+
+	last := map[string]interface{}{
+		"id":   99999,
+		"name": "item99999",
+		"tags": []interface{}{"a", "b"},
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		array.Contains(last)
+	}
+
+	if array.chain.failed() {
+		b.Fatal("unexpected failure")
+	}
+}
@@ -2,6 +2,7 @@ package httpexpect
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
@@ -23,6 +24,13 @@ type Binder struct {
 	Handler http.Handler
 	// TLS connection state used for https:// requests.
 	TLS *tls.ConnectionState
+	// Client address reported to handler via http.Request.RemoteAddr,
+	// e.g. "10.0.0.1:1234". If empty, RemoteAddr is left unchanged.
+	RemoteAddr string
+	// Base context for every request. Handler sees values both from base
+	// context and from request context; the latter take precedence.
+	// Cancellation and deadline are still taken from request context.
+	BaseContext context.Context
 }
 
 // NewBinder returns a new Binder given a http.Handler.
@@ -53,6 +61,14 @@ func (binder Binder) RoundTrip(req *http.Request) (*http.Response, error) {
 		req.TLS = binder.TLS
 	}
 
+	if binder.RemoteAddr != "" {
+		req.RemoteAddr = binder.RemoteAddr
+	}
+
+	if binder.BaseContext != nil {
+		req = req.WithContext(baseValuesContext{req.Context(), binder.BaseContext})
+	}
+
 	if req.RequestURI == "" {
 		req.RequestURI = req.URL.RequestURI()
 	}
@@ -63,6 +79,9 @@ func (binder Binder) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	resp := http.Response{
 		Request:    req,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
 		StatusCode: recorder.Code,
 		Status:     http.StatusText(recorder.Code),
 		Header:     recorder.Result().Header,
@@ -90,6 +109,9 @@ type FastBinder struct {
 	Handler fasthttp.RequestHandler
 	// TLS connection state used for https:// requests.
 	TLS *tls.ConnectionState
+	// Client address reported to handler via fasthttp.RequestCtx.RemoteAddr,
+	// e.g. "10.0.0.1:1234". If empty, zero address is used.
+	RemoteAddr string
 }
 
 // NewFastBinder returns a new FastBinder given a fasthttp.RequestHandler.
@@ -106,11 +128,20 @@ func NewFastBinder(handler fasthttp.RequestHandler) FastBinder {
 func (binder FastBinder) RoundTrip(stdreq *http.Request) (*http.Response, error) {
 	fastreq := std2fast(stdreq)
 
+	plain := connNonTLS{}
+	if binder.RemoteAddr != "" {
+		addr, err := net.ResolveTCPAddr("tcp", binder.RemoteAddr)
+		if err != nil {
+			return nil, err
+		}
+		plain.remote = addr
+	}
+
 	var conn net.Conn
 	if stdreq.URL != nil && stdreq.URL.Scheme == "https" && binder.TLS != nil {
-		conn = connTLS{state: binder.TLS}
+		conn = connTLS{connNonTLS: plain, state: binder.TLS}
 	} else {
-		conn = connNonTLS{}
+		conn = plain
 	}
 
 	ctx := fasthttp.RequestCtx{}
@@ -160,6 +191,9 @@ func fast2std(stdreq *http.Request, fastresp *fasthttp.Response) *http.Response
 
 	stdresp := &http.Response{
 		Request:    stdreq,
+		Proto:      stdreq.Proto,
+		ProtoMajor: stdreq.ProtoMajor,
+		ProtoMinor: stdreq.ProtoMinor,
 		StatusCode: status,
 		Status:     http.StatusText(status),
 	}
@@ -194,9 +228,13 @@ func (fastLogger) Printf(format string, args ...interface{}) {
 
 type connNonTLS struct {
 	net.Conn
+	remote net.Addr
 }
 
-func (connNonTLS) RemoteAddr() net.Addr {
+func (c connNonTLS) RemoteAddr() net.Addr {
+	if c.remote != nil {
+		return c.remote
+	}
 	return &net.TCPAddr{IP: net.IPv4zero}
 }
 
@@ -216,3 +254,17 @@ func (c connTLS) Handshake() error {
 func (c connTLS) ConnectionState() tls.ConnectionState {
 	return *c.state
 }
+
+// baseValuesContext takes cancellation and deadline from embedded context,
+// and looks up values in embedded context first and in base context then.
+type baseValuesContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c baseValuesContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
@@ -2,6 +2,7 @@ package httpexpect
 
 import (
 	"bufio"
+	"context"
 	"crypto/tls"
 	"io/ioutil"
 	"net/http"
@@ -167,6 +168,126 @@ func TestBinderChunked(t *testing.T) {
 	assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
 }
 
+func TestBinderRequireTLS(t *testing.T) {
+	requireTLS := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler := requireTLS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	e := WithConfig(Config{
+		BaseURL:  "https://example.com",
+		Reporter: newMockReporter(t),
+		Client: &http.Client{
+			Transport: Binder{
+				Handler: handler,
+				TLS:     &tls.ConnectionState{},
+			},
+		},
+	})
+
+	e.GET("/").Expect().Status(http.StatusOK)
+
+	e = WithConfig(Config{
+		BaseURL:  "https://example.com",
+		Reporter: newMockReporter(t),
+		Client: &http.Client{
+			Transport: NewBinder(handler),
+		},
+	})
+
+	e.GET("/").Expect().Status(http.StatusForbidden)
+}
+
+func TestBinderRemoteAddr(t *testing.T) {
+	var remoteAddr string
+
+	logAddr := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remoteAddr = r.RemoteAddr
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler := logAddr(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	client := &http.Client{
+		Transport: Binder{
+			Handler:    handler,
+			RemoteAddr: "10.1.2.3:4567",
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com/path", nil)
+	resp, err := client.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "10.1.2.3:4567", remoteAddr)
+
+	client = &http.Client{
+		Transport: NewBinder(handler),
+	}
+
+	req, _ = http.NewRequest("GET", "http://example.com/path", nil)
+	resp, err = client.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "", remoteAddr)
+}
+
+func TestBinderBaseContext(t *testing.T) {
+	type ctxKey string
+
+	var baseValue, reqValue interface{}
+	var reqErr error
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		baseValue = r.Context().Value(ctxKey("base"))
+		reqValue = r.Context().Value(ctxKey("req"))
+		reqErr = r.Context().Err()
+	})
+
+	base := context.WithValue(context.Background(), ctxKey("base"), "base-value")
+	base = context.WithValue(base, ctxKey("req"), "overridden")
+
+	client := &http.Client{
+		Transport: Binder{
+			Handler:     handler,
+			BaseContext: base,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(
+		context.WithValue(context.Background(), ctxKey("req"), "req-value"))
+
+	req, _ := http.NewRequest("GET", "http://example.com/path", nil)
+	req = req.WithContext(ctx)
+
+	_, err := client.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "base-value", baseValue)
+	assert.Equal(t, "req-value", reqValue)
+	assert.Nil(t, reqErr)
+
+	cancel()
+
+	req, _ = http.NewRequest("GET", "http://example.com/path", nil)
+	req = req.WithContext(ctx)
+
+	_, _ = Binder{Handler: handler, BaseContext: base}.RoundTrip(req)
+	assert.Equal(t, context.Canceled, reqErr)
+}
+
 func TestFastBinder(t *testing.T) {
 	handler := func(ctx *fasthttp.RequestCtx) {
 		assert.Equal(t, "POST", string(ctx.Request.Header.Method()))
@@ -287,6 +408,37 @@ func TestFastBinderTLS(t *testing.T) {
 	assert.True(t, isTLS)
 }
 
+func TestFastBinderRemoteAddr(t *testing.T) {
+	var remoteAddr string
+
+	handler := func(ctx *fasthttp.RequestCtx) {
+		remoteAddr = ctx.RemoteAddr().String()
+	}
+
+	client := &http.Client{
+		Transport: FastBinder{
+			Handler:    handler,
+			RemoteAddr: "10.1.2.3:4567",
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com/path", nil)
+	_, err := client.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.2.3:4567", remoteAddr)
+
+	client = &http.Client{
+		Transport: FastBinder{
+			Handler:    handler,
+			RemoteAddr: "bad address",
+		},
+	}
+
+	req, _ = http.NewRequest("GET", "http://example.com/path", nil)
+	_, err = client.Do(req)
+	assert.NotNil(t, err)
+}
+
 func TestFastBinderChunked(t *testing.T) {
 	handler := func(ctx *fasthttp.RequestCtx) {
 		assert.Equal(t, "POST", string(ctx.Request.Header.Method()))
@@ -15,7 +15,7 @@ type Boolean struct {
 // Example:
 //  boolean := NewBoolean(t, true)
 func NewBoolean(reporter Reporter, value bool) *Boolean {
-	return &Boolean{makeChain(reporter), value}
+	return &Boolean{makeChainFor("NewBoolean", reporter), value}
 }
 
 // Raw returns underlying value attached to Boolean.
@@ -28,13 +28,41 @@ func (b *Boolean) Raw() bool {
 	return b.value
 }
 
+// Store is similar to Value.Store.
+func (b *Boolean) Store(env *Environment, key string) *Boolean {
+	storeValue(&b.chain, env, key, b.value)
+	return b
+}
+
+// StoreTo saves boolean value into given variable.
+//
+// If a failure was already reported for this Boolean, the variable is
+// left unchanged.
+//
+// Example:
+//  var admin bool
+//  resp.JSON().Object().Value("admin").Boolean().StoreTo(&admin)
+func (b *Boolean) StoreTo(dst *bool) *Boolean {
+	if b.chain.failed() {
+		return b
+	}
+	if dst == nil {
+		b.chain.failUsage("\nunexpected nil pointer in StoreTo")
+		return b
+	}
+	*dst = b.value
+	return b
+}
+
 // Path is similar to Value.Path.
 func (b *Boolean) Path(path string) *Value {
+	defer b.chain.leave(b.chain.failed())
 	return getPath(&b.chain, b.value, path)
 }
 
 // Schema is similar to Value.Schema.
 func (b *Boolean) Schema(schema interface{}) *Boolean {
+	defer b.chain.leave(b.chain.failed())
 	checkSchema(&b.chain, b.value, schema)
 	return b
 }
@@ -45,8 +73,10 @@ func (b *Boolean) Schema(schema interface{}) *Boolean {
 //  boolean := NewBoolean(t, true)
 //  boolean.Equal(true)
 func (b *Boolean) Equal(value bool) *Boolean {
+	defer b.chain.leave(b.chain.failed())
 	if !(b.value == value) {
-		b.chain.fail("expected boolean == %v, but got %v", value, b.value)
+		b.chain.failExpected(value, b.value,
+			"expected boolean == %v, but got %v", value, b.value)
 	}
 	return b
 }
@@ -57,6 +87,7 @@ func (b *Boolean) Equal(value bool) *Boolean {
 //  boolean := NewBoolean(t, true)
 //  boolean.NotEqual(false)
 func (b *Boolean) NotEqual(value bool) *Boolean {
+	defer b.chain.leave(b.chain.failed())
 	if !(b.value != value) {
 		b.chain.fail("expected boolean != %v, but got %v", value, b.value)
 	}
@@ -69,6 +100,7 @@ func (b *Boolean) NotEqual(value bool) *Boolean {
 //  boolean := NewBoolean(t, true)
 //  boolean.True()
 func (b *Boolean) True() *Boolean {
+	defer b.chain.leave(b.chain.failed())
 	return b.Equal(true)
 }
 
@@ -78,5 +110,6 @@ func (b *Boolean) True() *Boolean {
 //  boolean := NewBoolean(t, false)
 //  boolean.False()
 func (b *Boolean) False() *Boolean {
+	defer b.chain.leave(b.chain.failed())
 	return b.Equal(false)
 }
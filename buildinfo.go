@@ -0,0 +1,41 @@
+package httpexpect
+
+import (
+	"runtime/debug"
+)
+
+// buildVersion returns version of this module from build info of the
+// running binary, or empty string if it's unknown.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	return moduleVersion(info)
+}
+
+// moduleVersion returns version of this module, which may be either the
+// main module or a dependency. Development builds, e.g. when running
+// tests of this module, have no version.
+func moduleVersion(info *debug.BuildInfo) string {
+	mod := &info.Main
+	if mod.Path != modulePath {
+		mod = nil
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				mod = dep
+				break
+			}
+		}
+	}
+	if mod == nil {
+		return ""
+	}
+	if mod.Replace != nil {
+		mod = mod.Replace
+	}
+	if mod.Version == "(devel)" {
+		return ""
+	}
+	return mod.Version
+}
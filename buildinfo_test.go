@@ -0,0 +1,77 @@
+package httpexpect
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfoModuleVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		info    debug.BuildInfo
+		version string
+	}{
+		{
+			name: "dependency",
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+				Deps: []*debug.Module{
+					{Path: "github.com/gavv/httpexpect", Version: "v1.0.0"},
+					{Path: modulePath, Version: "v2.16.0"},
+				},
+			},
+			version: "v2.16.0",
+		},
+		{
+			name: "replaced dependency",
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+				Deps: []*debug.Module{
+					{
+						Path:    modulePath,
+						Version: "v2.16.0",
+						Replace: &debug.Module{Path: "example.com/fork", Version: "v2.16.1"},
+					},
+				},
+			},
+			version: "v2.16.1",
+		},
+		{
+			name: "replaced by directory",
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+				Deps: []*debug.Module{
+					{
+						Path:    modulePath,
+						Version: "v2.16.0",
+						Replace: &debug.Module{Path: "../httpexpect"},
+					},
+				},
+			},
+			version: "",
+		},
+		{
+			name: "main module",
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: modulePath, Version: "(devel)"},
+			},
+			version: "",
+		},
+		{
+			name: "missing",
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+			},
+			version: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			info := tc.info
+			assert.Equal(t, tc.version, moduleVersion(&info))
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package httpexpect
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl provides methods to inspect directives of "Cache-Control"
+// header, as defined by RFC 9111.
+type CacheControl struct {
+	chain      chain
+	directives map[string]string
+}
+
+// NewCacheControl returns a new CacheControl object given a reporter used
+// to report failures and "Cache-Control" header value to be inspected.
+//
+// reporter should not be nil. If header can't be parsed, failure is
+// reported.
+//
+// Example:
+//  cc := NewCacheControl(reporter, `public, max-age=60`)
+//  cc.Public().MaxAge().Equal(time.Minute)
+func NewCacheControl(reporter Reporter, header string) *CacheControl {
+	chain := makeChainFor("NewCacheControl", reporter)
+	directives, err := parseCacheControl([]string{header})
+	if err != nil {
+		chain.fail("\nexpected valid \"Cache-Control\" header, but got:\n %q\n\n%s",
+			header, err.Error())
+		return &CacheControl{chain, nil}
+	}
+	return &CacheControl{chain, directives}
+}
+
+// Raw returns parsed directives. Keys are lower-case directive names,
+// values are directive arguments, or empty strings for directives
+// without arguments.
+//
+// Example:
+//  cc := NewCacheControl(t, `no-cache="Set-Cookie"`)
+//  assert.Equal(t, map[string]string{"no-cache": "Set-Cookie"}, cc.Raw())
+func (cc *CacheControl) Raw() map[string]string {
+	return cc.directives
+}
+
+// ContainsDirective succeeds if header contains given directive.
+// Directive name is case-insensitive.
+//
+// Example:
+//  cc := NewCacheControl(t, `must-revalidate`)
+//  cc.ContainsDirective("must-revalidate")
+func (cc *CacheControl) ContainsDirective(name string) *CacheControl {
+	defer cc.chain.leave(cc.chain.failed())
+	if cc.chain.failed() {
+		return cc
+	}
+	if _, ok := cc.directives[strings.ToLower(name)]; !ok {
+		cc.chain.fail("\nexpected \"Cache-Control\" header containing directive:\n"+
+			" %q\n\nbut got directives:\n %q", name, cc.names())
+	}
+	return cc
+}
+
+// NotContainsDirective succeeds if header doesn't contain given directive.
+// Directive name is case-insensitive.
+//
+// Example:
+//  cc := NewCacheControl(t, `public`)
+//  cc.NotContainsDirective("no-store")
+func (cc *CacheControl) NotContainsDirective(name string) *CacheControl {
+	defer cc.chain.leave(cc.chain.failed())
+	if cc.chain.failed() {
+		return cc
+	}
+	if _, ok := cc.directives[strings.ToLower(name)]; ok {
+		cc.chain.fail("\nexpected \"Cache-Control\" header not containing"+
+			" directive:\n %q\n\nbut got directives:\n %q",
+			name, cc.names())
+	}
+	return cc
+}
+
+// NoStore succeeds if header contains "no-store" directive.
+func (cc *CacheControl) NoStore() *CacheControl {
+	defer cc.chain.leave(cc.chain.failed())
+	return cc.ContainsDirective("no-store")
+}
+
+// NoCache succeeds if header contains "no-cache" directive, with or
+// without field names.
+func (cc *CacheControl) NoCache() *CacheControl {
+	defer cc.chain.leave(cc.chain.failed())
+	return cc.ContainsDirective("no-cache")
+}
+
+// Private succeeds if header contains "private" directive, with or
+// without field names.
+func (cc *CacheControl) Private() *CacheControl {
+	defer cc.chain.leave(cc.chain.failed())
+	return cc.ContainsDirective("private")
+}
+
+// Public succeeds if header contains "public" directive.
+func (cc *CacheControl) Public() *CacheControl {
+	defer cc.chain.leave(cc.chain.failed())
+	return cc.ContainsDirective("public")
+}
+
+// MaxAge returns a new Duration object that may be used to inspect
+// "max-age" directive.
+//
+// If directive is missing, MaxAge returns Duration in "not set" state,
+// see Duration.NotSet. If its argument isn't a non-negative integer,
+// failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.CacheControl().MaxAge().Ge(time.Hour)
+func (cc *CacheControl) MaxAge() *Duration {
+	defer cc.chain.leave(cc.chain.failed())
+	return cc.deltaSeconds("max-age")
+}
+
+// SMaxAge is like MaxAge, but for "s-maxage" directive.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.CacheControl().SMaxAge().Equal(10 * time.Minute)
+func (cc *CacheControl) SMaxAge() *Duration {
+	defer cc.chain.leave(cc.chain.failed())
+	return cc.deltaSeconds("s-maxage")
+}
+
+// Directive returns a new String object that may be used to inspect
+// argument of given directive, e.g. extension directive. Quoted arguments
+// are unquoted. Directive name is case-insensitive.
+//
+// If directive is missing, failure is reported. If it has no argument,
+// returned string is empty.
+//
+// Example:
+//  cc := NewCacheControl(t, `stale-while-revalidate=30, community="UCI"`)
+//  cc.Directive("community").Equal("UCI")
+func (cc *CacheControl) Directive(name string) *String {
+	defer cc.chain.leave(cc.chain.failed())
+	if cc.chain.failed() {
+		return &String{cc.chain, ""}
+	}
+	value, ok := cc.directives[strings.ToLower(name)]
+	if !ok {
+		cc.chain.fail("\nexpected \"Cache-Control\" header containing directive:\n"+
+			" %q\n\nbut got directives:\n %q", name, cc.names())
+		return &String{cc.chain, ""}
+	}
+	return &String{cc.chain.enter(strings.ToLower(name)), value}
+}
+
+func (cc *CacheControl) names() []string {
+	names := make([]string, 0, len(cc.directives))
+	for name := range cc.directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (cc *CacheControl) deltaSeconds(name string) *Duration {
+	if cc.chain.failed() {
+		return &Duration{cc.chain, nil}
+	}
+	value, ok := cc.directives[name]
+	if !ok {
+		return &Duration{cc.chain, nil}
+	}
+	seconds, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		cc.chain.fail("\nexpected \"Cache-Control\" directive %q containing"+
+			" non-negative integer, but got:\n %q", name, value)
+		return &Duration{cc.chain, nil}
+	}
+	d := time.Duration(seconds) * time.Second
+	return &Duration{cc.chain.enter(name), &d}
+}
+
+// parseCacheControl parses comma-separated list of directives from all
+// header lines. Directive is a token, optionally followed by "=" and
+// either a token or a quoted string.
+func parseCacheControl(headers []string) (map[string]string, error) {
+	directives := map[string]string{}
+	for _, header := range headers {
+		s := header
+		for {
+			s = strings.TrimLeft(s, " \t,")
+			if s == "" {
+				break
+			}
+			end := strings.IndexAny(s, "=, \t")
+			if end < 0 {
+				end = len(s)
+			}
+			name := strings.ToLower(s[:end])
+			if name == "" || !isToken(name) {
+				return nil, fmt.Errorf("invalid directive name at %q", s)
+			}
+			s = strings.TrimLeft(s[end:], " \t")
+
+			var value string
+			if strings.HasPrefix(s, "=") {
+				s = strings.TrimLeft(s[1:], " \t")
+				var err error
+				if value, s, err = parseDirectiveValue(s); err != nil {
+					return nil, fmt.Errorf("invalid argument of %q directive: %s",
+						name, err.Error())
+				}
+				s = strings.TrimLeft(s, " \t")
+			}
+			if s != "" && s[0] != ',' {
+				return nil, fmt.Errorf("unexpected %q after %q directive", s, name)
+			}
+
+			if _, ok := directives[name]; !ok {
+				directives[name] = value
+			}
+		}
+	}
+	return directives, nil
+}
+
+// parseDirectiveValue parses token or quoted string at the beginning of s
+// and returns it and the remaining part of s.
+func parseDirectiveValue(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexAny(s, ", \t")
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 || !isToken(s[:end]) {
+			return "", "", fmt.Errorf("expected token or quoted string at %q", s)
+		}
+		return s[:end], s[end:], nil
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 == len(s) {
+				return "", "", fmt.Errorf("unterminated quoted string %q", s)
+			}
+			i++
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted string %q", s)
+}
+
+// isToken reports whether s consists only of RFC 9110 token characters.
+func isToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ETag provides methods to inspect entity tag from "ETag" header,
+// as defined by RFC 9110.
+type ETag struct {
+	chain chain
+	value string
+	weak  bool
+}
+
+// NewETag returns a new ETag object given a reporter used to report
+// failures and "ETag" header value to be inspected.
+//
+// reporter should not be nil. If header is not a valid entity tag,
+// failure is reported.
+//
+// Example:
+//  etag := NewETag(reporter, `W/"abc"`)
+//  etag.IsWeak().Value().Equal("abc")
+func NewETag(reporter Reporter, header string) *ETag {
+	chain := makeChainFor("NewETag", reporter)
+	value, weak, ok := parseETag(header)
+	if !ok {
+		failETag(&chain, header)
+	}
+	return &ETag{chain, value, weak}
+}
+
+// Raw returns opaque tag, without quotes and weakness indicator.
+//
+// Example:
+//  etag := NewETag(t, `W/"abc"`)
+//  assert.Equal(t, "abc", etag.Raw())
+func (e *ETag) Raw() string {
+	return e.value
+}
+
+// Value returns a new String object that may be used to inspect opaque
+// tag, without quotes and weakness indicator.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.ETag().Value().NotEmpty()
+func (e *ETag) Value() *String {
+	defer e.chain.leave(e.chain.failed())
+	return &String{e.chain, e.value}
+}
+
+// IsWeak succeeds if entity tag is weak, i.e. has "W/" prefix.
+//
+// Example:
+//  etag := NewETag(t, `W/"abc"`)
+//  etag.IsWeak()
+func (e *ETag) IsWeak() *ETag {
+	defer e.chain.leave(e.chain.failed())
+	if e.chain.failed() {
+		return e
+	}
+	if !e.weak {
+		e.chain.fail("\nexpected weak entity tag, but got strong:\n %q",
+			formatETag(e.value, e.weak))
+	}
+	return e
+}
+
+// IsStrong succeeds if entity tag is strong, i.e. has no "W/" prefix.
+//
+// Example:
+//  etag := NewETag(t, `"abc"`)
+//  etag.IsStrong()
+func (e *ETag) IsStrong() *ETag {
+	defer e.chain.leave(e.chain.failed())
+	if e.chain.failed() {
+		return e
+	}
+	if e.weak {
+		e.chain.fail("\nexpected strong entity tag, but got weak:\n %q",
+			formatETag(e.value, e.weak))
+	}
+	return e
+}
+
+func parseETag(header string) (value string, weak bool, ok bool) {
+	s := strings.TrimSpace(header)
+	if strings.HasPrefix(s, "W/") {
+		weak = true
+		s = s[2:]
+	}
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", false, false
+	}
+	value = s[1 : len(s)-1]
+	for i := 0; i < len(value); i++ {
+		// etagc = %x21 / %x23-7E / obs-text
+		if c := value[i]; c == '"' || c < 0x21 || c == 0x7f {
+			return "", false, false
+		}
+	}
+	return value, weak, true
+}
+
+func formatETag(value string, weak bool) string {
+	if weak {
+		return `W/"` + value + `"`
+	}
+	return `"` + value + `"`
+}
+
+func failETag(chain *chain, header string) {
+	chain.fail("\nexpected \"ETag\" header in form of:\n %q or %q\n\nbut got:\n %q",
+		`"tag"`, `W/"tag"`, header)
+}
@@ -0,0 +1,215 @@
+package httpexpect
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControlFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	cc := &CacheControl{chain, nil}
+
+	cc.ContainsDirective("public")
+	cc.NotContainsDirective("public")
+	cc.NoStore()
+	cc.NoCache()
+	cc.Private()
+	cc.Public()
+
+	cc.MaxAge().chain.assertFailed(t)
+	cc.SMaxAge().chain.assertFailed(t)
+	cc.Directive("public").chain.assertFailed(t)
+}
+
+func TestCacheControlParse(t *testing.T) {
+	cases := []struct {
+		header     string
+		directives map[string]string
+	}{
+		{"", map[string]string{}},
+		{"no-store", map[string]string{"no-store": ""}},
+		{"Public, MAX-AGE=60", map[string]string{"public": "", "max-age": "60"}},
+		{
+			`private="Set-Cookie, X-Token", no-cache`,
+			map[string]string{"private": "Set-Cookie, X-Token", "no-cache": ""},
+		},
+		{
+			`ext="a \"quoted\" \\ value" , s-maxage = 10,,`,
+			map[string]string{"ext": `a "quoted" \ value`, "s-maxage": "10"},
+		},
+		{"max-age=1, max-age=2", map[string]string{"max-age": "1"}},
+	}
+
+	for _, tc := range cases {
+		reporter := newMockReporter(t)
+
+		cc := NewCacheControl(reporter, tc.header)
+		cc.chain.assertOK(t)
+
+		assert.Equal(t, tc.directives, cc.Raw(), tc.header)
+	}
+
+	for _, header := range []string{
+		`=60`,
+		`max-age=`,
+		`ext="unterminated`,
+		`ext="a"b`,
+		`max age`,
+		`a/b`,
+	} {
+		reporter := newMockReporter(t)
+
+		cc := NewCacheControl(reporter, header)
+		cc.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, fmt.Sprintf("%q", header), header)
+	}
+}
+
+func TestCacheControlDirectives(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cc := NewCacheControl(reporter,
+		`public, max-age=3600, s-maxage=600, stale-while-revalidate=30, `+
+			`community="UCI"`)
+	cc.chain.assertOK(t)
+
+	cc.Public()
+	cc.chain.assertOK(t)
+	cc.chain.reset()
+
+	cc.ContainsDirective("Stale-While-Revalidate")
+	cc.chain.assertOK(t)
+	cc.chain.reset()
+
+	cc.NotContainsDirective("no-store")
+	cc.chain.assertOK(t)
+	cc.chain.reset()
+
+	cc.NotContainsDirective("public")
+	cc.chain.assertFailed(t)
+	cc.chain.reset()
+
+	for _, fn := range []func() *CacheControl{cc.NoStore, cc.NoCache, cc.Private} {
+		fn()
+		cc.chain.assertFailed(t)
+		cc.chain.reset()
+	}
+
+	cc.MaxAge().Equal(time.Hour).chain.assertOK(t)
+	cc.SMaxAge().Equal(10 * time.Minute).chain.assertOK(t)
+
+	cc.Directive("community").Equal("UCI").chain.assertOK(t)
+	cc.Directive("public").Empty().chain.assertOK(t)
+	cc.Directive("stale-while-revalidate").Equal("30").chain.assertOK(t)
+
+	cc.Directive("missing").chain.assertFailed(t)
+	cc.chain.reset()
+}
+
+func TestCacheControlMaxAge(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewCacheControl(reporter, "no-cache").MaxAge().NotSet().chain.assertOK(t)
+	NewCacheControl(reporter, "s-maxage=0").SMaxAge().Equal(0).chain.assertOK(t)
+
+	cc := NewCacheControl(reporter, `max-age="abc"`)
+	cc.chain.assertOK(t)
+	cc.MaxAge().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, `"abc"`)
+
+	cc = NewCacheControl(reporter, `max-age=-1`)
+	cc.MaxAge().chain.assertFailed(t)
+}
+
+func TestETag(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	strong := NewETag(reporter, `"v1"`)
+	strong.chain.assertOK(t)
+	assert.Equal(t, "v1", strong.Raw())
+
+	strong.IsStrong()
+	strong.chain.assertOK(t)
+	strong.chain.reset()
+
+	strong.IsWeak()
+	strong.chain.assertFailed(t)
+	strong.chain.reset()
+
+	weak := NewETag(reporter, `W/"abc-123"`)
+	weak.chain.assertOK(t)
+
+	weak.IsWeak().Value().Equal("abc-123").chain.assertOK(t)
+
+	weak.IsStrong()
+	weak.chain.assertFailed(t)
+	weak.chain.reset()
+
+	empty := NewETag(reporter, `""`)
+	empty.chain.assertOK(t)
+	empty.Value().Empty().chain.assertOK(t)
+
+	for _, header := range []string{`v1`, `"v1`, `w/"v1"`, `"a"b"`, `W/`, `"a b"`} {
+		reporter := newMockReporter(t)
+
+		etag := NewETag(reporter, header)
+		etag.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, fmt.Sprintf("%q", header), header)
+	}
+}
+
+func TestResponseCacheControl(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	resp := NewResponse(reporter, &http.Response{
+		Header: http.Header{
+			"Cache-Control": {"public, max-age=60", `no-cache="Set-Cookie"`},
+			"Etag":          {`W/"xyz"`},
+		},
+	})
+
+	cc := resp.CacheControl()
+	cc.chain.assertOK(t)
+
+	cc.Public().NoCache().MaxAge().Equal(time.Minute)
+	cc.Directive("no-cache").Equal("Set-Cookie")
+	cc.chain.assertOK(t)
+
+	resp.ETag().IsWeak().Value().Equal("xyz")
+	resp.chain.assertOK(t)
+
+	resp = NewResponse(reporter, &http.Response{
+		Header: http.Header{
+			"Cache-Control": {`max-age=60, "bad"`},
+		},
+	})
+
+	resp.CacheControl().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, `max-age=60, \"bad\"`)
+	resp.chain.reset()
+
+	resp.CacheControl().NoStore().chain.assertFailed(t)
+	resp.chain.reset()
+
+	resp.ETag().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "missing")
+	resp.chain.reset()
+
+	resp = NewResponse(reporter, &http.Response{
+		Header: http.Header{},
+	})
+
+	cc = resp.CacheControl()
+	cc.chain.assertOK(t)
+	cc.NotContainsDirective("public").MaxAge().NotSet()
+	cc.chain.assertOK(t)
+}
@@ -1,24 +1,309 @@
 package httpexpect
 
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
 type chain struct {
-	reporter Reporter
-	failbit  bool
+	reporter  Reporter
+	failbit   bool
+	requestID string
+	path      string
+	stats     Stats
+	clock     func() time.Time
+	excerpt   int
+	precision int
+	tracker   *assertionTracker
 }
 
 func makeChain(reporter Reporter) chain {
-	return chain{reporter, false}
+	return chain{reporter, false, "", "", nil, nil, 0, 0, nil}
+}
+
+// assertionTracker is shared by all copies of chain derived from the
+// chain it was attached to, and counts assertions made on them. It's used
+// by end-of-test checks of requests and responses.
+type assertionTracker struct {
+	assertions int32
+	failures   int32
+}
+
+func (t *assertionTracker) add() {
+	atomic.AddInt32(&t.assertions, 1)
+}
+
+func (t *assertionTracker) count() int {
+	return int(atomic.LoadInt32(&t.assertions))
+}
+
+func (t *assertionTracker) failed() bool {
+	return atomic.LoadInt32(&t.failures) != 0
+}
+
+// makeChainFor is like makeChain, but panics if reporter is nil, since
+// there is no way to report failure otherwise. It's used by constructors.
+func makeChainFor(constructor string, reporter Reporter) chain {
+	if reporter == nil {
+		panic(usagePanic("\nunexpected nil reporter passed to " + constructor))
+	}
+	return makeChain(reporter)
+}
+
+// usagePanic formats panic message for incorrect usage that can't be
+// reported via reporter.
+func usagePanic(message string) string {
+	return "httpexpect: " + strings.TrimSpace(message)
+}
+
+// now returns current time using clock from Config.TimeNow, if any.
+func (c *chain) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// enter returns a copy of chain for a child value accessed by given key,
+// e.g. object key or response part name. Key is appended to chain path,
+// which is included in failures.
+func (c *chain) enter(key string) chain {
+	ret := *c
+	ret.path = joinPath(c.path, key)
+	return ret
+}
+
+// enterIndex is like enter, but for a child value accessed by array index.
+func (c *chain) enterIndex(index int) chain {
+	ret := *c
+	ret.path = fmt.Sprintf("%s[%d]", c.path, index)
+	return ret
 }
 
 func (c *chain) failed() bool {
 	return c.failbit
 }
 
+// leave is deferred by assertion methods, with failed() state of chain
+// before the assertion. If assertion succeeded, it's reported to
+// Config.Stats; failed assertions are reported by report. Assertions
+// invoked by other assertions of this package are not counted, and neither
+// are assertions skipped because of earlier failures.
+func (c *chain) leave(wasFailed bool) {
+	if wasFailed || c.failbit {
+		return
+	}
+	if c.tracker != nil {
+		c.tracker.add()
+	}
+	if c.stats == nil {
+		return
+	}
+	if !calledFromUser() {
+		return
+	}
+	c.stats.OnAssertion(AssertionStats{
+		Path:      c.path,
+		RequestID: c.requestID,
+	})
+}
+
 func (c *chain) fail(message string, args ...interface{}) {
+	c.report(Failure{Type: FailureAssertion}, message, args...)
+}
+
+// failUsage is like fail, but reports FailureAssertUsage, i.e. incorrect
+// usage of assertion, like invalid arguments. Message should name the
+// method and the problem.
+func (c *chain) failUsage(message string, args ...interface{}) {
+	c.report(Failure{Type: FailureAssertUsage}, message, args...)
+}
+
+// failExpected is like fail, but attaches expected and actual values to
+// failure, for reporters that consume structured failures.
+func (c *chain) failExpected(
+	expected, actual interface{}, message string, args ...interface{},
+) {
+	c.report(Failure{
+		Expected: &FailureValue{expected},
+		Actual:   &FailureValue{actual},
+	}, message, args...)
+}
+
+// failActual is like failExpected, but attaches only actual value.
+func (c *chain) failActual(actual interface{}, message string, args ...interface{}) {
+	c.report(Failure{
+		Actual: &FailureValue{actual},
+	}, message, args...)
+}
+
+// failBelongs is like failExpected, but reports FailureAssertBelongs and
+// attaches list of values instead of expected value.
+func (c *chain) failBelongs(
+	list, actual interface{}, message string, args ...interface{},
+) {
+	c.report(Failure{
+		Type:     FailureAssertBelongs,
+		Expected: &FailureValue{list},
+		Actual:   &FailureValue{actual},
+	}, message, args...)
+}
+
+// failRange is like failExpected, but attaches range bounds instead of
+// expected value.
+func (c *chain) failRange(
+	min, max, actual interface{}, message string, args ...interface{},
+) {
+	c.report(Failure{
+		Actual: &FailureValue{actual},
+		Bounds: &FailureBounds{min, max},
+	}, message, args...)
+}
+
+func (c *chain) report(failure Failure, message string, args ...interface{}) {
 	if c.failbit {
 		return
 	}
+	if c.reporter == nil {
+		// object was not created using constructor, so there is no
+		// way to report failure
+		panic(usagePanic(fmt.Sprintf(message, args...) +
+			"\n\nobject has nil reporter (was it created without constructor?)"))
+	}
 	c.failbit = true
-	c.reporter.Errorf(message, args...)
+	if c.tracker != nil {
+		c.tracker.add()
+		atomic.AddInt32(&c.tracker.failures, 1)
+	}
+	if c.stats != nil {
+		c.stats.OnAssertion(AssertionStats{
+			Failed:    true,
+			Message:   fmt.Sprintf(message, args...),
+			Path:      c.path,
+			RequestID: c.requestID,
+		})
+	}
+	if h, ok := c.reporter.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	failure.Message = fmt.Sprintf(message, args...)
+	failure.Path = c.path
+	failure.RequestID = c.requestID
+	location, name := callSite()
+	if !reportsCallSite(c.reporter) {
+		failure.Assertion = location
+	}
+	failure.AssertionName = name
+	if fr, ok := c.reporter.(FailureReporter); ok {
+		fr.ReportFailure(failure)
+		return
+	}
+	c.reporter.Errorf("%s", failure.String())
+}
+
+// reportsCallSite returns true if reporter already reports location of
+// failed assertion by itself, e.g. testify-based reporters print stack
+// trace of assertion.
+func reportsCallSite(reporter Reporter) bool {
+	switch r := reporter.(type) {
+	case *AssertReporter, *RequireReporter:
+		return true
+	case *failureRecorder:
+		return reportsCallSite(r.reporter)
+	}
+	return false
+}
+
+// failureRecorder is Reporter that forwards failures to underlying reporter
+// and remembers whether any failure was reported.
+type failureRecorder struct {
+	reporter Reporter
+	failed   bool
+}
+
+func (r *failureRecorder) Errorf(message string, args ...interface{}) {
+	r.failed = true
+	if h, ok := r.reporter.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	r.reporter.Errorf(message, args...)
+}
+
+func (r *failureRecorder) ReportFailure(failure Failure) {
+	r.failed = true
+	if h, ok := r.reporter.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if fr, ok := r.reporter.(FailureReporter); ok {
+		fr.ReportFailure(failure)
+		return
+	}
+	r.reporter.Errorf("%s", failure.String())
+}
+
+func (r *failureRecorder) Helper() {
+	if h, ok := r.reporter.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+}
+
+// record returns a copy of chain that records failures reported by any
+// value derived from it, even though values hold their own copies of chain.
+func (c *chain) record() (chain, *failureRecorder) {
+	rec := &failureRecorder{reporter: c.reporter}
+	ret := *c
+	ret.reporter = rec
+	return ret, rec
+}
+
+var packagePrefix = reflect.TypeOf(chain{}).PkgPath() + "."
+
+// callSite returns "file:line" of the first caller outside of this package,
+// i.e. location of user assertion that caused failure, and name of the
+// assertion method called by user, e.g. "Number.Equal". Frames from test
+// files of this package are treated as user code.
+func callSite() (string, string) {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	name := ""
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) ||
+			strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line), name
+		}
+		name = assertionName(frame.Function)
+		if !more {
+			return "", name
+		}
+	}
+}
+
+// calledFromUser returns true if caller of assertion method which deferred
+// leave is located outside of this package, or in its test files.
+func calledFromUser() bool {
+	// skip runtime.Callers, calledFromUser, leave, and assertion method
+	pcs := make([]uintptr, 1)
+	if runtime.Callers(4, pcs) == 0 {
+		return true
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	return !strings.HasPrefix(frame.Function, packagePrefix) ||
+		strings.HasSuffix(frame.File, "_test.go")
+}
+
+// assertionName converts function name like "pkg.(*Number).Equal" to
+// "Number.Equal".
+func assertionName(function string) string {
+	name := strings.TrimPrefix(function, packagePrefix)
+	name = strings.Replace(name, "(*", "", 1)
+	name = strings.Replace(name, ")", "", 1)
+	return name
 }
 
 func (c *chain) reset() {
@@ -1,9 +1,17 @@
 package httpexpect
 
 import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChainFail(t *testing.T) {
@@ -62,3 +70,325 @@ func TestChainReport(t *testing.T) {
 	chain.assertOK(r2)
 	assert.True(t, r2.reported)
 }
+
+func TestChainRequestID(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	chain := makeChain(reporter)
+	chain.requestID = "abcd1234"
+
+	chain.fail("failure %d", 123)
+
+	assert.True(t, reporter.reported)
+	assert.Contains(t, reporter.message, "failure 123")
+	assert.Contains(t, reporter.message, "abcd1234")
+}
+
+func TestChainPath(t *testing.T) {
+	chain1 := makeChain(newMockReporter(t))
+	assert.Equal(t, "", chain1.path)
+
+	chain2 := chain1.enter("JSON")
+	assert.Equal(t, "", chain1.path)
+	assert.Equal(t, "JSON", chain2.path)
+
+	chain3 := chain2.enter("items")
+	chain4 := chain3.enterIndex(2)
+	chain5 := chain4.enter("name")
+	assert.Equal(t, "JSON.items", chain3.path)
+	assert.Equal(t, "JSON.items[2]", chain4.path)
+	assert.Equal(t, "JSON.items[2].name", chain5.path)
+
+	chain6 := chain1.enterIndex(0)
+	assert.Equal(t, "[0]", chain6.path)
+}
+
+func TestChainPathFailure(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	chain := makeChain(reporter)
+	chain.fail("failure")
+	_, file, line, _ := runtime.Caller(0)
+	assert.Equal(t, fmt.Sprintf("failure\n\nassertion:\n %s:%d", file, line-1),
+		reporter.message)
+
+	parent := makeChain(reporter)
+	child := parent.enter("foo")
+	child.requestID = "abcd1234"
+	child.fail("failure")
+	_, file, line, _ = runtime.Caller(0)
+	assert.Equal(t,
+		fmt.Sprintf("failure\n\nassertion:\n %s:%d"+
+			"\n\npath:\n foo\n\nrequest id:\n abcd1234", file, line-1),
+		reporter.message)
+}
+
+func TestChainCallSite(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewObject(reporter, map[string]interface{}{"foo": "bar"}).
+		Value("foo").String().Equal("baz")
+	_, _, line, _ := runtime.Caller(0)
+
+	m := regexp.MustCompile(`assertion:\n (.+):(\d+)`).FindStringSubmatch(reporter.message)
+	require.Len(t, m, 3)
+
+	assert.Equal(t, "chain_test.go", filepath.Base(m[1]))
+	assert.Equal(t, strconv.Itoa(line-1), m[2])
+}
+
+type mockHelperT struct {
+	helpers int
+	errors  int
+}
+
+func (t *mockHelperT) Helper() {
+	t.helpers++
+}
+
+func (t *mockHelperT) Errorf(message string, args ...interface{}) {
+	t.errors++
+}
+
+func (t *mockHelperT) FailNow() {
+}
+
+func TestChainHelper(t *testing.T) {
+	helperT := &mockHelperT{}
+
+	chain := makeChain(helperT)
+	chain.fail("failure")
+
+	assert.Equal(t, 1, helperT.errors)
+	assert.NotZero(t, helperT.helpers)
+
+	helperT = &mockHelperT{}
+
+	chain = makeChain(NewAssertReporter(helperT))
+	chain.fail("failure")
+
+	assert.Equal(t, 1, helperT.errors)
+	assert.NotZero(t, helperT.helpers)
+
+	helperT = &mockHelperT{}
+
+	chain = makeChain(NewRequireReporter(helperT))
+	chain.fail("failure")
+
+	assert.Equal(t, 1, helperT.errors)
+	assert.NotZero(t, helperT.helpers)
+}
+
+func TestChainFailUsage(t *testing.T) {
+	reporter := &failureRecordingReporter{}
+
+	chain := makeChain(reporter)
+	chain.failUsage("\nunexpected nil argument passed to %s", "Foo")
+
+	assert.True(t, chain.failed())
+	require.Equal(t, 1, len(reporter.failures))
+	assert.Equal(t, FailureAssertUsage, reporter.failures[0].Type)
+	assert.Equal(t, "\nunexpected nil argument passed to Foo", reporter.failures[0].Message)
+
+	chain = makeChain(reporter)
+	chain.fail("\nexpected 1")
+
+	require.Equal(t, 2, len(reporter.failures))
+	assert.Equal(t, FailureAssertion, reporter.failures[1].Type)
+
+	assert.Equal(t, "FailureAssertion", FailureAssertion.String())
+	assert.Equal(t, "FailureAssertUsage", FailureAssertUsage.String())
+	assert.Equal(t, "FailureAssertBelongs", FailureAssertBelongs.String())
+	assert.Equal(t, "FailureType(99)", FailureType(99).String())
+}
+
+func TestChainNilReporter(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"httpexpect: unexpected nil reporter passed to NewDuration", func() {
+			NewDuration(nil, time.Second)
+		})
+
+	assert.PanicsWithValue(t,
+		"httpexpect: unexpected nil reporter passed to NewString", func() {
+			NewString(nil, "foo")
+		})
+
+	assert.PanicsWithValue(t,
+		"httpexpect: unexpected nil config.Reporter passed to WithConfig", func() {
+			WithConfig(Config{})
+		})
+
+	assert.PanicsWithValue(t,
+		"httpexpect: unexpected nil config.Reporter passed to NewRequest", func() {
+			NewRequest(Config{
+				RequestFactory: DefaultRequestFactory{},
+				Client:         &mockClient{},
+			}, "GET", "url")
+		})
+
+	assert.PanicsWithValue(t,
+		"httpexpect: array index out of bounds:\n  index 0\n\n  array is empty"+
+			"\n\nobject has nil reporter (was it created without constructor?)",
+		func() {
+			var array Array
+			array.Element(0)
+		})
+}
+
+func TestChainUsageFailures(t *testing.T) {
+	newRequest := func(reporter Reporter, path string, pathargs ...interface{}) *Request {
+		return NewRequest(Config{
+			RequestFactory: DefaultRequestFactory{},
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}, "GET", path, pathargs...)
+	}
+
+	cases := []struct {
+		name    string
+		fn      func(Reporter)
+		message string
+	}{
+		{
+			name: "String.StoreTo nil",
+			fn: func(r Reporter) {
+				NewString(r, "foo").StoreTo(nil)
+			},
+			message: "\nunexpected nil pointer in StoreTo",
+		},
+		{
+			name: "Boolean.StoreTo nil",
+			fn: func(r Reporter) {
+				NewBoolean(r, true).StoreTo(nil)
+			},
+			message: "\nunexpected nil pointer in StoreTo",
+		},
+		{
+			name: "String.InList empty",
+			fn: func(r Reporter) {
+				NewString(r, "foo").InList()
+			},
+			message: "\nunexpected empty list argument in InList",
+		},
+		{
+			name: "Number.InRangeOpts min greater than max",
+			fn: func(r Reporter) {
+				NewNumber(r, 1).InRangeOpts(2, 1, Bounds{})
+			},
+			message: "\nunexpected range with min greater than max" +
+				" passed to InRangeOpts:\n [2, 1]",
+		},
+		{
+			name: "Duration.InRangeOpts min greater than max",
+			fn: func(r Reporter) {
+				NewDuration(r, time.Second).InRangeOpts(time.Minute, time.Second, Bounds{})
+			},
+			message: "\nunexpected range with min greater than max" +
+				" passed to InRangeOpts:\n [1m0s, 1s]",
+		},
+		{
+			name: "Decimal.Equal invalid argument",
+			fn: func(r Reporter) {
+				NewDecimal(r, "1.5").Equal(struct{}{})
+			},
+			message: "\nunexpected decimal argument:\n" +
+				" value of type struct {} is neither string, number, nor *big.Rat",
+		},
+		{
+			name: "NewResponse multiple rtt",
+			fn: func(r Reporter) {
+				NewResponse(r, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       http.NoBody,
+				}, time.Second, time.Second)
+			},
+			message: "\nunexpected multiple rtt arguments passed to NewResponse",
+		},
+		{
+			name: "Response.ErrorClass without error",
+			fn: func(r Reporter) {
+				NewResponse(r, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       http.NoBody,
+				}).ErrorClass()
+			},
+			message: "\nunexpected ErrorClass call for response without transport error",
+		},
+		{
+			name: "Response.MsgPack multiple targets",
+			fn: func(r Reporter) {
+				var a, b interface{}
+				NewResponse(r, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       http.NoBody,
+				}).MsgPack(&a, &b)
+			},
+			message: "\nunexpected multiple targets in MsgPack",
+		},
+		{
+			name: "CookieJar.Cookies nil url",
+			fn: func(r Reporter) {
+				NewCookieJar(r, NewJar()).Cookies(nil)
+			},
+			message: "\nunexpected nil url in Cookies",
+		},
+		{
+			name: "NewRequest extra path arguments",
+			fn: func(r Reporter) {
+				newRequest(r, "/users/{user}", "john", "extra")
+			},
+			message: "\nunexpected extra arguments for url path format string:\n" +
+				" Request(\"GET\", [john extra]...)" +
+				"\n\npath has 1 parameter(s), but got 2 argument(s)",
+		},
+		{
+			name: "NewRequest nil path argument",
+			fn: func(r Reporter) {
+				newRequest(r, "/users/{user}", nil)
+			},
+			message: "\nunexpected nil argument for url path format string:\n" +
+				" Request(\"GET\", [<nil>]...)",
+		},
+		{
+			name: "Request.WithPath unknown key",
+			fn: func(r Reporter) {
+				newRequest(r, "/users/{user}").WithPath("repo", "httpexpect")
+			},
+			message: "\nunexpected key for url path format string:\n" +
+				" WithPath(\"repo\", httpexpect)\n\npath:\n \"/users/{user}\"",
+		},
+		{
+			name: "Request.WithHandler nil",
+			fn: func(r Reporter) {
+				newRequest(r, "/").WithHandler(nil)
+			},
+			message: "\nunexpected nil handler in WithHandler",
+		},
+		{
+			name: "Request.WithTimeout negative",
+			fn: func(r Reporter) {
+				newRequest(r, "/").WithTimeout(-time.Second)
+			},
+			message: "\nunexpected negative timeout -1s passed to WithTimeout",
+		},
+		{
+			name: "Request.WithAccept empty",
+			fn: func(r Reporter) {
+				newRequest(r, "/").WithAccept()
+			},
+			message: "\nunexpected empty media type list in WithAccept",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := &failureRecordingReporter{}
+
+			tc.fn(reporter)
+
+			require.Equal(t, 1, len(reporter.failures))
+			assert.Equal(t, FailureAssertUsage, reporter.failures[0].Type)
+			assert.Equal(t, tc.message, reporter.failures[0].Message)
+		})
+	}
+}
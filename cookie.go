@@ -22,7 +22,7 @@ type Cookie struct {
 //   cookie.Path().Equal("/")
 //   cookie.Expires().InRange(time.Now(), time.Now().Add(time.Hour * 24))
 func NewCookie(reporter Reporter, value *http.Cookie) *Cookie {
-	chain := makeChain(reporter)
+	chain := makeChainFor("NewCookie", reporter)
 	if value == nil {
 		chain.fail("expected non-nil cookie")
 	}
@@ -46,10 +46,11 @@ func (c *Cookie) Raw() *http.Cookie {
 //  cookie := NewCookie(t, &http.Cookie{...})
 //  cookie.Name().Equal("session")
 func (c *Cookie) Name() *String {
+	defer c.chain.leave(c.chain.failed())
 	if c.chain.failed() {
 		return &String{c.chain, ""}
 	}
-	return &String{c.chain, c.value.Name}
+	return &String{c.chain.enter("Name"), c.value.Name}
 }
 
 // Value returns a new String object that may be used to inspect
@@ -59,10 +60,11 @@ func (c *Cookie) Name() *String {
 //  cookie := NewCookie(t, &http.Cookie{...})
 //  cookie.Value().Equal("gH6z7Y")
 func (c *Cookie) Value() *String {
+	defer c.chain.leave(c.chain.failed())
 	if c.chain.failed() {
 		return &String{c.chain, ""}
 	}
-	return &String{c.chain, c.value.Value}
+	return &String{c.chain.enter("Value"), c.value.Value}
 }
 
 // Domain returns a new String object that may be used to inspect
@@ -72,10 +74,11 @@ func (c *Cookie) Value() *String {
 //  cookie := NewCookie(t, &http.Cookie{...})
 //  cookie.Domain().Equal("example.com")
 func (c *Cookie) Domain() *String {
+	defer c.chain.leave(c.chain.failed())
 	if c.chain.failed() {
 		return &String{c.chain, ""}
 	}
-	return &String{c.chain, c.value.Domain}
+	return &String{c.chain.enter("Domain"), c.value.Domain}
 }
 
 // Path returns a new String object that may be used to inspect
@@ -85,10 +88,11 @@ func (c *Cookie) Domain() *String {
 //  cookie := NewCookie(t, &http.Cookie{...})
 //  cookie.Path().Equal("/foo")
 func (c *Cookie) Path() *String {
+	defer c.chain.leave(c.chain.failed())
 	if c.chain.failed() {
 		return &String{c.chain, ""}
 	}
-	return &String{c.chain, c.value.Path}
+	return &String{c.chain.enter("Path"), c.value.Path}
 }
 
 // Expires returns a new DateTime object that may be used to inspect
@@ -98,10 +102,12 @@ func (c *Cookie) Path() *String {
 //  cookie := NewCookie(t, &http.Cookie{...})
 //  cookie.Expires().InRange(time.Now(), time.Now().Add(time.Hour * 24))
 func (c *Cookie) Expires() *DateTime {
+	defer c.chain.leave(c.chain.failed())
 	if c.chain.failed() {
-		return &DateTime{c.chain, time.Unix(0, 0)}
+		return newDateTimeEpoch(c.chain)
 	}
-	return &DateTime{c.chain, c.value.Expires}
+	expires := c.value.Expires
+	return &DateTime{c.chain.enter("Expires"), &expires}
 }
 
 // MaxAge returns a new Duration object that may be used to inspect
@@ -118,16 +124,17 @@ func (c *Cookie) Expires() *DateTime {
 //  cookie.MaxAge().IsSet()
 //  cookie.MaxAge().InRange(time.Minute, time.Minute*10)
 func (c *Cookie) MaxAge() *Duration {
+	defer c.chain.leave(c.chain.failed())
 	if c.chain.failed() {
 		return &Duration{c.chain, nil}
 	}
 	if c.value.MaxAge == 0 {
-		return &Duration{c.chain, nil}
+		return &Duration{c.chain.enter("MaxAge"), nil}
 	}
 	if c.value.MaxAge < 0 {
 		var zero time.Duration
-		return &Duration{c.chain, &zero}
+		return &Duration{c.chain.enter("MaxAge"), &zero}
 	}
 	d := time.Duration(c.value.MaxAge) * time.Second
-	return &Duration{c.chain, &d}
+	return &Duration{c.chain.enter("MaxAge"), &d}
 }
@@ -0,0 +1,96 @@
+package httpexpect
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CookieJar provides methods to inspect cookies accumulated in attached
+// http.CookieJar.
+//
+// Note that http.CookieJar returns only cookie names and values, so other
+// cookie attributes (domain, path, expiration, etc.) can't be inspected.
+type CookieJar struct {
+	chain chain
+	jar   http.CookieJar
+}
+
+// NewCookieJar returns a new CookieJar object given a reporter used to
+// report failures and cookie jar to be inspected.
+//
+// reporter and jar should not be nil.
+//
+// Example:
+//  jar := NewCookieJar(reporter, httpexpect.NewJar())
+//  jar.Cookies(u).Contains("session")
+func NewCookieJar(reporter Reporter, jar http.CookieJar) *CookieJar {
+	chain := makeChainFor("NewCookieJar", reporter)
+	if jar == nil {
+		chain.fail("expected non-nil cookie jar")
+	}
+	return &CookieJar{chain, jar}
+}
+
+// Raw returns underlying http.CookieJar value attached to CookieJar.
+// This is the value originally passed to NewCookieJar.
+//
+// Example:
+//  jar := NewCookieJar(t, j)
+//  assert.Equal(t, j, jar.Raw())
+func (j *CookieJar) Raw() http.CookieJar {
+	return j.jar
+}
+
+// Cookies returns a new Array object with names of all cookies that
+// the jar would send to given url.
+// Returned Array contains a String value for every cookie name.
+//
+// Example:
+//  jar := NewCookieJar(t, j)
+//  jar.Cookies(u).Contains("session")
+func (j *CookieJar) Cookies(u *url.URL) *Array {
+	defer j.chain.leave(j.chain.failed())
+	if j.chain.failed() {
+		return &Array{j.chain, nil}
+	}
+	if u == nil {
+		j.chain.failUsage("\nunexpected nil url in Cookies")
+		return &Array{j.chain, nil}
+	}
+	names := []interface{}{}
+	for _, c := range j.jar.Cookies(u) {
+		names = append(names, c.Name)
+	}
+	return &Array{j.chain, names}
+}
+
+// Cookie returns a new Cookie object that may be used to inspect given
+// cookie that the jar would send to given url.
+//
+// Only cookie name and value are available, see CookieJar.
+//
+// Example:
+//  jar := NewCookieJar(t, j)
+//  jar.Cookie(u, "session").Value().NotEmpty()
+func (j *CookieJar) Cookie(u *url.URL, name string) *Cookie {
+	defer j.chain.leave(j.chain.failed())
+	if j.chain.failed() {
+		return &Cookie{j.chain, nil}
+	}
+	if u == nil {
+		j.chain.failUsage("\nunexpected nil url in Cookie")
+		return &Cookie{j.chain, nil}
+	}
+	names := []string{}
+	for _, c := range j.jar.Cookies(u) {
+		if c.Name == name {
+			return &Cookie{j.chain.enter(fmt.Sprintf("Cookie[%q]", name)), c}
+		}
+		names = append(names, c.Name)
+	}
+	j.chain.fail(
+		"\nexpected cookie jar with cookie for url %q:\n %q\n\nbut got only cookies:\n%s",
+		u.String(), name, dumpValue(names))
+	return &Cookie{j.chain, nil}
+}
@@ -0,0 +1,64 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieJarFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	value := &CookieJar{chain, nil}
+
+	u, _ := url.Parse("http://example.com")
+
+	assert.True(t, value.Raw() == nil)
+	value.Cookies(u).chain.assertFailed(t)
+	value.Cookie(u, "foo").chain.assertFailed(t)
+}
+
+func TestCookieJarNil(t *testing.T) {
+	value := NewCookieJar(newMockReporter(t), nil)
+	value.chain.assertFailed(t)
+}
+
+func TestCookieJarCookies(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	jar := NewJar()
+
+	u1, _ := url.Parse("http://example.com/path")
+	u2, _ := url.Parse("http://other.com/path")
+
+	jar.SetCookies(u1, []*http.Cookie{
+		{Name: "session", Value: "abc", Path: "/"},
+		{Name: "lang", Value: "en", Path: "/"},
+	})
+
+	value := NewCookieJar(reporter, jar)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, jar, value.Raw())
+
+	value.Cookies(u1).ContainsOnly("session", "lang").chain.assertOK(t)
+	value.Cookies(u2).Empty().chain.assertOK(t)
+
+	value.Cookie(u1, "session").Value().Equal("abc").chain.assertOK(t)
+	value.Cookie(u1, "lang").Value().Equal("en").chain.assertOK(t)
+	value.chain.assertOK(t)
+
+	value.Cookie(u2, "session").chain.assertFailed(t)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Cookies(nil).chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Cookie(nil, "session").chain.assertFailed(t)
+	value.chain.reset()
+}
@@ -72,6 +72,12 @@ func TestCookieMaxAge(t *testing.T) {
 
 		value.MaxAge().NotSet().chain.assertOK(t)
 		value.MaxAge().IsSet().chain.assertFailed(t)
+
+		_, ok := value.MaxAge().Value()
+		require.False(t, ok)
+
+		require.Equal(t, time.Duration(0), value.MaxAge().Raw())
+		require.Equal(t, time.Hour, value.MaxAge().OrElse(time.Hour))
 	})
 
 	t.Run("zero", func(t *testing.T) {
@@ -86,6 +92,12 @@ func TestCookieMaxAge(t *testing.T) {
 
 		value.MaxAge().IsSet().chain.assertOK(t)
 		value.MaxAge().Equal(0).chain.assertOK(t)
+
+		d, ok := value.MaxAge().Value()
+		require.True(t, ok)
+
+		require.Equal(t, time.Duration(0), d)
+		require.Equal(t, time.Duration(0), value.MaxAge().OrElse(time.Hour))
 	})
 
 	t.Run("non-zero", func(t *testing.T) {
@@ -5,9 +5,12 @@ import (
 )
 
 // DateTime provides methods to inspect attached time.Time value.
+//
+// DateTime may be in "not set" state, e.g. when it's obtained from missing
+// response header. In this state, all checks except NotSet report failure.
 type DateTime struct {
 	chain chain
-	value time.Time
+	value *time.Time
 }
 
 // NewDateTime returns a new DateTime object given a reporter used to report
@@ -22,7 +25,12 @@ type DateTime struct {
 //   time.Sleep(time.Second)
 //   dt.Lt(time.Now())
 func NewDateTime(reporter Reporter, value time.Time) *DateTime {
-	return &DateTime{makeChain(reporter), value}
+	return &DateTime{makeChainFor("NewDateTime", reporter), &value}
+}
+
+func newDateTimeEpoch(chain chain) *DateTime {
+	epoch := time.Unix(0, 0)
+	return &DateTime{chain, &epoch}
 }
 
 // Raw returns underlying time.Time value attached to DateTime.
@@ -31,8 +39,70 @@ func NewDateTime(reporter Reporter, value time.Time) *DateTime {
 // Example:
 //  dt := NewDateTime(t, timestamp)
 //  assert.Equal(t, timestamp, dt.Raw())
+//
+// If DateTime is not set, zero time is returned.
 func (dt *DateTime) Raw() time.Time {
-	return dt.value
+	if dt.value == nil {
+		return time.Time{}
+	}
+	return *dt.value
+}
+
+// Value returns underlying time.Time value attached to DateTime and
+// a flag indicating whether DateTime is set.
+//
+// Unlike Raw, Value allows to distinguish unset DateTime from DateTime
+// set to zero time.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  if lastModified, ok := resp.LastModified().Value(); ok {
+//      assert.True(t, lastModified.Before(time.Now()))
+//  }
+func (dt *DateTime) Value() (time.Time, bool) {
+	if dt.value == nil {
+		return time.Time{}, false
+	}
+	return *dt.value, true
+}
+
+// OrElse returns underlying time.Time value attached to DateTime,
+// or def if DateTime is not set.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  expires := resp.Expires().OrElse(time.Now())
+func (dt *DateTime) OrElse(def time.Time) time.Time {
+	if dt.value == nil {
+		return def
+	}
+	return *dt.value
+}
+
+// IsSet succeeds if DateTime is set.
+//
+// Example:
+//  dt := NewDateTime(t, time.Now())
+//  dt.IsSet()
+func (dt *DateTime) IsSet() *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+	}
+	return dt
+}
+
+// NotSet succeeds if DateTime is not set.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Expires().NotSet()
+func (dt *DateTime) NotSet() *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value != nil {
+		dt.chain.fail("expected datetime is not set, but it is")
+	}
+	return dt
 }
 
 // Equal succeeds if DateTime is equal to given value.
@@ -41,9 +111,15 @@ func (dt *DateTime) Raw() time.Time {
 //  dt := NewDateTime(t, time.Unix(0, 1))
 //  dt.Equal(time.Unix(0, 1))
 func (dt *DateTime) Equal(value time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if !dt.value.Equal(value) {
-		dt.chain.fail("\nexpected datetime equal to:\n %s\n\nbut got:\n %s",
-			value, dt.value)
+		dt.chain.failExpected(value, *dt.value,
+			"\nexpected datetime equal to:\n %s\n\nbut got:\n %s",
+			value, *dt.value)
 	}
 	return dt
 }
@@ -54,6 +130,11 @@ func (dt *DateTime) Equal(value time.Time) *DateTime {
 //  dt := NewDateTime(t, time.Unix(0, 1))
 //  dt.NotEqual(time.Unix(0, 2))
 func (dt *DateTime) NotEqual(value time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if dt.value.Equal(value) {
 		dt.chain.fail("\nexpected datetime not equal to:\n %s", value)
 	}
@@ -66,9 +147,14 @@ func (dt *DateTime) NotEqual(value time.Time) *DateTime {
 //  dt := NewDateTime(t, time.Unix(0, 2))
 //  dt.Gt(time.Unix(0, 1))
 func (dt *DateTime) Gt(value time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if !dt.value.After(value) {
 		dt.chain.fail("\nexpected datetime > then:\n %s\n\nbut got:\n %s",
-			value, dt.value)
+			value, *dt.value)
 	}
 	return dt
 }
@@ -79,9 +165,14 @@ func (dt *DateTime) Gt(value time.Time) *DateTime {
 //  dt := NewDateTime(t, time.Unix(0, 2))
 //  dt.Ge(time.Unix(0, 1))
 func (dt *DateTime) Ge(value time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if !(dt.value.After(value) || dt.value.Equal(value)) {
 		dt.chain.fail("\nexpected datetime >= then:\n %s\n\nbut got:\n %s",
-			value, dt.value)
+			value, *dt.value)
 	}
 	return dt
 }
@@ -92,9 +183,14 @@ func (dt *DateTime) Ge(value time.Time) *DateTime {
 //  dt := NewDateTime(t, time.Unix(0, 1))
 //  dt.Lt(time.Unix(0, 2))
 func (dt *DateTime) Lt(value time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if !dt.value.Before(value) {
 		dt.chain.fail("\nexpected datetime < then:\n %s\n\nbut got:\n %s",
-			value, dt.value)
+			value, *dt.value)
 	}
 	return dt
 }
@@ -105,9 +201,14 @@ func (dt *DateTime) Lt(value time.Time) *DateTime {
 //  dt := NewDateTime(t, time.Unix(0, 1))
 //  dt.Le(time.Unix(0, 2))
 func (dt *DateTime) Le(value time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if !(dt.value.Before(value) || dt.value.Equal(value)) {
 		dt.chain.fail("\nexpected datetime <= then:\n %s\n\nbut got:\n %s",
-			value, dt.value)
+			value, *dt.value)
 	}
 	return dt
 }
@@ -119,11 +220,169 @@ func (dt *DateTime) Le(value time.Time) *DateTime {
 //  dt.InRange(time.Unix(0, 1), time.Unix(0, 3))
 //  dt.InRange(time.Unix(0, 2), time.Unix(0, 2))
 func (dt *DateTime) InRange(min, max time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
 	if !((dt.value.After(min) || dt.value.Equal(min)) &&
 		(dt.value.Before(max) || dt.value.Equal(max))) {
-		dt.chain.fail(
+		dt.chain.failRange(min, max, *dt.value,
 			"\nexpected datetime in range:\n min: %s\n max: %s\n\nbut got: %s",
-			min, max, dt.value)
+			min, max, *dt.value)
+	}
+	return dt
+}
+
+// InRangeExclusive succeeds if DateTime is in given range (min; max),
+// excluding min and max themselves.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 2))
+//  dt.InRangeExclusive(time.Unix(0, 1), time.Unix(0, 3))
+func (dt *DateTime) InRangeExclusive(min, max time.Time) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	return dt.InRangeOpts(min, max, Bounds{ExcludeMin: true, ExcludeMax: true})
+}
+
+// InRangeOpts succeeds if DateTime is in given range, with inclusivity of
+// min and max defined by bounds. If min is after max, failure is reported.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 2))
+//  dt.InRangeOpts(time.Unix(0, 1), time.Unix(0, 2),
+//      httpexpect.Bounds{ExcludeMin: true})
+func (dt *DateTime) InRangeOpts(min, max time.Time, bounds Bounds) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
+	if min.After(max) {
+		dt.chain.failUsage("\nunexpected range with min greater than max"+
+			" passed to InRangeOpts:\n %s",
+			bounds.format(min.String(), max.String()))
+		return dt
+	}
+	if !bounds.contains(compareTimes(*dt.value, min), compareTimes(*dt.value, max)) {
+		dt.chain.failRange(min, max, *dt.value,
+			"\nexpected datetime in range:\n %s\n\nbut got: %s",
+			bounds.format(min.String(), max.String()), *dt.value)
+	}
+	return dt
+}
+
+// IsRecent succeeds if DateTime is not after current time and not older
+// than current time minus window.
+//
+// Current time is obtained using Config.TimeNow, or time.Now if it's not
+// set. Failure message includes both instants and the delta.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.LastModified().IsRecent(5 * time.Second)
+func (dt *DateTime) IsRecent(window time.Duration) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
+	now := dt.chain.now()
+	delta := now.Sub(*dt.value)
+	if delta < 0 || delta > window {
+		dt.chain.fail("\nexpected datetime within %s before now:\n %s\n\n"+
+			"but got:\n %s\n\ndelta:\n %s",
+			window, now, *dt.value, delta)
+	}
+	return dt
+}
+
+// IsWithin succeeds if DateTime differs from ref by at most window,
+// in either direction.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(10, 0))
+//  dt.IsWithin(time.Unix(12, 0), 3*time.Second)
+func (dt *DateTime) IsWithin(ref time.Time, window time.Duration) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return dt
+	}
+	delta := dt.value.Sub(ref)
+	if delta > window || delta < -window {
+		dt.chain.fail("\nexpected datetime within %s of:\n %s\n\n"+
+			"but got:\n %s\n\ndelta:\n %s",
+			window, ref, *dt.value, delta)
+	}
+	return dt
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// AsFormat returns a new String object that may be used to inspect
+// DateTime formatted using given layout (see time.Time.Format).
+//
+// Time is formatted in its own location. Go can't detect invalid layouts,
+// so if layout is malformed, it's formatted literally and subsequent
+// checks will likely fail. Empty layout is reported as failure.
+//
+// Example:
+//  dt := NewDateTime(t, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC))
+//  dt.AsFormat("2006-01-02").Equal("2020-01-02")
+func (dt *DateTime) AsFormat(layout string) *String {
+	defer dt.chain.leave(dt.chain.failed())
+	s, ok := dt.format("AsFormat", layout)
+	if !ok {
+		return &String{dt.chain.enter("AsFormat"), ""}
+	}
+	return &String{dt.chain.enter("AsFormat"), s}
+}
+
+// EqualFormatted succeeds if DateTime formatted using given layout is
+// equal to expected string.
+//
+// This is useful when instants can't be compared directly, e.g. when
+// API truncates time to date-only or minute precision. See AsFormat
+// for details about formatting.
+//
+// Example:
+//  dt := NewDateTime(t, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC))
+//  dt.EqualFormatted("2006-01-02", "2020-01-02")
+//  dt.EqualFormatted("2006-01-02 15:04", "2020-01-02 15:04")
+func (dt *DateTime) EqualFormatted(layout string, expected string) *DateTime {
+	defer dt.chain.leave(dt.chain.failed())
+	s, ok := dt.format("EqualFormatted", layout)
+	if !ok {
+		return dt
+	}
+	if s != expected {
+		dt.chain.fail(
+			"\nexpected datetime formatted as %q equal to:\n %q\n\nbut got:\n %q",
+			layout, expected, s)
 	}
 	return dt
 }
+
+func (dt *DateTime) format(where, layout string) (string, bool) {
+	if dt.chain.failed() {
+		return "", false
+	}
+	if dt.value == nil {
+		dt.chain.fail("expected datetime is set, but it is not")
+		return "", false
+	}
+	if layout == "" {
+		dt.chain.failUsage("\nunexpected empty layout passed to %s", where)
+		return "", false
+	}
+	return dt.value.Format(layout), true
+}
@@ -1,6 +1,7 @@
 package httpexpect
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -14,7 +15,7 @@ func TestDateTimeFailed(t *testing.T) {
 
 	ts := time.Unix(0, 0)
 
-	value := &DateTime{chain, ts}
+	value := &DateTime{chain, &ts}
 
 	value.chain.assertFailed(t)
 
@@ -25,6 +26,77 @@ func TestDateTimeFailed(t *testing.T) {
 	value.Lt(ts)
 	value.Le(ts)
 	value.InRange(ts, ts)
+	value.IsRecent(time.Hour)
+	value.IsWithin(ts, time.Hour)
+	value.IsSet()
+	value.NotSet()
+}
+
+func TestDateTimeNotSet(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	ts := time.Unix(0, 0)
+
+	value := &DateTime{makeChain(reporter), nil}
+
+	assert.True(t, value.Raw().IsZero())
+
+	_, ok := value.Value()
+	assert.False(t, ok)
+
+	assert.Equal(t, ts, value.OrElse(ts))
+
+	value.NotSet()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsSet()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal(ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqual(ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Gt(ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Ge(ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Lt(ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Le(ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRange(ts, ts)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	set := NewDateTime(reporter, ts)
+
+	set.IsSet()
+	set.chain.assertOK(t)
+	set.chain.reset()
+
+	set.NotSet()
+	set.chain.assertFailed(t)
+	set.chain.reset()
+
+	raw, ok := set.Value()
+	assert.True(t, ok)
+	assert.Equal(t, ts, raw)
+
+	assert.Equal(t, ts, set.OrElse(time.Now()))
 }
 
 func TestDateTimeEqual(t *testing.T) {
@@ -132,3 +204,161 @@ func TestDateTimeInRange(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestDateTimeInRangeOpts(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Unix(0, 2))
+
+	value.InRangeExclusive(time.Unix(0, 1), time.Unix(0, 3))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeExclusive(time.Unix(0, 2), time.Unix(0, 3))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRangeOpts(time.Unix(0, 1), time.Unix(0, 2), Bounds{ExcludeMin: true})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeOpts(time.Unix(0, 1), time.Unix(0, 2), Bounds{ExcludeMax: true})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRangeOpts(time.Unix(0, 3), time.Unix(0, 1), Bounds{})
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "min greater than max")
+	value.chain.reset()
+}
+
+func TestDateTimeIsRecent(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	newValue := func(reporter Reporter, ts time.Time) *DateTime {
+		value := NewDateTime(reporter, ts)
+		value.chain.clock = func() time.Time {
+			return now
+		}
+		return value
+	}
+
+	reporter := newMockReporter(t)
+
+	newValue(reporter, now).IsRecent(5 * time.Second).chain.assertOK(t)
+	newValue(reporter, now.Add(-5*time.Second)).
+		IsRecent(5 * time.Second).chain.assertOK(t)
+
+	newValue(reporter, now.Add(-6*time.Second)).
+		IsRecent(5 * time.Second).chain.assertFailed(t)
+	assert.Contains(t, reporter.message, now.String())
+	assert.Contains(t, reporter.message, now.Add(-6*time.Second).String())
+	assert.Contains(t, reporter.message, "6s")
+
+	newValue(reporter, now.Add(time.Second)).
+		IsRecent(5 * time.Second).chain.assertFailed(t)
+
+	NewDateTime(reporter, time.Now()).IsRecent(time.Minute).chain.assertOK(t)
+
+	(&DateTime{makeChain(reporter), nil}).
+		IsRecent(time.Minute).chain.assertFailed(t)
+}
+
+func TestDateTimeIsRecentConfig(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       reporter,
+		TimeNow: func() time.Time {
+			return now
+		},
+	}
+
+	resp := NewRequest(config, "GET", "url").
+		WithHeader("Last-Modified", now.Add(-3*time.Second).Format(http.TimeFormat)).
+		Expect()
+
+	resp.LastModified().IsRecent(5 * time.Second).chain.assertOK(t)
+	resp.LastModified().IsRecent(2 * time.Second).chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "3s")
+}
+
+func TestDateTimeIsWithin(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Unix(10, 0))
+
+	value.IsWithin(time.Unix(12, 0), 2*time.Second)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsWithin(time.Unix(8, 0), 2*time.Second)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsWithin(time.Unix(13, 0), 2*time.Second)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "-3s")
+	value.chain.reset()
+
+	value.IsWithin(time.Unix(7, 0), 2*time.Second)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDateTimeAsFormat(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC))
+
+	value.AsFormat("2006-01-02").Equal("2020-01-02")
+	value.chain.assertOK(t)
+
+	value.AsFormat(time.RFC3339).Equal("2020-01-02T15:04:05Z")
+	value.chain.assertOK(t)
+
+	str := value.AsFormat("")
+	str.chain.assertFailed(t)
+	value.chain.assertFailed(t)
+
+	unset := &DateTime{makeChain(reporter), nil}
+
+	str = unset.AsFormat("2006-01-02")
+	str.chain.assertFailed(t)
+}
+
+func TestDateTimeEqualFormatted(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	morning := NewDateTime(reporter, time.Date(2020, 1, 2, 8, 0, 0, 0, time.UTC))
+	evening := time.Date(2020, 1, 2, 23, 59, 59, 0, time.UTC)
+
+	morning.Equal(evening)
+	morning.chain.assertFailed(t)
+	morning.chain.reset()
+
+	morning.EqualFormatted("2006-01-02", evening.Format("2006-01-02"))
+	morning.chain.assertOK(t)
+	morning.chain.reset()
+
+	morning.EqualFormatted("2006-01-02", "2020-01-03")
+	morning.chain.assertFailed(t)
+	morning.chain.reset()
+
+	morning.EqualFormatted("2006-01-02 15:04", "2020-01-02 08:00")
+	morning.chain.assertOK(t)
+	morning.chain.reset()
+
+	morning.EqualFormatted("", "2020-01-02")
+	morning.chain.assertFailed(t)
+	morning.chain.reset()
+
+	unset := &DateTime{makeChain(reporter), nil}
+
+	unset.EqualFormatted("2006-01-02", "2020-01-02")
+	unset.chain.assertFailed(t)
+}
@@ -0,0 +1,431 @@
+package httpexpect
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Decimal provides methods to inspect attached decimal number, e.g.
+// monetary amount, using exact arbitrary-precision comparison.
+//
+// Unlike String, Decimal treats "10.50" and "10.5" as equal values. Unlike
+// Number, Decimal doesn't lose precision, and remembers scale, i.e. the
+// number of digits after decimal point.
+type Decimal struct {
+	chain chain
+	value *big.Rat
+	text  string
+	scale int
+}
+
+// NewDecimal returns a new Decimal object given a reporter used to report
+// failures and a string to be parsed as decimal number.
+//
+// s should contain optional sign, digits with optional decimal point, and
+// optional exponent, e.g. "10.50", "-0.001", or "1.5e3". If parsing fails,
+// failure is reported.
+//
+// reporter should not be nil.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.Equal("10.5")
+func NewDecimal(reporter Reporter, s string) *Decimal {
+	chain := makeChainFor("NewDecimal", reporter)
+	return parseDecimal(&chain, s)
+}
+
+func parseDecimal(chain *chain, s string) *Decimal {
+	if chain.failed() {
+		return &Decimal{*chain, nil, s, 0}
+	}
+	value, scale, err := parseDecimalString(s)
+	if err != nil {
+		chain.fail(
+			"\nexpected string containing valid decimal:\n %q\n\nbut got error:\n %s",
+			s, err.Error())
+		return &Decimal{*chain, nil, s, 0}
+	}
+	return &Decimal{*chain, value, s, scale}
+}
+
+// Raw returns a copy of underlying value attached to Decimal, or zero if
+// parsing failed.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  assert.Equal(t, big.NewRat(21, 2), d.Raw())
+func (d *Decimal) Raw() *big.Rat {
+	if d.value == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(d.value)
+}
+
+// Scale returns number of digits after decimal point, as written in the
+// original string, or zero if parsing failed.
+//
+// Exponent is taken into account, e.g. scale of "1.25e1" is 1.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  assert.Equal(t, 2, d.Scale())
+func (d *Decimal) Scale() int {
+	return d.scale
+}
+
+// HasScale succeeds if decimal has exactly given number of digits after
+// decimal point, as written in the original string.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.HasScale(2)
+func (d *Decimal) HasScale(scale int) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	if d.chain.failed() {
+		return d
+	}
+	if d.scale != scale {
+		d.chain.fail("\nexpected decimal with scale:\n %d\n\nbut got decimal"+
+			" with scale %d:\n %s", scale, d.scale, d.text)
+	}
+	return d
+}
+
+// Equal succeeds if decimal is equal to given value.
+//
+// value may be a string with decimal number, a value of numeric type, or
+// *big.Rat. Comparison ignores scale, i.e. "10.50" is equal to "10.5".
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.Equal("10.5")
+//  d.Equal(10.5)
+func (d *Decimal) Equal(value interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	v, text, ok := d.arg(value)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(v) == 0) {
+		d.chain.fail("\nexpected decimal equal to:\n %s\n\nbut got:\n %s",
+			text, d.text)
+	}
+	return d
+}
+
+// NotEqual succeeds if decimal is not equal to given value.
+//
+// See Equal for accepted value types.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.NotEqual("10.51")
+func (d *Decimal) NotEqual(value interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	v, text, ok := d.arg(value)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(v) != 0) {
+		d.chain.fail("\nexpected decimal not equal to:\n %s\n\nbut got:\n %s",
+			text, d.text)
+	}
+	return d
+}
+
+// Gt succeeds if decimal is greater than given value.
+//
+// See Equal for accepted value types.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.Gt("10.49")
+func (d *Decimal) Gt(value interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	v, text, ok := d.arg(value)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(v) > 0) {
+		d.chain.fail("\nexpected decimal > then:\n %s\n\nbut got:\n %s",
+			text, d.text)
+	}
+	return d
+}
+
+// Ge succeeds if decimal is greater than or equal to given value.
+//
+// See Equal for accepted value types.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.Ge("10.5")
+func (d *Decimal) Ge(value interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	v, text, ok := d.arg(value)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(v) >= 0) {
+		d.chain.fail("\nexpected decimal >= then:\n %s\n\nbut got:\n %s",
+			text, d.text)
+	}
+	return d
+}
+
+// Lt succeeds if decimal is lesser than given value.
+//
+// See Equal for accepted value types.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.Lt("10.51")
+func (d *Decimal) Lt(value interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	v, text, ok := d.arg(value)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(v) < 0) {
+		d.chain.fail("\nexpected decimal < then:\n %s\n\nbut got:\n %s",
+			text, d.text)
+	}
+	return d
+}
+
+// Le succeeds if decimal is lesser than or equal to given value.
+//
+// See Equal for accepted value types.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.Le("10.5")
+func (d *Decimal) Le(value interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	v, text, ok := d.arg(value)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(v) <= 0) {
+		d.chain.fail("\nexpected decimal <= then:\n %s\n\nbut got:\n %s",
+			text, d.text)
+	}
+	return d
+}
+
+// InRange succeeds if decimal is in given range [min; max].
+//
+// See Equal for accepted value types.
+//
+// Example:
+//  d := NewDecimal(t, "10.50")
+//  d.InRange("10", "11")
+//  d.InRange("10.5", 10.5)
+func (d *Decimal) InRange(min, max interface{}) *Decimal {
+	defer d.chain.leave(d.chain.failed())
+	a, aText, ok := d.arg(min)
+	if !ok {
+		return d
+	}
+	b, bText, ok := d.arg(max)
+	if !ok {
+		return d
+	}
+	if !(d.value.Cmp(a) >= 0 && d.value.Cmp(b) <= 0) {
+		d.chain.fail("\nexpected decimal in range:\n [%s; %s]\n\nbut got:\n %s",
+			aText, bText, d.text)
+	}
+	return d
+}
+
+// arg converts argument of comparison method to big.Rat and returns it
+// along with its textual representation for failure messages.
+func (d *Decimal) arg(value interface{}) (*big.Rat, string, bool) {
+	if d.chain.failed() {
+		return nil, "", false
+	}
+	v, text, err := decimalValue(value)
+	if err != nil {
+		d.chain.failUsage("\nunexpected decimal argument:\n %s", err.Error())
+		return nil, "", false
+	}
+	return v, text, true
+}
+
+func decimalValue(value interface{}) (*big.Rat, string, error) {
+	switch v := value.(type) {
+	case string:
+		r, _, err := parseDecimalString(v)
+		return r, v, err
+
+	case *big.Rat:
+		if v == nil {
+			return nil, "", fmt.Errorf("nil *big.Rat")
+		}
+		return v, v.RatString(), nil
+
+	case int, int8, int16, int32, int64:
+		n := decimalInt(v)
+		return new(big.Rat).SetInt64(n), strconv.FormatInt(n, 10), nil
+
+	case uint, uint8, uint16, uint32, uint64:
+		n := decimalUint(v)
+		r := new(big.Rat).SetInt(new(big.Int).SetUint64(n))
+		return r, strconv.FormatUint(n, 10), nil
+
+	case float32:
+		return floatDecimal(float64(v), 32)
+
+	case float64:
+		return floatDecimal(v, 64)
+
+	default:
+		return nil, "", fmt.Errorf(
+			"value of type %T is neither string, number, nor *big.Rat", value)
+	}
+}
+
+// floatDecimal converts float to decimal using its shortest representation,
+// so that e.g. 10.5 is converted to exactly 10.5.
+func floatDecimal(f float64, bits int) (*big.Rat, string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, "", fmt.Errorf("non-finite number %s", formatFloat(f, bits, 0))
+	}
+	text := strconv.FormatFloat(f, 'f', -1, bits)
+	r, _, err := parseDecimalString(text)
+	return r, text, err
+}
+
+func decimalInt(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	default:
+		return n.(int64)
+	}
+}
+
+func decimalUint(v interface{}) uint64 {
+	switch n := v.(type) {
+	case uint:
+		return uint64(n)
+	case uint8:
+		return uint64(n)
+	case uint16:
+		return uint64(n)
+	case uint32:
+		return uint64(n)
+	default:
+		return n.(uint64)
+	}
+}
+
+// maxDecimalExponent limits exponent accepted by parseDecimalString, so that
+// strings like "1e1000000000" don't exhaust memory.
+const maxDecimalExponent = 10000
+
+// parseDecimalString parses string like "-10.50" or "1.5e3" and returns
+// its value and scale. Errors include position of offending character.
+func parseDecimalString(s string) (*big.Rat, int, error) {
+	pos := 0
+
+	fail := func(what string) (*big.Rat, int, error) {
+		if pos >= len(s) {
+			return nil, 0, fmt.Errorf("%s at position %d (end of string)", what, pos)
+		}
+		return nil, 0, fmt.Errorf("%s at position %d: %q", what, pos, s[pos])
+	}
+
+	if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+		pos++
+	}
+
+	intStart := pos
+	for pos < len(s) && isDigit(s[pos]) {
+		pos++
+	}
+	intDigits := s[intStart:pos]
+
+	var fracDigits string
+	if pos < len(s) && s[pos] == '.' {
+		pos++
+		fracStart := pos
+		for pos < len(s) && isDigit(s[pos]) {
+			pos++
+		}
+		fracDigits = s[fracStart:pos]
+	}
+
+	if intDigits == "" && fracDigits == "" {
+		return fail("expected digit")
+	}
+
+	exp := 0
+	if pos < len(s) && (s[pos] == 'e' || s[pos] == 'E') {
+		pos++
+		neg := false
+		if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+			neg = s[pos] == '-'
+			pos++
+		}
+		expStart := pos
+		for pos < len(s) && isDigit(s[pos]) {
+			if exp = exp*10 + int(s[pos]-'0'); exp > maxDecimalExponent {
+				return fail("exponent out of range")
+			}
+			pos++
+		}
+		if pos == expStart {
+			return fail("expected exponent digit")
+		}
+		if neg {
+			exp = -exp
+		}
+	}
+
+	if pos != len(s) {
+		return fail("unexpected character")
+	}
+
+	digits := intDigits + fracDigits
+	if digits == "" {
+		digits = "0"
+	}
+	num, _ := new(big.Int).SetString(digits, 10)
+	if s[0] == '-' {
+		num.Neg(num)
+	}
+
+	shift := len(fracDigits) - exp
+
+	value := new(big.Rat)
+	if shift >= 0 {
+		value.SetFrac(num, pow10(shift))
+	} else {
+		value.SetInt(num.Mul(num, pow10(-shift)))
+	}
+
+	scale := shift
+	if scale < 0 {
+		scale = 0
+	}
+
+	return value, scale, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,276 @@
+package httpexpect
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	value := parseDecimal(&chain, "1")
+
+	value.chain.assertFailed(t)
+
+	value.HasScale(0)
+	value.Equal("1")
+	value.NotEqual("1")
+	value.Gt("1")
+	value.Ge("1")
+	value.Lt("1")
+	value.Le("1")
+	value.InRange("1", "1")
+
+	assert.Equal(t, new(big.Rat), value.Raw())
+}
+
+func TestDecimalParse(t *testing.T) {
+	cases := []struct {
+		s     string
+		value *big.Rat
+		scale int
+	}{
+		{"0", big.NewRat(0, 1), 0},
+		{"10", big.NewRat(10, 1), 0},
+		{"10.50", big.NewRat(21, 2), 2},
+		{"-10.5", big.NewRat(-21, 2), 1},
+		{"+0.001", big.NewRat(1, 1000), 3},
+		{".5", big.NewRat(1, 2), 1},
+		{"5.", big.NewRat(5, 1), 0},
+		{"1.25e1", big.NewRat(25, 2), 1},
+		{"1.5E3", big.NewRat(1500, 1), 0},
+		{"15e-1", big.NewRat(3, 2), 1},
+		{"100000000000000000000.01",
+			new(big.Rat).SetFrac(
+				new(big.Int).Add(
+					new(big.Int).Mul(big.NewInt(1e10), big.NewInt(1e12)),
+					big.NewInt(1)),
+				big.NewInt(100)), 2},
+	}
+
+	for _, tc := range cases {
+		reporter := newMockReporter(t)
+
+		value := NewDecimal(reporter, tc.s)
+		value.chain.assertOK(t)
+
+		assert.Equal(t, 0, tc.value.Cmp(value.Raw()), tc.s)
+		assert.Equal(t, tc.scale, value.Scale(), tc.s)
+	}
+}
+
+func TestDecimalParseError(t *testing.T) {
+	cases := []struct {
+		s   string
+		err string
+	}{
+		{"", "expected digit at position 0 (end of string)"},
+		{"-", "expected digit at position 1 (end of string)"},
+		{".", "expected digit at position 1 (end of string)"},
+		{"10,50", "unexpected character at position 2: ','"},
+		{"10.5x", "unexpected character at position 4: 'x'"},
+		{" 10", "expected digit at position 0: ' '"},
+		{"1e", "expected exponent digit at position 2 (end of string)"},
+		{"1e+x", "expected exponent digit at position 3: 'x'"},
+		{"1e100000", "exponent out of range at position 7: '0'"},
+	}
+
+	for _, tc := range cases {
+		reporter := newMockReporter(t)
+
+		value := NewDecimal(reporter, tc.s)
+		value.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, tc.err, tc.s)
+	}
+}
+
+func TestDecimalEqual(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDecimal(reporter, "10.50")
+
+	value.Equal("10.5")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal("10.500")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal(10.5)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal(float32(10.5))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal(big.NewRat(21, 2))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal("10.51")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal(10)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqual("10.51")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotEqual("10.5")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal("abc")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal(math.NaN())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal(true)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal((*big.Rat)(nil))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDecimalPrecision(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDecimal(reporter, "0.30000000000000000001")
+
+	value.Gt("0.3")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal(0.3)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value = NewDecimal(reporter, "18446744073709551616")
+
+	value.Gt(uint64(math.MaxUint64))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Gt(int64(math.MaxInt64))
+	value.chain.assertOK(t)
+	value.chain.reset()
+}
+
+func TestDecimalCompare(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDecimal(reporter, "10.50")
+
+	value.Gt("10.49")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Gt("10.5")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Ge("10.5")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Ge("10.51")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Lt("10.51")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Lt(10.5)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Le(10.5)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Le("10.49")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRange("10", 11)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRange("10.5", "10.5")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRange("10.51", "11")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRange("x", "11")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDecimalHasScale(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDecimal(reporter, "10.50")
+
+	value.HasScale(2)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasScale(1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewDecimal(reporter, "10").HasScale(0).chain.assertOK(t)
+	NewDecimal(reporter, "10.5").HasScale(2).chain.assertFailed(t)
+}
+
+func TestDecimalFromString(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewString(reporter, "10.50").AsDecimal().
+		Equal("10.5").NotEqual("10.51").HasScale(2).
+		chain.assertOK(t)
+
+	value := NewString(reporter, "10.5O").AsDecimal()
+	value.chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, "unexpected character at position 4: 'O'")
+}
+
+func TestDecimalFromNumber(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewNumber(reporter, 10.5).AsDecimal().
+		Equal("10.50").HasScale(1).
+		chain.assertOK(t)
+
+	NewNumber(reporter, 0.1).AsDecimal().
+		Equal("0.1").
+		chain.assertOK(t)
+
+	NewNumber(reporter, 1e21).AsDecimal().
+		Equal("1000000000000000000000").HasScale(0).
+		chain.assertOK(t)
+
+	NewNumber(reporter, math.Inf(1)).AsDecimal().
+		chain.assertFailed(t)
+}
@@ -19,7 +19,38 @@ type Duration struct {
 //   d := NewDuration(reporter, time.Second)
 //   d.Le(time.Minute)
 func NewDuration(reporter Reporter, value time.Duration) *Duration {
-	return &Duration{makeChain(reporter), &value}
+	return &Duration{makeChainFor("NewDuration", reporter), &value}
+}
+
+// NewDurationFromString returns a new Duration object given a reporter used
+// to report failures and a string to be parsed as time.Duration.
+//
+// s should use Go duration syntax accepted by time.ParseDuration, e.g.
+// "250ms" or "2h45m". If parsing fails, failure is reported and returned
+// Duration is not set.
+//
+// reporter should not be nil.
+//
+// Example:
+//   d := NewDurationFromString(reporter, "1.5s")
+//   d.Equal(1500 * time.Millisecond)
+func NewDurationFromString(reporter Reporter, s string) *Duration {
+	chain := makeChainFor("NewDurationFromString", reporter)
+	return parseDuration(&chain, s)
+}
+
+func parseDuration(chain *chain, s string) *Duration {
+	if chain.failed() {
+		return &Duration{*chain, nil}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		chain.fail(
+			"\nexpected string containing valid duration:\n %q\n\nbut got error:\n %s",
+			s, err.Error())
+		return &Duration{*chain, nil}
+	}
+	return &Duration{*chain, &d}
 }
 
 // Raw returns underlying time.Duration value attached to Duration.
@@ -35,12 +66,44 @@ func (d *Duration) Raw() time.Duration {
 	return *d.value
 }
 
+// Value returns underlying time.Duration value attached to Duration and
+// a flag indicating whether Duration is set.
+//
+// Unlike Raw, Value allows to distinguish unset Duration from Duration
+// set to zero.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  if d, ok := cookie.MaxAge().Value(); ok {
+//      assert.Equal(t, time.Duration(0), d)
+//  }
+func (d *Duration) Value() (time.Duration, bool) {
+	if d.value == nil {
+		return 0, false
+	}
+	return *d.value, true
+}
+
+// OrElse returns underlying time.Duration value attached to Duration,
+// or def if Duration is not set.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  maxAge := cookie.MaxAge().OrElse(time.Hour)
+func (d *Duration) OrElse(def time.Duration) time.Duration {
+	if d.value == nil {
+		return def
+	}
+	return *d.value
+}
+
 // IsSet succeeds if Duration is set.
 //
 // Example:
 //  d := NewDuration(t, time.Second)
 //  d.IsSet()
 func (d *Duration) IsSet() *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 	}
@@ -49,6 +112,7 @@ func (d *Duration) IsSet() *Duration {
 
 // NotSet succeeds if Duration is not set.
 func (d *Duration) NotSet() *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value != nil {
 		d.chain.fail("expected duration is not set, but it is")
 	}
@@ -61,12 +125,14 @@ func (d *Duration) NotSet() *Duration {
 //  d := NewDuration(t, time.Second)
 //  d.Equal(time.Second)
 func (d *Duration) Equal(value time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
 	}
 	if !(*d.value == value) {
-		d.chain.fail("\nexpected duration equal to:\n %s\n\nbut got:\n %s",
+		d.chain.failExpected(value.String(), d.value.String(),
+			"\nexpected duration equal to:\n %s\n\nbut got:\n %s",
 			value, *d.value)
 	}
 	return d
@@ -78,6 +144,7 @@ func (d *Duration) Equal(value time.Duration) *Duration {
 //  d := NewDuration(t, time.Second)
 //  d.NotEqual(time.Minute)
 func (d *Duration) NotEqual(value time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
@@ -94,6 +161,7 @@ func (d *Duration) NotEqual(value time.Duration) *Duration {
 //  d := NewDuration(t, time.Minute)
 //  d.Gt(time.Second)
 func (d *Duration) Gt(value time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
@@ -111,6 +179,7 @@ func (d *Duration) Gt(value time.Duration) *Duration {
 //  d := NewDuration(t, time.Minute)
 //  d.Ge(time.Second)
 func (d *Duration) Ge(value time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
@@ -128,6 +197,7 @@ func (d *Duration) Ge(value time.Duration) *Duration {
 //  d := NewDuration(t, time.Second)
 //  d.Lt(time.Minute)
 func (d *Duration) Lt(value time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
@@ -145,6 +215,7 @@ func (d *Duration) Lt(value time.Duration) *Duration {
 //  d := NewDuration(t, time.Second)
 //  d.Le(time.Minute)
 func (d *Duration) Le(value time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
@@ -163,14 +234,64 @@ func (d *Duration) Le(value time.Duration) *Duration {
 //  d.InRange(time.Second, time.Hour)
 //  d.InRange(time.Minute, time.Minute)
 func (d *Duration) InRange(min, max time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
 	if d.value == nil {
 		d.chain.fail("expected duration is set, but it is not")
 		return d
 	}
 	if !(*d.value >= min && *d.value <= max) {
-		d.chain.fail(
+		d.chain.failRange(min.String(), max.String(), d.value.String(),
 			"\nexpected duration in range:\n min: %s\n max: %s\n\nbut got: %s",
 			min, max, *d.value)
 	}
 	return d
 }
+
+// InRangeExclusive succeeds if Duration is in given range (min; max),
+// excluding min and max themselves.
+//
+// Example:
+//  d := NewDuration(t, time.Minute)
+//  d.InRangeExclusive(time.Second, time.Hour)
+func (d *Duration) InRangeExclusive(min, max time.Duration) *Duration {
+	defer d.chain.leave(d.chain.failed())
+	return d.InRangeOpts(min, max, Bounds{ExcludeMin: true, ExcludeMax: true})
+}
+
+// InRangeOpts succeeds if Duration is in given range, with inclusivity of
+// min and max defined by bounds. If min is greater than max, failure is
+// reported.
+//
+// Example:
+//  d := NewDuration(t, time.Minute)
+//  d.InRangeOpts(0, time.Minute, httpexpect.Bounds{ExcludeMin: true})
+func (d *Duration) InRangeOpts(min, max time.Duration, bounds Bounds) *Duration {
+	defer d.chain.leave(d.chain.failed())
+	if d.value == nil {
+		d.chain.fail("expected duration is set, but it is not")
+		return d
+	}
+	if min > max {
+		d.chain.failUsage("\nunexpected range with min greater than max"+
+			" passed to InRangeOpts:\n %s",
+			bounds.format(min.String(), max.String()))
+		return d
+	}
+	if !bounds.contains(compareDurations(*d.value, min),
+		compareDurations(*d.value, max)) {
+		d.chain.failRange(min.String(), max.String(), d.value.String(),
+			"\nexpected duration in range:\n %s\n\nbut got: %s",
+			bounds.format(min.String(), max.String()), *d.value)
+	}
+	return d
+}
+
+func compareDurations(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
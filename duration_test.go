@@ -59,6 +59,12 @@ func TestDurationSet(t *testing.T) {
 	value.NotSet()
 	value.chain.assertFailed(t)
 	value.chain.reset()
+
+	d, ok := value.Value()
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+
+	assert.Equal(t, time.Second, value.OrElse(time.Minute))
 }
 
 func TestDurationUnset(t *testing.T) {
@@ -73,6 +79,13 @@ func TestDurationUnset(t *testing.T) {
 	value.NotSet()
 	value.chain.assertOK(t)
 	value.chain.reset()
+
+	d, ok := value.Value()
+	assert.False(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	assert.Equal(t, time.Duration(0), value.Raw())
+	assert.Equal(t, time.Minute, value.OrElse(time.Minute))
 }
 
 func TestDurationEqual(t *testing.T) {
@@ -180,3 +193,54 @@ func TestDurationInRange(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestDurationInRangeOpts(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDuration(reporter, time.Second)
+
+	value.InRangeExclusive(0, time.Minute)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeExclusive(time.Second, time.Minute)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "(1s, 1m0s)")
+	value.chain.reset()
+
+	value.InRangeOpts(0, time.Second, Bounds{ExcludeMin: true})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeOpts(0, time.Second, Bounds{ExcludeMax: true})
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "[0s, 1s)")
+	value.chain.reset()
+
+	value.InRangeOpts(time.Minute, 0, Bounds{})
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "min greater than max")
+	value.chain.reset()
+
+	unset := &Duration{makeChain(reporter), nil}
+	unset.InRangeExclusive(0, time.Minute)
+	unset.chain.assertFailed(t)
+}
+
+func TestDurationFromString(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewDurationFromString(reporter, "1.5s")
+	value1.chain.assertOK(t)
+	value1.IsSet()
+	value1.Equal(1500 * time.Millisecond)
+	value1.chain.assertOK(t)
+
+	value2 := NewDurationFromString(reporter, "")
+	value2.chain.assertFailed(t)
+	assert.Equal(t, time.Duration(0), value2.Raw())
+
+	value3 := NewDurationFromString(reporter, "10 parsecs")
+	value3.chain.assertFailed(t)
+	assert.Equal(t, time.Duration(0), value3.Raw())
+}
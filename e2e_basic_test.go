@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 )
 
@@ -58,7 +60,7 @@ func createBasicHandler() http.Handler {
 }
 
 func testBasicHandler(e *Expect) {
-	e.GET("/foo")
+	e.GET("/foo").Discard()
 	e.GET("/foo").Expect()
 	e.GET("/foo").Expect().Status(http.StatusOK)
 
@@ -91,6 +93,165 @@ func testBasicHandler(e *Expect) {
 		Form().ValueEqual("username", "john").ValueEqual("password", "secret")
 }
 
+func createMethodsHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodOptions:
+			w.Header().Set("Allow", "GET, HEAD, OPTIONS, PURGE")
+			w.WriteHeader(http.StatusNoContent)
+
+		case http.MethodHead:
+			w.Header().Set("Content-Length", "11")
+			w.WriteHeader(http.StatusOK)
+
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`hello world`))
+
+		case "PURGE":
+			w.WriteHeader(http.StatusAccepted)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	return mux
+}
+
+func testMethodsHandler(e *Expect) {
+	e.OPTIONS("/resource").
+		Expect().
+		Status(http.StatusNoContent).
+		Header("Allow").Equal("GET, HEAD, OPTIONS, PURGE")
+
+	resp := e.HEAD("/resource").
+		Expect().
+		Status(http.StatusOK)
+
+	resp.Header("Content-Length").Equal("11")
+	resp.Body().Empty()
+	resp.NoContent()
+
+	e.GET("/resource").
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("hello world")
+
+	e.Request("PURGE", "/resource").
+		Expect().
+		Status(http.StatusAccepted)
+
+	e.TRACE("/resource").
+		Expect().
+		Status(http.StatusMethodNotAllowed)
+}
+
+func TestE2EBasicMethodsLive(t *testing.T) {
+	server := httptest.NewServer(createMethodsHandler())
+	defer server.Close()
+
+	testMethodsHandler(New(t, server.URL))
+}
+
+func TestE2EBasicMethodsBinder(t *testing.T) {
+	testMethodsHandler(WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: NewBinder(createMethodsHandler()),
+		},
+	}))
+}
+
+func createNegotiationHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+			mediaType := strings.TrimSpace(strings.Split(part, ";")[0])
+
+			switch mediaType {
+			case "application/json":
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"foo":123}`))
+				return
+
+			case "application/xml":
+				w.Header().Set("Content-Type", "application/xml")
+				_, _ = w.Write([]byte(`<foo>123</foo>`))
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotAcceptable)
+	})
+
+	return mux
+}
+
+func TestE2EBasicNegotiation(t *testing.T) {
+	server := httptest.NewServer(createNegotiationHandler())
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	e.GET("/data").WithAccept("application/json", "application/xml").
+		Expect().
+		Status(http.StatusOK).
+		ContentType("application/json").
+		VaryContains("accept")
+
+	e.GET("/data").WithAccept("application/xml", "application/json").
+		Expect().
+		Status(http.StatusOK).
+		ContentType("application/xml").
+		Vary("Accept", "Accept-Encoding")
+
+	e.GET("/data").WithAccept("text/html").
+		Expect().
+		Status(http.StatusNotAcceptable).
+		VaryContains("Accept")
+}
+
+func TestE2EBasicRequestID(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("X-Request-Id")))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	logger := &mockLogger{}
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:         server.URL,
+		Reporter:        reporter,
+		Printers:        []Printer{NewCompactPrinter(logger)},
+		RequestIDFunc:   NewRequestID,
+		RequestIDHeader: "X-Request-Id",
+	})
+
+	resp := e.GET("/").Expect()
+	resp.chain.assertOK(t)
+
+	id := resp.Body().Raw()
+	if id == "" {
+		t.Fatal("expected non-empty request id")
+	}
+
+	assert.Equal(t, []string{"[" + id + "] GET " + server.URL + "/"}, logger.logs)
+
+	resp.Status(http.StatusTeapot)
+	assert.Contains(t, reporter.message, id)
+}
+
 func TestE2EBasicLiveDefault(t *testing.T) {
 	handler := createBasicHandler()
 
@@ -0,0 +1,105 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createCacheHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	modified := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	mux.HandleFunc("/etag", func(w http.ResponseWriter, r *http.Request) {
+		const etag = `W/"v1"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "private, max-age=0, must-revalidate")
+		for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+			if strings.TrimSpace(tag) == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{"version":1}`))
+	})
+
+	mux.HandleFunc("/modified", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil &&
+			!modified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`hello`))
+	})
+
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`hello`))
+	})
+
+	return mux
+}
+
+func TestE2ECacheRevalidateETag(t *testing.T) {
+	server := httptest.NewServer(createCacheHandler())
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	resp := e.GET("/etag").Expect().Status(http.StatusOK)
+	resp.ETag().IsWeak().Value().Equal("v1")
+	resp.CacheControl().Private().MaxAge().Equal(0)
+
+	e.Revalidate(resp, "GET", "/etag").
+		Expect().
+		Status(http.StatusNotModified).
+		Body().Empty()
+
+	e.GET("/etag").WithIfNoneMatch(`W/"v0"`, `W/"v1"`).
+		Expect().
+		Status(http.StatusNotModified)
+
+	e.GET("/etag").WithIfNoneMatch("v0").
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestE2ECacheRevalidateLastModified(t *testing.T) {
+	server := httptest.NewServer(createCacheHandler())
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	resp := e.GET("/modified").Expect().Status(http.StatusOK)
+
+	e.Revalidate(resp, "GET", "/modified").
+		Expect().
+		Status(http.StatusNotModified)
+
+	modified := resp.LastModified().Raw()
+
+	e.GET("/modified").WithIfModifiedSince(modified.Add(-time.Second)).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestE2ECacheRevalidateNoValidators(t *testing.T) {
+	server := httptest.NewServer(createCacheHandler())
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	resp := e.GET("/plain").Expect().Status(http.StatusOK)
+
+	reporter := newMockReporter(t)
+
+	req := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	}).Revalidate(resp, "GET", "/plain")
+
+	req.chain.assertFailed(t)
+}
@@ -0,0 +1,85 @@
+package httpexpect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func createCompressHandler(payload string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/gzip", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			_, _ = w.Write([]byte(payload))
+			return
+		}
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(payload))
+		_ = zw.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		_, _ = w.Write(buf.Bytes())
+	})
+
+	return mux
+}
+
+func TestE2ECompressBodySize(t *testing.T) {
+	payload := strings.Repeat("hello, world! ", 1000)
+
+	server := httptest.NewServer(createCompressHandler(payload))
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	t.Run("compressed", func(t *testing.T) {
+		resp := e.GET("/gzip").
+			WithHeader("Accept-Encoding", "gzip").
+			Expect().
+			Status(http.StatusOK).
+			ContentEncoding("gzip")
+
+		compressed := resp.BodySize()
+		compressed.Gt(0).Lt(len(payload))
+		compressed.Equal(len(resp.RawBytes()))
+
+		resp.UncompressedBodySize().Equal(len(payload))
+		resp.UncompressedBodySize().Gt(compressed.Raw())
+	})
+
+	t.Run("decompressed by transport", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		resp := e.GET("/gzip").Expect()
+		resp.chain.assertOK(t)
+
+		size := resp.UncompressedBodySize()
+		size.chain.assertOK(t)
+		size.Equal(len(payload))
+
+		resp.BodySize().chain.assertFailed(t)
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		resp := e.GET("/gzip").
+			WithHeader("Accept-Encoding", "identity").
+			Expect().
+			Status(http.StatusOK)
+
+		resp.BodySize().Equal(len(payload))
+		resp.UncompressedBodySize().Equal(len(payload))
+	})
+}
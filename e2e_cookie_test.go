@@ -3,6 +3,7 @@ package httpexpect
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -144,3 +145,64 @@ func TestE2ECookieBinderFastEnabled(t *testing.T) {
 
 	testCookieHandler(e, true)
 }
+
+func createSessionHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("password") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:  "session",
+			Value: "token-" + r.PostFormValue("user"),
+			Path:  "/",
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(cookie.Value))
+	})
+
+	return mux
+}
+
+func TestE2ECookieJarSession(t *testing.T) {
+	handler := createSessionHandler()
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client:   &http.Client{},
+	}).WithCookieJar()
+
+	e.GET("/profile").Expect().Status(http.StatusUnauthorized)
+
+	e.CookieJar().Cookies(u).Empty()
+
+	e.POST("/login").
+		WithFormField("user", "ford").
+		WithFormField("password", "secret").
+		Expect().
+		Status(http.StatusNoContent)
+
+	e.CookieJar().Cookies(u).ContainsOnly("session")
+	e.CookieJar().Cookie(u, "session").Value().Equal("token-ford")
+
+	e.GET("/profile").Expect().
+		Status(http.StatusOK).
+		Text().Equal("token-ford")
+}
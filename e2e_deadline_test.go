@@ -0,0 +1,68 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestE2EDeadlineScenario(t *testing.T) {
+	var count int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	}).WithTimeout(100 * time.Millisecond)
+
+	start := time.Now()
+
+	e.GET("/slow").Expect().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "scenario deadline exceeded")
+
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	reporter.reported = false
+	reporter.message = ""
+
+	e.GET("/slow").Expect().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "scenario deadline exceeded")
+	assert.Contains(t, reporter.message, "elapsed")
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestE2EDeadlineNotExceeded(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	}).WithTimeout(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		e.GET("/").Expect().
+			Status(http.StatusNoContent).chain.assertOK(t)
+	}
+}
@@ -0,0 +1,94 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestE2EErrorExpectError(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(time.Millisecond * 200)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	reporter := newMockReporter(t)
+
+	t.Run("connection refused", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		server.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		resp := e.GET("/").ExpectError(ErrorConnectionRefused)
+		resp.chain.assertOK(t)
+		assert.Error(t, resp.Error())
+		assert.Equal(t, ErrorConnectionRefused, resp.ErrorClass())
+
+		resp = e.GET("/").ExpectError(ErrorTLS, ErrorTimeout)
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "of class ConnectionRefused")
+
+		resp = e.GET("/").Expect()
+		resp.chain.assertFailed(t)
+		assert.Error(t, resp.Error())
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		server := httptest.NewTLSServer(handler)
+		defer server.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Client:   &http.Client{Transport: &http.Transport{}},
+		})
+
+		resp := e.GET("/").ExpectError(ErrorTLS)
+		resp.chain.assertOK(t)
+		assert.Equal(t, ErrorTLS, resp.ErrorClass())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Client:   &http.Client{Timeout: time.Millisecond * 50},
+		})
+
+		resp := e.GET("/slow").ExpectError()
+		resp.chain.assertOK(t)
+		assert.Equal(t, ErrorTimeout, resp.ErrorClass())
+	})
+
+	t.Run("response received", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		resp := e.GET("/").ExpectError()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "200 OK")
+		assert.NoError(t, resp.Error())
+
+		resp = e.GET("/").Expect()
+		resp.chain.assertOK(t)
+		resp.ErrorClass()
+		resp.chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,158 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createEventuallyHandler(polls *int32) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/job", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(polls, 1) <= 3 {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"ready":false}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ready":true,"result":42}`))
+	})
+
+	mux.HandleFunc("/never", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(polls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"ready":false}`))
+	})
+
+	return mux
+}
+
+func TestE2EEventually(t *testing.T) {
+	t.Run("converges", func(t *testing.T) {
+		var polls int32
+
+		server := httptest.NewServer(createEventuallyHandler(&polls))
+		defer server.Close()
+
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		resp := e.Eventually(10*time.Millisecond, 5*time.Second,
+			func() *Request {
+				return e.GET("/job")
+			},
+			func(resp *Response) bool {
+				resp.Status(http.StatusOK)
+				return resp.JSON().Object().Value("ready").Boolean().Raw()
+			},
+		)
+
+		resp.chain.assertOK(t)
+		assert.False(t, reporter.reported)
+		assert.Equal(t, int32(4), atomic.LoadInt32(&polls))
+
+		resp.JSON().Object().ValueEqual("result", 42)
+		resp.chain.assertOK(t)
+
+		resp.Status(http.StatusAccepted)
+		resp.chain.assertFailed(t)
+		assert.True(t, reporter.reported)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var polls int32
+
+		server := httptest.NewServer(createEventuallyHandler(&polls))
+		defer server.Close()
+
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		resp := e.Eventually(10*time.Millisecond, 100*time.Millisecond,
+			func() *Request {
+				return e.GET("/never")
+			},
+			func(resp *Response) bool {
+				resp.Status(http.StatusOK)
+				return true
+			},
+		)
+
+		resp.chain.assertFailed(t)
+		assert.True(t, reporter.reported)
+		assert.Greater(t, atomic.LoadInt32(&polls), int32(1))
+
+		assert.Contains(t, reporter.message, "expected condition to hold within 100ms")
+		assert.Contains(t, reporter.message, "attempts")
+		assert.Contains(t, reporter.message, "202 Accepted")
+		assert.Contains(t, reporter.message, `{\"ready\":false}`)
+		assert.Contains(t, reporter.message, "expected status equal to")
+	})
+
+	t.Run("check returned false", func(t *testing.T) {
+		var polls int32
+
+		server := httptest.NewServer(createEventuallyHandler(&polls))
+		defer server.Close()
+
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		resp := e.Eventually(10*time.Millisecond, 50*time.Millisecond,
+			func() *Request {
+				return e.GET("/never")
+			},
+			func(resp *Response) bool {
+				return false
+			},
+		)
+
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "check returned false")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Reporter: reporter,
+		})
+
+		build := func() *Request {
+			return e.GET("/")
+		}
+		check := func(*Response) bool {
+			return true
+		}
+
+		e.Eventually(0, time.Second, build, check).chain.assertFailed(t)
+		e.Eventually(time.Second, 0, build, check).chain.assertFailed(t)
+		e.Eventually(time.Second, time.Second, nil, check).chain.assertFailed(t)
+		e.Eventually(time.Second, time.Second, build, nil).chain.assertFailed(t)
+
+		e.Eventually(time.Second, time.Second,
+			func() *Request {
+				return nil
+			}, check).chain.assertFailed(t)
+	})
+}
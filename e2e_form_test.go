@@ -0,0 +1,75 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func createFormHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mustMarshalJSON(r.PostForm)))
+	})
+}
+
+func mustMarshalJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+type formTestUser struct {
+	Name    string            `form:"name"`
+	Tags    []string          `form:"tags"`
+	Address formTestAddress   `form:"address"`
+	Meta    map[string]string `form:"meta,omitempty"`
+}
+
+type formTestAddress struct {
+	City string `form:"city"`
+}
+
+func TestE2EFormObject(t *testing.T) {
+	server := httptest.NewServer(createFormHandler())
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	user := formTestUser{
+		Name:    "john",
+		Tags:    []string{"a", "b"},
+		Address: formTestAddress{City: "Paris"},
+		Meta:    map[string]string{"k": "v"},
+	}
+
+	e.POST("/").WithFormObject(user).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Equal(map[string]interface{}{
+		"name":         []string{"john"},
+		"tags":         []string{"a", "b"},
+		"address.city": []string{"Paris"},
+		"meta.k":       []string{"v"},
+	})
+
+	e.POST("/").WithFormObject(user, FormEncodeOpts{
+		SliceStyle: FormSliceBrackets,
+		NestStyle:  FormNestBrackets,
+	}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Equal(map[string]interface{}{
+		"name":          []string{"john"},
+		"tags[]":        []string{"a", "b"},
+		"address[city]": []string{"Paris"},
+		"meta[k]":       []string{"v"},
+	})
+}
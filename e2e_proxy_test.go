@@ -0,0 +1,318 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const proxyTagHeader = "X-Test-Proxy"
+
+// testProxy is a tiny forward proxy. It tags forwarded requests with
+// proxyTagHeader and tunnels CONNECT requests.
+type testProxy struct {
+	mu       sync.Mutex
+	requests []string
+	auth     string
+}
+
+func (p *testProxy) log() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.requests...)
+}
+
+func (p *testProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.requests = append(p.requests, r.Method+" "+r.Host)
+	p.mu.Unlock()
+
+	if p.auth != "" && r.Header.Get("Proxy-Authorization") != "Basic "+
+		base64.StdEncoding.EncodeToString([]byte(p.auth)) {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="test"`)
+		w.WriteHeader(http.StatusProxyAuthRequired)
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Authorization")
+	out.Header.Set(proxyTagHeader, "1")
+
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+func (p *testProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	target, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer target.Close()
+
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(target, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, target)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+func createProxyOriginHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/tagged", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(proxyTagHeader) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+func TestE2EProxyConfig(t *testing.T) {
+	origin := httptest.NewServer(createProxyOriginHandler())
+	defer origin.Close()
+
+	t.Run("url", func(t *testing.T) {
+		proxy := &testProxy{}
+		proxyServer := httptest.NewServer(proxy)
+		defer proxyServer.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: newMockReporter(t),
+			ProxyURL: proxyServer.URL,
+		})
+
+		e.GET("/tagged").
+			Expect().
+			Status(http.StatusNoContent)
+
+		assert.Equal(t, []string{"GET " + origin.Listener.Addr().String()}, proxy.log())
+	})
+
+	t.Run("func", func(t *testing.T) {
+		proxy := &testProxy{}
+		proxyServer := httptest.NewServer(proxy)
+		defer proxyServer.Close()
+
+		proxyURL, _ := url.Parse(proxyServer.URL)
+
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: newMockReporter(t),
+			Proxy: func(req *http.Request) (*url.URL, error) {
+				if req.URL.Path == "/tagged" {
+					return proxyURL, nil
+				}
+				return nil, nil
+			},
+		})
+
+		e.GET("/tagged").
+			Expect().
+			Status(http.StatusNoContent)
+
+		e.GET("/tagged/").
+			Expect().
+			Status(http.StatusNotFound)
+
+		assert.Equal(t, 1, len(proxy.log()))
+	})
+
+	t.Run("auth", func(t *testing.T) {
+		proxy := &testProxy{auth: "user:secret"}
+		proxyServer := httptest.NewServer(proxy)
+		defer proxyServer.Close()
+
+		proxyURL, _ := url.Parse(proxyServer.URL)
+
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: newMockReporter(t),
+			ProxyURL: proxyURL.String(),
+		})
+
+		e.GET("/tagged").
+			Expect().
+			Status(http.StatusProxyAuthRequired)
+
+		proxyURL.User = url.UserPassword("user", "secret")
+
+		e.GET("/tagged").WithProxy(proxyURL.String()).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: reporter,
+			ProxyURL: "ftp://localhost",
+		})
+
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "ProxyURL")
+
+		reporter = newMockReporter(t)
+
+		WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: reporter,
+			ProxyURL: "http://localhost:3128",
+			Proxy:    http.ProxyFromEnvironment,
+		})
+
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "both ProxyURL and Proxy are set")
+
+		reporter = newMockReporter(t)
+
+		WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: reporter,
+			Client:   &mockClient{},
+			ProxyURL: "http://localhost:3128",
+		})
+
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "expected *http.Client")
+	})
+}
+
+func TestE2EProxyRequest(t *testing.T) {
+	origin := httptest.NewServer(createProxyOriginHandler())
+	defer origin.Close()
+
+	proxy := &testProxy{}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+
+	t.Run("override", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		e.GET("/tagged").
+			Expect().
+			Status(http.StatusBadRequest)
+
+		e.GET("/tagged").WithProxy(proxyServer.URL).
+			Expect().
+			Status(http.StatusNoContent)
+
+		e.GET("/tagged").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: newMockReporter(t),
+			ProxyURL: proxyServer.URL,
+		})
+
+		e.GET("/tagged").WithProxy("").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("connect", func(t *testing.T) {
+		secureOrigin := httptest.NewTLSServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+		defer secureOrigin.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  secureOrigin.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		e.GET("/").
+			WithTLSConfig(&tls.Config{InsecureSkipVerify: true}).
+			WithProxy(proxyServer.URL).
+			Expect().
+			Status(http.StatusNoContent)
+
+		assert.Contains(t, proxy.log(),
+			"CONNECT "+secureOrigin.Listener.Addr().String())
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		reporter := &failureRecordingReporter{}
+
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: reporter,
+		})
+
+		e.GET("/tagged").WithProxy("localhost:3128").chain.assertFailed(t)
+
+		assert.Equal(t, 1, len(reporter.failures))
+		assert.Equal(t, FailureAssertUsage, reporter.failures[0].Type)
+	})
+
+	t.Run("proxy unreachable", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := l.Addr().String()
+		_ = l.Close()
+
+		e := WithConfig(Config{
+			BaseURL:  origin.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		resp := e.GET("/tagged").WithProxy("http://" + addr).
+			ExpectError(ErrorProxy)
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, ErrorProxy, resp.ErrorClass())
+	})
+}
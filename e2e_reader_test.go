@@ -0,0 +1,214 @@
+package httpexpect
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// patternReader generates infinite deterministic byte sequence.
+type patternReader struct {
+	pos int
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte((r.pos*7 + r.pos/251) % 256)
+		r.pos++
+	}
+	return len(p), nil
+}
+
+func patternDigest(n int64) string {
+	h := sha256.New()
+	if _, err := io.Copy(h, io.LimitReader(&patternReader{}, n)); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func digestHandler(w http.ResponseWriter, r *http.Request) {
+	h := sha256.New()
+	n, err := io.Copy(h, r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("X-Content-Length", strconv.FormatInt(r.ContentLength, 10))
+	w.Header().Set("X-Transfer-Encoding", strings.Join(r.TransferEncoding, ","))
+	w.Header().Set("X-Body-Length", strconv.FormatInt(n, 10))
+	_, _ = w.Write([]byte(hex.EncodeToString(h.Sum(nil))))
+}
+
+func TestE2EReader(t *testing.T) {
+	const size = 8 << 20
+
+	server := httptest.NewServer(http.HandlerFunc(digestHandler))
+	defer server.Close()
+
+	logger := &mockLogger{}
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Printers: []Printer{
+			NewDebugPrinter(logger, true),
+			NewCurlPrinter(logger),
+		},
+	})
+
+	digest := patternDigest(size)
+
+	t.Run("known length", func(t *testing.T) {
+		e.PUT("/upload").
+			WithHeader("Content-Type", "application/octet-stream").
+			WithReader(io.LimitReader(&patternReader{}, size), size).
+			Expect().
+			Status(http.StatusOK).
+			Header("X-Content-Length").Equal(strconv.Itoa(size))
+
+		e.PUT("/upload").
+			WithReader(io.LimitReader(&patternReader{}, size), size).
+			Expect().
+			Status(http.StatusOK).
+			Body().Equal(digest)
+	})
+
+	t.Run("unknown length", func(t *testing.T) {
+		resp := e.PUT("/upload").
+			WithReader(io.LimitReader(&patternReader{}, size), -1).
+			Expect().
+			Status(http.StatusOK)
+
+		resp.Header("X-Transfer-Encoding").Equal("chunked")
+		resp.Header("X-Body-Length").Equal(strconv.Itoa(size))
+		resp.Body().Equal(digest)
+	})
+
+	// printers should not consume the body
+	assert.NotEmpty(t, logger.logs)
+	for _, log := range logger.logs {
+		assert.Less(t, len(log), 4096)
+	}
+}
+
+func TestE2EReaderPrinted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(digestHandler))
+	defer server.Close()
+
+	digest := patternDigest(1000)
+
+	for _, tc := range []struct {
+		name    string
+		limit   int64
+		printed bool
+	}{
+		{name: "default limit", limit: 0, printed: true},
+		{name: "exact limit", limit: 1000, printed: true},
+		{name: "over limit", limit: 999, printed: false},
+		{name: "buffering disabled", limit: -1, printed: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &mockLogger{}
+
+			e := WithConfig(Config{
+				BaseURL:         server.URL,
+				Reporter:        NewAssertReporter(t),
+				BodyBufferLimit: tc.limit,
+				Printers: []Printer{
+					NewDebugPrinter(logger, true),
+				},
+			})
+
+			e.PUT("/upload").
+				WithReader(io.LimitReader(&patternReader{}, 1000), 1000).
+				Expect().
+				Status(http.StatusOK).
+				Body().Equal(digest)
+
+			e.PUT("/upload").
+				WithChunked(io.LimitReader(&patternReader{}, 1000)).
+				Expect().
+				Status(http.StatusOK).
+				Body().Equal(digest)
+
+			// request and response for every request
+			assert.Equal(t, 4, len(logger.logs))
+
+			if tc.printed {
+				assert.NotContains(t, logger.logs[0], "<streaming body")
+				assert.Greater(t, len(logger.logs[0]), 1000)
+			} else {
+				assert.Contains(t, logger.logs[0], "<streaming body, 1000 bytes>")
+			}
+
+			// chunked body is never buffered
+			assert.Contains(t, logger.logs[2], "<streaming body, unknown bytes>")
+		})
+	}
+}
+
+func TestE2EReaderChunkedPipe(t *testing.T) {
+	received := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			head := make([]byte, 5)
+			if _, err := io.ReadFull(r.Body, head); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			close(received)
+			tail, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write(append(head, tail...))
+		}))
+	defer server.Close()
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, _ = pw.Write([]byte("hello"))
+		// second part is written only after server received the first
+		// one, so the request would hang if body was buffered
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			pw.CloseWithError(errors.New("request body was not streamed"))
+			return
+		}
+		_, _ = pw.Write([]byte(" world"))
+		_ = pw.Close()
+	}()
+
+	logger := &mockLogger{}
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Printers: []Printer{
+			NewDebugPrinter(logger, true),
+		},
+	})
+
+	e.PUT("/upload").
+		WithChunked(pr).
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("hello world")
+
+	assert.Contains(t, logger.logs[0], "<streaming body, unknown bytes>")
+}
@@ -0,0 +1,122 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createRedactHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t", Path: "/"})
+		_, _ = w.Write([]byte(user + ":" + pass))
+	})
+}
+
+func TestE2ERedactURLUserInfo(t *testing.T) {
+	server := httptest.NewServer(createRedactHandler())
+	defer server.Close()
+
+	logger := &mockLogger{}
+
+	baseURL := strings.Replace(server.URL, "http://", "http://john:secret@", 1)
+
+	e := WithConfig(Config{
+		BaseURL:  baseURL,
+		Reporter: newMockReporter(t),
+		Printers: []Printer{
+			NewCompactPrinter(logger),
+			NewCurlPrinter(logger),
+			NewDebugPrinter(logger, false),
+		},
+	})
+
+	resp := e.GET("/").WithWireCapture().
+		Expect().
+		Status(http.StatusOK)
+
+	resp.Body().Equal("john:secret")
+	resp.WireRequest().Contains("Authorization: *****")
+	resp.WireRequest().NotContains("secret")
+	resp.chain.assertOK(t)
+
+	all := strings.Join(logger.logs, "\n")
+
+	assert.Contains(t, all, "Authorization: *****")
+	assert.NotContains(t, all, "secret")
+	assert.NotContains(t, all, "am9objpzZWNyZXQ") // base64 of john:secret
+
+	// explicit WithBasicAuth takes precedence
+	e.GET("/").WithBasicAuth("mary", "pass").
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("mary:pass")
+
+	// user info in request URL
+	e = WithConfig(Config{
+		Reporter: newMockReporter(t),
+	})
+
+	e.GET(baseURL + "/path").
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("john:secret")
+}
+
+func TestE2ERedactCookies(t *testing.T) {
+	server := httptest.NewServer(createRedactHandler())
+	defer server.Close()
+
+	logger := &mockLogger{}
+
+	e := WithConfig(Config{
+		BaseURL:       server.URL,
+		Reporter:      newMockReporter(t),
+		Printers:      []Printer{NewDebugPrinter(logger, false)},
+		RedactHeaders: append([]string{"Cookie", "Set-Cookie"}, DefaultRedactHeaders...),
+	})
+
+	e.GET("/").WithBasicAuth("john", "secret").
+		WithCookie("token", "t0k3n").
+		Expect().
+		Status(http.StatusOK).
+		Cookie("session").Value().Equal("s3cr3t")
+
+	all := strings.Join(logger.logs, "\n")
+
+	assert.Contains(t, all, "Authorization: *****")
+	assert.Contains(t, all, "Cookie: token=*****")
+	assert.Contains(t, all, "Set-Cookie: session=*****; Path=/")
+	assert.NotContains(t, all, "t0k3n")
+	assert.NotContains(t, all, "s3cr3t")
+}
+
+func TestE2ERedactDisabled(t *testing.T) {
+	server := httptest.NewServer(createRedactHandler())
+	defer server.Close()
+
+	logger := &mockLogger{}
+
+	e := WithConfig(Config{
+		BaseURL:       server.URL,
+		Reporter:      newMockReporter(t),
+		Printers:      []Printer{NewDebugPrinter(logger, false)},
+		RedactHeaders: []string{},
+	})
+
+	e.GET("/").WithBasicAuth("john", "secret").
+		Expect().
+		Status(http.StatusOK)
+
+	all := strings.Join(logger.logs, "\n")
+
+	assert.Contains(t, all, "Authorization: Basic am9objpzZWNyZXQ=")
+}
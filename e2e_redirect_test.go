@@ -1,8 +1,11 @@
 package httpexpect
 
 import (
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/valyala/fasthttp"
@@ -19,6 +22,21 @@ func createRedirectHandler() http.Handler {
 		http.Redirect(w, r, "/foo", http.StatusFound)
 	})
 
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		_, _ = w.Write(b)
+	})
+
+	mux.HandleFunc("/307", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		http.Redirect(w, r, "/echo", http.StatusTemporaryRedirect)
+	})
+
+	mux.HandleFunc("/308", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		http.Redirect(w, r, "/echo", http.StatusPermanentRedirect)
+	})
+
 	return mux
 }
 
@@ -72,3 +90,52 @@ func TestE2ERedirectBinderFast(t *testing.T) {
 		},
 	}))
 }
+
+// onlyReader hides all methods of underlying reader except Read.
+type onlyReader struct {
+	io.Reader
+}
+
+func TestE2ERedirectBody(t *testing.T) {
+	handler := createRedirectHandler()
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	logger := &mockLogger{}
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Printers: []Printer{
+			NewDebugPrinter(logger, true),
+			NewCurlPrinter(logger),
+		},
+	})
+
+	for _, tc := range []struct {
+		path string
+		code int
+	}{
+		{"/307", http.StatusTemporaryRedirect},
+		{"/308", http.StatusPermanentRedirect},
+	} {
+		path := tc.path
+
+		e.POST(path).
+			WithText("text").
+			Expect().
+			Status(http.StatusOK).Body().Equal("text")
+
+		e.POST(path).
+			WithReader(onlyReader{strings.NewReader("reader")}, 6).
+			Expect().
+			Status(http.StatusOK).Body().Equal("reader")
+
+		// chunked body is not buffered and can't be re-sent
+		e.POST(path).
+			WithChunked(onlyReader{strings.NewReader("chunked")}).
+			Expect().
+			Status(tc.code)
+	}
+}
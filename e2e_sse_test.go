@@ -0,0 +1,102 @@
+package httpexpect
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createSSEHandler(proceed <-chan struct{}) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, ": hello\n\n")
+		fmt.Fprint(w, "event: created\nid: 1\ndata: {\"id\": 1}\n\n")
+		flusher.Flush()
+
+		fmt.Fprint(w, "event: progress\nid: 2\ndata: 50%\n\n")
+		flusher.Flush()
+
+		// wait until the client has read events above, to ensure that
+		// the body is read incrementally
+		select {
+		case <-proceed:
+		case <-r.Context().Done():
+			return
+		}
+
+		fmt.Fprint(w, "event: done\nid: 3\nretry: 1000\ndata: line1\ndata: line2\n\n")
+		flusher.Flush()
+	})
+
+	return mux
+}
+
+func TestE2ESSE(t *testing.T) {
+	proceed := make(chan struct{})
+
+	server := httptest.NewServer(createSSEHandler(proceed))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	resp := e.GET("/events").Expect().
+		Status(http.StatusOK).
+		ContentType("text/event-stream")
+
+	sse := resp.SSE()
+	defer sse.Close()
+
+	ev := sse.Next(time.Second)
+	ev.Event().Equal("created")
+	ev.ID().Equal("1")
+	ev.JSONData().Object().ValueEqual("id", 1)
+	ev.chain.assertOK(t)
+
+	ev = sse.Next(time.Second)
+	ev.Event().Equal("progress")
+	ev.Data().Equal("50%")
+	ev.chain.assertOK(t)
+
+	close(proceed)
+
+	ev = sse.Next(time.Second)
+	ev.Event().Equal("done")
+	ev.ID().Equal("3")
+	ev.Retry().Equal(time.Second)
+	ev.Data().Equal("line1\nline2")
+	ev.chain.assertOK(t)
+
+	sse.chain.assertOK(t)
+}
+
+func TestE2ESSETimeout(t *testing.T) {
+	proceed := make(chan struct{})
+
+	server := httptest.NewServer(createSSEHandler(proceed))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	sse := e.GET("/events").Expect().SSE()
+	defer sse.Close()
+
+	sse.ExpectEvent("progress", time.Second).Data().Equal("50%")
+	sse.chain.assertOK(t)
+
+	sse.ExpectEvent("done", time.Millisecond*100)
+	sse.chain.assertFailed(t)
+}
@@ -0,0 +1,232 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTimeoutHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/slow-body", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("start"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte("end"))
+	})
+
+	mux.HandleFunc("/ws-slow-handshake", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		upgrader := &websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+	})
+
+	mux.HandleFunc("/ws-silent", func(w http.ResponseWriter, r *http.Request) {
+		upgrader := &websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// neither read nor write until client goes away
+		<-r.Context().Done()
+		time.Sleep(time.Second)
+	})
+
+	return mux
+}
+
+func TestE2ETimeoutRequest(t *testing.T) {
+	server := httptest.NewServer(createTimeoutHandler())
+	defer server.Close()
+
+	t.Run("config", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{RequestTimeout: 50 * time.Millisecond},
+		})
+
+		e.GET("/slow").Expect().chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "request timeout of 50ms exceeded")
+	})
+
+	t.Run("body", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{RequestTimeout: 50 * time.Millisecond},
+		})
+
+		e.GET("/slow-body").Expect().chain.assertFailed(t)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{RequestTimeout: 50 * time.Millisecond},
+		})
+
+		e.GET("/slow").WithTimeout(0).
+			Expect().
+			Status(http.StatusNoContent).chain.assertOK(t)
+
+		e.GET("/slow").WithTimeout(time.Minute).
+			Expect().
+			Status(http.StatusNoContent).chain.assertOK(t)
+
+		e.GET("/slow").WithTimeout(20 * time.Millisecond).
+			Expect().chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "request timeout of 20ms exceeded")
+	})
+
+	t.Run("expect error", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		resp := e.GET("/slow").WithTimeout(20 * time.Millisecond).
+			ExpectError(ErrorTimeout)
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, ErrorTimeout, resp.ErrorClass())
+	})
+
+	t.Run("scenario deadline sooner", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{RequestTimeout: time.Minute},
+		}).WithTimeout(50 * time.Millisecond)
+
+		e.GET("/slow").Expect().chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "scenario deadline exceeded")
+	})
+
+	t.Run("request timeout sooner", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{RequestTimeout: 50 * time.Millisecond},
+		}).WithTimeout(time.Minute)
+
+		e.GET("/slow").Expect().chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "request timeout of 50ms exceeded")
+	})
+}
+
+func TestE2ETimeoutWebsocket(t *testing.T) {
+	server := httptest.NewServer(createTimeoutHandler())
+	defer server.Close()
+
+	t.Run("handshake", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{WebsocketHandshakeTimeout: 50 * time.Millisecond},
+		})
+
+		e.GET("/ws-slow-handshake").WithWebsocketUpgrade().
+			Expect().chain.assertFailed(t)
+		assert.Contains(t, reporter.message,
+			"websocket handshake timeout of 50ms exceeded")
+	})
+
+	t.Run("handshake override", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{WebsocketHandshakeTimeout: 50 * time.Millisecond},
+		})
+
+		ws := e.GET("/ws-slow-handshake").WithWebsocketUpgrade().
+			WithTimeout(time.Minute).
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		ws.chain.assertOK(t)
+		ws.Disconnect()
+	})
+
+	t.Run("read", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{WebsocketReadTimeout: 50 * time.Millisecond},
+		})
+
+		ws := e.GET("/ws-silent").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.Expect()
+		ws.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "read timeout after 50ms")
+	})
+
+	t.Run("write", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Timeouts: Timeouts{WebsocketWriteTimeout: 50 * time.Millisecond},
+		})
+
+		ws := e.GET("/ws-silent").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		chunk := make([]byte, 1<<20)
+		for i := 0; i < 256 && !ws.chain.failed(); i++ {
+			ws.WriteBytesBinary(chunk)
+		}
+		ws.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "write timeout after 50ms")
+	})
+}
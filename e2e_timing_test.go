@@ -0,0 +1,120 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createTimingHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(`hello`))
+	})
+
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/slow", http.StatusFound)
+	})
+
+	return mux
+}
+
+func TestE2ETimingBreakdown(t *testing.T) {
+	server := httptest.NewServer(createTimingHandler())
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	resp := e.GET("/slow").WithTimingBreakdown().Expect().Status(http.StatusOK)
+
+	timings := resp.Timings()
+
+	timings.Attempts().Equal(1)
+	timings.Connect().IsSet()
+	timings.TLSHandshake().NotSet()
+	timings.TimeToFirstByte().Ge(10 * time.Millisecond)
+	timings.TimeToFirstByte().Le(timings.Total().Raw())
+	timings.Total().Le(resp.RoundTripTime().Raw())
+
+	resp = e.GET("/slow").WithTimingBreakdown().Expect().Status(http.StatusOK)
+
+	resp.Timings().Connect().NotSet()
+	resp.Timings().TimeToFirstByte().Le(resp.Timings().Total().Raw())
+
+	resp = e.GET("/redirect").WithTimingBreakdown().Expect().Status(http.StatusOK)
+
+	resp.Timings().Attempts().Equal(2)
+	resp.Timings().TimeToFirstByte().Ge(10 * time.Millisecond)
+
+	e.GET("/slow").Expect().Timings().Total().NotSet()
+}
+
+func TestE2ETimingBreakdownTLS(t *testing.T) {
+	server := httptest.NewTLSServer(createTimingHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client:   server.Client(),
+	})
+
+	timings := e.GET("/slow").WithTimingBreakdown().Expect().
+		Status(http.StatusOK).
+		Timings()
+
+	timings.Connect().IsSet()
+	timings.TLSHandshake().IsSet().Le(timings.Total().Raw())
+	timings.TimeToFirstByte().Le(timings.Total().Raw())
+}
+
+func TestE2ETimingConnectionReused(t *testing.T) {
+	server := httptest.NewServer(createTimingHandler())
+	defer server.Close()
+
+	stats := NewStatsCollector()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: &http.Transport{},
+		},
+		Stats: stats,
+	})
+
+	e.GET("/slow").WithTimingBreakdown().
+		Expect().
+		ConnectionReused().False()
+
+	e.GET("/slow").WithTimingBreakdown().
+		Expect().
+		ConnectionReused().True()
+
+	e.GET("/slow").WithTimingBreakdown().WithHeader("Connection", "close").
+		Expect().
+		ConnectionReused().True()
+
+	e.GET("/slow").WithTimingBreakdown().
+		Expect().
+		ConnectionReused().False()
+
+	s := stats.Summary()
+	if s.ReusedConnections != 2 || s.NewConnections != 2 {
+		t.Errorf("unexpected connection counters: %d reused, %d new",
+			s.ReusedConnections, s.NewConnections)
+	}
+
+	reporter := newMockReporter(t)
+
+	resp := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	}).GET("/slow").Expect()
+
+	resp.ConnectionReused().chain.assertFailed(t)
+	resp.chain.assertOK(t)
+}
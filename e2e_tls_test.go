@@ -1,11 +1,20 @@
 package httpexpect
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"log"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
@@ -138,3 +147,195 @@ func TestE2EAutoTLSBinderFast(t *testing.T) {
 		})
 	}
 }
+
+func createTestCert(
+	t *testing.T, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(
+		rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func TestE2EMutualTLS(t *testing.T) {
+	caCert, caKey := createTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+
+	makeClientCert := func(notBefore, notAfter time.Time) tls.Certificate {
+		cert, key := createTestCert(t, &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: "test client"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}, caCert, caKey)
+
+		return tls.Certificate{
+			Certificate: [][]byte{cert.Raw},
+			PrivateKey:  key,
+		}
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+		}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	client := &http.Client{}
+
+	t.Run("valid certificate", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: NewAssertReporter(t),
+			Client:   client,
+		})
+
+		e.GET("/").
+			WithTLSConfig(&tls.Config{
+				RootCAs: rootCAs,
+				Certificates: []tls.Certificate{
+					makeClientCert(time.Now().Add(-time.Hour), time.Now().Add(time.Hour)),
+				},
+			}).
+			Expect().
+			Status(http.StatusOK).
+			Body().Equal("test client")
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Client:   client,
+		})
+
+		e.GET("/").
+			WithTLSConfig(&tls.Config{
+				RootCAs: rootCAs,
+				Certificates: []tls.Certificate{
+					makeClientCert(time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)),
+				},
+			}).
+			Expect().
+			chain.assertFailed(t)
+	})
+
+	t.Run("no certificate", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Client:   client,
+		})
+
+		e.GET("/").
+			WithTLSConfig(&tls.Config{
+				RootCAs: rootCAs,
+			}).
+			Expect().
+			chain.assertFailed(t)
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+			Client:   client,
+		})
+
+		e.GET("/").
+			Expect().
+			chain.assertFailed(t)
+	})
+
+	assert.Nil(t, client.Transport)
+}
+
+func TestE2ETLSConnectionState(t *testing.T) {
+	server := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`hello`))
+		}))
+	server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		MaxVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client:   server.Client(),
+	})
+
+	resp := e.GET("/").Expect().Status(http.StatusOK)
+
+	resp.TLS().IsSet().
+		Version(tls.VersionTLS12).
+		PeerCertificateCount(1)
+
+	resp.TLS().PeerCertificateSubject(0).Contains("O=Acme Co")
+
+	state := resp.TLS().Raw()
+	resp.TLS().CipherSuite(state.CipherSuite)
+
+	reporter := newMockReporter(t)
+
+	NewResponse(reporter, resp.Raw()).TLS().Version(tls.VersionTLS13).
+		chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, "TLS 1.3")
+	assert.Contains(t, reporter.message, "TLS 1.2")
+
+	plain := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`hello`))
+		}))
+	defer plain.Close()
+
+	New(t, plain.URL).GET("/").Expect().TLS().NotSet()
+}
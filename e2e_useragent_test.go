@@ -0,0 +1,58 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func createUserAgentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua, ok := r.Header["User-Agent"]; ok {
+			w.Header()["User-Agent"] = ua
+		} else {
+			w.Header().Set("No-User-Agent", "true")
+		}
+	})
+}
+
+func TestE2EUserAgentLive(t *testing.T) {
+	server := httptest.NewServer(createUserAgentHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/").Expect().
+		Header("User-Agent").Equal("httpexpect/v2")
+
+	e.GET("/").WithUserAgent("custom/1.0").Expect().
+		Header("User-Agent").Equal("custom/1.0")
+
+	e.GET("/").WithUserAgent("custom/1.0").
+		WithHeader("User-Agent", "explicit/1.0").Expect().
+		Header("User-Agent").Equal("explicit/1.0")
+
+	e.GET("/").WithUserAgent("").Expect().
+		Header("No-User-Agent").Equal("true")
+
+	e = WithConfig(Config{
+		BaseURL:          server.URL,
+		Reporter:         NewAssertReporter(t),
+		DefaultUserAgent: "suite/1.0",
+	})
+
+	e.GET("/").Expect().
+		Header("User-Agent").Equal("suite/1.0")
+
+	e = WithConfig(Config{
+		BaseURL:          server.URL,
+		Reporter:         NewAssertReporter(t),
+		DefaultUserAgent: NoUserAgent,
+	})
+
+	e.GET("/").Expect().
+		Header("No-User-Agent").Equal("true")
+}
@@ -0,0 +1,198 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func createWebsocketSettingsHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		upgrader := &websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			panic(err)
+		}
+		defer c.Close()
+		for {
+			_, message, err := c.ReadMessage()
+			if err != nil {
+				break
+			}
+			err = c.WriteMessage(websocket.TextMessage,
+				[]byte(strings.Repeat(string(message), 100)))
+			if err != nil {
+				break
+			}
+		}
+	})
+
+	mux.HandleFunc("/compress", func(w http.ResponseWriter, r *http.Request) {
+		upgrader := &websocket.Upgrader{
+			EnableCompression: true,
+		}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			panic(err)
+		}
+		defer c.Close()
+		for {
+			mt, message, err := c.ReadMessage()
+			if err != nil {
+				break
+			}
+			err = c.WriteMessage(mt, message)
+			if err != nil {
+				break
+			}
+		}
+	})
+
+	return mux
+}
+
+func TestE2EWebsocketMaxMessageSize(t *testing.T) {
+	server := httptest.NewServer(createWebsocketSettingsHandler())
+	defer server.Close()
+
+	t.Run("within limit", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		ws := e.GET("/large").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket().
+			WithMaxMessageSize(1000)
+		defer ws.Disconnect()
+
+		ws.WriteText("x").Expect().TextMessage().Body().Length().Equal(100)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		ws := e.GET("/large").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket().
+			WithMaxMessageSize(10)
+		defer ws.Disconnect()
+
+		ws.WriteText("x").Expect()
+		ws.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, "10 bytes")
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		ws := e.GET("/large").WithWebsocketUpgrade().
+			Expect().
+			Websocket().
+			WithMaxMessageSize(0)
+		defer ws.Disconnect()
+
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestE2EWebsocketCompression(t *testing.T) {
+	server := httptest.NewServer(createWebsocketSettingsHandler())
+	defer server.Close()
+
+	t.Run("enabled", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		ws := e.GET("/compress").
+			WithWebsocketUpgrade().
+			WithWebsocketCompression().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.CompressionEnabled()
+		ws.chain.assertOK(t)
+
+		ws.WriteText("hello").Expect().TextMessage().Body().Equal("hello")
+		ws.chain.assertOK(t)
+
+		ws.CompressionDisabled()
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		ws := e.GET("/compress").
+			WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.CompressionDisabled()
+		ws.chain.assertOK(t)
+
+		ws.CompressionEnabled()
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("not supported by server", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		ws := e.GET("/large").
+			WithWebsocketUpgrade().
+			WithWebsocketCompression().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.CompressionDisabled()
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("custom dialer", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		req := NewRequest(Config{
+			RequestFactory:  DefaultRequestFactory{},
+			BaseURL:         server.URL,
+			Client:          &mockClient{},
+			WebsocketDialer: &mockWebsocketDialer{},
+			Reporter:        reporter,
+		}, "GET", "/compress")
+
+		req.WithWebsocketUpgrade().WithWebsocketCompression().Expect()
+		req.chain.assertFailed(t)
+	})
+}
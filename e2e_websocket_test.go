@@ -8,6 +8,7 @@ import (
 
 	fastwebsocket "github.com/fasthttp/websocket"
 	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 )
 
@@ -170,6 +171,82 @@ func TestE2EWebsocketHandlerStandard(t *testing.T) {
 			req.WithWebsocketDialer(NewWebsocketDialer(handler))
 		}))
 	})
+
+	t.Run("handler-method", func(t *testing.T) {
+		handler := createWebsocketHandler(wsHandlerOpts{})
+
+		e := WithConfig(Config{
+			Reporter: NewAssertReporter(t),
+			Printers: []Printer{
+				NewDebugPrinter(t, true),
+			},
+		})
+
+		testWebsocket(e.Builder(func(req *Request) {
+			req.WithHandler(handler)
+		}))
+	})
+}
+
+func TestE2EWebsocketHandlerHandshake(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token" {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("missing token"))
+			return
+		}
+		upgrader := &websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		for {
+			mt, message, err := c.ReadMessage()
+			if err != nil {
+				break
+			}
+			if err := c.WriteMessage(mt, message); err != nil {
+				break
+			}
+		}
+	})
+
+	e := WithConfig(Config{
+		Reporter:        NewAssertReporter(t),
+		WebsocketDialer: NewWebsocketDialer(mux),
+	})
+
+	e.GET("/test").WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusUnauthorized).
+		ContentType("text/plain").
+		Body().Equal("missing token")
+
+	ws := e.GET("/test").WithWebsocketUpgrade().
+		WithHeader("Authorization", "token").
+		Expect().
+		Status(http.StatusSwitchingProtocols).
+		Websocket()
+	defer ws.Disconnect()
+
+	ws.WriteText("hi").
+		Expect().
+		TextMessage().Body().Equal("hi")
+
+	upgrader := &websocket.Upgrader{}
+
+	e.GET("/bad").WithWebsocketUpgrade().
+		WithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Del("Sec-Websocket-Key")
+			_, _ = upgrader.Upgrade(w, r, nil)
+		})).
+		Expect().
+		Status(http.StatusBadRequest).
+		Body().NotEmpty()
 }
 
 func TestE2EWebsocketHandlerFast(t *testing.T) {
@@ -284,6 +361,119 @@ func TestE2EWebsocketTimeouts(t *testing.T) {
 	})
 }
 
+func TestE2EWebsocketExpectNoMessage(t *testing.T) {
+	newWebsocket := func(t *testing.T, handler http.Handler) (*Websocket, func()) {
+		server := httptest.NewServer(handler)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		ws := e.GET("/test").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+
+		return ws, func() {
+			ws.Disconnect()
+			server.Close()
+		}
+	}
+
+	t.Run("silence", func(t *testing.T) {
+		ws, cleanup := newWebsocket(t, createWebsocketHandler(wsHandlerOpts{}))
+		defer cleanup()
+
+		ws.WriteText("a").Expect().Body().Equal("a")
+		ws.chain.assertOK(t)
+
+		ws.ExpectNoMessage(time.Millisecond * 50)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("message", func(t *testing.T) {
+		ws, cleanup := newWebsocket(t, createWebsocketHandler(wsHandlerOpts{}))
+		defer cleanup()
+
+		ws.WriteText("a")
+		ws.ExpectNoMessage(time.Second)
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("slow-message", func(t *testing.T) {
+		blockCh := make(chan struct{})
+
+		ws, cleanup := newWebsocket(t, createWebsocketHandler(wsHandlerOpts{
+			preWrite: func() {
+				<-blockCh
+			},
+		}))
+		defer cleanup()
+
+		ws.WriteText("a")
+
+		ws.ExpectNoMessage(time.Millisecond * 10)
+		ws.chain.assertOK(t)
+
+		close(blockCh)
+
+		ws.Expect().TextMessage().Body().Equal("a")
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("invalid-duration", func(t *testing.T) {
+		ws, cleanup := newWebsocket(t, createWebsocketHandler(wsHandlerOpts{}))
+		defer cleanup()
+
+		ws.ExpectNoMessage(0)
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestE2EWebsocketDrain(t *testing.T) {
+	handler := createWebsocketHandler(wsHandlerOpts{})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	ws := e.GET("/test").WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusSwitchingProtocols).
+		Websocket()
+	defer ws.Disconnect()
+
+	ws.WriteText("a").WriteText("b").WriteText("c")
+
+	msgs := ws.Drain(2, time.Second)
+	ws.chain.assertOK(t)
+	assert.Equal(t, 2, len(msgs))
+	msgs[0].Body().Equal("a")
+	msgs[1].Body().Equal("b")
+
+	msgs = ws.Drain(10, time.Millisecond*100)
+	ws.chain.assertOK(t)
+	assert.Equal(t, 1, len(msgs))
+	msgs[0].Body().Equal("c")
+
+	ws.WriteText("d").CloseWithText("bye")
+
+	msgs = ws.Drain(10, time.Second)
+	ws.chain.assertOK(t)
+	assert.Equal(t, 2, len(msgs))
+	msgs[0].TextMessage().Body().Equal("d")
+	msgs[1].CloseMessage()
+
+	for _, m := range msgs {
+		m.chain.assertOK(t)
+	}
+}
+
 func TestE2EWebsocketClosed(t *testing.T) {
 	t.Run("close-write", func(t *testing.T) {
 		handler := createWebsocketHandler(wsHandlerOpts{})
@@ -404,3 +594,311 @@ func TestE2EWebsocketDisconnected(t *testing.T) {
 		ws.chain.assertOK(t)
 	})
 }
+
+func TestE2EWebsocketHandshakeRequest(t *testing.T) {
+	handshake := make(chan *http.Request, 1)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		handshake <- r
+		upgrader := &websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _, _ = c.ReadMessage()
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	check := func(t *testing.T, e *Expect) {
+		ws := e.GET("/ws").
+			WithQuery("room", "x").
+			WithHeader("Authorization", "token").
+			WithHeader("Sec-WebSocket-Protocol", "chat").
+			WithHeader("Host", "example.com").
+			WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		r := <-handshake
+
+		assert.Equal(t, "x", r.URL.Query().Get("room"))
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.Equal(t, "chat", r.Header.Get("Sec-Websocket-Protocol"))
+		assert.Equal(t, "example.com", r.Host)
+	}
+
+	t.Run("live", func(t *testing.T) {
+		check(t, WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: NewAssertReporter(t),
+		}))
+	})
+
+	t.Run("dialer", func(t *testing.T) {
+		check(t, WithConfig(Config{
+			BaseURL:         "http://example.org",
+			Reporter:        NewAssertReporter(t),
+			WebsocketDialer: NewWebsocketDialer(mux),
+		}))
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		})
+
+		ws := e.GET("/ws").
+			WithHeader("Sec-WebSocket-Key", "key").
+			WithHeader("Connection", "close").
+			WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		<-handshake
+
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message,
+			`["Connection" "Sec-Websocket-Key"]`)
+	})
+}
+
+func TestE2EWebsocketEverySchema(t *testing.T) {
+	handler := createWebsocketHandler(wsHandlerOpts{})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	})
+
+	schema := `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"}
+		},
+		"required": ["id"]
+	}`
+
+	connect := func() *Websocket {
+		reporter.reported = false
+		return e.GET("/test").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		ws := connect()
+		defer ws.Disconnect()
+
+		ws.WriteText(`{"id": 1}`).WriteText(`{"id": 2}`).WriteText(`{"id": 3}`)
+
+		ws.EverySchema(schema, 2, time.Second)
+		ws.chain.assertOK(t)
+
+		ws.EverySchema(schema, 1, time.Second)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ws := connect()
+		defer ws.Disconnect()
+
+		ws.WriteText(`{"id": 1}`).WriteText(`{"id": "x"}`).WriteText(`{`)
+
+		ws.EverySchema(schema, 3, time.Second)
+		ws.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, "failed for 2 of 3 WebSocket messages")
+		assert.Contains(t, reporter.message, "message 1: id:")
+		assert.Contains(t, reporter.message, "message 2: unexpected end")
+		assert.NotContains(t, reporter.message, "message 0:")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ws := connect()
+		defer ws.Disconnect()
+
+		ws.WriteText(`{"id": 1}`)
+
+		ws.EverySchema(schema, 2, time.Millisecond*100)
+		ws.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, "expected 2 WebSocket messages")
+		assert.Contains(t, reporter.message, "but got 1")
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		ws := connect()
+		defer ws.Disconnect()
+
+		ws.WriteText(`{"id": 1}`).CloseWithText("bye")
+
+		ws.EverySchema(schema, 2, time.Second)
+		ws.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message, "but got 1")
+	})
+
+	t.Run("bad schema", func(t *testing.T) {
+		ws := connect()
+		defer ws.Disconnect()
+
+		ws.EverySchema(`{"type": 123}`, 1, time.Second)
+		ws.chain.assertFailed(t)
+	})
+}
+
+func createWebsocketProtocolHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/protocol", func(w http.ResponseWriter, r *http.Request) {
+		upgrader := &websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			panic(err)
+		}
+		defer c.Close()
+
+		write := func(s string) {
+			_ = c.WriteMessage(websocket.TextMessage, []byte(s))
+		}
+
+		// step 1: HELLO -> WELCOME
+		if _, msg, err := c.ReadMessage(); err != nil || string(msg) != "HELLO" {
+			return
+		}
+		write("WELCOME")
+
+		// step 2: AUTH <mode> -> OK
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		write("OK")
+
+		// step 3: READY, unless server is slow
+		if string(msg) == "AUTH slow" {
+			time.Sleep(time.Millisecond * 200)
+		}
+		write("READY")
+
+		// step 4: close, optionally after unexpected message
+		if string(msg) == "AUTH noisy" {
+			write("NOISE")
+		}
+		_ = c.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye"))
+
+		_, _, _ = c.ReadMessage()
+	})
+
+	return mux
+}
+
+func TestE2EWebsocketExpectSequence(t *testing.T) {
+	server := httptest.NewServer(createWebsocketProtocolHandler())
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	})
+
+	text := func(s string) func(*WebsocketMessage) {
+		return func(m *WebsocketMessage) {
+			m.TextMessage().Body().Equal(s)
+		}
+	}
+
+	connect := func(auth string) *Websocket {
+		reporter.reported = false
+		ws := e.GET("/protocol").WithWebsocketUpgrade().
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket().
+			WithReadTimeout(time.Millisecond * 100)
+
+		ws.WriteText("HELLO").ExpectSequence(text("WELCOME"))
+		ws.WriteText(auth)
+
+		return ws
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ws := connect("AUTH ok")
+		defer ws.Disconnect()
+
+		ws.ExpectSequence(text("OK"), text("READY")).
+			ExpectClosed(websocket.CloseNormalClosure)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		ws := connect("AUTH ok")
+		defer ws.Disconnect()
+
+		ws.ExpectSequence(text("OK"), text("DONE"))
+		ws.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "diverged at index 1")
+		assert.Contains(t, reporter.message, `"READY"`)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ws := connect("AUTH slow")
+		defer ws.Disconnect()
+
+		ws.ExpectSequence(text("OK"), text("READY"))
+		ws.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "read timeout after 100ms at index 1")
+	})
+
+	t.Run("closed early", func(t *testing.T) {
+		ws := connect("AUTH ok")
+		defer ws.Disconnect()
+
+		ws.ExpectSequence(text("OK"), text("READY"), text("MORE"))
+		ws.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "closed with code 1000 at index 2")
+	})
+
+	t.Run("extra message", func(t *testing.T) {
+		ws := connect("AUTH noisy")
+		defer ws.Disconnect()
+
+		ws.ExpectSequence(text("OK"), text("READY"))
+		ws.chain.assertOK(t)
+
+		ws.ExpectClosed()
+		ws.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "unexpected text message")
+		assert.Contains(t, reporter.message, `"NOISE"`)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		ws := connect("AUTH ok")
+		defer ws.Disconnect()
+
+		ws.ExpectSequence(text("OK"), text("READY")).
+			ExpectClosed(websocket.CloseGoingAway)
+		ws.chain.assertFailed(t)
+	})
+}
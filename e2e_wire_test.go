@@ -0,0 +1,122 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createWireHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Custom", "custom-value")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("hello, " + r.URL.Query().Get("name")))
+	})
+
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strings.Repeat("x", 1000)))
+	})
+
+	return mux
+}
+
+func testWireCapture(e *Expect) {
+	resp := e.POST("/echo").
+		WithQuery("name", "john").
+		WithHeader("X-Request", "request-value").
+		WithText("request body").
+		WithWireCapture().
+		Expect().
+		Status(http.StatusAccepted)
+
+	resp.WireRequest().
+		Match(`^POST /echo\?name=john HTTP/1.1\r\n`)
+
+	resp.WireRequest().
+		Contains("X-Request: request-value\r\n").
+		Contains("Content-Type: text/plain; charset=utf-8\r\n").
+		Contains("\r\n\r\nrequest body")
+
+	resp.WireResponse().
+		Match(`^HTTP/1.1 202 Accepted\r\n`)
+
+	resp.WireResponse().
+		Contains("X-Custom: custom-value\r\n").
+		Contains("\r\n\r\nhello, john")
+
+	resp.Body().Equal("hello, john")
+
+	large := e.GET("/large").
+		WithWireCapture(100).
+		Expect().
+		Status(http.StatusOK)
+
+	large.WireResponse().
+		Contains(strings.Repeat("x", 100) + "\n... (900 bytes truncated)").
+		NotContains(strings.Repeat("x", 101))
+
+	large.Body().Length().Equal(1000)
+
+	e.GET("/large").
+		WithWireCapture(-1).
+		Expect().
+		WireResponse().Contains(strings.Repeat("x", 1000))
+}
+
+func TestE2EWireCaptureLive(t *testing.T) {
+	server := httptest.NewServer(createWireHandler())
+	defer server.Close()
+
+	testWireCapture(WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+	}))
+}
+
+func TestE2EWireCaptureBinder(t *testing.T) {
+	testWireCapture(WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: NewBinder(createWireHandler()),
+		},
+	}))
+}
+
+func TestE2EWireCaptureFailures(t *testing.T) {
+	server := httptest.NewServer(createWireHandler())
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	})
+
+	resp := e.GET("/echo").Expect()
+	resp.chain.assertOK(t)
+
+	resp.WireRequest().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "WithWireCapture")
+
+	resp.chain.reset()
+	resp.WireResponse().chain.assertFailed(t)
+
+	e.GET("/echo").WithWireCapture(1, 2).chain.assertFailed(t)
+
+	e = WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+		Client:   &mockClient{},
+	})
+
+	e.GET("/echo").WithWireCapture().Expect().chain.assertFailed(t)
+}
@@ -0,0 +1,226 @@
+package httpexpect
+
+import (
+	"sort"
+	"sync"
+)
+
+// Environment provides a container for arbitrary data shared between
+// tests and requests, e.g. values extracted from one response and used
+// in the next request.
+//
+// Values are stored using Store methods of String, Number, Boolean and
+// Value, or using Put. Getters report failure if value is missing or has
+// unexpected type.
+//
+// Environment is safe for concurrent use.
+//
+// Example:
+//  env := e.Env()
+//
+//  e.POST("/users").WithJSON(user).
+//      Expect().
+//      JSON().Object().Value("id").String().Store(env, "user_id")
+//
+//  e.GET("/users/{id}", env.GetString("user_id")).
+//      Expect().
+//      Status(http.StatusOK)
+type Environment struct {
+	chain chain
+	mu    sync.RWMutex
+	data  map[string]interface{}
+}
+
+// NewEnvironment returns a new empty Environment given a reporter used
+// to report failures.
+//
+// reporter should not be nil.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", "value")
+func NewEnvironment(reporter Reporter) *Environment {
+	return &Environment{
+		chain: makeChainFor("NewEnvironment", reporter),
+		data:  make(map[string]interface{}),
+	}
+}
+
+// Put saves value with given key, overwriting previous value, if any.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", 123)
+func (e *Environment) Put(key string, value interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.data[key] = value
+}
+
+// Has returns true if value with given key is present.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  if env.Has("user_id") {
+//      ...
+//  }
+func (e *Environment) Has(key string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, ok := e.data[key]
+	return ok
+}
+
+// Delete removes value with given key. Does nothing if there is no
+// such value.
+func (e *Environment) Delete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.data, key)
+}
+
+// Keys returns sorted list of stored keys.
+func (e *Environment) Keys() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	keys := make([]string, 0, len(e.data))
+	for k := range e.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns value with given key.
+//
+// If there is no such value, failure is reported and nil is returned.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", 123)
+//  value := env.Get("key").(int)
+func (e *Environment) Get(key string) interface{} {
+	value, _ := e.get(key)
+	return value
+}
+
+// GetString returns string value with given key.
+//
+// If there is no such value, or it's not a string, failure is reported
+// and empty string is returned.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", "value")
+//  value := env.GetString("key")
+func (e *Environment) GetString(key string) string {
+	value, ok := e.get(key)
+	if !ok {
+		return ""
+	}
+	s, ok := value.(string)
+	if !ok {
+		e.fail("\nexpected environment value %q of type string, but got %T",
+			key, value)
+		return ""
+	}
+	return s
+}
+
+// GetFloat returns numeric value with given key.
+//
+// Value may be of any numeric type, it's converted to float64. If there is
+// no such value, or it's not a number, failure is reported and zero is
+// returned.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", 123)
+//  value := env.GetFloat("key")
+func (e *Environment) GetFloat(key string) float64 {
+	value, ok := e.get(key)
+	if !ok {
+		return 0
+	}
+	switch value.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+	default:
+		e.fail("\nexpected environment value %q of numeric type, but got %T",
+			key, value)
+		return 0
+	}
+	chain := e.chain
+	f, _ := canonNumber(&chain, value)
+	return f
+}
+
+// GetInt is like GetFloat, but converts value to int.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", 123.0)
+//  value := env.GetInt("key")
+func (e *Environment) GetInt(key string) int {
+	return int(e.GetFloat(key))
+}
+
+// GetBool returns boolean value with given key.
+//
+// If there is no such value, or it's not a boolean, failure is reported
+// and false is returned.
+//
+// Example:
+//  env := NewEnvironment(t)
+//  env.Put("key", true)
+//  value := env.GetBool("key")
+func (e *Environment) GetBool(key string) bool {
+	value, ok := e.get(key)
+	if !ok {
+		return false
+	}
+	b, ok := value.(bool)
+	if !ok {
+		e.fail("\nexpected environment value %q of type bool, but got %T",
+			key, value)
+		return false
+	}
+	return b
+}
+
+func (e *Environment) get(key string) (interface{}, bool) {
+	e.mu.RLock()
+	value, ok := e.data[key]
+	e.mu.RUnlock()
+
+	if !ok {
+		e.fail("\nexpected environment containing key %q, but got keys:\n %q",
+			key, e.Keys())
+		return nil, false
+	}
+	return value, true
+}
+
+// fail reports failure using a copy of environment chain, so that the
+// environment remains usable after failure.
+func (e *Environment) fail(message string, args ...interface{}) {
+	chain := e.chain
+	chain.fail(message, args...)
+}
+
+// storeValue implements Store methods of String, Number, Boolean and Value.
+func storeValue(chain *chain, env *Environment, key string, value interface{}) {
+	if chain.failed() {
+		return
+	}
+	if env == nil {
+		chain.failUsage("\nunexpected nil environment in Store")
+		return
+	}
+	env.Put(key, value)
+}
@@ -0,0 +1,181 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentBasic(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	env := NewEnvironment(reporter)
+
+	assert.False(t, env.Has("foo"))
+	assert.Equal(t, []string{}, env.Keys())
+
+	env.Put("foo", "bar")
+	env.Put("num", 123)
+	env.Put("bool", true)
+
+	assert.True(t, env.Has("foo"))
+	assert.Equal(t, []string{"bool", "foo", "num"}, env.Keys())
+
+	assert.Equal(t, "bar", env.Get("foo"))
+	assert.Equal(t, "bar", env.GetString("foo"))
+	assert.Equal(t, 123.0, env.GetFloat("num"))
+	assert.Equal(t, 123, env.GetInt("num"))
+	assert.Equal(t, true, env.GetBool("bool"))
+	assert.False(t, reporter.reported)
+
+	env.Delete("foo")
+	assert.False(t, env.Has("foo"))
+}
+
+func TestEnvironmentFailures(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	env := NewEnvironment(reporter)
+
+	env.Put("str", "bar")
+	env.Put("num", 123)
+
+	assert.Nil(t, env.Get("missing"))
+	assert.True(t, reporter.reported)
+	assert.Contains(t, reporter.message, `"missing"`)
+	reporter.reported = false
+
+	assert.Equal(t, "", env.GetString("missing"))
+	assert.True(t, reporter.reported)
+	reporter.reported = false
+
+	assert.Equal(t, "", env.GetString("num"))
+	assert.True(t, reporter.reported)
+	reporter.reported = false
+
+	assert.Equal(t, 0.0, env.GetFloat("str"))
+	assert.True(t, reporter.reported)
+	reporter.reported = false
+
+	assert.Equal(t, false, env.GetBool("str"))
+	assert.True(t, reporter.reported)
+	reporter.reported = false
+
+	assert.Equal(t, "bar", env.GetString("str"))
+	assert.False(t, reporter.reported)
+}
+
+func TestEnvironmentStore(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	env := NewEnvironment(reporter)
+
+	NewString(reporter, "foo").Store(env, "str").chain.assertOK(t)
+	NewNumber(reporter, 123).Store(env, "num").chain.assertOK(t)
+	NewBoolean(reporter, true).Store(env, "bool").chain.assertOK(t)
+	NewValue(reporter, map[string]interface{}{"a": 1.0}).
+		Store(env, "value").chain.assertOK(t)
+
+	assert.Equal(t, "foo", env.GetString("str"))
+	assert.Equal(t, 123.0, env.GetFloat("num"))
+	assert.Equal(t, true, env.GetBool("bool"))
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, env.Get("value"))
+
+	var s string
+	var n float64
+	var b bool
+
+	NewString(reporter, "foo").StoreTo(&s).chain.assertOK(t)
+	NewNumber(reporter, 123).StoreTo(&n).chain.assertOK(t)
+	NewBoolean(reporter, true).StoreTo(&b).chain.assertOK(t)
+
+	assert.Equal(t, "foo", s)
+	assert.Equal(t, 123.0, n)
+	assert.Equal(t, true, b)
+
+	NewString(reporter, "foo").Store(nil, "str").chain.assertFailed(t)
+	NewString(reporter, "foo").StoreTo(nil).chain.assertFailed(t)
+	NewNumber(reporter, 123).StoreTo(nil).chain.assertFailed(t)
+	NewBoolean(reporter, true).StoreTo(nil).chain.assertFailed(t)
+}
+
+func TestEnvironmentStoreFailed(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	env := NewEnvironment(reporter)
+
+	env.Put("str", "old")
+	env.Put("num", 1)
+	env.Put("bool", false)
+
+	str := NewString(reporter, "new")
+	str.chain.fail("fail")
+	str.Store(env, "str")
+	str.Store(env, "str2")
+
+	num := NewNumber(reporter, 2)
+	num.chain.fail("fail")
+	num.Store(env, "num")
+
+	boolean := NewBoolean(reporter, true)
+	boolean.chain.fail("fail")
+	boolean.Store(env, "bool")
+
+	value := NewValue(reporter, "new")
+	value.chain.fail("fail")
+	value.Store(env, "value")
+
+	assert.Equal(t, "old", env.GetString("str"))
+	assert.Equal(t, 1.0, env.GetFloat("num"))
+	assert.Equal(t, false, env.GetBool("bool"))
+	assert.False(t, env.Has("str2"))
+	assert.False(t, env.Has("value"))
+
+	s, n, b := "old", 1.0, false
+
+	str.StoreTo(&s)
+	num.StoreTo(&n)
+	boolean.StoreTo(&b)
+
+	assert.Equal(t, "old", s)
+	assert.Equal(t, 1.0, n)
+	assert.Equal(t, false, b)
+
+	// failure in the middle of chain prevents storing
+	NewObject(reporter, map[string]interface{}{"id": 123.0}).
+		Value("id").String().Store(env, "id")
+	assert.False(t, env.Has("id"))
+}
+
+func TestEnvironmentScenario(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "u42"}`))
+	})
+
+	mux.HandleFunc("/users/u42", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	e := WithConfig(Config{
+		Client: &http.Client{
+			Transport: NewBinder(mux),
+		},
+		Reporter: newMockReporter(t),
+	})
+
+	e.POST("/users").
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("id").String().Store(e.Env(), "user_id")
+
+	e.GET("/users/{id}", e.Env().GetString("user_id")).
+		Expect().
+		Status(http.StatusOK).chain.assertOK(t)
+
+	assert.True(t, e.Env() == e.Builder(func(*Request) {}).Env())
+}
@@ -0,0 +1,149 @@
+package httpexpect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// eventuallyBodyLimit is maximum number of body bytes included into
+// failure reported by Expect.Eventually.
+const eventuallyBodyLimit = 1024
+
+// Eventually repeatedly sends request and checks response, until check
+// succeeds or timeout expires. It's useful for polling asynchronous APIs.
+//
+// build is invoked for every attempt and returns a new request to send.
+// check is invoked for every response and returns true if condition holds.
+// Failures reported by check and by request itself are not reported, but
+// treated as "not yet"; the attempt succeeds only if check returns true and
+// no failures were reported during it. Attempts are separated by interval.
+//
+// If condition holds, Eventually returns the last response, and further
+// assertions on it are reported as usual. Otherwise, after timeout, failure
+// is reported with number of attempts, status and body of the last response,
+// and failures suppressed during the last attempt.
+//
+// Example:
+//  resp := e.Eventually(100*time.Millisecond, 5*time.Second,
+//      func() *httpexpect.Request {
+//          return e.GET("/jobs/{id}", id)
+//      },
+//      func(resp *httpexpect.Response) bool {
+//          resp.Status(http.StatusOK)
+//          return resp.JSON().Object().Value("state").String().Raw() == "done"
+//      },
+//  )
+//
+//  resp.JSON().Object().ValueEqual("result", 42)
+func (e *Expect) Eventually(
+	interval, timeout time.Duration,
+	build func() *Request,
+	check func(*Response) bool,
+) *Response {
+	chain := makeChain(e.config.Reporter)
+
+	if interval <= 0 || timeout <= 0 {
+		chain.failUsage("\nunexpected non-positive interval %s or timeout %s"+
+			" passed to Eventually", interval, timeout)
+		return makeResponse(responseOpts{config: e.config, chain: chain})
+	}
+	if build == nil || check == nil {
+		chain.failUsage("\nunexpected nil callback passed to Eventually")
+		return makeResponse(responseOpts{config: e.config, chain: chain})
+	}
+
+	var (
+		deadline = time.Now().Add(timeout)
+		attempts = 0
+		last     *Response
+		rec      *pollReporter
+	)
+
+	for {
+		attempts++
+
+		req := build()
+		if req == nil {
+			chain.failUsage("\nunexpected nil request for attempt %d in Eventually", attempts)
+			return makeResponse(responseOpts{config: e.config, chain: chain})
+		}
+		if req.chain.failed() {
+			// failure was already reported when building request
+			req.markExpected()
+			return makeResponse(responseOpts{config: e.config, chain: req.chain})
+		}
+
+		reporter, stats := req.chain.reporter, req.chain.stats
+
+		rec = &pollReporter{}
+		req.chain.reporter = rec
+		req.chain.stats = nil
+
+		last = req.Expect()
+		ok := check(last)
+
+		// condition itself counts as assertion on every attempt
+		if last.chain.tracker != nil {
+			last.chain.tracker.add()
+		}
+
+		last.chain.reporter = reporter
+		last.chain.stats = stats
+
+		if ok && len(rec.failures) == 0 {
+			last.chain.failbit = false
+			return last
+		}
+
+		if !time.Now().Add(interval).Before(deadline) {
+			break
+		}
+		time.Sleep(interval)
+	}
+
+	last.chain.failbit = false
+
+	reason := "check returned false"
+	if len(rec.failures) != 0 {
+		reason = strings.Join(rec.failures, "\n\n")
+	}
+
+	status := "none"
+	body := ""
+	if last.resp != nil {
+		status = statusCodeText(last.resp.StatusCode)
+		body = string(last.content)
+		if len(body) > eventuallyBodyLimit {
+			body = body[:eventuallyBodyLimit] + "..."
+		}
+	}
+
+	last.chain.fail("\nexpected condition to hold within %s,"+
+		" but it didn't after %d attempts\n\nlast response status:\n %s"+
+		"\n\nlast response body:\n %q\n\nlast attempt failures:\n%s",
+		timeout, attempts, status, body, indentText(reason))
+
+	return last
+}
+
+// pollReporter is Reporter that remembers failures instead of reporting
+// them. It's used to suppress failures of attempts in Expect.Eventually.
+type pollReporter struct {
+	failures []string
+}
+
+func (r *pollReporter) Errorf(message string, args ...interface{}) {
+	r.failures = append(r.failures, strings.TrimSpace(fmt.Sprintf(message, args...)))
+}
+
+func (r *pollReporter) ReportFailure(failure Failure) {
+	failure.Assertion = ""
+	r.failures = append(r.failures, strings.TrimSpace(failure.String()))
+}
+
+// indentText prefixes every line of text with a space, so that nested
+// failure messages are aligned with the rest of the message.
+func indentText(text string) string {
+	return " " + strings.Replace(text, "\n", "\n ", -1)
+}
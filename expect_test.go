@@ -5,7 +5,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,7 +23,7 @@ func TestExpectMethods(t *testing.T) {
 		Reporter: reporter,
 	}
 
-	var reqs [8]*Request
+	var reqs [9]*Request
 
 	e := WithConfig(config)
 
@@ -33,6 +35,7 @@ func TestExpectMethods(t *testing.T) {
 	reqs[5] = e.PUT("/url")
 	reqs[6] = e.PATCH("/url")
 	reqs[7] = e.DELETE("/url")
+	reqs[8] = e.TRACE("/url")
 
 	assert.Equal(t, "METHOD", reqs[0].http.Method)
 	assert.Equal(t, "OPTIONS", reqs[1].http.Method)
@@ -42,6 +45,278 @@ func TestExpectMethods(t *testing.T) {
 	assert.Equal(t, "PUT", reqs[5].http.Method)
 	assert.Equal(t, "PATCH", reqs[6].http.Method)
 	assert.Equal(t, "DELETE", reqs[7].http.Method)
+	assert.Equal(t, "TRACE", reqs[8].http.Method)
+
+	for _, req := range reqs {
+		req.chain.assertOK(t)
+		req.Discard()
+	}
+}
+
+func TestExpectCustomMethods(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		BaseURL:  "http://example.com",
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	e := WithConfig(config)
+
+	r1 := e.Request("PURGE", "/url")
+	r1.chain.assertOK(t)
+	assert.Equal(t, "PURGE", r1.http.Method)
+
+	r2 := e.Request("REPORT", "/url")
+	r2.chain.assertOK(t)
+	assert.Equal(t, "REPORT", r2.http.Method)
+
+	r3 := e.Request("", "/url")
+	r3.chain.assertFailed(t)
+}
+
+func TestExpectCookieJar(t *testing.T) {
+	t.Run("with jar", func(t *testing.T) {
+		jar := NewJar()
+
+		e := WithConfig(Config{
+			Client:   &http.Client{Jar: jar},
+			Reporter: newMockReporter(t),
+		})
+
+		cj := e.CookieJar()
+		cj.chain.assertOK(t)
+		assert.Equal(t, jar, cj.Raw())
+	})
+
+	t.Run("without jar", func(t *testing.T) {
+		e := WithConfig(Config{
+			Client:   &http.Client{},
+			Reporter: newMockReporter(t),
+		})
+
+		e.CookieJar().chain.assertFailed(t)
+	})
+
+	t.Run("custom client", func(t *testing.T) {
+		e := WithConfig(Config{
+			Client:   &mockClient{},
+			Reporter: newMockReporter(t),
+		})
+
+		e.CookieJar().chain.assertFailed(t)
+	})
+}
+
+func TestExpectWithCookieJar(t *testing.T) {
+	t.Run("http client", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		client := &http.Client{}
+
+		e1 := WithConfig(Config{
+			Client:   client,
+			Reporter: reporter,
+		})
+
+		e2 := e1.WithCookieJar()
+
+		assert.False(t, reporter.reported)
+		assert.Nil(t, client.Jar)
+		assert.Equal(t, client, e1.config.Client)
+
+		client2, ok := e2.config.Client.(*http.Client)
+		assert.True(t, ok)
+		assert.False(t, client == client2)
+		assert.NotNil(t, client2.Jar)
+
+		e2.CookieJar().chain.assertOK(t)
+	})
+
+	t.Run("custom client", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		client := &mockClient{}
+
+		e1 := WithConfig(Config{
+			Client:   client,
+			Reporter: reporter,
+		})
+
+		e2 := e1.WithCookieJar()
+
+		assert.True(t, reporter.reported)
+		assert.Equal(t, client, e2.config.Client)
+	})
+}
+
+func TestExpectConfigValidate(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := []struct {
+		name     string
+		config   Config
+		problems []string
+	}{
+		{
+			name: "valid",
+			config: Config{
+				BaseURL:  "http://example.com/api?key=1",
+				Reporter: reporter,
+			},
+		},
+		{
+			name: "valid empty base url",
+			config: Config{
+				Reporter: reporter,
+			},
+		},
+		{
+			name:     "nil reporter",
+			config:   Config{},
+			problems: []string{"Reporter is nil"},
+		},
+		{
+			name: "unparsable base url",
+			config: Config{
+				BaseURL:  "http://example.com:port",
+				Reporter: reporter,
+			},
+			problems: []string{`BaseURL "http://example.com:port" can't be parsed`},
+		},
+		{
+			name: "base url without scheme",
+			config: Config{
+				BaseURL:  "example.com/api",
+				Reporter: reporter,
+			},
+			problems: []string{
+				`BaseURL "example.com/api" should be absolute URL with scheme and host`,
+			},
+		},
+		{
+			name: "nil printer",
+			config: Config{
+				Reporter: reporter,
+				Printers: []Printer{NewCompactPrinter(t), nil},
+			},
+			problems: []string{"Printers[1] is nil"},
+		},
+		{
+			name: "request id header without func",
+			config: Config{
+				Reporter:        reporter,
+				RequestIDHeader: "X-Request-Id",
+			},
+			problems: []string{"RequestIDHeader is set, but RequestIDFunc is nil"},
+		},
+		{
+			name: "multiple problems",
+			config: Config{
+				BaseURL:         "/api",
+				RequestIDHeader: "X-Request-Id",
+			},
+			problems: []string{
+				"Reporter is nil",
+				`BaseURL "/api" should be absolute URL with scheme and host`,
+				"RequestIDHeader is set, but RequestIDFunc is nil",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+
+			if len(tc.problems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			if assert.Error(t, err) {
+				lines := strings.Split(err.Error(), "\n")
+				assert.Equal(t, len(tc.problems), len(lines))
+				for i, p := range tc.problems {
+					if i < len(lines) {
+						assert.Contains(t, lines[i], p)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExpectConfigReport(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Reporter: reporter,
+		})
+
+		assert.False(t, reporter.reported)
+		assert.NotNil(t, e.config.RequestFactory)
+		assert.NotNil(t, e.config.Client)
+		assert.NotNil(t, e.config.WebsocketDialer)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		WithConfig(Config{
+			BaseURL:         "example.com",
+			RequestIDHeader: "X-Request-Id",
+			Reporter:        reporter,
+		})
+
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "BaseURL")
+		assert.Contains(t, reporter.message, "RequestIDFunc")
+	})
+
+	t.Run("nil reporter", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithConfig(Config{})
+		})
+	})
+}
+
+func TestExpectNewWithConfigE(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		e, err := NewWithConfigE(Config{
+			BaseURL:  "http://example.com",
+			Reporter: newMockReporter(t),
+		})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, e) {
+			assert.NotNil(t, e.config.RequestFactory)
+			assert.NotNil(t, e.config.Client)
+			assert.NotNil(t, e.config.WebsocketDialer)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		e, err := NewWithConfigE(Config{
+			BaseURL:  "example.com",
+			Reporter: reporter,
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, e)
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("nil reporter", func(t *testing.T) {
+		e, err := NewWithConfigE(Config{})
+
+		assert.Error(t, err)
+		assert.Nil(t, e)
+	})
 }
 
 func TestExpectBuilders(t *testing.T) {
@@ -68,11 +343,14 @@ func TestExpectBuilders(t *testing.T) {
 		reqs2 = append(reqs2, r)
 	})
 
-	e.Request("METHOD", "/url")
+	e.Request("METHOD", "/url").Discard()
 
 	r1 := e1.Request("METHOD", "/url")
 	r2 := e2.Request("METHOD", "/url")
 
+	r1.Discard()
+	r2.Discard()
+
 	assert.Equal(t, 2, int(len(reqs1)))
 	assert.Equal(t, 1, int(len(reqs2)))
 
@@ -81,6 +359,49 @@ func TestExpectBuilders(t *testing.T) {
 	assert.Equal(t, r1, reqs2[0])
 }
 
+func TestExpectWithDeadline(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	e := WithConfig(config)
+
+	soon := time.Now().Add(time.Minute)
+	late := time.Now().Add(time.Hour)
+
+	for _, e2 := range []*Expect{
+		e.WithDeadline(soon),
+		e.WithDeadline(soon).WithDeadline(late),
+		e.WithDeadline(late).WithDeadline(soon),
+	} {
+		req := e2.GET("/url")
+		d, ok := req.http.Context().Deadline()
+		assert.True(t, ok)
+		assert.True(t, d.Equal(soon))
+
+		req.Expect().chain.assertOK(t)
+		assert.NotNil(t, client.req)
+	}
+
+	_, ok := e.GET("/url").http.Context().Deadline()
+	assert.False(t, ok)
+
+	client.req = nil
+
+	req := e.WithDeadline(time.Now().Add(-time.Second)).GET("/url")
+	req.Expect().chain.assertFailed(t)
+	assert.Nil(t, client.req)
+
+	client.req = nil
+
+	req = e.WithTimeout(0).GET("/url")
+	req.Expect().chain.assertFailed(t)
+	assert.Nil(t, client.req)
+}
+
 func TestExpectMatchers(t *testing.T) {
 	client := &mockClient{}
 
@@ -105,7 +426,7 @@ func TestExpectMatchers(t *testing.T) {
 		resps2 = append(resps2, r)
 	})
 
-	e.Request("METHOD", "/url")
+	e.Request("METHOD", "/url").Discard()
 
 	req1 := e1.Request("METHOD", "/url")
 	req2 := e2.Request("METHOD", "/url")
@@ -256,6 +577,7 @@ func TestExpectRequestFactory(t *testing.T) {
 	r1 := e1.Request("GET", "/")
 	r1.chain.assertOK(t)
 	assert.NotNil(t, r1.http)
+	r1.Discard()
 
 	f2 := &testRequestFactory{}
 	e2 := WithConfig(Config{
@@ -267,6 +589,7 @@ func TestExpectRequestFactory(t *testing.T) {
 	r2.chain.assertOK(t)
 	assert.NotNil(t, f2.lastreq)
 	assert.True(t, f2.lastreq == r2.http)
+	r2.Discard()
 
 	f3 := &testRequestFactory{
 		fail: true,
@@ -280,3 +603,12 @@ func TestExpectRequestFactory(t *testing.T) {
 	r3.chain.assertFailed(t)
 	assert.Nil(t, f3.lastreq)
 }
+
+func TestExpectDefaultUserAgent(t *testing.T) {
+	assert.Equal(t, "httpexpect/v2.16.0", formatUserAgent("v2.16.0"))
+	assert.Equal(t, "httpexpect/v2", formatUserAgent(""))
+
+	// tests are run for development build of this module, which has no
+	// version in build info, so fallback is used
+	assert.Equal(t, "httpexpect/v2", defaultUserAgent)
+}
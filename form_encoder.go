@@ -0,0 +1,272 @@
+package httpexpect
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormSliceStyle defines how slice fields are encoded by WithFormObject.
+type FormSliceStyle int
+
+const (
+	// FormSliceRepeat encodes slices as repeated keys, e.g. "tags=a&tags=b".
+	FormSliceRepeat FormSliceStyle = iota
+
+	// FormSliceBrackets encodes slices as bracketed keys, e.g. "tags[]=a&tags[]=b".
+	FormSliceBrackets
+)
+
+// FormNestStyle defines how nested struct and map fields are encoded by
+// WithFormObject.
+type FormNestStyle int
+
+const (
+	// FormNestDots encodes nested fields with dotted prefixes, e.g. "user.name".
+	FormNestDots FormNestStyle = iota
+
+	// FormNestBrackets encodes nested fields with bracketed prefixes,
+	// e.g. "user[name]".
+	FormNestBrackets
+)
+
+// FormEncodeOpts define parameters for encoding objects passed to
+// WithFormObject.
+type FormEncodeOpts struct {
+	// How slices are encoded. Default is FormSliceRepeat.
+	SliceStyle FormSliceStyle
+
+	// How nested structs and maps are encoded. Default is FormNestDots.
+	NestStyle FormNestStyle
+
+	// Layout used to format time.Time values. Default is time.RFC3339.
+	TimeLayout string
+}
+
+type formEncoder struct {
+	opts   FormEncodeOpts
+	values url.Values
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func encodeFormObject(object interface{}, opts FormEncodeOpts) (url.Values, error) {
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = time.RFC3339
+	}
+
+	enc := formEncoder{
+		opts:   opts,
+		values: make(url.Values),
+	}
+
+	v := indirectValue(reflect.ValueOf(object))
+	if !v.IsValid() {
+		return enc.values, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		if err := enc.encode("", v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(
+			"\nexpected struct or map in WithFormObject, but got:\n %s", v.Type())
+	}
+
+	return enc.values, nil
+}
+
+func (e *formEncoder) encode(path string, v reflect.Value) error {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	if s, ok, err := e.scalar(v); ok || err != nil {
+		if err != nil {
+			return fmt.Errorf(
+				"\nfailed to encode form field:\n %s\n\nerror:\n %s", path, err.Error())
+		}
+		e.values.Add(path, s)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return e.encodeStruct(path, v)
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return e.unsupported(path, v)
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			elem := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+			if err := e.encode(e.nest(path, k), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem := indirectValue(v.Index(i))
+			if !elem.IsValid() {
+				continue
+			}
+			if _, ok, _ := e.scalar(elem); ok {
+				if err := e.encode(e.slice(path), elem); err != nil {
+					return err
+				}
+			} else {
+				if err := e.encode(e.nest(path, strconv.Itoa(i)), elem); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	default:
+		return e.unsupported(path, v)
+	}
+}
+
+func (e *formEncoder) encodeStruct(path string, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("form")
+		if tag == "-" {
+			continue
+		}
+
+		name, omitempty := parseFormTag(tag)
+
+		fv := v.Field(i)
+		if omitempty && isEmptyValue(fv) {
+			continue
+		}
+
+		if name == "" && field.Anonymous {
+			ft := indirectValue(fv)
+			if ft.Kind() == reflect.Struct && ft.Type() != timeType {
+				if err := e.encodeStruct(path, ft); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if err := e.encode(e.nest(path, name), fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *formEncoder) scalar(v reflect.Value) (string, bool, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(e.opts.TimeLayout), true, nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), true, err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), true, nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true, nil
+	}
+
+	return "", false, nil
+}
+
+func (e *formEncoder) unsupported(path string, v reflect.Value) error {
+	return fmt.Errorf(
+		"\nunsupported form field kind:\n %s\n\nfield:\n %s", v.Type(), path)
+}
+
+func (e *formEncoder) nest(path, key string) string {
+	if path == "" {
+		return key
+	}
+	if e.opts.NestStyle == FormNestBrackets {
+		return path + "[" + key + "]"
+	}
+	return path + "." + key
+}
+
+func (e *formEncoder) slice(path string) string {
+	if e.opts.SliceStyle == FormSliceBrackets {
+		return path + "[]"
+	}
+	return path
+}
+
+func parseFormTag(tag string) (name string, omitempty bool) {
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+	return v.IsZero()
+}
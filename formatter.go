@@ -0,0 +1,146 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Formatter converts Failure to message passed to Reporter.
+//
+// See FormatterReporter.
+type Formatter interface {
+	FormatFailure(Failure) string
+}
+
+// JSONFormatter implements Formatter by rendering every failure as a single
+// line JSON object, which may be parsed by CI tooling.
+//
+// The object has the following fields:
+//  - "testName": JSONFormatter.TestName, if non-empty
+//  - "assertionName": name of failed assertion, e.g. "Number.Equal"
+//  - "assertType": failure type, e.g. "FailureAssertion"
+//  - "severity": always "error"
+//  - "message": failure message, with surrounding whitespace trimmed
+//  - "expected", "actual": expected and actual values, if available
+//  - "bounds": object with "min" and "max", for range assertions
+//  - "path", "requestId", "assertion": value path, request ID, and
+//    assertion location, if available
+//
+// Values that can't be marshaled to JSON (e.g. NaN or channels) are
+// written as strings produced by fmt.Sprint.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.com/",
+//      Reporter: httpexpect.NewFormatterReporter(
+//          httpexpect.NewWriterReporter(os.Stdout),
+//          httpexpect.JSONFormatter{TestName: t.Name()},
+//      ),
+//  })
+type JSONFormatter struct {
+	// Name of the test, written as "testName" field. May be empty.
+	TestName string
+}
+
+type jsonFailure struct {
+	TestName      string          `json:"testName,omitempty"`
+	AssertionName string          `json:"assertionName,omitempty"`
+	AssertType    string          `json:"assertType"`
+	Severity      string          `json:"severity"`
+	Message       string          `json:"message"`
+	Expected      json.RawMessage `json:"expected,omitempty"`
+	Actual        json.RawMessage `json:"actual,omitempty"`
+	Bounds        *jsonBounds     `json:"bounds,omitempty"`
+	Path          string          `json:"path,omitempty"`
+	RequestID     string          `json:"requestId,omitempty"`
+	Assertion     string          `json:"assertion,omitempty"`
+}
+
+type jsonBounds struct {
+	Min json.RawMessage `json:"min"`
+	Max json.RawMessage `json:"max"`
+}
+
+// FormatFailure implements Formatter.FormatFailure.
+func (f JSONFormatter) FormatFailure(failure Failure) string {
+	out := jsonFailure{
+		TestName:      f.TestName,
+		AssertionName: failure.AssertionName,
+		AssertType:    failure.Type.String(),
+		Severity:      "error",
+		Message:       strings.TrimSpace(failure.Message),
+		Path:          failure.Path,
+		RequestID:     failure.RequestID,
+		Assertion:     failure.Assertion,
+	}
+	if failure.Expected != nil {
+		out.Expected = marshalFailureValue(failure.Expected.Value)
+	}
+	if failure.Actual != nil {
+		out.Actual = marshalFailureValue(failure.Actual.Value)
+	}
+	if failure.Bounds != nil {
+		out.Bounds = &jsonBounds{
+			Min: marshalFailureValue(failure.Bounds.Min),
+			Max: marshalFailureValue(failure.Bounds.Max),
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// can't fail, all values are pre-marshaled
+	_ = enc.Encode(out)
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// marshalFailureValue marshals value to JSON, or, if it's not possible,
+// marshals its string representation.
+func marshalFailureValue(value interface{}) json.RawMessage {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		buf.Reset()
+		_ = enc.Encode(fmt.Sprint(value))
+	}
+	return json.RawMessage(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// FormatterReporter implements Reporter and FailureReporter interfaces by
+// formatting every failure using Formatter and passing formatted message
+// to underlying reporter.
+//
+// Example:
+//  reporter := httpexpect.NewFormatterReporter(
+//      httpexpect.NewAssertReporter(t),
+//      httpexpect.JSONFormatter{TestName: t.Name()},
+//  )
+type FormatterReporter struct {
+	reporter  Reporter
+	formatter Formatter
+}
+
+// NewFormatterReporter returns a new FormatterReporter object.
+func NewFormatterReporter(reporter Reporter, formatter Formatter) *FormatterReporter {
+	return &FormatterReporter{reporter, formatter}
+}
+
+// Errorf implements Reporter.Errorf.
+//
+// Message is formatted as Failure with only Message field set.
+func (r *FormatterReporter) Errorf(message string, args ...interface{}) {
+	if h, ok := r.reporter.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	r.ReportFailure(Failure{Message: fmt.Sprintf(message, args...)})
+}
+
+// ReportFailure implements FailureReporter.ReportFailure.
+func (r *FormatterReporter) ReportFailure(failure Failure) {
+	if h, ok := r.reporter.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	r.reporter.Errorf("%s", r.formatter.FormatFailure(failure))
+}
@@ -0,0 +1,146 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordFailure(t *testing.T, fn func(r Reporter)) Failure {
+	reporter := &failureRecordingReporter{}
+
+	fn(reporter)
+
+	require.Equal(t, 1, len(reporter.failures))
+
+	failure := reporter.failures[0]
+	assert.Contains(t, failure.Assertion, "formatter_test.go")
+
+	// location depends on line numbers and is not stable
+	failure.Assertion = ""
+
+	return failure
+}
+
+func TestJSONFormatterGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		fn   func(r Reporter)
+	}{
+		{"equal", func(r Reporter) {
+			NewNumber(r, 5).Equal(6)
+		}},
+		{"equal_object", func(r Reporter) {
+			NewObject(r, map[string]interface{}{"a": 1}).
+				Equal(map[string]interface{}{"a": 2})
+		}},
+		{"in_range", func(r Reporter) {
+			NewNumber(r, 5).InRange(1, 3)
+		}},
+		{"not_empty", func(r Reporter) {
+			NewString(r, "").NotEmpty()
+		}},
+		{"usage", func(r Reporter) {
+			NewString(r, "foo").InList()
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			failure := recordFailure(t, tc.fn)
+
+			out := JSONFormatter{TestName: "TestExample"}.FormatFailure(failure)
+
+			NewString(t, out).
+				EqualFile("testdata/json_formatter/" + tc.name + ".golden")
+		})
+	}
+}
+
+func TestJSONFormatterFields(t *testing.T) {
+	failure := Failure{
+		Type:          FailureAssertUsage,
+		AssertionName: "Number.InRange",
+		Message:       "\n  message <&>  \n",
+		Actual:        &FailureValue{Value: 5},
+		Bounds:        &FailureBounds{Min: 3, Max: 1},
+		Path:          "JSON.Number",
+		RequestID:     "req-1",
+		Assertion:     "file.go:10",
+	}
+
+	assert.JSONEq(t, `{
+		"assertionName": "Number.InRange",
+		"assertType": "FailureAssertUsage",
+		"severity": "error",
+		"message": "message <&>",
+		"actual": 5,
+		"bounds": {"min": 3, "max": 1},
+		"path": "JSON.Number",
+		"requestId": "req-1",
+		"assertion": "file.go:10"
+	}`, JSONFormatter{}.FormatFailure(failure))
+
+	assert.NotContains(t, JSONFormatter{}.FormatFailure(failure), "\n")
+	assert.NotContains(t, JSONFormatter{}.FormatFailure(failure), "testName")
+}
+
+func TestJSONFormatterUnserializable(t *testing.T) {
+	failure := Failure{
+		Message:  "message",
+		Expected: &FailureValue{Value: math.NaN()},
+		Actual:   &FailureValue{Value: make(chan int)},
+	}
+
+	out := JSONFormatter{}.FormatFailure(failure)
+
+	var obj map[string]interface{}
+	require.Nil(t, json.Unmarshal([]byte(out), &obj))
+
+	assert.Equal(t, "NaN", obj["expected"])
+	assert.Regexp(t, "^0x[0-9a-f]+$", obj["actual"])
+
+	failure = Failure{
+		Message: "message",
+		Actual:  &FailureValue{Value: errors.New("some error")},
+	}
+
+	out = JSONFormatter{}.FormatFailure(failure)
+
+	assert.JSONEq(t, `{
+		"assertType": "FailureAssertion",
+		"severity": "error",
+		"message": "message",
+		"actual": {}
+	}`, out)
+}
+
+func TestFormatterReporter(t *testing.T) {
+	recorder := &failureRecordingReporter{}
+
+	reporter := NewFormatterReporter(recorder, JSONFormatter{TestName: "TestX"})
+
+	NewNumber(reporter, 1).Equal(2)
+	reporter.Errorf("some %s", "error")
+
+	assert.Empty(t, recorder.failures)
+	require.Equal(t, 2, len(recorder.errors))
+
+	var obj map[string]interface{}
+
+	require.Nil(t, json.Unmarshal([]byte(recorder.errors[0]), &obj))
+	assert.Equal(t, "TestX", obj["testName"])
+	assert.Equal(t, "Number.Equal", obj["assertionName"])
+	assert.Equal(t, 2.0, obj["expected"])
+	assert.Equal(t, 1.0, obj["actual"])
+
+	obj = nil
+	require.Nil(t, json.Unmarshal([]byte(recorder.errors[1]), &obj))
+	assert.Equal(t, "TestX", obj["testName"])
+	assert.Equal(t, "some error", obj["message"])
+	assert.NotContains(t, obj, "assertionName")
+}
@@ -7,15 +7,16 @@ require (
 	github.com/fasthttp/websocket v1.4.2
 	github.com/fatih/structs v1.0.0
 	github.com/google/go-querystring v1.0.0
-	github.com/gorilla/websocket v1.0.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/imkira/go-interpol v1.0.0
 	github.com/k0kubun/colorstring v0.0.0-20150214042306-9440f1994b88 // indirect
 	github.com/mattn/go-colorable v0.1.2 // indirect
 	github.com/onsi/ginkgo v1.10.1 // indirect
 	github.com/onsi/gomega v1.7.0 // indirect
 	github.com/sergi/go-diff v1.0.0 // indirect
-	github.com/stretchr/testify v1.3.0
+	github.com/stretchr/testify v1.6.1
 	github.com/valyala/fasthttp v1.9.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
 	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
 	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
 	github.com/xeipuuv/gojsonschema v1.1.0
@@ -24,6 +25,7 @@ require (
 	github.com/yudai/golcs v0.0.0-20170316035057-ecda9a501e82 // indirect
 	github.com/yudai/pp v2.0.1+incompatible // indirect
 	golang.org/x/net v0.0.0-20190827160401-ba9fcec4b297
-	gopkg.in/yaml.v2 v2.2.2 // indirect
+	golang.org/x/text v0.3.0
+	gopkg.in/yaml.v2 v2.2.2
 	moul.io/http2curl v1.0.1-0.20190925090545-5cd742060b0e
 )
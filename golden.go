@@ -0,0 +1,113 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// UpdateGolden enables update mode for golden files.
+//
+// When UpdateGolden is true, String.EqualFile and Value.EqualFile don't
+// compare actual value with golden file, but instead (re)write the file
+// with actual value and succeed. This is useful to refresh snapshots
+// after intended changes.
+//
+// UpdateGolden is initialized to true if HTTPEXPECT_UPDATE_GOLDEN
+// environment variable is set to non-empty value.
+//
+// Example:
+//  HTTPEXPECT_UPDATE_GOLDEN=1 go test ./...
+var UpdateGolden = os.Getenv("HTTPEXPECT_UPDATE_GOLDEN") != ""
+
+func readGolden(chain *chain, path string) ([]byte, bool) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		chain.fail("\nfailed to read golden file:\n %s\n\nerror:\n %s",
+			path, err.Error())
+		return nil, false
+	}
+	return b, true
+}
+
+func writeGolden(chain *chain, path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		chain.fail("\nfailed to write golden file:\n %s\n\nerror:\n %s",
+			path, err.Error())
+		return
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		chain.fail("\nfailed to write golden file:\n %s\n\nerror:\n %s",
+			path, err.Error())
+	}
+}
+
+func trimTrailingNewlines(s string) string {
+	return strings.TrimRight(s, "\r\n")
+}
+
+func checkGoldenText(chain *chain, actual, path string) {
+	if chain.failed() {
+		return
+	}
+
+	if UpdateGolden {
+		writeGolden(chain, path, []byte(trimTrailingNewlines(actual)+"\n"))
+		return
+	}
+
+	b, ok := readGolden(chain, path)
+	if !ok {
+		return
+	}
+
+	expected := trimTrailingNewlines(string(b))
+
+	if expected != trimTrailingNewlines(actual) {
+		chain.fail(
+			"\nexpected string equal to golden file:\n %s\n\n"+
+				"expected:\n %q\n\nbut got:\n %q",
+			path, expected, actual)
+	}
+}
+
+func checkGoldenJSON(chain *chain, actual interface{}, path string) {
+	if chain.failed() {
+		return
+	}
+
+	if UpdateGolden {
+		b, err := json.MarshalIndent(actual, "", "  ")
+		if err != nil {
+			chain.fail(err.Error())
+			return
+		}
+		writeGolden(chain, path, append(b, '\n'))
+		return
+	}
+
+	b, ok := readGolden(chain, path)
+	if !ok {
+		return
+	}
+
+	var expected interface{}
+	if err := json.Unmarshal(b, &expected); err != nil {
+		chain.fail("\nfailed to decode golden file:\n %s\n\nerror:\n %s",
+			path, err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		chain.fail(
+			"\nexpected value equal to golden file:\n %s\n\n"+
+				"expected:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+			path,
+			dumpValue(expected),
+			dumpValue(actual),
+			diffValues(expected, actual))
+	}
+}
@@ -1,10 +1,17 @@
 package httpexpect
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/xeipuuv/gojsonschema"
 	"github.com/yalp/jsonpath"
@@ -23,6 +30,136 @@ func toString(str interface{}) (s string, ok bool) {
 	return
 }
 
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// splitKeyPath splits path like "items[0].id" or "items.0.id" into
+// segments. Brackets may contain array index or object key, which allows
+// keys with dots, e.g. "labels[app.kubernetes.io/name]".
+func splitKeyPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	var keys []string
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket in path %q", path)
+			}
+			keys = append(keys, path[i+1:i+end])
+			i += end + 1
+			if i < len(path) && path[i] != '.' && path[i] != '[' {
+				return nil, fmt.Errorf("unexpected %q after bracket in path %q",
+					path[i], path)
+			}
+			if i < len(path) && path[i] == '.' {
+				i++
+				if i == len(path) {
+					return nil, fmt.Errorf("empty segment in path %q", path)
+				}
+			}
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty segment in path %q", path)
+			}
+			keys = append(keys, path[i:i+end])
+			i += end
+			if i < len(path) && path[i] == '.' {
+				i++
+				if i == len(path) {
+					return nil, fmt.Errorf("empty segment in path %q", path)
+				}
+			}
+		}
+	}
+	return keys, nil
+}
+
+// formatKeyPath formats path segments, using brackets for array indexes.
+func formatKeyPath(keys []string, indexes []bool) string {
+	var b strings.Builder
+	for i, k := range keys {
+		if indexes[i] {
+			b.WriteString("[" + k + "]")
+		} else {
+			if i != 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(k)
+		}
+	}
+	return b.String()
+}
+
+type keyPathLookup struct {
+	keys    []string
+	indexes []bool
+	found   int
+	node    interface{}
+}
+
+// lookupKeyPath finds the longest prefix of path segments existing in value.
+func lookupKeyPath(value interface{}, keys []string) keyPathLookup {
+	lk := keyPathLookup{
+		keys:    keys,
+		indexes: make([]bool, len(keys)),
+		node:    value,
+	}
+
+	for i, key := range keys {
+		switch node := lk.node.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return lk
+			}
+			lk.node = child
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return lk
+			}
+			lk.indexes[i] = true
+			lk.node = node[index]
+		default:
+			return lk
+		}
+		lk.found++
+	}
+
+	return lk
+}
+
+func (lk *keyPathLookup) prefix() string {
+	if lk.found == 0 {
+		return "(root)"
+	}
+	return fmt.Sprintf("%q", formatKeyPath(lk.keys[:lk.found], lk.indexes[:lk.found]))
+}
+
+func (lk *keyPathLookup) describe() string {
+	switch node := lk.node.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf("keys available at that point:\n%s",
+			dumpValue(sortedKeys(node)))
+	case []interface{}:
+		return fmt.Sprintf("indexes available at that point:\n [0; %d)", len(node))
+	default:
+		return fmt.Sprintf("value at that point is not an object or array:\n%s",
+			dumpValue(node))
+	}
+}
+
 func getPath(chain *chain, value interface{}, path string) *Value {
 	if chain.failed() {
 		return &Value{*chain, nil}
@@ -37,26 +174,133 @@ func getPath(chain *chain, value interface{}, path string) *Value {
 	return &Value{*chain, result}
 }
 
-func checkSchema(chain *chain, value, schema interface{}) {
+func getPointer(chain *chain, value interface{}, pointer string) *Value {
 	if chain.failed() {
-		return
+		return &Value{*chain, nil}
 	}
 
-	valueLoader := gojsonschema.NewGoLoader(value)
+	if pointer == "" {
+		return &Value{*chain, value}
+	}
 
-	var schemaLoader gojsonschema.JSONLoader
+	if pointer[0] != '/' {
+		chain.fail("\ninvalid JSON pointer (should be empty or start with '/'):\n %q",
+			pointer)
+		return &Value{*chain, nil}
+	}
 
-	if str, ok := toString(schema); ok {
-		if ok, _ := regexp.MatchString(`^\w+://`, str); ok {
-			schemaLoader = gojsonschema.NewReferenceLoader(str)
-		} else {
-			schemaLoader = gojsonschema.NewStringLoader(str)
+	tokens := strings.Split(pointer[1:], "/")
+
+	for i, token := range tokens {
+		if !validPointerToken(token) {
+			chain.fail("\ninvalid escape sequence in JSON pointer token %q:\n %q",
+				token, pointer)
+			return &Value{*chain, nil}
+		}
+
+		key := strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		switch node := value.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				chain.fail("\nJSON pointer %q: token %d (%q) not found in object,"+
+					" keys available at that point:\n%s",
+					pointer, i, token, dumpValue(sortedKeys(node)))
+				return &Value{*chain, nil}
+			}
+			value = child
+
+		case []interface{}:
+			if key == "-" {
+				chain.fail("\nJSON pointer %q: token %d (\"-\") refers to"+
+					" nonexistent element after the last array element", pointer, i)
+				return &Value{*chain, nil}
+			}
+			index, ok := parsePointerIndex(key)
+			if !ok {
+				chain.fail("\nJSON pointer %q: token %d (%q) is not a valid array index",
+					pointer, i, token)
+				return &Value{*chain, nil}
+			}
+			if index >= len(node) {
+				chain.fail("\nJSON pointer %q: token %d (%q) out of array bounds,"+
+					" indexes available at that point:\n [0; %d)",
+					pointer, i, token, len(node))
+				return &Value{*chain, nil}
+			}
+			value = node[index]
+
+		default:
+			chain.fail("\nJSON pointer %q: token %d (%q) can't be resolved,"+
+				" value at that point is %s, not an object or array:\n%s",
+				pointer, i, token, jsonTypeName(node), dumpValue(node))
+			return &Value{*chain, nil}
 		}
-	} else {
-		schemaLoader = gojsonschema.NewGoLoader(schema)
 	}
 
-	result, err := gojsonschema.Validate(schemaLoader, valueLoader)
+	return &Value{*chain, value}
+}
+
+// validPointerToken checks that every "~" in token is followed by "0" or "1".
+func validPointerToken(token string) bool {
+	for i := 0; i < len(token); i++ {
+		if token[i] == '~' {
+			if i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1') {
+				return false
+			}
+			i++
+		}
+	}
+	return true
+}
+
+// parsePointerIndex parses array index as defined by RFC 6901: "0" or
+// decimal number without leading zeros.
+func parsePointerIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
+// jsonTypeName returns JSON type name of canonical value.
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func checkSchema(chain *chain, value, schema interface{}) {
+	if chain.failed() {
+		return
+	}
+
+	valueLoader := gojsonschema.NewGoLoader(value)
+
+	result, err := gojsonschema.Validate(schemaLoader(schema), valueLoader)
 	if err != nil {
 		chain.fail("\n%s\n\nschema:\n%s\n\nvalue:\n%s",
 			err.Error(),
@@ -81,6 +325,18 @@ func checkSchema(chain *chain, value, schema interface{}) {
 	}
 }
 
+// schemaLoader returns loader for schema given as URL, JSON string, or
+// Go value.
+func schemaLoader(schema interface{}) gojsonschema.JSONLoader {
+	if str, ok := toString(schema); ok {
+		if ok, _ := regexp.MatchString(`^\w+://`, str); ok {
+			return gojsonschema.NewReferenceLoader(str)
+		}
+		return gojsonschema.NewStringLoader(str)
+	}
+	return gojsonschema.NewGoLoader(schema)
+}
+
 func dumpSchema(schema interface{}) string {
 	if s, ok := toString(schema); ok {
 		schema = s
@@ -107,7 +363,8 @@ func canonArray(chain *chain, in interface{}) ([]interface{}, bool) {
 	if ok {
 		out, ok = data.([]interface{})
 		if !ok {
-			chain.fail("expected array, got %v", out)
+			chain.fail("\nexpected value convertible to JSON array, but got %T:\n%s",
+				in, dumpValue(data))
 		}
 	}
 	return out, ok
@@ -119,34 +376,455 @@ func canonMap(chain *chain, in interface{}) (map[string]interface{}, bool) {
 	if ok {
 		out, ok = data.(map[string]interface{})
 		if !ok {
-			chain.fail("expected map, got %v", out)
+			chain.fail("\nexpected value convertible to JSON object, but got %T:\n%s",
+				in, dumpValue(data))
 		}
 	}
 	return out, ok
 }
 
+// canonArrayShared is like canonArray, but if value is already in canonical
+// form, e.g. it's Raw() of another Array, it's returned as is instead of
+// being copied. Returned slice should not be modified.
+func canonArrayShared(chain *chain, in interface{}) ([]interface{}, bool) {
+	if arr, ok := in.([]interface{}); ok && arr != nil && isCanonical(arr) {
+		return arr, true
+	}
+	return canonArray(chain, in)
+}
+
+// isCanonical returns true if value consists only of types produced by
+// canonValue and would not be changed by it.
+func isCanonical(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, bool:
+		return true
+
+	case float64:
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+
+	case string:
+		return utf8.ValidString(v)
+
+	case []interface{}:
+		if v == nil {
+			return false
+		}
+		for _, e := range v {
+			if !isCanonical(e) {
+				return false
+			}
+		}
+		return true
+
+	case map[string]interface{}:
+		if v == nil {
+			return false
+		}
+		for k, e := range v {
+			if !utf8.ValidString(k) || !isCanonical(e) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return false
+	}
+}
+
+// equalValues reports whether two values in canonical form are equal.
+// It's equivalent to reflect.DeepEqual, but avoids reflection for types
+// produced by canonValue and stops at the first difference.
+func equalValues(expected, actual interface{}) bool {
+	switch e := expected.(type) {
+	case nil:
+		return actual == nil
+
+	case bool:
+		a, ok := actual.(bool)
+		return ok && a == e
+
+	case float64:
+		a, ok := actual.(float64)
+		return ok && a == e
+
+	case string:
+		a, ok := actual.(string)
+		return ok && a == e
+
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) || (a == nil) != (e == nil) {
+			return false
+		}
+		for i := range e {
+			if !equalValues(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok || len(a) != len(e) || (a == nil) != (e == nil) {
+			return false
+		}
+		for k, ev := range e {
+			av, ok := a[k]
+			if !ok || !equalValues(ev, av) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
+
+// diffPath returns path of the first element that differs between two
+// values in canonical form, e.g. "[2].name", or empty string if values
+// differ at the top level or are equal. Object keys are visited in
+// sorted order, so the result is deterministic.
+func diffPath(expected, actual interface{}) string {
+	switch e := expected.(type) {
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			return ""
+		}
+		for i := range e {
+			if !equalValues(e[i], a[i]) {
+				return fmt.Sprintf("[%d]", i) + diffSubPath(e[i], a[i])
+			}
+		}
+
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok || len(a) != len(e) {
+			return ""
+		}
+		for _, k := range sortedKeys(e) {
+			if av, ok := a[k]; !ok || !equalValues(e[k], av) {
+				return k + diffSubPath(e[k], av)
+			}
+		}
+	}
+	return ""
+}
+
+func diffSubPath(expected, actual interface{}) string {
+	path := diffPath(expected, actual)
+	if path == "" || path[0] == '[' {
+		return path
+	}
+	return "." + path
+}
+
+// canonValue converts value to canonical form by encoding it to JSON and
+// decoding back into interface{}. Hence, json.Marshaler, encoding.TextMarshaler
+// and "json" struct tags are honored.
 func canonValue(chain *chain, in interface{}) (interface{}, bool) {
-	b, err := json.Marshal(in)
+	b, err := json.Marshal(addressableMarshaler(in))
 	if err != nil {
-		chain.fail(err.Error())
+		chain.failUsage("\nunexpected value of type %T that can't be encoded as JSON:\n %s",
+			in, err.Error())
 		return nil, false
 	}
 
 	var out interface{}
 	if err := json.Unmarshal(b, &out); err != nil {
-		chain.fail(err.Error())
+		chain.failUsage("\nunexpected value of type %T that can't be decoded from JSON:\n %s",
+			in, err.Error())
 		return nil, false
 	}
 
 	return out, true
 }
 
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// addressableMarshaler returns pointer to a copy of value if value's type
+// implements json.Marshaler or encoding.TextMarshaler only with pointer
+// receiver, because encoding/json ignores such methods for non-addressable
+// values. Otherwise, value is returned as is.
+func addressableMarshaler(in interface{}) interface{} {
+	v := reflect.ValueOf(in)
+	if !v.IsValid() || v.Kind() == reflect.Ptr {
+		return in
+	}
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return in
+	}
+	pt := reflect.PtrTo(t)
+	if !pt.Implements(jsonMarshalerType) && !pt.Implements(textMarshalerType) {
+		return in
+	}
+	p := reflect.New(t)
+	p.Elem().Set(v)
+	return p.Interface()
+}
+
+// formatNumber formats number for failure message. Integral values are
+// printed as plain integers, and json.Number is printed verbatim.
+// Bounds defines inclusivity of range bounds for InRangeOpts methods of
+// Number, Duration, and DateTime. Zero value defines closed range [min, max].
+type Bounds struct {
+	// If true, min itself is not in range.
+	ExcludeMin bool
+
+	// If true, max itself is not in range.
+	ExcludeMax bool
+}
+
+// contains reports whether value is in range, given signs of (value - min)
+// and (value - max).
+func (b Bounds) contains(cmpMin, cmpMax int) bool {
+	return (cmpMin > 0 || (cmpMin == 0 && !b.ExcludeMin)) &&
+		(cmpMax < 0 || (cmpMax == 0 && !b.ExcludeMax))
+}
+
+// format returns range in interval notation, e.g. "(0, 1]".
+func (b Bounds) format(min, max string) string {
+	left, right := "[", "]"
+	if b.ExcludeMin {
+		left = "("
+	}
+	if b.ExcludeMax {
+		right = ")"
+	}
+	return left + min + ", " + max + right
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func formatNumber(value interface{}) string {
+	return formatNumberPrecision(value, 0)
+}
+
+// formatNumberPrecision formats number for failure message. Integer numbers
+// are always printed without fractional part and exponent, e.g. "1000000"
+// instead of "1e+06". If precision is positive, it defines maximum number
+// of digits after decimal point for non-integer numbers.
+func formatNumberPrecision(value interface{}, precision int) string {
+	switch v := value.(type) {
+	case json.Number:
+		return string(v)
+	case float32:
+		return formatFloat(float64(v), 32, precision)
+	case float64:
+		return formatFloat(v, 64, precision)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatFloat(f float64, bits int, precision int) string {
+	abs := math.Abs(f)
+
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case abs >= 1e21:
+		return strconv.FormatFloat(f, 'g', -1, bits)
+	case f == math.Trunc(f):
+		return strconv.FormatFloat(f, 'f', 0, bits)
+	case precision > 0:
+		s := strconv.FormatFloat(f, 'f', precision, bits)
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		if s != "0" && s != "-0" {
+			return s
+		}
+		return strconv.FormatFloat(f, 'g', precision+1, bits)
+	case abs < 1e-6:
+		return strconv.FormatFloat(f, 'g', -1, bits)
+	default:
+		return strconv.FormatFloat(f, 'f', -1, bits)
+	}
+}
+
+// failType reports that value has unexpected JSON type. Failure includes
+// expected type, actual type, and truncated rendering of actual value,
+// e.g. "expected: string, actual: number (42)".
+func failType(chain *chain, expected string, value interface{}) {
+	chain.fail("\nunexpected value type:\n expected: %s, actual: %s",
+		expected, describeValue(value))
+}
+
+// maxDescribedValue is maximum length of value rendering in describeValue.
+const maxDescribedValue = 64
+
+// describeValue returns JSON type name of value followed by its compact
+// rendering, truncated to maxDescribedValue bytes.
+func describeValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	var s string
+	if b, err := json.Marshal(value); err == nil {
+		s = string(b)
+	} else {
+		s = fmt.Sprintf("%#v", value)
+	}
+	if len(s) > maxDescribedValue {
+		s = s[:maxDescribedValue] + "..."
+	}
+	return fmt.Sprintf("%s (%s)", jsonTypeName(value), s)
+}
+
 func dumpValue(value interface{}) string {
-	b, err := json.MarshalIndent(value, " ", "  ")
-	if err != nil {
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, value, " "); err != nil {
 		return " " + fmt.Sprintf("%#v", value)
 	}
-	return " " + string(b)
+	return " " + buf.String()
+}
+
+// CanonicalJSON returns indented JSON representation of value, using the
+// same stable encoding as failure messages, e.g. for fixtures and snapshots.
+//
+// Object keys are sorted at every level, numbers are formatted consistently
+// regardless of their Go type, and HTML characters are not escaped. Values
+// are converted to JSON using encoding/json first, so json.Marshaler and
+// "json" struct tags are honored. Error is returned if value can't be
+// encoded, e.g. if it contains NaN or ±Inf.
+//
+// Example:
+//  s, err := httpexpect.CanonicalJSON(map[string]interface{}{"b": 1, "a": 2})
+//  // s is "{\n  \"a\": 2,\n  \"b\": 1\n}"
+func CanonicalJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(addressableMarshaler(value))
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := writeCanonicalJSON(&buf, decoded, ""); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeCanonicalJSON writes indented JSON representation of value, so that
+// the same value always produces byte-identical output.
+//
+// Object keys are sorted at every level, including objects nested into
+// arrays, numbers are formatted consistently regardless of their Go type,
+// non-finite numbers are written as NaN, +Inf, and -Inf, and HTML characters
+// are not escaped. Values of other types are first converted to JSON using
+// encoding/json.
+func writeCanonicalJSON(buf *bytes.Buffer, value interface{}, indent string) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+
+	case string:
+		return writeCanonicalString(buf, v)
+
+	case json.Number:
+		buf.WriteString(string(v))
+
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			buf.WriteString(formatNumber(v))
+			return nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[")
+		for i, elem := range v {
+			if i != 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n" + indent + "  ")
+			if err := writeCanonicalJSON(buf, elem, indent+"  "); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("\n" + indent + "]")
+
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{")
+		for i, key := range sortedKeys(v) {
+			if i != 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n" + indent + "  ")
+			if err := writeCanonicalString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := writeCanonicalJSON(buf, v[key], indent+"  "); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("\n" + indent + "}")
+
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var decoded interface{}
+		if err := dec.Decode(&decoded); err != nil {
+			return err
+		}
+		return writeCanonicalJSON(buf, decoded, indent)
+	}
+
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+	return nil
 }
 
 func diffValues(expected, actual interface{}) string {
@@ -182,3 +860,360 @@ func diffValues(expected, actual interface{}) string {
 
 	return "--- expected\n+++ actual\n" + str
 }
+
+// Ignore is a placeholder that may be used in expected values passed to
+// Response.JSONStrict and Value.EqualStrict to skip comparison of volatile
+// fields, like timestamps or generated identifiers.
+//
+// Ignore matches any actual value, but the key or element holding it should
+// still be present.
+//
+// Example:
+//  resp.JSONStrict(map[string]interface{}{
+//      "id":   httpexpect.Ignore,
+//      "name": "john",
+//  })
+var Ignore = ignoreValue{}
+
+const ignoreMarker = "\x00httpexpect:ignore\x00"
+
+type ignoreValue struct{}
+
+// MarshalJSON implements json.Marshaler.
+//
+// Ignore is encoded as a special marker string, which is preserved during
+// canonicalization and recognized by strict comparison.
+func (ignoreValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ignoreMarker)
+}
+
+func checkStrict(chain *chain, actual, value interface{}) {
+	if chain.failed() {
+		return
+	}
+
+	expected, ok := canonValue(chain, value)
+	if !ok {
+		return
+	}
+
+	var diff strictDiff
+	compareStrict(&diff, expected, actual, "")
+
+	if !diff.empty() {
+		chain.fail("\nexpected value strictly equal to:\n%s\n\nbut got:\n%s\n\n%s",
+			dumpValue(expected),
+			dumpValue(actual),
+			diff.String())
+	}
+}
+
+type strictDiff struct {
+	extra    []string
+	missing  []string
+	mismatch []string
+}
+
+func (d *strictDiff) empty() bool {
+	return len(d.extra) == 0 && len(d.missing) == 0 && len(d.mismatch) == 0
+}
+
+func (d *strictDiff) String() string {
+	var sections []string
+	if len(d.extra) != 0 {
+		sections = append(sections,
+			"unexpected keys:\n"+dumpValue(d.extra))
+	}
+	if len(d.missing) != 0 {
+		sections = append(sections,
+			"missing keys:\n"+dumpValue(d.missing))
+	}
+	if len(d.mismatch) != 0 {
+		sections = append(sections,
+			"mismatched values:\n"+dumpValue(d.mismatch))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// compareStrict compares canonical expected and actual values and records
+// every actual key absent from expected, every expected key absent from
+// actual, and every mismatched value. Expected values equal to Ignore
+// match anything.
+func compareStrict(diff *strictDiff, expected, actual interface{}, path string) {
+	if s, ok := expected.(string); ok && s == ignoreMarker {
+		return
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			diff.mismatch = append(diff.mismatch, strictPath(path))
+			return
+		}
+		for _, k := range sortedKeys(act) {
+			if _, ok := exp[k]; !ok {
+				diff.extra = append(diff.extra, joinPath(path, k))
+			}
+		}
+		for _, k := range sortedKeys(exp) {
+			if _, ok := act[k]; !ok {
+				diff.missing = append(diff.missing, joinPath(path, k))
+				continue
+			}
+			compareStrict(diff, exp[k], act[k], joinPath(path, k))
+		}
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok || len(act) != len(exp) {
+			diff.mismatch = append(diff.mismatch, strictPath(path))
+			return
+		}
+		for i := range exp {
+			compareStrict(diff, exp[i], act[i], fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			diff.mismatch = append(diff.mismatch, strictPath(path))
+		}
+	}
+}
+
+// Comparator is a custom equality function used by Value.EqualWith and
+// Object.EqualWith.
+//
+// Comparator is invoked for every leaf (non-object and non-array) value of
+// expected value, with path to that value (e.g. "items[2].created") and
+// both values in canonical form. If handled is false, default equality is
+// used for the leaf; otherwise, equal defines the result.
+//
+// Integers in expected value that can't be represented exactly as float64
+// (e.g. large int64 values) are passed as json.Number instead of float64.
+type Comparator func(path string, expected, actual interface{}) (handled, equal bool)
+
+// NumericStringEquality is a Comparator that treats a JSON string consisting
+// solely of a decimal integer as equal to a number of the same value, in both
+// directions, e.g. "123" and 123. This matches proto3 JSON mapping, which
+// encodes int64 fields as strings.
+//
+// Integers are compared exactly, using big.Int. Other leaves are compared
+// using default equality.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"id": "9007199254740993"})
+//  object.EqualWith(map[string]interface{}{
+//      "id": int64(9007199254740993),
+//  }, NumericStringEquality)
+func NumericStringEquality(path string, expected, actual interface{}) (bool, bool) {
+	_, expectedString := expected.(string)
+	_, actualString := actual.(string)
+	if expectedString == actualString {
+		return false, false
+	}
+
+	e, ok := exactInteger(expected)
+	if !ok {
+		return false, false
+	}
+	a, ok := exactInteger(actual)
+	if !ok {
+		return false, false
+	}
+
+	return true, e.Cmp(a) == 0
+}
+
+var integerRegexp = regexp.MustCompile(`^-?[0-9]+$`)
+
+// exactInteger converts integer number or string consisting solely of
+// decimal integer to big.Int.
+func exactInteger(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case string:
+		if !integerRegexp.MatchString(v) {
+			return nil, false
+		}
+		return new(big.Int).SetString(v, 10)
+	case json.Number:
+		return new(big.Int).SetString(v.String(), 10)
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) || v != math.Trunc(v) {
+			return nil, false
+		}
+		i, _ := big.NewFloat(v).Int(nil)
+		return i, true
+	default:
+		return nil, false
+	}
+}
+
+// canonValueExact is like canonValue, but preserves integers that can't be
+// represented exactly as float64 as json.Number.
+func canonValueExact(chain *chain, in interface{}) (interface{}, bool) {
+	b, err := json.Marshal(addressableMarshaler(in))
+	if err != nil {
+		chain.failUsage("\nunexpected value of type %T that can't be encoded as JSON:\n %s",
+			in, err.Error())
+		return nil, false
+	}
+
+	var out interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err := dec.Decode(&out); err != nil {
+		chain.failUsage("\nunexpected value of type %T that can't be decoded from JSON:\n %s",
+			in, err.Error())
+		return nil, false
+	}
+
+	return convertNumbers(out), true
+}
+
+// convertNumbers converts json.Number values to float64, except integers
+// that can't be represented exactly as float64.
+func convertNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = convertNumbers(v[k])
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = convertNumbers(v[i])
+		}
+		return v
+	case json.Number:
+		f, _ := v.Float64()
+		if i, ok := new(big.Int).SetString(v.String(), 10); ok {
+			if exact, _ := big.NewFloat(f).Int(nil); exact.Cmp(i) != 0 {
+				return v
+			}
+		}
+		return f
+	default:
+		return v
+	}
+}
+
+// numbersEqual compares json.Number with canonical value exactly.
+func numbersEqual(expected json.Number, actual interface{}) bool {
+	e, ok := exactInteger(expected)
+	if !ok {
+		return false
+	}
+	if _, ok := actual.(float64); !ok {
+		return false
+	}
+	a, ok := exactInteger(actual)
+	return ok && e.Cmp(a) == 0
+}
+
+func checkWith(chain *chain, actual, value interface{}, cmp Comparator) {
+	if chain.failed() {
+		return
+	}
+
+	expected, ok := canonValueExact(chain, value)
+	if !ok {
+		return
+	}
+
+	var mismatches []string
+	compareWith(&mismatches, cmp, expected, actual, "")
+
+	if len(mismatches) != 0 {
+		chain.fail("\nexpected value equal to:\n%s\n\nbut got:\n%s\n\n"+
+			"mismatched values:\n%s",
+			dumpValue(expected),
+			dumpValue(actual),
+			strings.Join(mismatches, "\n"))
+	}
+}
+
+// compareWith compares canonical expected and actual values and records
+// every mismatch with its path. Leaf values are first passed to cmp.
+func compareWith(
+	mismatches *[]string, cmp Comparator, expected, actual interface{}, path string,
+) {
+	record := func(path, reason string, expected, actual interface{}) {
+		*mismatches = append(*mismatches,
+			fmt.Sprintf(" %s (%s):\n  expected: %s\n  actual: %s",
+				strictPath(path), reason,
+				strings.TrimSpace(dumpValue(expected)),
+				strings.TrimSpace(dumpValue(actual))))
+	}
+	mismatch := func(reason string) {
+		record(path, reason, expected, actual)
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			mismatch("type mismatch")
+			return
+		}
+		for _, k := range sortedKeys(act) {
+			if _, ok := exp[k]; !ok {
+				record(joinPath(path, k), "unexpected key", nil, act[k])
+			}
+		}
+		for _, k := range sortedKeys(exp) {
+			if _, ok := act[k]; !ok {
+				record(joinPath(path, k), "missing key", exp[k], nil)
+				continue
+			}
+			compareWith(mismatches, cmp, exp[k], act[k], joinPath(path, k))
+		}
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok || len(act) != len(exp) {
+			mismatch("length mismatch")
+			return
+		}
+		for i := range exp {
+			compareWith(mismatches, cmp, exp[i], act[i], fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	default:
+		if cmp != nil {
+			if handled, equal := cmp(path, expected, actual); handled {
+				if !equal {
+					mismatch("comparator")
+				}
+				return
+			}
+		}
+		if n, ok := expected.(json.Number); ok {
+			if !numbersEqual(n, actual) {
+				mismatch("not equal")
+			}
+			return
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			mismatch("not equal")
+		}
+	}
+}
+
+func strictPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,7 +1,13 @@
 package httpexpect
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -42,6 +48,91 @@ func TestCanonNumber(t *testing.T) {
 	chain.reset()
 }
 
+type testUUID [4]byte
+
+func (u testUUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%x-%x-%x-%x", u[0], u[1], u[2], u[3]))
+}
+
+type testDecimal struct {
+	units int64
+	scale int
+}
+
+func (d *testDecimal) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%0*d", d.units/100, d.scale, d.units%100)), nil
+}
+
+type testBrokenMarshaler struct{}
+
+func (testBrokenMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("broken")
+}
+
+func TestCanonValue(t *testing.T) {
+	type myStruct struct {
+		ID       testUUID  `json:"id"`
+		Name     string    `json:"name"`
+		Comment  string    `json:"comment,omitempty"`
+		Tags     []string  `json:"tags,omitempty"`
+		Internal int       `json:"-"`
+		Created  time.Time `json:"created"`
+	}
+
+	reporter := newMockReporter(t)
+	chain := makeChain(reporter)
+
+	d1, ok := canonValue(&chain, testUUID{1, 2, 3, 4})
+	assert.True(t, ok)
+	assert.Equal(t, "1-2-3-4", d1)
+	chain.assertOK(t)
+	chain.reset()
+
+	d2, ok := canonValue(&chain, testDecimal{1234, 2})
+	assert.True(t, ok)
+	assert.Equal(t, "12.34", d2)
+	chain.assertOK(t)
+	chain.reset()
+
+	d3, ok := canonValue(&chain, &testDecimal{1234, 2})
+	assert.True(t, ok)
+	assert.Equal(t, "12.34", d3)
+	chain.assertOK(t)
+	chain.reset()
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	d4, ok := canonValue(&chain, myStruct{
+		ID:       testUUID{0xa, 0xb, 0xc, 0xd},
+		Name:     "foo",
+		Internal: 123,
+		Created:  created,
+	})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"id":      "a-b-c-d",
+		"name":    "foo",
+		"created": "2020-01-02T03:04:05Z",
+	}, d4)
+	chain.assertOK(t)
+	chain.reset()
+
+	_, ok = canonValue(&chain, testBrokenMarshaler{})
+	assert.False(t, ok)
+	chain.assertFailed(t)
+	chain.reset()
+
+	assert.Contains(t, reporter.message, "httpexpect.testBrokenMarshaler")
+	assert.Contains(t, reporter.message, "broken")
+
+	_, ok = canonValue(&chain, math.NaN())
+	assert.False(t, ok)
+	chain.assertFailed(t)
+	chain.reset()
+
+	assert.Contains(t, reporter.message, "float64")
+}
+
 func TestCanonArray(t *testing.T) {
 	type (
 		myArray []interface{}
@@ -124,6 +215,155 @@ func TestCanonMap(t *testing.T) {
 	chain.reset()
 }
 
+func TestCanonParity(t *testing.T) {
+	type tagged struct {
+		A int    `json:"a"`
+		B string `json:"b,omitempty"`
+		C string `json:"-"`
+	}
+
+	cases := []struct {
+		name     string
+		actual   interface{}
+		match    interface{}
+		mismatch interface{}
+	}{
+		{
+			name:     "struct with tags",
+			actual:   map[string]interface{}{"a": 1, "b": "x"},
+			match:    tagged{A: 1, B: "x", C: "ignored"},
+			mismatch: tagged{A: 2, B: "x"},
+		},
+		{
+			name:     "map[string]int",
+			actual:   map[string]interface{}{"a": 1, "b": 2},
+			match:    map[string]int{"a": 1, "b": 2},
+			mismatch: map[string]int{"a": 1, "b": 3},
+		},
+		{
+			name:     "[]int",
+			actual:   []interface{}{1, 2, 3},
+			match:    []int{1, 2, 3},
+			mismatch: []int{1, 2, 4},
+		},
+		{
+			name:     "raw object",
+			actual:   map[string]interface{}{"a": 1, "b": "x"},
+			match:    json.RawMessage(`{"b":"x","a":1.0}`),
+			mismatch: json.RawMessage(`{"a":1,"b":"y"}`),
+		},
+		{
+			name:     "raw array",
+			actual:   []interface{}{1, "x"},
+			match:    json.RawMessage(`[1, "x"]`),
+			mismatch: json.RawMessage(`["x", 1]`),
+		},
+	}
+
+	type form struct {
+		name     string
+		positive func(r Reporter, actual, expected interface{}) chain
+		negative func(r Reporter, actual, expected interface{}) chain
+	}
+
+	forms := []form{
+		{
+			name: "Value.Equal",
+			positive: func(r Reporter, actual, expected interface{}) chain {
+				return NewValue(r, actual).Equal(expected).chain
+			},
+			negative: func(r Reporter, actual, expected interface{}) chain {
+				return NewValue(r, actual).NotEqual(expected).chain
+			},
+		},
+		{
+			name: "Object.ValueEqual",
+			positive: func(r Reporter, actual, expected interface{}) chain {
+				return NewObject(r, map[string]interface{}{"k": actual}).
+					ValueEqual("k", expected).chain
+			},
+			negative: func(r Reporter, actual, expected interface{}) chain {
+				return NewObject(r, map[string]interface{}{"k": actual}).
+					ValueNotEqual("k", expected).chain
+			},
+		},
+		{
+			name: "Array.Contains",
+			positive: func(r Reporter, actual, expected interface{}) chain {
+				return NewArray(r, []interface{}{actual}).Contains(expected).chain
+			},
+			negative: func(r Reporter, actual, expected interface{}) chain {
+				return NewArray(r, []interface{}{actual}).NotContains(expected).chain
+			},
+		},
+	}
+
+	objectForms := []form{
+		{
+			name: "Object.Equal",
+			positive: func(r Reporter, actual, expected interface{}) chain {
+				return NewObject(r, actual.(map[string]interface{})).
+					Equal(expected).chain
+			},
+			negative: func(r Reporter, actual, expected interface{}) chain {
+				return NewObject(r, actual.(map[string]interface{})).
+					NotEqual(expected).chain
+			},
+		},
+		{
+			name: "Object.ContainsMap",
+			positive: func(r Reporter, actual, expected interface{}) chain {
+				return NewObject(r, actual.(map[string]interface{})).
+					ContainsMap(expected).chain
+			},
+			negative: func(r Reporter, actual, expected interface{}) chain {
+				return NewObject(r, actual.(map[string]interface{})).
+					NotContainsMap(expected).chain
+			},
+		},
+	}
+
+	arrayForms := []form{
+		{
+			name: "Array.Equal",
+			positive: func(r Reporter, actual, expected interface{}) chain {
+				return NewArray(r, actual.([]interface{})).Equal(expected).chain
+			},
+			negative: func(r Reporter, actual, expected interface{}) chain {
+				return NewArray(r, actual.([]interface{})).NotEqual(expected).chain
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		caseForms := forms
+		switch tc.actual.(type) {
+		case map[string]interface{}:
+			caseForms = append(caseForms, objectForms...)
+		case []interface{}:
+			caseForms = append(caseForms, arrayForms...)
+		}
+
+		for _, f := range caseForms {
+			t.Run(tc.name+"/"+f.name, func(t *testing.T) {
+				reporter := newMockReporter(t)
+
+				c := f.positive(reporter, tc.actual, tc.match)
+				c.assertOK(t)
+
+				c = f.negative(reporter, tc.actual, tc.match)
+				c.assertFailed(t)
+
+				c = f.positive(reporter, tc.actual, tc.mismatch)
+				c.assertFailed(t)
+
+				c = f.negative(reporter, tc.actual, tc.mismatch)
+				c.assertOK(t)
+			})
+		}
+	}
+}
+
 func TestDiffErrors(t *testing.T) {
 	na := " (unavailable)"
 
@@ -135,3 +375,307 @@ func TestDiffErrors(t *testing.T) {
 	assert.NotEqual(t, na, diffValues(map[string]interface{}{}, map[string]interface{}{}))
 	assert.NotEqual(t, na, diffValues([]interface{}{}, []interface{}{}))
 }
+
+func TestCompareStrict(t *testing.T) {
+	expected, _ := canonValue(&chain{}, map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": 2, "d": Ignore},
+		"e": []interface{}{map[string]interface{}{"f": 3}},
+		"g": 4,
+	})
+
+	actual, _ := canonValue(&chain{}, map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": 5, "d": "anything", "x": true},
+		"e": []interface{}{map[string]interface{}{"f": 3, "y": nil}},
+		"z": 6,
+	})
+
+	var diff strictDiff
+	compareStrict(&diff, expected, actual, "")
+
+	assert.Equal(t, []string{"z", "b.x", "e[0].y"}, diff.extra)
+	assert.Equal(t, []string{"g"}, diff.missing)
+	assert.Equal(t, []string{"b.c"}, diff.mismatch)
+
+	var rootDiff strictDiff
+	compareStrict(&rootDiff, expected, "foo", "")
+
+	assert.Equal(t, []string{"$"}, rootDiff.mismatch)
+}
+
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		value  interface{}
+		result string
+	}{
+		{float64(0), "0"},
+		{float64(3), "3"},
+		{float64(-3), "-3"},
+		{float64(1000000), "1000000"},
+		{float64(12345678), "12345678"},
+		{float64(1 << 53), "9007199254740992"},
+		{float64(0.1), "0.1"},
+		{float64(-2.5), "-2.5"},
+		{float64(123456789.5), "123456789.5"},
+		{float64(1.5e-7), "1.5e-07"},
+		{float64(1e21), "1e+21"},
+		{float64(1.5e300), "1.5e+300"},
+		{float32(0.25), "0.25"},
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "+Inf"},
+		{math.Inf(-1), "-Inf"},
+		{json.Number("12.50"), "12.50"},
+		{json.Number("1e3"), "1e3"},
+		{123, "123"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.result, formatNumber(tc.value), "%#v", tc.value)
+	}
+}
+
+func TestFormatNumberPrecision(t *testing.T) {
+	cases := []struct {
+		value  float64
+		result string
+	}{
+		{3, "3"},
+		{3.14159, "3.14"},
+		{2.5, "2.5"},
+		{-0.125, "-0.12"},
+		{0.001, "0.001"},
+		{123456.789, "123456.79"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.result, formatNumberPrecision(tc.value, 2), "%v", tc.value)
+	}
+
+	assert.Equal(t, "3.14159", formatNumberPrecision(3.14159, 0))
+	assert.Equal(t, "3.14159", formatNumberPrecision(3.14159, -1))
+}
+
+func TestDumpValueCanonical(t *testing.T) {
+	value := map[string]interface{}{
+		"b": []interface{}{
+			map[string]interface{}{"y": 1, "x": json.Number("2"), "z": nil},
+			map[string]interface{}{},
+			[]interface{}{},
+		},
+		"a": "<&>",
+		"c": map[string]interface{}{
+			"n": math.NaN(),
+			"i": int64(3),
+			"f": 1.5,
+			"t": true,
+		},
+	}
+
+	expected := ` {
+   "a": "<&>",
+   "b": [
+     {
+       "x": 2,
+       "y": 1,
+       "z": null
+     },
+     {},
+     []
+   ],
+   "c": {
+     "f": 1.5,
+     "i": 3,
+     "n": NaN,
+     "t": true
+   }
+ }`
+
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, expected, dumpValue(value))
+	}
+
+	type data struct {
+		B int `json:"b"`
+		A int `json:"a"`
+	}
+
+	assert.Equal(t, " {\n   \"a\": 2,\n   \"b\": 1\n }", dumpValue(data{B: 1, A: 2}))
+	assert.Equal(t, " 123", dumpValue(123))
+	assert.Equal(t, " 123", dumpValue(float32(123)))
+}
+
+func TestIsCanonical(t *testing.T) {
+	canonical := []interface{}{
+		nil,
+		true,
+		1.5,
+		"str",
+		[]interface{}{},
+		map[string]interface{}{},
+		[]interface{}{1.0, "a", map[string]interface{}{"b": []interface{}{nil}}},
+	}
+
+	for _, v := range canonical {
+		assert.True(t, isCanonical(v), "%#v", v)
+
+		c, ok := canonValue(&chain{}, v)
+		assert.True(t, ok)
+		assert.Equal(t, c, v)
+	}
+
+	notCanonical := []interface{}{
+		1,
+		int64(1),
+		float32(1),
+		math.NaN(),
+		math.Inf(1),
+		"\xff",
+		[]interface{}(nil),
+		map[string]interface{}(nil),
+		[]string{"a"},
+		[]interface{}{1.0, 2},
+		map[string]interface{}{"a": []interface{}{uint(1)}},
+		map[string]interface{}{"\xff": 1.0},
+		json.Number("1"),
+	}
+
+	for _, v := range notCanonical {
+		assert.False(t, isCanonical(v), "%#v", v)
+	}
+}
+
+func TestEqualValues(t *testing.T) {
+	values := []interface{}{
+		nil,
+		true,
+		false,
+		0.0,
+		1.0,
+		"",
+		"a",
+		[]interface{}{},
+		[]interface{}{1.0},
+		[]interface{}{1.0, "a"},
+		[]interface{}{"a", 1.0},
+		[]interface{}{[]interface{}{nil}},
+		map[string]interface{}{},
+		map[string]interface{}{"a": 1.0},
+		map[string]interface{}{"a": 2.0},
+		map[string]interface{}{"b": 1.0},
+		map[string]interface{}{"a": 1.0, "b": 1.0},
+		map[string]interface{}{"a": map[string]interface{}{"b": nil}},
+		json.Number("9007199254740993"),
+		json.Number("9007199254740995"),
+	}
+
+	for _, a := range values {
+		for _, b := range values {
+			assert.Equal(t, reflect.DeepEqual(a, b), equalValues(a, b),
+				"%#v vs %#v", a, b)
+		}
+	}
+
+	assert.True(t, equalValues(
+		[]interface{}{map[string]interface{}{"a": []interface{}{1.0, "x"}}},
+		[]interface{}{map[string]interface{}{"a": []interface{}{1.0, "x"}}}))
+}
+
+func TestDiffPath(t *testing.T) {
+	cases := []struct {
+		expected interface{}
+		actual   interface{}
+		path     string
+	}{
+		{1.0, 2.0, ""},
+		{1.0, "1", ""},
+		{[]interface{}{1.0}, []interface{}{1.0, 2.0}, ""},
+		{[]interface{}{1.0, 2.0}, []interface{}{1.0, 3.0}, "[1]"},
+		{
+			[]interface{}{1.0, map[string]interface{}{"a": 1.0, "b": 2.0}},
+			[]interface{}{1.0, map[string]interface{}{"a": 1.0, "b": 3.0}},
+			"[1].b",
+		},
+		{
+			map[string]interface{}{"z": 1.0, "a": []interface{}{[]interface{}{1.0}}},
+			map[string]interface{}{"z": 2.0, "a": []interface{}{[]interface{}{2.0}}},
+			"a[0][0]",
+		},
+		{
+			map[string]interface{}{"a": map[string]interface{}{"b": 1.0}},
+			map[string]interface{}{"a": map[string]interface{}{"c": 1.0}},
+			"a.b",
+		},
+		{
+			[]interface{}{1.0, 2.0},
+			[]interface{}{1.0, 2.0},
+			"",
+		},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.path, diffPath(tc.expected, tc.actual),
+			"%#v vs %#v", tc.expected, tc.actual)
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	type item struct {
+		Name  string  `json:"name"`
+		Price float32 `json:"price"`
+		Tags  []string
+	}
+
+	s, err := CanonicalJSON(map[string]interface{}{
+		"z":    1,
+		"a":    int64(2),
+		"item": item{Name: "<x>", Price: 1.5, Tags: []string{}},
+		"list": []interface{}{map[string]interface{}{"b": 1.0, "a": nil}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "a": 2,
+  "item": {
+    "Tags": [],
+    "name": "<x>",
+    "price": 1.5
+  },
+  "list": [
+    {
+      "a": null,
+      "b": 1
+    }
+  ],
+  "z": 1
+}`, s)
+
+	for i := 0; i < 20; i++ {
+		s2, err := CanonicalJSON(map[string]interface{}{
+			"z":    1.0,
+			"a":    2,
+			"item": map[string]interface{}{"price": 1.5, "name": "<x>", "Tags": nil},
+			"list": []map[string]int{},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+  "a": 2,
+  "item": {
+    "Tags": null,
+    "name": "<x>",
+    "price": 1.5
+  },
+  "list": [],
+  "z": 1
+}`, s2)
+	}
+
+	s, err = CanonicalJSON("str")
+	assert.NoError(t, err)
+	assert.Equal(t, `"str"`, s)
+
+	_, err = CanonicalJSON(math.NaN())
+	assert.Error(t, err)
+
+	_, err = CanonicalJSON(make(chan int))
+	assert.Error(t, err)
+}
@@ -31,7 +31,7 @@ type Match struct {
 //   m.Name("host").Equal("example.com")
 //   m.Name("user").Equal("john")
 func NewMatch(reporter Reporter, submatches []string, names []string) *Match {
-	return makeMatch(makeChain(reporter), submatches, names)
+	return makeMatch(makeChainFor("NewMatch", reporter), submatches, names)
 }
 
 func makeMatch(chain chain, submatches []string, names []string) *Match {
@@ -64,6 +64,7 @@ func (m *Match) Raw() []string {
 //  m := NewMatch(t, submatches, names)
 //  m.Length().Equal(len(submatches))
 func (m *Match) Length() *Number {
+	defer m.chain.leave(m.chain.failed())
 	return &Number{m.chain, float64(len(m.submatches))}
 }
 
@@ -83,6 +84,7 @@ func (m *Match) Length() *Number {
 //   m.Index(1).Equal("example.com")
 //   m.Index(2).Equal("john")
 func (m *Match) Index(index int) *String {
+	defer m.chain.leave(m.chain.failed())
 	if index < 0 || index >= len(m.submatches) {
 		m.chain.fail(
 			"\nsubmatch index out of bounds:\n  index %d\n\n  bounds [%d; %d)",
@@ -109,6 +111,7 @@ func (m *Match) Index(index int) *String {
 //   m.Name("host").Equal("example.com")
 //   m.Name("user").Equal("john")
 func (m *Match) Name(name string) *String {
+	defer m.chain.leave(m.chain.failed())
 	index, ok := m.names[name]
 	if !ok {
 		m.chain.fail(
@@ -126,6 +129,7 @@ func (m *Match) Name(name string) *String {
 //  m := NewMatch(t, submatches, names)
 //  m.Empty()
 func (m *Match) Empty() *Match {
+	defer m.chain.leave(m.chain.failed())
 	if len(m.submatches) != 0 {
 		m.chain.fail("\nexpected zero submatches, but got:\n  %s",
 			dumpValue(m.submatches))
@@ -139,6 +143,7 @@ func (m *Match) Empty() *Match {
 //  m := NewMatch(t, submatches, names)
 //  m.NotEmpty()
 func (m *Match) NotEmpty() *Match {
+	defer m.chain.leave(m.chain.failed())
 	if len(m.submatches) == 0 {
 		m.chain.fail("expected non-zero submatches")
 	}
@@ -157,6 +162,7 @@ func (m *Match) NotEmpty() *Match {
 //   m := NewMatch(t, r.FindStringSubmatch(s), nil)
 //   m.Values("example.com", "john")
 func (m *Match) Values(values ...string) *Match {
+	defer m.chain.leave(m.chain.failed())
 	if values == nil {
 		values = []string{}
 	}
@@ -180,6 +186,7 @@ func (m *Match) Values(values ...string) *Match {
 //   m := NewMatch(t, r.FindStringSubmatch(s), nil)
 //   m.NotValues("example.com", "bob")
 func (m *Match) NotValues(values ...string) *Match {
+	defer m.chain.leave(m.chain.failed())
 	if values == nil {
 		values = []string{}
 	}
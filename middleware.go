@@ -0,0 +1,95 @@
+package httpexpect
+
+// Middleware combines request builder and response matcher into one unit.
+//
+// It's useful for concerns that need both sides of a round trip, e.g.
+// request signing and response signature verification.
+//
+// See Expect.Use.
+type Middleware interface {
+	// BeforeRequest is invoked for a newly created request, after all
+	// builders attached to Expect instance.
+	BeforeRequest(*Request)
+
+	// AfterResponse is invoked for a newly received response, after all
+	// matchers attached to Expect instance.
+	AfterResponse(*Response)
+}
+
+// Use returns a copy of Expect instance with given middlewares attached
+// to it. Returned copy contains all previously attached middlewares plus
+// new ones.
+//
+// BeforeRequest methods are invoked in the order of attachment, and
+// AfterResponse methods are invoked in the reverse order, so that the
+// first attached middleware is the outermost one.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.com")
+//
+//  m := e.Use(httpexpect.RequestIDMiddleware{Header: "X-Request-Id"})
+//
+//  m.GET("/some-path").
+//      Expect().
+//      Status(http.StatusOK)
+func (e *Expect) Use(mw ...Middleware) *Expect {
+	ret := *e
+	ret.middlewares = append(append([]Middleware(nil), e.middlewares...), mw...)
+	return &ret
+}
+
+// RequestIDMiddleware is Middleware that sends a new request ID in request
+// header and checks that the same ID is echoed back in response header.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.com").
+//      Use(httpexpect.RequestIDMiddleware{Header: "X-Request-Id"})
+type RequestIDMiddleware struct {
+	// Header is the name of request and response header with request ID.
+	// Should not be empty.
+	Header string
+
+	// Func generates request IDs. If nil, NewRequestID is used.
+	Func func() string
+}
+
+// BeforeRequest implements Middleware.BeforeRequest.
+//
+// Sets header to a new request ID. If the header is already set, e.g. by
+// a builder, its value is kept.
+func (m RequestIDMiddleware) BeforeRequest(req *Request) {
+	if req.http == nil {
+		return
+	}
+	if m.Header == "" {
+		req.chain.failUsage("\nunexpected empty header in RequestIDMiddleware")
+		return
+	}
+	if req.http.Header.Get(m.Header) != "" {
+		return
+	}
+	fn := m.Func
+	if fn == nil {
+		fn = NewRequestID
+	}
+	req.WithHeader(m.Header, fn())
+}
+
+// AfterResponse implements Middleware.AfterResponse.
+//
+// Reports failure if response header is not equal to request ID sent in
+// request header.
+func (m RequestIDMiddleware) AfterResponse(resp *Response) {
+	if resp.resp == nil || resp.resp.Request == nil || m.Header == "" {
+		return
+	}
+	id := resp.resp.Request.Header.Get(m.Header)
+	if id == "" {
+		return
+	}
+	if actual := resp.resp.Header.Get(m.Header); actual != id {
+		resp.chain.fail(
+			"\nexpected response header %q echoing request ID:\n %q\n\nbut got:\n %q",
+			m.Header, id, actual)
+	}
+}
@@ -0,0 +1,127 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMiddleware struct {
+	name string
+	log  *[]string
+}
+
+func (m recordingMiddleware) BeforeRequest(req *Request) {
+	*m.log = append(*m.log, "before "+m.name)
+}
+
+func (m recordingMiddleware) AfterResponse(resp *Response) {
+	*m.log = append(*m.log, "after "+m.name)
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var log []string
+
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	})
+
+	e1 := e.Use(
+		recordingMiddleware{"a", &log},
+		recordingMiddleware{"b", &log},
+	)
+	e2 := e1.Use(recordingMiddleware{"c", &log})
+
+	e1 = e1.Builder(func(*Request) {
+		log = append(log, "builder")
+	}).Matcher(func(*Response) {
+		log = append(log, "matcher")
+	})
+
+	e.GET("/url").Expect().chain.assertOK(t)
+	assert.Empty(t, log)
+
+	e1.GET("/url").Expect().chain.assertOK(t)
+	assert.Equal(t, []string{
+		"builder", "before a", "before b", "matcher", "after b", "after a",
+	}, log)
+
+	log = nil
+
+	e2.GET("/url").Expect().chain.assertOK(t)
+	assert.Equal(t, []string{
+		"before a", "before b", "before c", "after c", "after b", "after a",
+	}, log)
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	echo := true
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if echo {
+			w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+		} else {
+			w.Header().Set("X-Request-Id", "other")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		Client: &http.Client{
+			Transport: NewBinder(handler),
+		},
+		Reporter: reporter,
+	})
+
+	t.Run("echoed", func(t *testing.T) {
+		m := e.Use(RequestIDMiddleware{
+			Header: "X-Request-Id",
+			Func:   func() string { return "abc" },
+		})
+
+		resp := m.GET("/").Expect()
+		resp.chain.assertOK(t)
+		resp.Header("X-Request-Id").Equal("abc")
+	})
+
+	t.Run("default func", func(t *testing.T) {
+		m := e.Use(RequestIDMiddleware{Header: "X-Request-Id"})
+
+		resp := m.GET("/").Expect()
+		resp.chain.assertOK(t)
+		resp.Header("X-Request-Id").Length().Equal(8)
+	})
+
+	t.Run("preset", func(t *testing.T) {
+		m := e.Use(RequestIDMiddleware{Header: "X-Request-Id"})
+
+		resp := m.GET("/").WithHeader("X-Request-Id", "preset").Expect()
+		resp.chain.assertOK(t)
+		resp.Header("X-Request-Id").Equal("preset")
+	})
+
+	t.Run("not echoed", func(t *testing.T) {
+		echo = false
+		defer func() { echo = true }()
+
+		m := e.Use(RequestIDMiddleware{
+			Header: "X-Request-Id",
+			Func:   func() string { return "abc" },
+		})
+
+		resp := m.GET("/").Expect()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, `"other"`)
+	})
+
+	t.Run("empty header", func(t *testing.T) {
+		m := e.Use(RequestIDMiddleware{})
+
+		req := m.GET("/")
+		req.chain.assertFailed(t)
+	})
+}
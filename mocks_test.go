@@ -1,8 +1,12 @@
 package httpexpect
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
+
+	"github.com/gorilla/websocket"
 )
 
 type mockClient struct {
@@ -24,13 +28,50 @@ func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
 type mockReporter struct {
 	testing  *testing.T
 	reported bool
+	message  string
 }
 
 func newMockReporter(t *testing.T) *mockReporter {
-	return &mockReporter{t, false}
+	return &mockReporter{t, false, ""}
 }
 
 func (r *mockReporter) Errorf(message string, args ...interface{}) {
 	r.testing.Logf("Fail: "+message, args...)
 	r.reported = true
+	r.message = fmt.Sprintf(message, args...)
+}
+
+// mockCollectingReporter collects all reported failures.
+type mockCollectingReporter struct {
+	messages []string
+}
+
+func (r *mockCollectingReporter) Errorf(message string, args ...interface{}) {
+	r.messages = append(r.messages, fmt.Sprintf(message, args...))
+}
+
+// firstLines returns first non-empty line of every collected message.
+func (r *mockCollectingReporter) firstLines() []string {
+	lines := make([]string, 0, len(r.messages))
+	for _, msg := range r.messages {
+		msg = strings.TrimSpace(msg)
+		lines = append(lines, strings.SplitN(msg, "\n", 2)[0])
+	}
+	return lines
+}
+
+type mockLogger struct {
+	logs []string
+}
+
+func (l *mockLogger) Logf(message string, args ...interface{}) {
+	l.logs = append(l.logs, fmt.Sprintf(message, args...))
+}
+
+type mockWebsocketDialer struct{}
+
+func (d *mockWebsocketDialer) Dial(
+	url string, reqH http.Header,
+) (*websocket.Conn, *http.Response, error) {
+	return nil, nil, websocket.ErrBadHandshake
 }
@@ -1,7 +1,11 @@
 package httpexpect
 
 import (
+	"encoding/json"
 	"math"
+	"reflect"
+	"strconv"
+	"time"
 )
 
 // Number provides methods to inspect attached float64 value
@@ -19,7 +23,7 @@ type Number struct {
 // Example:
 //  number := NewNumber(t, 123.4)
 func NewNumber(reporter Reporter, value float64) *Number {
-	return &Number{makeChain(reporter), value}
+	return &Number{makeChainFor("NewNumber", reporter), value}
 }
 
 // Raw returns underlying value attached to Number.
@@ -32,34 +36,258 @@ func (n *Number) Raw() float64 {
 	return n.value
 }
 
+// Store is similar to Value.Store.
+func (n *Number) Store(env *Environment, key string) *Number {
+	storeValue(&n.chain, env, key, n.value)
+	return n
+}
+
+// StoreTo saves number value into given variable.
+//
+// If a failure was already reported for this Number, the variable is
+// left unchanged.
+//
+// Example:
+//  var total float64
+//  resp.JSON().Object().Value("total").Number().StoreTo(&total)
+func (n *Number) StoreTo(dst *float64) *Number {
+	if n.chain.failed() {
+		return n
+	}
+	if dst == nil {
+		n.chain.failUsage("\nunexpected nil pointer in StoreTo")
+		return n
+	}
+	*dst = n.value
+	return n
+}
+
 // Path is similar to Value.Path.
 func (n *Number) Path(path string) *Value {
+	defer n.chain.leave(n.chain.failed())
 	return getPath(&n.chain, n.value, path)
 }
 
 // Schema is similar to Value.Schema.
 func (n *Number) Schema(schema interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	checkSchema(&n.chain, n.value, schema)
 	return n
 }
 
+// AsDuration returns a new Duration object that may be used to inspect
+// number as time.Duration, i.e. as integer count of nanoseconds.
+//
+// If number is not integer or doesn't fit into time.Duration, AsDuration
+// reports failure and returns empty (but non-nil) object.
+//
+// Example:
+//  number := NewNumber(t, 1500000000)
+//  number.AsDuration().Equal(1500 * time.Millisecond)
+func (n *Number) AsDuration() *Duration {
+	defer n.chain.leave(n.chain.failed())
+	if n.chain.failed() {
+		return &Duration{n.chain, nil}
+	}
+	if n.value != math.Trunc(n.value) ||
+		n.value < math.MinInt64 || n.value >= math.MaxInt64 {
+		n.chain.fail(
+			"\nexpected number representing integer count of nanoseconds,"+
+				" but got:\n %s", n.format(n.value))
+		return &Duration{n.chain, nil}
+	}
+	d := time.Duration(n.value)
+	return &Duration{n.chain, &d}
+}
+
+// AsDecimal returns a new Decimal object that may be used to compare number
+// with decimal values exactly.
+//
+// Number is converted using its shortest decimal representation, e.g. 10.5
+// is converted to exactly "10.5". Note that JSON decoding doesn't preserve
+// trailing zeros, so scale of 10.50 in JSON is 1; to check scale, encode
+// amount as string and use String.AsDecimal.
+//
+// If number is NaN or ±Inf, AsDecimal reports failure and returns empty (but
+// non-nil) object.
+//
+// Example:
+//  number := NewNumber(t, 10.5)
+//  number.AsDecimal().Equal("10.50")
+func (n *Number) AsDecimal() *Decimal {
+	defer n.chain.leave(n.chain.failed())
+	if n.chain.failed() {
+		return &Decimal{n.chain, nil, "", 0}
+	}
+	if math.IsNaN(n.value) || math.IsInf(n.value, 0) {
+		n.chain.fail("\nexpected finite number representing decimal, but got:\n %s",
+			n.format(n.value))
+		return &Decimal{n.chain, nil, "", 0}
+	}
+	return parseDecimal(&n.chain, strconv.FormatFloat(n.value, 'f', -1, 64))
+}
+
+// Decode converts number into given target and stores result there.
+//
+// target should be a non-nil pointer to int, int8, int16, int32, int64,
+// uint, uint8, uint16, uint32, uint64, float32, float64, or json.Number.
+//
+// If number doesn't fit into target type (it's out of range, it's negative
+// and target is unsigned, or it's fractional and target is integer), Decode
+// reports failure and leaves target unchanged.
+//
+// Example:
+//  var port uint16
+//  number := NewNumber(t, 8080)
+//  number.Decode(&port)
+func (n *Number) Decode(target interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
+	if n.chain.failed() {
+		return n
+	}
+	if rv := reflect.ValueOf(target); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		n.chain.failUsage("\nunexpected nil pointer in Decode:\n %T", target)
+		return n
+	}
+	switch t := target.(type) {
+	case *int:
+		if v, ok := n.decodeInt("int", strconv.IntSize); ok {
+			*t = int(v)
+		}
+	case *int8:
+		if v, ok := n.decodeInt("int8", 8); ok {
+			*t = int8(v)
+		}
+	case *int16:
+		if v, ok := n.decodeInt("int16", 16); ok {
+			*t = int16(v)
+		}
+	case *int32:
+		if v, ok := n.decodeInt("int32", 32); ok {
+			*t = int32(v)
+		}
+	case *int64:
+		if v, ok := n.decodeInt("int64", 64); ok {
+			*t = v
+		}
+	case *uint:
+		if v, ok := n.decodeUint("uint", strconv.IntSize); ok {
+			*t = uint(v)
+		}
+	case *uint8:
+		if v, ok := n.decodeUint("uint8", 8); ok {
+			*t = uint8(v)
+		}
+	case *uint16:
+		if v, ok := n.decodeUint("uint16", 16); ok {
+			*t = uint16(v)
+		}
+	case *uint32:
+		if v, ok := n.decodeUint("uint32", 32); ok {
+			*t = uint32(v)
+		}
+	case *uint64:
+		if v, ok := n.decodeUint("uint64", 64); ok {
+			*t = v
+		}
+	case *float32:
+		if math.Abs(n.value) > math.MaxFloat32 && !math.IsInf(n.value, 0) {
+			n.chain.fail(
+				"\nexpected number in range of float32 to decode into float32,"+
+					" but got:\n %s", n.format(n.value))
+			return n
+		}
+		*t = float32(n.value)
+	case *float64:
+		*t = n.value
+	case *json.Number:
+		if math.IsNaN(n.value) || math.IsInf(n.value, 0) {
+			n.chain.fail(
+				"\nexpected finite number to decode into json.Number, but got:\n %s",
+				n.format(n.value))
+			return n
+		}
+		*t = json.Number(strconv.FormatFloat(n.value, 'g', -1, 64))
+	default:
+		n.chain.failUsage(
+			"\nunexpected target in Decode:\n %T\n\n"+
+				"(expected non-nil pointer to integer, float or json.Number)",
+			target)
+	}
+	return n
+}
+
+func (n *Number) decodeInt(typ string, bits int) (int64, bool) {
+	if !n.decodeInteger(typ) {
+		return 0, false
+	}
+	if n.value < -math.Ldexp(1, bits-1) || n.value >= math.Ldexp(1, bits-1) {
+		n.chain.fail(
+			"\nexpected number in range [%d; %d] to decode into %s,"+
+				" but got:\n %s",
+			int64(math.MinInt64)>>(64-bits), int64(math.MaxInt64)>>(64-bits),
+			typ, n.format(n.value))
+		return 0, false
+	}
+	return int64(n.value), true
+}
+
+func (n *Number) decodeUint(typ string, bits int) (uint64, bool) {
+	if !n.decodeInteger(typ) {
+		return 0, false
+	}
+	if n.value < 0 {
+		n.chain.fail(
+			"\nexpected non-negative number to decode into %s, but got:\n %s",
+			typ, n.format(n.value))
+		return 0, false
+	}
+	if n.value >= math.Ldexp(1, bits) {
+		n.chain.fail(
+			"\nexpected number in range [0; %d] to decode into %s,"+
+				" but got:\n %s",
+			uint64(math.MaxUint64)>>(64-bits), typ, n.format(n.value))
+		return 0, false
+	}
+	return uint64(n.value), true
+}
+
+func (n *Number) decodeInteger(typ string) bool {
+	if math.IsNaN(n.value) || math.IsInf(n.value, 0) ||
+		n.value != math.Trunc(n.value) {
+		n.chain.fail(
+			"\nexpected integer number to decode into %s, but got:\n %s",
+			typ, n.format(n.value))
+		return false
+	}
+	return true
+}
+
 // Equal succeeds if number is equal to given value.
 //
 // value should have numeric type convertible to float64. Before comparison,
 // it is converted to float64.
 //
+// If number or value is NaN or ±Inf, failure is reported regardless of
+// comparison result. Use IsNaN and NotNaN to check for NaN explicitly.
+//
 // Example:
 //  number := NewNumber(t, 123)
 //  number.Equal(float64(123))
 //  number.Equal(int32(123))
 func (n *Number) Equal(value interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	v, ok := canonNumber(&n.chain, value)
 	if !ok {
 		return n
 	}
+	if !n.checkFinite(v) {
+		return n
+	}
 	if !(n.value == v) {
-		n.chain.fail("\nexpected number equal to:\n %v\n\nbut got:\n %v",
-			v, n.value)
+		n.chain.failExpected(v, n.value,
+			"\nexpected number equal to:\n %s\n\nbut got:\n %s",
+			n.format(v), n.format(n.value))
 	}
 	return n
 }
@@ -74,13 +302,14 @@ func (n *Number) Equal(value interface{}) *Number {
 //  number.NotEqual(float64(321))
 //  number.NotEqual(int32(321))
 func (n *Number) NotEqual(value interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	v, ok := canonNumber(&n.chain, value)
 	if !ok {
 		return n
 	}
 	if !(n.value != v) {
-		n.chain.fail("\nexpected number not equal to:\n %v\n\nbut got:\n %v",
-			v, n.value)
+		n.chain.fail("\nexpected number not equal to:\n %s\n\nbut got:\n %s",
+			n.format(v), n.format(n.value))
 	}
 	return n
 }
@@ -91,17 +320,18 @@ func (n *Number) NotEqual(value interface{}) *Number {
 //  number := NewNumber(t, 123.0)
 //  number.EqualDelta(123.2, 0.3)
 func (n *Number) EqualDelta(value, delta float64) *Number {
+	defer n.chain.leave(n.chain.failed())
 	if math.IsNaN(n.value) || math.IsNaN(value) || math.IsNaN(delta) {
-		n.chain.fail("\nexpected number equal to:\n %v\n\nbut got:\n %v\n\ndelta:\n %v",
-			value, n.value, delta)
+		n.chain.fail("\nexpected number equal to:\n %s\n\nbut got:\n %s\n\ndelta:\n %s",
+			n.format(value), n.format(n.value), n.format(delta))
 		return n
 	}
 
 	diff := (n.value - value)
 
 	if diff < -delta || diff > delta {
-		n.chain.fail("\nexpected number equal to:\n %v\n\nbut got:\n %v\n\ndelta:\n %v",
-			value, n.value, delta)
+		n.chain.fail("\nexpected number equal to:\n %s\n\nbut got:\n %s\n\ndelta:\n %s",
+			n.format(value), n.format(n.value), n.format(delta))
 		return n
 	}
 
@@ -114,10 +344,11 @@ func (n *Number) EqualDelta(value, delta float64) *Number {
 //  number := NewNumber(t, 123.0)
 //  number.NotEqualDelta(123.2, 0.1)
 func (n *Number) NotEqualDelta(value, delta float64) *Number {
+	defer n.chain.leave(n.chain.failed())
 	if math.IsNaN(n.value) || math.IsNaN(value) || math.IsNaN(delta) {
 		n.chain.fail(
-			"\nexpected number not equal to:\n %v\n\nbut got:\n %v\n\ndelta:\n %v",
-			value, n.value, delta)
+			"\nexpected number not equal to:\n %s\n\nbut got:\n %s\n\ndelta:\n %s",
+			n.format(value), n.format(n.value), n.format(delta))
 		return n
 	}
 
@@ -125,8 +356,8 @@ func (n *Number) NotEqualDelta(value, delta float64) *Number {
 
 	if !(diff < -delta || diff > delta) {
 		n.chain.fail(
-			"\nexpected number not equal to:\n %v\n\nbut got:\n %v\n\ndelta:\n %v",
-			value, n.value, delta)
+			"\nexpected number not equal to:\n %s\n\nbut got:\n %s\n\ndelta:\n %s",
+			n.format(value), n.format(n.value), n.format(delta))
 		return n
 	}
 
@@ -138,18 +369,25 @@ func (n *Number) NotEqualDelta(value, delta float64) *Number {
 // value should have numeric type convertible to float64. Before comparison,
 // it is converted to float64.
 //
+// If number or value is NaN or ±Inf, failure is reported regardless of
+// comparison result. Use IsNaN and NotNaN to check for NaN explicitly.
+//
 // Example:
 //  number := NewNumber(t, 123)
 //  number.Gt(float64(122))
 //  number.Gt(int32(122))
 func (n *Number) Gt(value interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	v, ok := canonNumber(&n.chain, value)
 	if !ok {
 		return n
 	}
+	if !n.checkFinite(v) {
+		return n
+	}
 	if !(n.value > v) {
-		n.chain.fail("\nexpected number > then:\n %v\n\nbut got:\n %v",
-			v, n.value)
+		n.chain.fail("\nexpected number > then:\n %s\n\nbut got:\n %s",
+			n.format(v), n.format(n.value))
 	}
 	return n
 }
@@ -159,18 +397,25 @@ func (n *Number) Gt(value interface{}) *Number {
 // value should have numeric type convertible to float64. Before comparison,
 // it is converted to float64.
 //
+// If number or value is NaN or ±Inf, failure is reported regardless of
+// comparison result. Use IsNaN and NotNaN to check for NaN explicitly.
+//
 // Example:
 //  number := NewNumber(t, 123)
 //  number.Ge(float64(122))
 //  number.Ge(int32(122))
 func (n *Number) Ge(value interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	v, ok := canonNumber(&n.chain, value)
 	if !ok {
 		return n
 	}
+	if !n.checkFinite(v) {
+		return n
+	}
 	if !(n.value >= v) {
-		n.chain.fail("\nexpected number >= then:\n %v\n\nbut got:\n %v",
-			v, n.value)
+		n.chain.fail("\nexpected number >= then:\n %s\n\nbut got:\n %s",
+			n.format(v), n.format(n.value))
 	}
 	return n
 }
@@ -180,18 +425,25 @@ func (n *Number) Ge(value interface{}) *Number {
 // value should have numeric type convertible to float64. Before comparison,
 // it is converted to float64.
 //
+// If number or value is NaN or ±Inf, failure is reported regardless of
+// comparison result. Use IsNaN and NotNaN to check for NaN explicitly.
+//
 // Example:
 //  number := NewNumber(t, 123)
 //  number.Lt(float64(124))
 //  number.Lt(int32(124))
 func (n *Number) Lt(value interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	v, ok := canonNumber(&n.chain, value)
 	if !ok {
 		return n
 	}
+	if !n.checkFinite(v) {
+		return n
+	}
 	if !(n.value < v) {
-		n.chain.fail("\nexpected number < then:\n %v\n\nbut got:\n %v",
-			v, n.value)
+		n.chain.fail("\nexpected number < then:\n %s\n\nbut got:\n %s",
+			n.format(v), n.format(n.value))
 	}
 	return n
 }
@@ -201,18 +453,25 @@ func (n *Number) Lt(value interface{}) *Number {
 // value should have numeric type convertible to float64. Before comparison,
 // it is converted to float64.
 //
+// If number or value is NaN or ±Inf, failure is reported regardless of
+// comparison result. Use IsNaN and NotNaN to check for NaN explicitly.
+//
 // Example:
 //  number := NewNumber(t, 123)
 //  number.Le(float64(124))
 //  number.Le(int32(124))
 func (n *Number) Le(value interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	v, ok := canonNumber(&n.chain, value)
 	if !ok {
 		return n
 	}
+	if !n.checkFinite(v) {
+		return n
+	}
 	if !(n.value <= v) {
-		n.chain.fail("\nexpected number <= then:\n %v\n\nbut got:\n %v",
-			v, n.value)
+		n.chain.fail("\nexpected number <= then:\n %s\n\nbut got:\n %s",
+			n.format(v), n.format(n.value))
 	}
 	return n
 }
@@ -222,12 +481,16 @@ func (n *Number) Le(value interface{}) *Number {
 // min and max should have numeric type convertible to float64. Before comparison,
 // they are converted to float64.
 //
+// If number, min, or max is NaN or ±Inf, failure is reported regardless of
+// comparison result.
+//
 // Example:
 //  number := NewNumber(t, 123)
 //  number.InRange(float32(100), int32(200))  // success
 //  number.InRange(100, 200)                  // success
 //  number.InRange(123, 123)                  // success
 func (n *Number) InRange(min, max interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
 	a, ok := canonNumber(&n.chain, min)
 	if !ok {
 		return n
@@ -236,9 +499,117 @@ func (n *Number) InRange(min, max interface{}) *Number {
 	if !ok {
 		return n
 	}
+	if !n.checkFinite(a, b) {
+		return n
+	}
 	if !(n.value >= a && n.value <= b) {
-		n.chain.fail("\nexpected number in range:\n [%v; %v]\n\nbut got:\n %v",
-			a, b, n.value)
+		n.chain.failRange(a, b, n.value,
+			"\nexpected number in range:\n [%s; %s]\n\nbut got:\n %s",
+			n.format(a), n.format(b), n.format(n.value))
+	}
+	return n
+}
+
+// InRangeExclusive succeeds if number is in given range (min; max), excluding
+// min and max themselves.
+//
+// See InRangeOpts for details.
+//
+// Example:
+//  number := NewNumber(t, 0.5)
+//  number.InRangeExclusive(0, 1)    // success
+//  number.InRangeExclusive(0.5, 1)  // failure
+func (n *Number) InRangeExclusive(min, max interface{}) *Number {
+	defer n.chain.leave(n.chain.failed())
+	return n.InRangeOpts(min, max, Bounds{ExcludeMin: true, ExcludeMax: true})
+}
+
+// InRangeOpts succeeds if number is in given range, with inclusivity of min
+// and max defined by bounds.
+//
+// min and max should have numeric type convertible to float64. If min is
+// greater than max, failure is reported. If number, min, or max is NaN or
+// ±Inf, failure is reported regardless of comparison result.
+//
+// Example:
+//  number := NewNumber(t, 1)
+//  number.InRangeOpts(0, 1, httpexpect.Bounds{ExcludeMin: true})  // (0, 1]
+func (n *Number) InRangeOpts(min, max interface{}, bounds Bounds) *Number {
+	defer n.chain.leave(n.chain.failed())
+	a, ok := canonNumber(&n.chain, min)
+	if !ok {
+		return n
+	}
+	b, ok := canonNumber(&n.chain, max)
+	if !ok {
+		return n
+	}
+	if !n.checkFinite(a, b) {
+		return n
+	}
+	if a > b {
+		n.chain.failUsage("\nunexpected range with min greater than max"+
+			" passed to InRangeOpts:\n %s",
+			bounds.format(n.format(a), n.format(b)))
+		return n
+	}
+	if !bounds.contains(compareFloats(n.value, a), compareFloats(n.value, b)) {
+		n.chain.failRange(a, b, n.value,
+			"\nexpected number in range:\n %s\n\nbut got:\n %s",
+			bounds.format(n.format(a), n.format(b)), n.format(n.value))
+	}
+	return n
+}
+
+// IsNaN succeeds if number is NaN.
+//
+// Example:
+//  number := NewNumber(t, math.NaN())
+//  number.IsNaN()
+func (n *Number) IsNaN() *Number {
+	defer n.chain.leave(n.chain.failed())
+	if n.chain.failed() {
+		return n
+	}
+	if !math.IsNaN(n.value) {
+		n.chain.fail("\nexpected number being NaN, but got:\n %s",
+			n.format(n.value))
 	}
 	return n
 }
+
+// NotNaN succeeds if number is not NaN.
+//
+// Example:
+//  number := NewNumber(t, 123)
+//  number.NotNaN()
+func (n *Number) NotNaN() *Number {
+	defer n.chain.leave(n.chain.failed())
+	if n.chain.failed() {
+		return n
+	}
+	if math.IsNaN(n.value) {
+		n.chain.fail("\nexpected number not being NaN, but got:\n %s",
+			n.format(n.value))
+	}
+	return n
+}
+
+// checkFinite reports failure if number or any of given values is NaN
+// or ±Inf, since comparisons with such values are meaningless.
+func (n *Number) checkFinite(values ...float64) bool {
+	for _, v := range append([]float64{n.value}, values...) {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			n.chain.fail("\nunexpected non-finite value in number comparison:\n %s"+
+				"\n\nnumber:\n %s", n.format(v), n.format(n.value))
+			return false
+		}
+	}
+	return true
+}
+
+// format returns string representation of number for failure message,
+// using Config.FloatPrecision.
+func (n *Number) format(value interface{}) string {
+	return formatNumberPrecision(value, n.chain.precision)
+}
@@ -1,8 +1,10 @@
 package httpexpect
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +20,8 @@ func TestNumberFailed(t *testing.T) {
 
 	value.Path("$").chain.assertFailed(t)
 	value.Schema("")
+	value.AsDuration().chain.assertFailed(t)
+	value.Decode(new(int))
 
 	value.Equal(0)
 	value.NotEqual(0)
@@ -50,6 +54,31 @@ func TestNumberGetters(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestNumberAsDuration(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewNumber(reporter, 1500000000)
+	d1 := value1.AsDuration()
+	value1.chain.assertOK(t)
+	d1.chain.assertOK(t)
+	assert.Equal(t, 1500*time.Millisecond, d1.Raw())
+
+	value2 := NewNumber(reporter, 1.5)
+	d2 := value2.AsDuration()
+	value2.chain.assertFailed(t)
+	d2.chain.assertFailed(t)
+
+	value3 := NewNumber(reporter, math.Inf(1))
+	d3 := value3.AsDuration()
+	value3.chain.assertFailed(t)
+	d3.chain.assertFailed(t)
+
+	value4 := NewNumber(reporter, math.NaN())
+	d4 := value4.AsDuration()
+	value4.chain.assertFailed(t)
+	d4.chain.assertFailed(t)
+}
+
 func TestNumberEqual(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -74,6 +103,52 @@ func TestNumberEqual(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestNumberFailureFormat(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 12345678)
+
+	value.Equal(1000000)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "\n 1000000\n")
+	assert.Contains(t, reporter.message, "\n 12345678")
+	assert.NotContains(t, reporter.message, "e+")
+	value.chain.reset()
+
+	value.InRange(0.5, 1.25)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "[0.5; 1.25]")
+	value.chain.reset()
+
+	value = NewNumber(reporter, 3.14159)
+	value.chain.precision = 2
+
+	value.Equal(2.71828)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "\n 2.72\n")
+	assert.Contains(t, reporter.message, "\n 3.14")
+	value.chain.reset()
+}
+
+func TestNumberFailureFormatConfig(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       reporter,
+		FloatPrecision: 3,
+	}
+
+	resp := NewRequest(config, "GET", "url").
+		WithJSON(map[string]interface{}{"pi": 3.14159265}).
+		Expect()
+
+	resp.JSON().Object().Value("pi").Number().Equal(3).chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "\n 3.142")
+	assert.NotContains(t, reporter.message, "3.1415")
+}
+
 func TestNumberEqualDelta(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -148,6 +223,72 @@ func TestNumberEqualNaN(t *testing.T) {
 	v8.chain.assertFailed(t)
 }
 
+func TestNumberNonFinite(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	nan := math.NaN()
+	inf := math.Inf(1)
+	ninf := math.Inf(-1)
+
+	for _, v := range []float64{nan, inf, ninf} {
+		checks := []func(*Number){
+			func(n *Number) { n.Equal(v) },
+			func(n *Number) { n.Gt(v) },
+			func(n *Number) { n.Ge(v) },
+			func(n *Number) { n.Lt(v) },
+			func(n *Number) { n.Le(v) },
+			func(n *Number) { n.InRange(v, 1000) },
+			func(n *Number) { n.InRange(-1000, v) },
+		}
+		for _, check := range checks {
+			number := NewNumber(reporter, 1)
+			check(number)
+			number.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, "non-finite value")
+		}
+
+		for _, fn := range []func(*Number){
+			func(n *Number) { n.Equal(1) },
+			func(n *Number) { n.Equal(v) },
+			func(n *Number) { n.Gt(1) },
+			func(n *Number) { n.Ge(1) },
+			func(n *Number) { n.Lt(1) },
+			func(n *Number) { n.Le(1) },
+			func(n *Number) { n.InRange(ninf, inf) },
+		} {
+			number := NewNumber(reporter, v)
+			fn(number)
+			number.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, "non-finite value")
+		}
+	}
+}
+
+func TestNumberIsNaN(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewNumber(reporter, math.NaN()).IsNaN().chain.assertOK(t)
+	NewNumber(reporter, math.NaN()).NotNaN().chain.assertFailed(t)
+
+	NewNumber(reporter, 123).IsNaN().chain.assertFailed(t)
+	NewNumber(reporter, 123).NotNaN().chain.assertOK(t)
+
+	NewNumber(reporter, math.Inf(1)).IsNaN().chain.assertFailed(t)
+	NewNumber(reporter, math.Inf(1)).NotNaN().chain.assertOK(t)
+
+	// NaN survives Decode into float types
+	var f64 float64
+	NewNumber(reporter, math.NaN()).Decode(&f64).chain.assertOK(t)
+	NewNumber(reporter, f64).IsNaN().chain.assertOK(t)
+
+	var f32 float32
+	NewNumber(reporter, math.NaN()).Decode(&f32).chain.assertOK(t)
+	NewNumber(reporter, float64(f32)).IsNaN().Gt(0).chain.assertFailed(t)
+
+	// JSON can't contain NaN, so it's rejected during canonicalization
+	NewValue(reporter, math.NaN()).chain.assertFailed(t)
+}
+
 func TestNumberGreater(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -230,6 +371,56 @@ func TestNumberInRange(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestNumberInRangeOpts(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 1)
+
+	value.InRangeExclusive(0, 2)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeExclusive(1, 2)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRangeExclusive(0, 1)
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "(0, 1)")
+	value.chain.reset()
+
+	value.InRangeOpts(0, 1, Bounds{ExcludeMin: true})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeOpts(1, 2, Bounds{ExcludeMin: true})
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "(1, 2]")
+	value.chain.reset()
+
+	value.InRangeOpts(1, 2, Bounds{ExcludeMax: true})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeOpts(0, 1, Bounds{ExcludeMax: true})
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "[0, 1)")
+	value.chain.reset()
+
+	value.InRangeOpts(1, 1, Bounds{})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRangeOpts(2, 0, Bounds{})
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "min greater than max")
+	value.chain.reset()
+
+	value.InRangeOpts(0, math.Inf(1), Bounds{})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
 func TestNumberConvertEqual(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -337,3 +528,168 @@ func TestNumberConvertInRange(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestNumberDecodeInt(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	t.Run("int8", func(t *testing.T) {
+		var v int8
+
+		NewNumber(reporter, 127).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int8(127), v)
+
+		NewNumber(reporter, -128).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int8(-128), v)
+
+		NewNumber(reporter, 128).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -129).Decode(&v).chain.assertFailed(t)
+		assert.Equal(t, int8(-128), v)
+	})
+
+	t.Run("int16", func(t *testing.T) {
+		var v int16
+
+		NewNumber(reporter, 32767).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int16(32767), v)
+
+		NewNumber(reporter, -32768).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int16(-32768), v)
+
+		NewNumber(reporter, 32768).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -32769).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("int32", func(t *testing.T) {
+		var v int32
+
+		NewNumber(reporter, math.MaxInt32).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int32(math.MaxInt32), v)
+
+		NewNumber(reporter, math.MinInt32).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int32(math.MinInt32), v)
+
+		NewNumber(reporter, math.MaxInt32+1).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, math.MinInt32-1).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("int64", func(t *testing.T) {
+		var v int64
+
+		NewNumber(reporter, -(1 << 63)).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int64(math.MinInt64), v)
+
+		NewNumber(reporter, 1<<53).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, int64(1<<53), v)
+
+		NewNumber(reporter, 1<<63).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -(1<<63)*2).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("int", func(t *testing.T) {
+		var v int
+
+		NewNumber(reporter, -123).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, -123, v)
+
+		NewNumber(reporter, 1.5).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, math.NaN()).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, math.Inf(1)).Decode(&v).chain.assertFailed(t)
+		assert.Equal(t, -123, v)
+	})
+}
+
+func TestNumberDecodeUint(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	t.Run("uint8", func(t *testing.T) {
+		var v uint8
+
+		NewNumber(reporter, 255).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, uint8(255), v)
+
+		NewNumber(reporter, 0).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, uint8(0), v)
+
+		NewNumber(reporter, 256).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -1).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("uint16", func(t *testing.T) {
+		var v uint16
+
+		NewNumber(reporter, 65535).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, uint16(65535), v)
+
+		NewNumber(reporter, 65536).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -1).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("uint32", func(t *testing.T) {
+		var v uint32
+
+		NewNumber(reporter, math.MaxUint32).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, uint32(math.MaxUint32), v)
+
+		NewNumber(reporter, math.MaxUint32+1).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -1).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		var v uint64
+
+		NewNumber(reporter, 1<<63).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, uint64(1<<63), v)
+
+		NewNumber(reporter, 1<<64).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -1).Decode(&v).chain.assertFailed(t)
+	})
+
+	t.Run("uint", func(t *testing.T) {
+		var v uint
+
+		NewNumber(reporter, 123).Decode(&v).chain.assertOK(t)
+		assert.Equal(t, uint(123), v)
+
+		NewNumber(reporter, 0.5).Decode(&v).chain.assertFailed(t)
+		NewNumber(reporter, -0.5).Decode(&v).chain.assertFailed(t)
+	})
+}
+
+func TestNumberDecodeFloat(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	var v32 float32
+
+	NewNumber(reporter, 1.5).Decode(&v32).chain.assertOK(t)
+	assert.Equal(t, float32(1.5), v32)
+
+	NewNumber(reporter, math.MaxFloat32).Decode(&v32).chain.assertOK(t)
+	assert.Equal(t, float32(math.MaxFloat32), v32)
+
+	NewNumber(reporter, math.MaxFloat32*2).Decode(&v32).chain.assertFailed(t)
+	NewNumber(reporter, -math.MaxFloat32*2).Decode(&v32).chain.assertFailed(t)
+
+	var v64 float64
+
+	NewNumber(reporter, math.MaxFloat64).Decode(&v64).chain.assertOK(t)
+	assert.Equal(t, math.MaxFloat64, v64)
+
+	var vn json.Number
+
+	NewNumber(reporter, 123.5).Decode(&vn).chain.assertOK(t)
+	assert.Equal(t, json.Number("123.5"), vn)
+
+	NewNumber(reporter, math.NaN()).Decode(&vn).chain.assertFailed(t)
+}
+
+func TestNumberDecodeBadTarget(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	var s string
+	var p *int
+
+	NewNumber(reporter, 1).Decode(nil).chain.assertFailed(t)
+	NewNumber(reporter, 1).Decode(p).chain.assertFailed(t)
+	NewNumber(reporter, 1).Decode(1).chain.assertFailed(t)
+	NewNumber(reporter, 1).Decode(&s).chain.assertFailed(t)
+}
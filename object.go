@@ -1,6 +1,8 @@
 package httpexpect
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 )
 
@@ -20,7 +22,7 @@ type Object struct {
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 func NewObject(reporter Reporter, value map[string]interface{}) *Object {
-	chain := makeChain(reporter)
+	chain := makeChainFor("NewObject", reporter)
 	if value == nil {
 		chain.fail("expected non-nil map value")
 	} else {
@@ -41,23 +43,52 @@ func (o *Object) Raw() map[string]interface{} {
 
 // Path is similar to Value.Path.
 func (o *Object) Path(path string) *Value {
+	defer o.chain.leave(o.chain.failed())
 	return getPath(&o.chain, o.value, path)
 }
 
+// Pointer is similar to Value.Pointer.
+func (o *Object) Pointer(pointer string) *Value {
+	defer o.chain.leave(o.chain.failed())
+	return getPointer(&o.chain, o.value, pointer)
+}
+
 // Schema is similar to Value.Schema.
 func (o *Object) Schema(schema interface{}) *Object {
+	defer o.chain.leave(o.chain.failed())
 	checkSchema(&o.chain, o.value, schema)
 	return o
 }
 
+// SortedRaw returns underlying value attached to Object as a list of
+// key/value pairs sorted by key.
+//
+// Nested objects, including objects nested into arrays, are converted to
+// SortedMap too, so the result has the same order every time and may be
+// used for snapshots. SortedMap is encoded to JSON as an object, keeping
+// the order.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
+//  assert.Equal(t, httpexpect.SortedMap{
+//      {Key: "bar", Value: 456.0},
+//      {Key: "foo", Value: 123.0},
+//  }, object.SortedRaw())
+func (o *Object) SortedRaw() SortedMap {
+	return sortedMap(o.value)
+}
+
 // Keys returns a new Array object that may be used to inspect objects keys.
 //
+// Keys are sorted, so the order is the same every time.
+//
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
-//  object.Keys().ContainsOnly("foo", "bar")
+//  object.Keys().Elements("bar", "foo")
 func (o *Object) Keys() *Array {
+	defer o.chain.leave(o.chain.failed())
 	keys := []interface{}{}
-	for k := range o.value {
+	for _, k := range sortedKeys(o.value) {
 		keys = append(keys, k)
 	}
 	return &Array{o.chain, keys}
@@ -65,17 +96,108 @@ func (o *Object) Keys() *Array {
 
 // Values returns a new Array object that may be used to inspect objects values.
 //
+// Values are ordered by their keys, in the same order as returned by Keys.
+//
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
-//  object.Values().ContainsOnly(123, 456)
+//  object.Values().Elements(456, 123)
 func (o *Object) Values() *Array {
+	defer o.chain.leave(o.chain.failed())
 	values := []interface{}{}
-	for _, v := range o.value {
-		values = append(values, v)
+	for _, k := range sortedKeys(o.value) {
+		values = append(values, o.value[k])
 	}
 	return &Array{o.chain, values}
 }
 
+// Iter returns a new slice of key/value pairs attached to object fields,
+// sorted by key.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
+//
+//  for _, item := range object.Iter() {
+//      item.Value.Number().Gt(100)
+//  }
+func (o *Object) Iter() []ObjectItem {
+	if o.chain.failed() {
+		return []ObjectItem{}
+	}
+	ret := []ObjectItem{}
+	for _, k := range sortedKeys(o.value) {
+		ret = append(ret, ObjectItem{k, Value{o.chain.enter(k), o.value[k]}})
+	}
+	return ret
+}
+
+// ObjectItem is a key and value of object field, as returned by Object.Iter.
+type ObjectItem struct {
+	Key   string
+	Value Value
+}
+
+// SortedMap is a JSON object represented as a list of key/value pairs
+// sorted by key, as returned by Object.SortedRaw.
+type SortedMap []SortedMapEntry
+
+// SortedMapEntry is a key and value of SortedMap.
+type SortedMapEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalJSON implements json.Marshaler. SortedMap is encoded as JSON
+// object with keys in the same order as entries.
+func (m SortedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, e := range m {
+		if i != 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		value, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// sortedMap converts map to SortedMap, recursively converting nested maps.
+func sortedMap(m map[string]interface{}) SortedMap {
+	if m == nil {
+		return nil
+	}
+	ret := make(SortedMap, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		ret = append(ret, SortedMapEntry{k, sortedValue(m[k])})
+	}
+	return ret
+}
+
+func sortedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return sortedMap(v)
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i := range v {
+			ret[i] = sortedValue(v[i])
+		}
+		return ret
+	default:
+		return value
+	}
+}
+
 // Value returns a new Value object that may be used to inspect single value
 // for given key.
 //
@@ -83,13 +205,53 @@ func (o *Object) Values() *Array {
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.Value("foo").Number().Equal(123)
 func (o *Object) Value(key string) *Value {
+	defer o.chain.leave(o.chain.failed())
 	value, ok := o.value[key]
 	if !ok {
 		o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
 			key, dumpValue(o.value))
 		return &Value{o.chain, nil}
 	}
-	return &Value{o.chain, value}
+	return &Value{o.chain.enter(key), value}
+}
+
+// ValueOr returns a new Value object that may be used to inspect single
+// value for given key, or given default value if there is no such key.
+//
+// Unlike Value, ValueOr doesn't report failure if key is absent. Default
+// value is converted to canonical form, and the returned object reports
+// failures of subsequent assertions as usual.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123})
+//  object.ValueOr("foo", 0).Number().Equal(123)
+//  object.ValueOr("bar", 0).Number().Equal(0)
+func (o *Object) ValueOr(key string, def interface{}) *Value {
+	defer o.chain.leave(o.chain.failed())
+	if o.chain.failed() {
+		return &Value{o.chain, nil}
+	}
+	if value, ok := o.value[key]; ok {
+		return &Value{o.chain.enter(key), value}
+	}
+	value, ok := canonValue(&o.chain, def)
+	if !ok {
+		return &Value{o.chain, nil}
+	}
+	return &Value{o.chain.enter(key), value}
+}
+
+// HasKey returns true if object contains given key.
+//
+// HasKey never reports failure, so it may be used for branching in test flow.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123})
+//  if object.HasKey("next") {
+//      e.GET(object.Value("next").String().Raw()).Expect()
+//  }
+func (o *Object) HasKey(key string) bool {
+	return o.containsKey(key)
 }
 
 // Empty succeeds if object is empty.
@@ -98,7 +260,12 @@ func (o *Object) Value(key string) *Value {
 //  object := NewObject(t, map[string]interface{}{})
 //  object.Empty()
 func (o *Object) Empty() *Object {
-	return o.Equal(map[string]interface{}{})
+	defer o.chain.leave(o.chain.failed())
+	if len(o.value) != 0 {
+		o.chain.fail("\nexpected empty object, but got object with %d key(s):\n%s",
+			len(o.value), dumpValue(o.value))
+	}
+	return o
 }
 
 // NotEmpty succeeds if object is non-empty.
@@ -107,7 +274,12 @@ func (o *Object) Empty() *Object {
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.NotEmpty()
 func (o *Object) NotEmpty() *Object {
-	return o.NotEqual(map[string]interface{}{})
+	defer o.chain.leave(o.chain.failed())
+	if len(o.value) == 0 {
+		o.chain.failActual(o.value,
+			"\nexpected non-empty object, but got object with 0 keys")
+	}
+	return o
 }
 
 // Equal succeeds if object is equal to given Go map or struct.
@@ -119,12 +291,14 @@ func (o *Object) NotEmpty() *Object {
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.Equal(map[string]interface{}{"foo": 123})
 func (o *Object) Equal(value interface{}) *Object {
+	defer o.chain.leave(o.chain.failed())
 	expected, ok := canonMap(&o.chain, value)
 	if !ok {
 		return o
 	}
 	if !reflect.DeepEqual(expected, o.value) {
-		o.chain.fail("\nexpected object equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+		o.chain.failExpected(expected, o.value,
+			"\nexpected object equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
 			dumpValue(expected),
 			dumpValue(o.value),
 			diffValues(expected, o.value))
@@ -132,6 +306,29 @@ func (o *Object) Equal(value interface{}) *Object {
 	return o
 }
 
+// EqualWith succeeds if object is deeply equal to given Go map or struct,
+// using given Comparator for leaf values. See Value.EqualWith.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "created": "2020-01-02T03:04:05.300Z",
+//  })
+//  object.EqualWith(map[string]interface{}{
+//      "created": "2020-01-02T03:04:05Z",
+//  }, func(path string, expected, actual interface{}) (bool, bool) {
+//      if path != "created" {
+//          return false, false
+//      }
+//      e, _ := time.Parse(time.RFC3339, expected.(string))
+//      a, _ := time.Parse(time.RFC3339, actual.(string))
+//      return true, a.Sub(e) < time.Second && e.Sub(a) < time.Second
+//  })
+func (o *Object) EqualWith(value interface{}, cmp Comparator) *Object {
+	defer o.chain.leave(o.chain.failed())
+	checkWith(&o.chain, o.value, value, cmp)
+	return o
+}
+
 // NotEqual succeeds if object is not equal to given Go map or struct.
 // Before comparison, both object and value are converted to canonical form.
 //
@@ -141,6 +338,7 @@ func (o *Object) Equal(value interface{}) *Object {
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.Equal(map[string]interface{}{"bar": 123})
 func (o *Object) NotEqual(v interface{}) *Object {
+	defer o.chain.leave(o.chain.failed())
 	expected, ok := canonMap(&o.chain, v)
 	if !ok {
 		return o
@@ -152,12 +350,62 @@ func (o *Object) NotEqual(v interface{}) *Object {
 	return o
 }
 
+// EqualMerged succeeds if object is equal to the result of deep merging
+// given base with given overrides.
+// Before merging, base and all overrides are converted to canonical form.
+//
+// base and overrides should be map[string]interface{} or struct.
+//
+// Overrides are applied in order, so later values win. Nested objects are
+// merged recursively, while arrays and other values are replaced as a whole.
+// If an object and a non-object value appear at the same path, failure is
+// reported.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "foo": 123,
+//      "bar": map[string]interface{}{"a": 1, "b": 3},
+//  })
+//
+//  object.EqualMerged(
+//      map[string]interface{}{
+//          "foo": 123,
+//          "bar": map[string]interface{}{"a": 1, "b": 2},
+//      },
+//      map[string]interface{}{
+//          "bar": map[string]interface{}{"b": 3},
+//      })
+func (o *Object) EqualMerged(base interface{}, overrides ...interface{}) *Object {
+	defer o.chain.leave(o.chain.failed())
+	expected, ok := canonMap(&o.chain, base)
+	if !ok {
+		return o
+	}
+	for _, override := range overrides {
+		m, ok := canonMap(&o.chain, override)
+		if !ok {
+			return o
+		}
+		if !mergeMaps(&o.chain, expected, m, "") {
+			return o
+		}
+	}
+	if !reflect.DeepEqual(expected, o.value) {
+		o.chain.fail("\nexpected object equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+			dumpValue(expected),
+			dumpValue(o.value),
+			diffValues(expected, o.value))
+	}
+	return o
+}
+
 // ContainsKey succeeds if object contains given key.
 //
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.ContainsKey("foo")
 func (o *Object) ContainsKey(key string) *Object {
+	defer o.chain.leave(o.chain.failed())
 	if !o.containsKey(key) {
 		o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
 			key, dumpValue(o.value))
@@ -165,12 +413,47 @@ func (o *Object) ContainsKey(key string) *Object {
 	return o
 }
 
+// ContainsOnlyKeys succeeds if object contains all given keys and no
+// other keys.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
+//  object.ContainsOnlyKeys("foo", "bar")
+func (o *Object) ContainsOnlyKeys(keys ...string) *Object {
+	defer o.chain.leave(o.chain.failed())
+	if o.chain.failed() {
+		return o
+	}
+	expected := map[string]bool{}
+	for _, k := range keys {
+		expected[k] = true
+	}
+	var extra, missing []string
+	for _, k := range sortedKeys(o.value) {
+		if !expected[k] {
+			extra = append(extra, k)
+		}
+	}
+	for _, k := range keys {
+		if _, ok := o.value[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	if len(extra) != 0 || len(missing) != 0 {
+		diff := strictDiff{extra: extra, missing: missing}
+		o.chain.fail("\nexpected object containing only keys:\n%s\n\nbut got:\n%s\n\n%s",
+			dumpValue(keys), dumpValue(o.value), diff.String())
+	}
+	return o
+}
+
 // NotContainsKey succeeds if object doesn't contain given key.
 //
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.NotContainsKey("bar")
 func (o *Object) NotContainsKey(key string) *Object {
+	defer o.chain.leave(o.chain.failed())
 	if o.containsKey(key) {
 		o.chain.fail(
 			"\nexpected object not containing key '%s', but got:\n%s", key,
@@ -179,6 +462,99 @@ func (o *Object) NotContainsKey(key string) *Object {
 	return o
 }
 
+// HasNull succeeds if object contains given key and its value is null.
+//
+// Missing key is not treated as null value, which is useful to distinguish
+// fields explicitly cleared by PATCH request from fields not sent at all.
+// Failure message tells whether the key is absent or present with non-null
+// value.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": nil, "bar": 123})
+//  object.HasNull("foo")  // success
+//  object.HasNull("bar")  // failure (key present with non-null value)
+//  object.HasNull("baz")  // failure (key absent)
+func (o *Object) HasNull(key string) *Object {
+	defer o.chain.leave(o.chain.failed())
+	if o.chain.failed() {
+		return o
+	}
+	value, ok := o.value[key]
+	switch {
+	case !ok:
+		o.chain.fail(
+			"\nexpected object containing key '%s' with null value,"+
+				" but key is absent:\n%s", key, dumpValue(o.value))
+	case value != nil:
+		o.chain.fail(
+			"\nexpected object containing key '%s' with null value,"+
+				" but key is present with non-null value:\n%s\n\nobject:\n%s",
+			key, dumpValue(value), dumpValue(o.value))
+	}
+	return o
+}
+
+// ContainsPath succeeds if object contains given nested path. Value at
+// the path is not checked and may be anything, including null.
+//
+// Path segments are separated by dots. Array elements may be referred
+// using brackets or dots, e.g. "items[0].id" or "items.0.id". Brackets may
+// be also used for keys containing dots, e.g. "labels[app.version]".
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "items": []interface{}{
+//          map[string]interface{}{"id": 1},
+//      },
+//  })
+//  object.ContainsPath("items[0].id")
+func (o *Object) ContainsPath(path string) *Object {
+	defer o.chain.leave(o.chain.failed())
+	if o.chain.failed() {
+		return o
+	}
+	lk, ok := o.lookupPath("ContainsPath", path)
+	if ok && lk.found != len(lk.keys) {
+		o.chain.fail(
+			"\nexpected object containing path:\n %q\n\n"+
+				"but longest existing prefix is:\n %s\n\n%s",
+			path, lk.prefix(), lk.describe())
+	}
+	return o
+}
+
+// NotContainsPath succeeds if object doesn't contain given nested path.
+// See ContainsPath for path syntax.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "user": map[string]interface{}{"name": "john"},
+//  })
+//  object.NotContainsPath("user.email")
+func (o *Object) NotContainsPath(path string) *Object {
+	defer o.chain.leave(o.chain.failed())
+	if o.chain.failed() {
+		return o
+	}
+	lk, ok := o.lookupPath("NotContainsPath", path)
+	if ok && lk.found == len(lk.keys) {
+		o.chain.fail(
+			"\nexpected object not containing path:\n %q\n\nbut it has value:\n%s",
+			path, dumpValue(lk.node))
+	}
+	return o
+}
+
+func (o *Object) lookupPath(where, path string) (keyPathLookup, bool) {
+	keys, err := splitKeyPath(path)
+	if err != nil {
+		o.chain.failUsage("\nunexpected invalid path passed to %s:\n %q\n\nerror:\n %s",
+			where, path, err.Error())
+		return keyPathLookup{}, false
+	}
+	return lookupKeyPath(o.value, keys), true
+}
+
 // ContainsMap succeeds if object contains given Go value.
 // Before comparison, both object and value are converted to canonical form.
 //
@@ -210,9 +586,14 @@ func (o *Object) NotContainsKey(key string) *Object {
 //      "bar": []interface{}{"x"},
 //  })
 func (o *Object) ContainsMap(value interface{}) *Object {
-	if !o.containsMap(value) {
+	defer o.chain.leave(o.chain.failed())
+	submap, ok := canonMap(&o.chain, value)
+	if !ok {
+		return o
+	}
+	if !checkContainsMap(o.value, submap) {
 		o.chain.fail("\nexpected object containing sub-object:\n%s\n\nbut got:\n%s",
-			dumpValue(value), dumpValue(o.value))
+			dumpValue(submap), dumpValue(o.value))
 	}
 	return o
 }
@@ -226,9 +607,14 @@ func (o *Object) ContainsMap(value interface{}) *Object {
 //  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
 //  object.NotContainsMap(map[string]interface{}{"foo": 123, "bar": "no-no-no"})
 func (o *Object) NotContainsMap(value interface{}) *Object {
-	if o.containsMap(value) {
+	defer o.chain.leave(o.chain.failed())
+	submap, ok := canonMap(&o.chain, value)
+	if !ok {
+		return o
+	}
+	if checkContainsMap(o.value, submap) {
 		o.chain.fail("\nexpected object not containing sub-object:\n%s\n\nbut got:\n%s",
-			dumpValue(value), dumpValue(o.value))
+			dumpValue(submap), dumpValue(o.value))
 	}
 	return o
 }
@@ -242,6 +628,7 @@ func (o *Object) NotContainsMap(value interface{}) *Object {
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.ValueEqual("foo", 123)
 func (o *Object) ValueEqual(key string, value interface{}) *Object {
+	defer o.chain.leave(o.chain.failed())
 	if !o.containsKey(key) {
 		o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
 			key, dumpValue(o.value))
@@ -274,6 +661,7 @@ func (o *Object) ValueEqual(key string, value interface{}) *Object {
 //  object.ValueNotEqual("foo", "bad value")  // success
 //  object.ValueNotEqual("bar", "bad value")  // failure! (key is missing)
 func (o *Object) ValueNotEqual(key string, value interface{}) *Object {
+	defer o.chain.leave(o.chain.failed())
 	if !o.containsKey(key) {
 		o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
 			key, dumpValue(o.value))
@@ -299,14 +687,6 @@ func (o *Object) containsKey(key string) bool {
 	return false
 }
 
-func (o *Object) containsMap(sm interface{}) bool {
-	submap, ok := canonMap(&o.chain, sm)
-	if !ok {
-		return false
-	}
-	return checkContainsMap(o.value, submap)
-}
-
 func checkContainsMap(outer, inner map[string]interface{}) bool {
 	for k, iv := range inner {
 		ov, ok := outer[k]
@@ -327,3 +707,30 @@ func checkContainsMap(outer, inner map[string]interface{}) bool {
 	}
 	return true
 }
+
+func mergeMaps(chain *chain, dst, src map[string]interface{}, path string) bool {
+	for k, sv := range src {
+		kpath := joinPath(path, k)
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = sv
+			continue
+		}
+		dm, dmap := dv.(map[string]interface{})
+		sm, smap := sv.(map[string]interface{})
+		switch {
+		case dmap && smap:
+			if !mergeMaps(chain, dm, sm, kpath) {
+				return false
+			}
+		case dmap != smap:
+			chain.failUsage(
+				"\nunexpected merge conflict at path %q:\n%s\n\ncan't be merged with:\n%s",
+				kpath, dumpValue(dv), dumpValue(sv))
+			return false
+		default:
+			dst[k] = sv
+		}
+	}
+	return true
+}
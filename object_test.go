@@ -1,7 +1,13 @@
 package httpexpect
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,7 +22,9 @@ func TestObjectFailed(t *testing.T) {
 	value.chain.assertFailed(t)
 
 	value.Path("$").chain.assertFailed(t)
+	value.Pointer("").chain.assertFailed(t)
 	value.Schema("")
+	value.HasNull("foo")
 
 	assert.False(t, value.Keys() == nil)
 	assert.False(t, value.Values() == nil)
@@ -26,16 +34,22 @@ func TestObjectFailed(t *testing.T) {
 	value.Values().chain.assertFailed(t)
 	value.Value("foo").chain.assertFailed(t)
 
+	assert.Equal(t, []ObjectItem{}, value.Iter())
+	assert.Nil(t, value.SortedRaw())
+
 	value.Empty()
 	value.NotEmpty()
 	value.Equal(nil)
 	value.NotEqual(nil)
+	value.EqualWith(nil, nil)
 	value.ContainsKey("foo")
+	value.ContainsOnlyKeys("foo")
 	value.NotContainsKey("foo")
 	value.ContainsMap(nil)
 	value.NotContainsMap(nil)
 	value.ValueEqual("foo", nil)
 	value.ValueNotEqual("foo", nil)
+	value.EqualMerged(nil)
 }
 
 func TestObjectGetters(t *testing.T) {
@@ -69,6 +83,10 @@ func TestObjectGetters(t *testing.T) {
 	value.chain.assertOK(t)
 	value.chain.reset()
 
+	assert.Equal(t, m, value.Pointer("").Raw())
+	value.chain.assertOK(t)
+	value.chain.reset()
+
 	value.Schema(`{"type": "object"}`)
 	value.chain.assertOK(t)
 	value.chain.reset()
@@ -132,6 +150,113 @@ func TestObjectEmpty(t *testing.T) {
 	value3.chain.reset()
 }
 
+func TestObjectNullMissingEmpty(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		object   bool
+		empty    bool
+		notEmpty bool
+		message  string
+	}{
+		{
+			name:    "null",
+			body:    `null`,
+			message: "expected: object, actual: null",
+		},
+		{
+			name:    "missing",
+			body:    ``,
+			message: "expected object containing key 'k'",
+		},
+		{
+			name:     "empty",
+			body:     `{}`,
+			object:   true,
+			empty:    true,
+			notEmpty: false,
+			message:  "object with 0 keys",
+		},
+		{
+			name:     "non-empty",
+			body:     `{"a":1}`,
+			object:   true,
+			empty:    false,
+			notEmpty: true,
+			message:  "object with 1 key(s)",
+		},
+	}
+
+	check := func(t *testing.T, reporter *mockReporter, get func() *Value,
+		wantObject, empty, notEmpty bool, message string,
+	) {
+		object := get().Object()
+		if !wantObject {
+			object.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, message)
+			return
+		}
+		object.chain.assertOK(t)
+
+		object = get().Object().Empty()
+		if empty {
+			object.chain.assertOK(t)
+		} else {
+			object.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, message)
+		}
+
+		object = get().Object().NotEmpty()
+		if notEmpty {
+			object.chain.assertOK(t)
+		} else {
+			object.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, message)
+		}
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name+" value", func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			outer := `{}`
+			if tc.body != "" {
+				outer = `{"k":` + tc.body + `}`
+			}
+
+			check(t, reporter, func() *Value {
+				resp := NewResponse(reporter, &http.Response{
+					StatusCode: http.StatusOK,
+					Header: http.Header{
+						"Content-Type": {"application/json"},
+					},
+					Body: ioutil.NopCloser(bytes.NewBufferString(outer)),
+				})
+				return resp.JSON().Object().Value("k")
+			}, tc.object, tc.empty, tc.notEmpty, tc.message)
+		})
+
+		if tc.body == "" {
+			continue
+		}
+
+		t.Run(tc.name+" body", func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			check(t, reporter, func() *Value {
+				resp := NewResponse(reporter, &http.Response{
+					StatusCode: http.StatusOK,
+					Header: http.Header{
+						"Content-Type": {"application/json"},
+					},
+					Body: ioutil.NopCloser(bytes.NewBufferString(tc.body)),
+				})
+				return resp.JSON()
+			}, tc.object, tc.empty, tc.notEmpty, tc.message)
+		})
+	}
+}
+
 func TestObjectEqualEmpty(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -249,6 +374,263 @@ func TestObjectEqualStruct(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectEqualMerged(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": 123,
+		"bar": map[string]interface{}{
+			"a": 1,
+			"b": map[string]interface{}{
+				"c": "x",
+				"d": "z",
+			},
+		},
+		"baz": []interface{}{"c"},
+	})
+
+	base := map[string]interface{}{
+		"foo": 123,
+		"bar": map[string]interface{}{
+			"a": 1,
+			"b": map[string]interface{}{
+				"c": "x",
+				"d": "y",
+			},
+		},
+		"baz": []interface{}{"a", "b"},
+	}
+
+	value.EqualMerged(base)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualMerged(base,
+		map[string]interface{}{
+			"bar": map[string]interface{}{
+				"b": map[string]interface{}{
+					"d": "z",
+				},
+			},
+		},
+		map[string]interface{}{
+			"baz": []interface{}{"c"},
+		})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualMerged(base,
+		map[string]interface{}{
+			"bar": map[string]interface{}{
+				"b": map[string]interface{}{
+					"d": "w",
+				},
+			},
+			"baz": []interface{}{"c"},
+		},
+		map[string]interface{}{
+			"bar": map[string]interface{}{
+				"b": map[string]interface{}{
+					"d": "z",
+				},
+			},
+		})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualMerged(base,
+		map[string]interface{}{
+			"bar": map[string]interface{}{
+				"b": map[string]interface{}{
+					"d": "z",
+				},
+			},
+			"baz": []interface{}{"c"},
+			"qux": true,
+		})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	type Override struct {
+		Baz []string `json:"baz"`
+		Bar struct {
+			B struct {
+				D string `json:"d"`
+			} `json:"b"`
+		} `json:"bar"`
+	}
+
+	override := Override{}
+	override.Baz = []string{"c"}
+	override.Bar.B.D = "z"
+
+	value.EqualMerged(base, override)
+	value.chain.assertOK(t)
+	value.chain.reset()
+}
+
+func TestObjectEqualMergedConflict(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": 123,
+	})
+
+	value.EqualMerged(
+		map[string]interface{}{
+			"foo": map[string]interface{}{"a": 1},
+		},
+		map[string]interface{}{
+			"foo": 123,
+		})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualMerged(
+		map[string]interface{}{
+			"bar": map[string]interface{}{"a": 1},
+		},
+		map[string]interface{}{
+			"bar": map[string]interface{}{
+				"a": map[string]interface{}{"b": 2},
+			},
+		})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualMerged(
+		map[string]interface{}{"foo": 123},
+		"not a map")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestObjectMergeMapsPath(t *testing.T) {
+	reporter := &failureRecordingReporter{}
+	chain := makeChain(reporter)
+
+	dst := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": 1.0},
+		},
+	}
+	src := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{
+				"c": map[string]interface{}{},
+			},
+		},
+	}
+
+	assert.False(t, mergeMaps(&chain, dst, src, ""))
+	assert.True(t, chain.failed())
+
+	assert.Equal(t, 1, len(reporter.failures))
+	assert.Equal(t, FailureAssertUsage, reporter.failures[0].Type)
+	assert.Contains(t, reporter.failures[0].Message, `"a.b.c"`)
+}
+
+func TestObjectContainsPath(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":  "john",
+			"email": nil,
+		},
+		"items": []interface{}{
+			map[string]interface{}{"id": 1},
+			map[string]interface{}{"id": 2, "tags": []interface{}{"a"}},
+		},
+		"labels": map[string]interface{}{
+			"app.version": "1.0",
+		},
+	})
+
+	for _, path := range []string{
+		"user",
+		"user.name",
+		"user.email",
+		"items[0]",
+		"items[1].id",
+		"items.1.id",
+		"items[1].tags[0]",
+		"labels[app.version]",
+	} {
+		value.ContainsPath(path)
+		value.chain.assertOK(t)
+		value.chain.reset()
+
+		value.NotContainsPath(path)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	}
+
+	for _, path := range []string{
+		"missing",
+		"user.age",
+		"user.name.first",
+		"user.email.domain",
+		"items[2]",
+		"items[-1]",
+		"items[x]",
+		"items[1].tags[1]",
+		"labels.app.version",
+	} {
+		value.ContainsPath(path)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+
+		value.NotContainsPath(path)
+		value.chain.assertOK(t)
+		value.chain.reset()
+	}
+
+	for _, path := range []string{
+		"",
+		"user.",
+		"user..name",
+		"items[0",
+		"items[0]id",
+	} {
+		value.ContainsPath(path)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+
+		value.NotContainsPath(path)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	}
+}
+
+func TestObjectContainsPathMessage(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1, "name": "a"},
+		},
+	})
+
+	value.ContainsPath("items[0].price")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, `"items[0]"`)
+	assert.Contains(t, reporter.message, `"id"`)
+	assert.Contains(t, reporter.message, `"name"`)
+	value.chain.reset()
+
+	value.ContainsPath("items[3].id")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, `"items"`)
+	assert.Contains(t, reporter.message, "[0; 1)")
+	value.chain.reset()
+
+	value.ContainsPath("orders")
+	value.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "(root)")
+	value.chain.reset()
+}
+
 func TestObjectContainsKey(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -279,6 +661,55 @@ func TestObjectContainsKey(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectValueOr(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": 123.0,
+		"bar": nil,
+	})
+
+	value.ValueOr("foo", 0).Number().Equal(123)
+	value.chain.assertOK(t)
+
+	value.ValueOr("bar", "default").Null()
+	value.chain.assertOK(t)
+
+	value.ValueOr("baz", 456).Number().Equal(456)
+	value.chain.assertOK(t)
+
+	value.ValueOr("baz", []string{"a"}).Array().Elements("a")
+	value.chain.assertOK(t)
+
+	assert.Equal(t, nil, value.ValueOr("baz", nil).Raw())
+	value.chain.assertOK(t)
+
+	assert.False(t, reporter.reported)
+
+	def := value.ValueOr("baz", 456)
+	def.chain.assertOK(t)
+	def.Number().Equal(789).chain.assertFailed(t)
+	assert.True(t, reporter.reported)
+
+	value.ValueOr("baz", func() {})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestObjectHasKey(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{"foo": 123, "bar": nil})
+
+	assert.True(t, value.HasKey("foo"))
+	assert.True(t, value.HasKey("bar"))
+	assert.False(t, value.HasKey("baz"))
+	assert.False(t, value.HasKey("FOO"))
+
+	value.chain.assertOK(t)
+	assert.False(t, reporter.reported)
+}
+
 func TestObjectContainsMapSuccess(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -546,6 +977,51 @@ func TestObjectValueEqualStruct(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectValueEqualMarshaler(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"id":    "1-2-3-4",
+		"price": "12.34",
+		"item": map[string]interface{}{
+			"id":   "a-b-c-d",
+			"name": "foo",
+		},
+	})
+
+	type Item struct {
+		ID      testUUID `json:"id"`
+		Name    string   `json:"name"`
+		Comment string   `json:"comment,omitempty"`
+	}
+
+	value.ValueEqual("id", testUUID{1, 2, 3, 4})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqual("price", testDecimal{1234, 2})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqual("item", Item{ID: testUUID{0xa, 0xb, 0xc, 0xd}, Name: "foo"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqual("item", Item{
+		ID: testUUID{0xa, 0xb, 0xc, 0xd}, Name: "foo", Comment: "bar",
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqual("id", testUUID{4, 3, 2, 1})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqual("id", testBrokenMarshaler{})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
 func TestObjectConvertEqual(t *testing.T) {
 	type (
 		myMap map[string]interface{}
@@ -653,3 +1129,236 @@ func TestObjectConvertValueEqual(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestObjectContainsOnlyKeys(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": 123,
+		"bar": "baz",
+	})
+
+	value.ContainsOnlyKeys("foo", "bar")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsOnlyKeys("bar", "foo")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsOnlyKeys("foo")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ContainsOnlyKeys("foo", "bar", "qux")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ContainsOnlyKeys()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	empty := NewObject(reporter, map[string]interface{}{})
+
+	empty.ContainsOnlyKeys()
+	empty.chain.assertOK(t)
+	empty.chain.reset()
+}
+
+func TestObjectEqualWith(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	// timestamps are equal if they're within a second
+	timestampTolerance := func(path string, expected, actual interface{}) (bool, bool) {
+		es, ok1 := expected.(string)
+		as, ok2 := actual.(string)
+		if !ok1 || !ok2 {
+			return false, false
+		}
+		e, err1 := time.Parse(time.RFC3339Nano, es)
+		a, err2 := time.Parse(time.RFC3339Nano, as)
+		if err1 != nil || err2 != nil {
+			return false, false
+		}
+		d := a.Sub(e)
+		return true, d > -time.Second && d < time.Second
+	}
+
+	var paths []string
+	recordPaths := func(path string, expected, actual interface{}) (bool, bool) {
+		paths = append(paths, path)
+		return timestampTolerance(path, expected, actual)
+	}
+
+	value := NewObject(reporter, map[string]interface{}{
+		"id":      1,
+		"created": "2020-01-02T03:04:05.300Z",
+		"items": []interface{}{
+			map[string]interface{}{
+				"name":    "foo",
+				"updated": "2020-01-02T03:04:06Z",
+			},
+		},
+	})
+
+	value.EqualWith(map[string]interface{}{
+		"id":      1,
+		"created": "2020-01-02T03:04:05Z",
+		"items": []interface{}{
+			map[string]interface{}{
+				"name":    "foo",
+				"updated": "2020-01-02T03:04:05.500Z",
+			},
+		},
+	}, recordPaths)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	assert.Equal(t,
+		[]string{"created", "id", "items[0].name", "items[0].updated"}, paths)
+
+	value.EqualWith(map[string]interface{}{
+		"id":      1,
+		"created": "2020-01-02T03:04:07Z",
+		"items": []interface{}{
+			map[string]interface{}{
+				"name":    "bar",
+				"updated": "2020-01-02T03:04:06Z",
+			},
+		},
+	}, timestampTolerance)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message,
+		"created (comparator):\n  expected: \"2020-01-02T03:04:07Z\"\n"+
+			"  actual: \"2020-01-02T03:04:05.300Z\"")
+	assert.Contains(t, reporter.message,
+		"items[0].name (not equal):\n  expected: \"bar\"\n  actual: \"foo\"")
+
+	value.EqualWith(map[string]interface{}{
+		"id":      1,
+		"created": "2020-01-02T03:04:05Z",
+		"extra":   true,
+	}, nil)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "extra (missing key)")
+	assert.Contains(t, reporter.message, "items (unexpected key)")
+	assert.Contains(t, reporter.message, "created (not equal)")
+}
+
+func TestObjectHasNull(t *testing.T) {
+	cases := []struct {
+		name    string
+		object  map[string]interface{}
+		ok      bool
+		message string
+	}{
+		{
+			name:    "key absent",
+			object:  map[string]interface{}{"bar": 123},
+			ok:      false,
+			message: "but key is absent",
+		},
+		{
+			name:   "key present with null",
+			object: map[string]interface{}{"foo": nil, "bar": 123},
+			ok:     true,
+		},
+		{
+			name:    "key present with value",
+			object:  map[string]interface{}{"foo": "", "bar": 123},
+			ok:      false,
+			message: "but key is present with non-null value",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			value := NewObject(reporter, tc.object)
+
+			value.HasNull("foo")
+			if tc.ok {
+				value.chain.assertOK(t)
+			} else {
+				value.chain.assertFailed(t)
+				assert.Contains(t, reporter.message, tc.message)
+			}
+		})
+	}
+}
+
+func TestObjectOrder(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	m := map[string]interface{}{}
+	keys := []interface{}{}
+	values := []interface{}{}
+	for i := 0; i < 60; i++ {
+		k := fmt.Sprintf("key%02d", i)
+		m[k] = float64(i)
+		keys = append(keys, k)
+		values = append(values, float64(i))
+	}
+
+	for n := 0; n < 20; n++ {
+		value := NewObject(reporter, m)
+
+		assert.Equal(t, keys, value.Keys().Raw())
+		assert.Equal(t, values, value.Values().Raw())
+
+		items := value.Iter()
+		assert.Equal(t, len(keys), len(items))
+		for i, item := range items {
+			assert.Equal(t, keys[i], item.Key)
+			assert.Equal(t, values[i], item.Value.Raw())
+			assert.Equal(t, keys[i], item.Value.chain.path)
+		}
+
+		sorted := value.SortedRaw()
+		assert.Equal(t, len(keys), len(sorted))
+		for i, e := range sorted {
+			assert.Equal(t, keys[i], e.Key)
+			assert.Equal(t, values[i], e.Value)
+		}
+
+		value.chain.assertOK(t)
+	}
+}
+
+func TestObjectSortedRaw(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": 123,
+		"bar": map[string]interface{}{
+			"y": "<b>",
+			"x": []interface{}{map[string]interface{}{"d": 1, "c": nil}},
+		},
+	})
+
+	expected := SortedMap{
+		{"bar", SortedMap{
+			{"x", []interface{}{SortedMap{{"c", nil}, {"d", 1.0}}}},
+			{"y", "<b>"},
+		}},
+		{"foo", 123.0},
+	}
+
+	assert.Equal(t, expected, value.SortedRaw())
+
+	b, err := json.Marshal(value.SortedRaw())
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`{"bar":{"x":[{"c":null,"d":1}],"y":"\u003cb\u003e"},"foo":123}`, string(b))
+
+	b, err = json.Marshal(SortedMap{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(b))
+
+	value.chain.assertOK(t)
+}
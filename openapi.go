@@ -0,0 +1,505 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v2"
+)
+
+// OpenAPIOpts defines parameters for NewOpenAPIMatcher.
+type OpenAPIOpts struct {
+	// If true, responses to requests whose method and path are not described
+	// in the spec are not validated. Otherwise, failure is reported.
+	SkipUnknownPaths bool
+}
+
+// NewOpenAPIMatcher returns a matcher that validates every response against
+// OpenAPI 3.0 spec loaded from given file, in JSON or YAML format.
+//
+// The spec is parsed and all its response schemas are compiled once, when
+// the matcher is created; if this fails, error is returned.
+//
+// For every response, the matcher finds operation by request method and
+// path, using path templates from the spec (e.g. "/users/{id}") and path
+// prefixes of the spec servers. Then it checks that response status code
+// is documented for the operation (explicitly, as range like "2XX", or
+// via "default"), that response Content-Type is one of the documented
+// media types, and that JSON body matches the documented schema.
+//
+// Every schema validation error is reported as a separate failure, with
+// JSON Pointer of the invalid value.
+//
+// Example:
+//  matcher, err := httpexpect.NewOpenAPIMatcher("api/openapi.yaml")
+//  if err != nil {
+//      t.Fatal(err)
+//  }
+//
+//  e := httpexpect.New(t, "http://example.com").Matcher(matcher)
+//
+//  e.GET("/users/{id}", 123).
+//      Expect().
+//      Status(http.StatusOK)
+func NewOpenAPIMatcher(
+	specPath string, opts ...OpenAPIOpts,
+) (func(*Response), error) {
+	var opt OpenAPIOpts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	spec, err := loadOpenAPISpec(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(resp *Response) {
+		spec.match(resp, opt)
+	}, nil
+}
+
+type openapiSpec struct {
+	path      string
+	basePaths []string
+	paths     []openapiPath
+}
+
+type openapiPath struct {
+	template   string
+	segments   []string
+	operations map[string]openapiOperation
+}
+
+type openapiOperation struct {
+	responses map[string]openapiResponse
+}
+
+type openapiResponse struct {
+	// media type => schema, schema is nil if not specified
+	content map[string]*gojsonschema.Schema
+}
+
+func loadOpenAPISpec(specPath string) (*openapiSpec, error) {
+	data, err := ioutil.ReadFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+		doc = convertYAML(doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse OpenAPI spec %q: %s", specPath, err)
+	}
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid OpenAPI spec %q: expected object", specPath)
+	}
+
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf(
+			"invalid OpenAPI spec %q: expected \"openapi\" field with version 3.x",
+			specPath)
+	}
+
+	paths, ok := root["paths"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf(
+			"invalid OpenAPI spec %q: expected \"paths\" object", specPath)
+	}
+
+	convertNullable(root)
+
+	spec := &openapiSpec{path: specPath}
+
+	if servers, ok := root["servers"].([]interface{}); ok {
+		for _, s := range servers {
+			server, _ := s.(map[string]interface{})
+			u, err := url.Parse(fmt.Sprint(server["url"]))
+			if err != nil {
+				continue
+			}
+			if p := strings.TrimRight(u.Path, "/"); p != "" {
+				spec.basePaths = append(spec.basePaths, p)
+			}
+		}
+	}
+
+	for _, template := range sortedKeys(paths) {
+		item, ok := paths[template].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid OpenAPI spec %q: expected object for path %q",
+				specPath, template)
+		}
+
+		p := openapiPath{
+			template:   template,
+			segments:   strings.Split(strings.Trim(template, "/"), "/"),
+			operations: make(map[string]openapiOperation),
+		}
+
+		for method, v := range item {
+			op, ok := v.(map[string]interface{})
+			if !ok || !isOpenAPIMethod(method) {
+				continue
+			}
+			operation, err := compileOpenAPIOperation(root, op)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid OpenAPI spec %q: operation %s %s: %s",
+					specPath, strings.ToUpper(method), template, err)
+			}
+			p.operations[strings.ToUpper(method)] = operation
+		}
+
+		spec.paths = append(spec.paths, p)
+	}
+
+	return spec, nil
+}
+
+func isOpenAPIMethod(method string) bool {
+	switch method {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
+
+func compileOpenAPIOperation(
+	root map[string]interface{}, op map[string]interface{},
+) (openapiOperation, error) {
+	operation := openapiOperation{
+		responses: make(map[string]openapiResponse),
+	}
+
+	responses, ok := op["responses"].(map[string]interface{})
+	if !ok {
+		return operation, errors.New("expected \"responses\" object")
+	}
+
+	for code, v := range responses {
+		r, err := resolveOpenAPIRef(root, v)
+		if err != nil {
+			return operation, err
+		}
+
+		response := openapiResponse{
+			content: make(map[string]*gojsonschema.Schema),
+		}
+
+		content, _ := r["content"].(map[string]interface{})
+		for mediaType, v := range content {
+			media, _ := v.(map[string]interface{})
+			schema, ok := media["schema"].(map[string]interface{})
+			if !ok {
+				response.content[mediaType] = nil
+				continue
+			}
+
+			// make components available for references from schema
+			doc := make(map[string]interface{}, len(schema)+1)
+			for k, v := range schema {
+				doc[k] = v
+			}
+			if components, ok := root["components"]; ok {
+				doc["components"] = components
+			}
+
+			compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(doc))
+			if err != nil {
+				return operation, fmt.Errorf(
+					"response %s, media type %q: %s", code, mediaType, err)
+			}
+			response.content[mediaType] = compiled
+		}
+
+		operation.responses[strings.ToUpper(code)] = response
+	}
+
+	return operation, nil
+}
+
+// resolveOpenAPIRef resolves local "$ref" of response object.
+func resolveOpenAPIRef(
+	root map[string]interface{}, v interface{},
+) (map[string]interface{}, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("expected object")
+	}
+
+	ref, ok := obj["$ref"].(string)
+	if !ok {
+		return obj, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported non-local reference %q", ref)
+	}
+
+	var node interface{} = root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+		if node, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+	}
+
+	return resolveOpenAPIRef(root, node)
+}
+
+// convertYAML converts maps decoded by yaml.v2 to JSON-compatible maps.
+func convertYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = convertYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = convertYAML(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// convertNullable replaces OpenAPI "nullable" keyword, which is not part of
+// JSON Schema, with "null" type.
+func convertNullable(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if nullable, _ := v["nullable"].(bool); nullable {
+			if typ, ok := v["type"].(string); ok {
+				v["type"] = []interface{}{typ, "null"}
+			}
+		}
+		delete(v, "nullable")
+		for _, val := range v {
+			convertNullable(val)
+		}
+	case []interface{}:
+		for _, val := range v {
+			convertNullable(val)
+		}
+	}
+}
+
+func (s *openapiSpec) match(resp *Response, opts OpenAPIOpts) {
+	if resp.chain.failed() {
+		return
+	}
+
+	if resp.resp.Request == nil {
+		resp.chain.fail("\nunexpected response without request in OpenAPI matcher")
+		return
+	}
+
+	method := resp.resp.Request.Method
+	reqPath := resp.resp.Request.URL.Path
+
+	path := s.findPath(reqPath)
+	if path == nil {
+		if !opts.SkipUnknownPaths {
+			resp.chain.fail("\nexpected request path described in OpenAPI spec %q,"+
+				" but got:\n %q", s.path, reqPath)
+		}
+		return
+	}
+
+	operation, ok := path.operations[method]
+	if !ok {
+		if !opts.SkipUnknownPaths {
+			resp.chain.fail("\nexpected operation described in OpenAPI spec %q,"+
+				" but got:\n %s %s", s.path, method, path.template)
+		}
+		return
+	}
+
+	status := resp.resp.StatusCode
+	where := fmt.Sprintf("%s %s", method, path.template)
+
+	response, ok := operation.find(status)
+	if !ok {
+		resp.chain.fail("\nexpected status code documented for %s,"+
+			" but got:\n %d", where, status)
+		return
+	}
+
+	if len(response.content) == 0 {
+		return
+	}
+
+	contentType := resp.resp.Header.Get("Content-Type")
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	schemaType, ok := response.findMediaType(mediaType)
+	if !ok {
+		resp.chain.fail("\nexpected Content-Type documented for %s (status %d):\n %q"+
+			"\n\nbut got:\n %q", where, status, response.mediaTypes(), contentType)
+		return
+	}
+
+	schema := response.content[schemaType]
+	if schema == nil || !isJSONMediaType(mediaType) {
+		return
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(resp.content, &body); err != nil {
+		resp.chain.fail("\nexpected valid JSON body for %s (status %d):\n %s",
+			where, status, err.Error())
+		return
+	}
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(body))
+	if err != nil {
+		resp.chain.fail(err.Error())
+		return
+	}
+
+	errs := result.Errors()
+	for i, e := range errs {
+		chain := &resp.chain
+		if i != len(errs)-1 {
+			// report every error separately, using a copy of the chain
+			c := resp.chain
+			chain = &c
+		}
+		chain.fail("\nOpenAPI schema validation failed for %s (status %d)"+
+			" at %q:\n %s", where, status, jsonPointerOf(e.Context()), e.Description())
+	}
+}
+
+// findPath finds path with the least number of template parameters
+// matching given request path.
+func (s *openapiSpec) findPath(reqPath string) *openapiPath {
+	candidates := []string{reqPath}
+	for _, base := range s.basePaths {
+		if strings.HasPrefix(reqPath, base+"/") || reqPath == base {
+			candidates = append(candidates, strings.TrimPrefix(reqPath, base))
+		}
+	}
+
+	var (
+		best       *openapiPath
+		bestParams int
+	)
+
+	for _, candidate := range candidates {
+		segments := strings.Split(strings.Trim(candidate, "/"), "/")
+		for i := range s.paths {
+			params, ok := s.paths[i].matchSegments(segments)
+			if ok && (best == nil || params < bestParams) {
+				best, bestParams = &s.paths[i], params
+			}
+		}
+	}
+
+	return best
+}
+
+func (p *openapiPath) matchSegments(segments []string) (int, bool) {
+	if len(segments) != len(p.segments) {
+		return 0, false
+	}
+	params := 0
+	for i, seg := range p.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if segments[i] == "" {
+				return 0, false
+			}
+			params++
+		} else if seg != segments[i] {
+			return 0, false
+		}
+	}
+	return params, true
+}
+
+func (o *openapiOperation) find(status int) (openapiResponse, bool) {
+	for _, code := range []string{
+		fmt.Sprint(status),
+		fmt.Sprintf("%dXX", status/100),
+		"DEFAULT",
+	} {
+		if response, ok := o.responses[code]; ok {
+			return response, true
+		}
+	}
+	return openapiResponse{}, false
+}
+
+func (r *openapiResponse) findMediaType(mediaType string) (string, bool) {
+	if mediaType == "" {
+		return "", false
+	}
+
+	var wildcard string
+
+	for key := range r.content {
+		expected, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			continue
+		}
+		if expected == mediaType {
+			return key, true
+		}
+		if expected == "*/*" ||
+			(strings.HasSuffix(expected, "/*") &&
+				strings.HasPrefix(mediaType, strings.TrimSuffix(expected, "*"))) {
+			wildcard = key
+		}
+	}
+
+	return wildcard, wildcard != ""
+}
+
+func (r *openapiResponse) mediaTypes() []string {
+	ret := make([]string, 0, len(r.content))
+	for key := range r.content {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// jsonPointerOf converts gojsonschema context to JSON Pointer.
+func jsonPointerOf(ctx *gojsonschema.JsonContext) string {
+	const sep = "\x00"
+
+	var b strings.Builder
+	tokens := strings.Split(ctx.String(sep), sep)
+	for _, token := range tokens[1:] {
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
@@ -0,0 +1,291 @@
+package httpexpect
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.0
+servers:
+  - url: http://example.com/api
+paths:
+  /users:
+    get:
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+  /users/{id}:
+    get:
+      responses:
+        200:
+          $ref: '#/components/responses/User'
+        4XX:
+          content:
+            application/problem+json:
+              schema:
+                type: object
+                required: [title]
+    delete:
+      responses:
+        204:
+          description: deleted
+  /users/me:
+    get:
+      responses:
+        default:
+          content:
+            text/*: {}
+components:
+  responses:
+    User:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/User'
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        email:
+          type: string
+          nullable: true
+        x/y:
+          type: integer
+        tags:
+          type: object
+          additionalProperties:
+            type: string
+`
+
+func writeOpenAPISpec(t *testing.T, name, spec string) string {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(spec), 0600))
+	return path
+}
+
+func createOpenAPIHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	reply := func(w http.ResponseWriter, status int, typ, body string) {
+		if typ != "" {
+			w.Header().Set("Content-Type", typ)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+
+	mux.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, "application/json",
+			`[{"id": 1, "name": "john", "email": null}]`)
+	})
+
+	mux.HandleFunc("/api/users/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/users/me":
+			reply(w, http.StatusOK, "text/plain", "me")
+		case r.Method == http.MethodDelete:
+			reply(w, http.StatusNoContent, "", "")
+		case r.URL.Path == "/api/users/1":
+			reply(w, http.StatusOK, "application/json",
+				`{"id": 1, "name": "john", "tags": {"a/b": "c"}}`)
+		case r.URL.Path == "/api/users/2":
+			reply(w, http.StatusOK, "application/json",
+				`{"id": "2", "x/y": "s", "tags": {"a": 1}}`)
+		case r.URL.Path == "/api/users/3":
+			reply(w, http.StatusOK, "text/html", "<html></html>")
+		case r.URL.Path == "/api/users/4":
+			reply(w, http.StatusInternalServerError, "application/json", "{}")
+		case r.URL.Path == "/api/users/5":
+			reply(w, http.StatusNotFound, "application/problem+json",
+				`{"title": "not found"}`)
+		default:
+			reply(w, http.StatusOK, "application/json", `{"id": 1, "name": `)
+		}
+	})
+
+	mux.HandleFunc("/api/unknown", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, "", "")
+	})
+
+	return mux
+}
+
+func newOpenAPIExpect(t *testing.T, matcher func(*Response)) (*Expect, *mockReporter) {
+	reporter := newMockReporter(t)
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com/api",
+		Reporter: reporter,
+		Client: &http.Client{
+			Transport: NewBinder(createOpenAPIHandler()),
+		},
+	})
+	return e.Matcher(matcher), reporter
+}
+
+func TestOpenAPIMatcherValid(t *testing.T) {
+	matcher, err := NewOpenAPIMatcher(
+		writeOpenAPISpec(t, "openapi.yaml", testOpenAPISpec))
+	require.NoError(t, err)
+
+	e, _ := newOpenAPIExpect(t, matcher)
+
+	for _, req := range []*Request{
+		e.GET("/users"),
+		e.GET("/users/1"),
+		e.GET("/users/me"),
+		e.GET("/users/5"),
+		e.DELETE("/users/1"),
+	} {
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+	}
+}
+
+func TestOpenAPIMatcherInvalid(t *testing.T) {
+	matcher, err := NewOpenAPIMatcher(
+		writeOpenAPISpec(t, "openapi.yaml", testOpenAPISpec))
+	require.NoError(t, err)
+
+	e, reporter := newOpenAPIExpect(t, matcher)
+
+	t.Run("schema", func(t *testing.T) {
+		reporter := &mockCollectingReporter{}
+
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com/api",
+			Reporter: reporter,
+			Client: &http.Client{
+				Transport: NewBinder(createOpenAPIHandler()),
+			},
+		}).Matcher(matcher)
+
+		resp := e.GET("/users/2").Expect()
+		resp.chain.assertFailed(t)
+
+		require.Equal(t, 4, len(reporter.messages))
+
+		all := strings.Join(reporter.messages, "\n")
+		assert.Contains(t, all, `GET /users/{id} (status 200) at "":`)
+		assert.Contains(t, all, `at "/id":`)
+		assert.Contains(t, all, `at "/x~1y":`)
+		assert.Contains(t, all, `at "/tags":`)
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		resp := e.GET("/users/3").Expect()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "text/html")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		resp := e.GET("/users/4").Expect()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "500")
+	})
+
+	t.Run("body", func(t *testing.T) {
+		resp := e.GET("/users/6").Expect()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "valid JSON")
+	})
+
+	t.Run("method", func(t *testing.T) {
+		resp := e.POST("/users").Expect()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "POST /users")
+	})
+
+	t.Run("path", func(t *testing.T) {
+		resp := e.GET("/unknown").Expect()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "/api/unknown")
+	})
+}
+
+func TestOpenAPIMatcherSkipUnknown(t *testing.T) {
+	matcher, err := NewOpenAPIMatcher(
+		writeOpenAPISpec(t, "openapi.yaml", testOpenAPISpec),
+		OpenAPIOpts{SkipUnknownPaths: true})
+	require.NoError(t, err)
+
+	e, _ := newOpenAPIExpect(t, matcher)
+
+	e.GET("/unknown").Expect().chain.assertOK(t)
+	e.POST("/users").Expect().chain.assertOK(t)
+
+	e.GET("/users/2").Expect().chain.assertFailed(t)
+}
+
+func TestOpenAPIMatcherJSON(t *testing.T) {
+	spec := `{
+		"openapi": "3.0.3",
+		"paths": {
+			"/api/users": {
+				"get": {
+					"responses": {
+						"200": {
+							"content": {
+								"application/json": {
+									"schema": {"type": "object"}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	matcher, err := NewOpenAPIMatcher(writeOpenAPISpec(t, "openapi.json", spec))
+	require.NoError(t, err)
+
+	e, reporter := newOpenAPIExpect(t, matcher)
+
+	e.GET("/users").Expect().chain.assertFailed(t)
+	assert.Contains(t, reporter.message, `at "":`)
+}
+
+func TestOpenAPIMatcherBadSpec(t *testing.T) {
+	for name, spec := range map[string]string{
+		"syntax":    "openapi: [",
+		"version":   "openapi: 2.0\npaths: {}",
+		"paths":     "openapi: 3.0.0",
+		"responses": "openapi: 3.0.0\npaths:\n  /a:\n    get: {}",
+		"ref": "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      responses:\n" +
+			"        200:\n          $ref: '#/components/responses/Missing'",
+		"schema": "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      responses:\n" +
+			"        200:\n          content:\n            application/json:\n" +
+			"              schema:\n                type: 123",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewOpenAPIMatcher(writeOpenAPISpec(t, "openapi.yaml", spec))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewOpenAPIMatcher("/nonexistent/openapi.yaml")
+	assert.Error(t, err)
+}
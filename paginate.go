@@ -0,0 +1,108 @@
+package httpexpect
+
+// PaginateOpts defines parameters for Expect.Paginate.
+type PaginateOpts struct {
+	// Maximum number of pages to request. If zero, DefaultMaxPages is used.
+	// If there are more pages, failure is reported.
+	MaxPages int
+
+	// If non-nil, invoked for every page after receiving response and
+	// before extracting items. page is zero-based page number.
+	OnPage func(page int, resp *Response)
+}
+
+// DefaultMaxPages is the default value of PaginateOpts.MaxPages.
+const DefaultMaxPages = 100
+
+// Paginate walks paginated list endpoint and returns a new Array object
+// with items of all pages concatenated.
+//
+// build is invoked for every page with the cursor of that page, which is
+// empty for the first page, and returns request for the page. extract is
+// invoked for every response and returns items of the page and cursor of
+// the next page, or empty cursor if it was the last page.
+//
+// Paginate reports failure if the same cursor is returned twice, or if
+// there are more than MaxPages pages. If request or assertion for some page
+// fails, walking stops and returned Array is failed too.
+//
+// Example:
+//  items := e.Paginate(
+//      func(cursor string) *httpexpect.Request {
+//          return e.GET("/items").WithQuery("cursor", cursor)
+//      },
+//      func(resp *httpexpect.Response) (*httpexpect.Array, string) {
+//          obj := resp.Status(http.StatusOK).JSON().Object()
+//          return obj.Value("items").Array(),
+//              obj.ValueOr("next", "").String().Raw()
+//      },
+//      httpexpect.PaginateOpts{MaxPages: 10},
+//  )
+//
+//  items.Length().Equal(25)
+func (e *Expect) Paginate(
+	build func(cursor string) *Request,
+	extract func(resp *Response) (items *Array, next string),
+	opts PaginateOpts,
+) *Array {
+	chain := makeChain(e.config.Reporter)
+
+	maxPages := opts.MaxPages
+	if maxPages == 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var (
+		all    = []interface{}{}
+		cursor = ""
+		seen   = map[string]int{}
+	)
+
+	for page := 0; ; page++ {
+		if page >= maxPages {
+			chain.fail("\nexpected at most %d pages, but got more"+
+				" (next cursor %q)", maxPages, cursor)
+			return &Array{chain, all}
+		}
+
+		req := build(cursor)
+		if req == nil {
+			chain.failUsage("\nunexpected nil request for page %d (cursor %q)",
+				page, cursor)
+			return &Array{chain, all}
+		}
+
+		resp := req.Expect()
+		if opts.OnPage != nil {
+			opts.OnPage(page, resp)
+		}
+		if resp.chain.failed() {
+			return &Array{resp.chain, all}
+		}
+
+		items, next := extract(resp)
+		if items == nil {
+			chain.failUsage("\nunexpected nil items for page %d (cursor %q)",
+				page, cursor)
+			return &Array{chain, all}
+		}
+		if items.chain.failed() {
+			return &Array{items.chain, all}
+		}
+
+		all = append(all, items.value...)
+
+		if next == "" {
+			return &Array{chain, all}
+		}
+
+		if prev, ok := seen[next]; ok {
+			chain.fail("\nunexpected cursor loop: cursor %q returned by page %d"+
+				" was already returned by page %d", next, page, prev)
+			return &Array{chain, all}
+		}
+		seen[next] = page
+
+		cursor = next
+	}
+}
@@ -0,0 +1,173 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createPaginatedHandler(pages map[string]map[string]interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	})
+}
+
+func newPaginateExpect(t *testing.T, handler http.Handler) (*Expect, *mockReporter) {
+	reporter := newMockReporter(t)
+	return WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: reporter,
+		Client: &http.Client{
+			Transport: NewBinder(handler),
+		},
+	}), reporter
+}
+
+func paginateItems(e *Expect, opts PaginateOpts) *Array {
+	return e.Paginate(
+		func(cursor string) *Request {
+			return e.GET("/items").WithQuery("cursor", cursor)
+		},
+		func(resp *Response) (*Array, string) {
+			obj := resp.Status(http.StatusOK).JSON().Object()
+			return obj.Value("items").Array(),
+				obj.ValueOr("next", "").String().Raw()
+		},
+		opts,
+	)
+}
+
+func TestPaginate(t *testing.T) {
+	e, _ := newPaginateExpect(t, createPaginatedHandler(
+		map[string]map[string]interface{}{
+			"": {
+				"items": []interface{}{1, 2},
+				"next":  "p2",
+			},
+			"p2": {
+				"items": []interface{}{3, 4},
+				"next":  "p3",
+			},
+			"p3": {
+				"items": []interface{}{5},
+			},
+		}))
+
+	var pages []int
+
+	items := paginateItems(e, PaginateOpts{
+		OnPage: func(page int, resp *Response) {
+			pages = append(pages, page)
+			resp.ContentType("application/json")
+		},
+	})
+
+	items.chain.assertOK(t)
+	items.Length().Equal(5)
+	items.Elements(1, 2, 3, 4, 5)
+	items.chain.assertOK(t)
+
+	assert.Equal(t, []int{0, 1, 2}, pages)
+}
+
+func TestPaginateCursorLoop(t *testing.T) {
+	e, reporter := newPaginateExpect(t, createPaginatedHandler(
+		map[string]map[string]interface{}{
+			"": {
+				"items": []interface{}{1},
+				"next":  "p2",
+			},
+			"p2": {
+				"items": []interface{}{2},
+				"next":  "p3",
+			},
+			"p3": {
+				"items": []interface{}{3},
+				"next":  "p2",
+			},
+		}))
+
+	items := paginateItems(e, PaginateOpts{})
+
+	items.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "cursor loop")
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0}, items.Raw())
+}
+
+func TestPaginateMaxPages(t *testing.T) {
+	e, reporter := newPaginateExpect(t, createPaginatedHandler(
+		map[string]map[string]interface{}{
+			"": {
+				"items": []interface{}{1},
+				"next":  "p2",
+			},
+			"p2": {
+				"items": []interface{}{2},
+				"next":  "p3",
+			},
+			"p3": {
+				"items": []interface{}{3},
+			},
+		}))
+
+	items := paginateItems(e, PaginateOpts{MaxPages: 2})
+
+	items.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, "at most 2 pages")
+
+	e, _ = newPaginateExpect(t, createPaginatedHandler(
+		map[string]map[string]interface{}{
+			"": {
+				"items": []interface{}{1},
+				"next":  "p2",
+			},
+			"p2": {
+				"items": []interface{}{2},
+			},
+		}))
+
+	paginateItems(e, PaginateOpts{MaxPages: 2}).chain.assertOK(t)
+}
+
+func TestPaginatePageFailure(t *testing.T) {
+	e, _ := newPaginateExpect(t, createPaginatedHandler(
+		map[string]map[string]interface{}{
+			"": {
+				"items": []interface{}{1},
+				"next":  "missing",
+			},
+		}))
+
+	var pages int
+
+	items := paginateItems(e, PaginateOpts{
+		OnPage: func(page int, resp *Response) {
+			pages++
+		},
+	})
+
+	items.chain.assertFailed(t)
+	assert.Equal(t, 2, pages)
+
+	e, _ = newPaginateExpect(t, createPaginatedHandler(nil))
+
+	items = e.Paginate(
+		func(cursor string) *Request {
+			return nil
+		},
+		func(resp *Response) (*Array, string) {
+			return nil, ""
+		},
+		PaginateOpts{},
+	)
+
+	items.chain.assertFailed(t)
+}
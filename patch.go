@@ -0,0 +1,373 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PatchOp defines single operation of JSON Patch document, as defined by
+// RFC 6902.
+//
+// Op should be one of "add", "remove", "replace", "move", "copy", or "test".
+// Path and From are JSON Pointers, as defined by RFC 6901. Value is used
+// by "add", "replace", and "test" operations, and From is used by "move"
+// and "copy" operations.
+//
+// Example:
+//  ops := []httpexpect.PatchOp{
+//      {Op: "replace", Path: "/name", Value: "john"},
+//      {Op: "remove", Path: "/tags/0"},
+//      {Op: "move", From: "/old", Path: "/new"},
+//  }
+type PatchOp struct {
+	Op    string
+	Path  string
+	Value interface{}
+	From  string
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// "value" member is written only for operations that use it, so that nil
+// Value is written as null for "add", "replace", and "test" operations.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	obj := map[string]interface{}{
+		"op":   op.Op,
+		"path": op.Path,
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		obj["value"] = op.Value
+	case "move", "copy":
+		obj["from"] = op.From
+	}
+	return json.Marshal(obj)
+}
+
+// validate checks that operation is well-formed according to RFC 6902.
+func (op PatchOp) validate() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.From != "" {
+			return fmt.Errorf("unexpected from %q for %q operation", op.From, op.Op)
+		}
+	case "remove":
+		if op.Value != nil {
+			return fmt.Errorf("unexpected value for %q operation", op.Op)
+		}
+		if op.From != "" {
+			return fmt.Errorf("unexpected from %q for %q operation", op.From, op.Op)
+		}
+	case "move", "copy":
+		if op.From == "" {
+			return fmt.Errorf("missing from for %q operation", op.Op)
+		}
+		if op.Value != nil {
+			return fmt.Errorf("unexpected value for %q operation", op.Op)
+		}
+		if _, err := splitPointer(op.From); err != nil {
+			return fmt.Errorf("invalid from %q: %s", op.From, err.Error())
+		}
+	case "":
+		return errors.New("missing op")
+	default:
+		return fmt.Errorf("unknown op %q (expected one of"+
+			" \"add\", \"remove\", \"replace\", \"move\", \"copy\", \"test\")", op.Op)
+	}
+	if _, err := splitPointer(op.Path); err != nil {
+		return fmt.Errorf("invalid path %q: %s", op.Path, err.Error())
+	}
+	if op.Op == "move" && strings.HasPrefix(op.Path, op.From+"/") {
+		return fmt.Errorf("can't move %q into its own child %q", op.From, op.Path)
+	}
+	return nil
+}
+
+func validatePatch(ops []PatchOp) error {
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("operation %d: %s", i, err.Error())
+		}
+	}
+	return nil
+}
+
+// ApplyJSONPatch applies JSON Patch to document and returns the result.
+// It may be used to derive expected value from a fixture in tests, so that
+// assertions stay in sync with the patch sent to server.
+//
+// document is converted to canonical form first, so it may be a struct,
+// map, or slice, and is not modified. Operations are validated and applied
+// as defined by RFC 6902; if some operation can't be applied, e.g. if
+// "test" operation fails, error is returned.
+//
+// Example:
+//  ops := []httpexpect.PatchOp{
+//      {Op: "replace", Path: "/name", Value: "john"},
+//  }
+//
+//  expected, err := httpexpect.ApplyJSONPatch(fixture, ops)
+//  if err != nil {
+//      t.Fatal(err)
+//  }
+//
+//  e.PATCH("/users/1").WithJSONPatch(ops).
+//      Expect().
+//      JSON().Equal(expected)
+func ApplyJSONPatch(document interface{}, ops []PatchOp) (interface{}, error) {
+	if err := validatePatch(ops); err != nil {
+		return nil, err
+	}
+	doc, err := patchCanon(document)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		doc, err = applyPatchOp(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %q): %s", i, op.Op, op.Path, err.Error())
+		}
+	}
+	return doc, nil
+}
+
+// ApplyMergePatch applies JSON Merge Patch to document and returns the
+// result, as defined by RFC 7396.
+//
+// Keys of patch object overwrite keys of document object recursively, and
+// null values remove keys. If patch is not an object, it replaces document.
+// Both document and patch are converted to canonical form first, and are
+// not modified.
+//
+// Example:
+//  patch := map[string]interface{}{"name": "john", "nickname": nil}
+//
+//  expected, err := httpexpect.ApplyMergePatch(fixture, patch)
+//  if err != nil {
+//      t.Fatal(err)
+//  }
+//
+//  e.PATCH("/users/1").WithMergePatch(patch).
+//      Expect().
+//      JSON().Equal(expected)
+func ApplyMergePatch(document, patch interface{}) (interface{}, error) {
+	doc, err := patchCanon(document)
+	if err != nil {
+		return nil, err
+	}
+	p, err := patchCanon(patch)
+	if err != nil {
+		return nil, err
+	}
+	return mergePatch(doc, p), nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	obj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for k, v := range obj {
+		if v == nil {
+			delete(targetObj, k)
+		} else {
+			targetObj[k] = mergePatch(targetObj[k], v)
+		}
+	}
+	return targetObj
+}
+
+// patchCanon converts value to canonical form, returning a deep copy.
+func patchCanon(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(addressableMarshaler(value))
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func applyPatchOp(doc interface{}, op PatchOp) (interface{}, error) {
+	path, _ := splitPointer(op.Path)
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := patchCanon(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "test" {
+			actual, err := patchGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equalValues(value, actual) {
+				return nil, fmt.Errorf("test failed, expected:\n%s\n\nbut got:\n%s",
+					dumpValue(value), dumpValue(actual))
+			}
+			return doc, nil
+		}
+		return patchSet(doc, path, value, op.Op == "add")
+
+	case "remove":
+		return patchRemove(doc, path)
+
+	case "move", "copy":
+		from, _ := splitPointer(op.From)
+		value, err := patchGet(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from %q: %s", op.From, err.Error())
+		}
+		if op.Op == "move" {
+			if doc, err = patchRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else if value, err = patchCanon(value); err != nil {
+			return nil, err
+		}
+		return patchSet(doc, path, value, true)
+	}
+
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// splitPointer splits JSON Pointer into unescaped tokens.
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, errors.New("should be empty or start with '/'")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if !validPointerToken(token) {
+			return nil, fmt.Errorf("token %d (%q) has invalid escape sequence", i, token)
+		}
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func patchGet(doc interface{}, path []string) (interface{}, error) {
+	for _, key := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			doc = child
+		case []interface{}:
+			index, ok := parsePointerIndex(key)
+			if !ok || index >= len(node) {
+				return nil, fmt.Errorf("index %q out of array bounds [0; %d)",
+					key, len(node))
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("can't resolve %q in %s", key, jsonTypeName(node))
+		}
+	}
+	return doc, nil
+}
+
+// patchSet adds or replaces value at path. If insert is true, value is
+// inserted into array, otherwise array element is replaced. Replaced
+// value should exist. Returns updated document.
+func patchSet(
+	doc interface{}, path []string, value interface{}, insert bool,
+) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return patchUpdate(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := node[key]; !ok && !insert {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			node[key] = value
+			return node, nil
+		case []interface{}:
+			if insert && key == "-" {
+				return append(node, value), nil
+			}
+			index, ok := parsePointerIndex(key)
+			if !ok || index > len(node) || (!insert && index == len(node)) {
+				return nil, fmt.Errorf("index %q out of array bounds", key)
+			}
+			if !insert {
+				node[index] = value
+				return node, nil
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		default:
+			return nil, fmt.Errorf("can't set %q in %s", key, jsonTypeName(node))
+		}
+	})
+}
+
+func patchRemove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, errors.New("can't remove whole document")
+	}
+	return patchUpdate(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := node[key]; !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			delete(node, key)
+			return node, nil
+		case []interface{}:
+			index, ok := parsePointerIndex(key)
+			if !ok || index >= len(node) {
+				return nil, fmt.Errorf("index %q out of array bounds [0; %d)",
+					key, len(node))
+			}
+			return append(node[:index], node[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("can't remove %q from %s", key, jsonTypeName(node))
+		}
+	})
+}
+
+// patchUpdate invokes fn for parent of value at path (non-empty) and the
+// last path token, and stores updated parent back into document.
+func patchUpdate(
+	doc interface{}, path []string,
+	fn func(parent interface{}, key string) (interface{}, error),
+) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := patchGet(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = patchUpdate(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		node[path[0]] = child
+	case []interface{}:
+		index, _ := parsePointerIndex(path[0])
+		node[index] = child
+	}
+	return doc, nil
+}
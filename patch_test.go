@@ -0,0 +1,278 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchOpMarshal(t *testing.T) {
+	cases := []struct {
+		op       PatchOp
+		expected string
+	}{
+		{PatchOp{Op: "add", Path: "/a", Value: 1}, `{"op":"add","path":"/a","value":1}`},
+		{PatchOp{Op: "test", Path: "/a"}, `{"op":"test","path":"/a","value":null}`},
+		{PatchOp{Op: "remove", Path: "/a"}, `{"op":"remove","path":"/a"}`},
+		{PatchOp{Op: "copy", From: "/a", Path: "/b"},
+			`{"from":"/a","op":"copy","path":"/b"}`},
+	}
+
+	for _, tc := range cases {
+		b, err := json.Marshal(tc.op)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, string(b))
+	}
+}
+
+func TestPatchApplyJSONPatch(t *testing.T) {
+	parse := func(s string) interface{} {
+		var v interface{}
+		require.NoError(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+
+	// examples from RFC 6902, appendix A
+	cases := []struct {
+		name     string
+		doc      string
+		ops      []PatchOp
+		expected string
+	}{
+		{
+			name:     "add object member",
+			doc:      `{"foo": "bar"}`,
+			ops:      []PatchOp{{Op: "add", Path: "/baz", Value: "qux"}},
+			expected: `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:     "add array element",
+			doc:      `{"foo": ["bar", "baz"]}`,
+			ops:      []PatchOp{{Op: "add", Path: "/foo/1", Value: "qux"}},
+			expected: `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:     "append array element",
+			doc:      `{"foo": ["bar"]}`,
+			ops:      []PatchOp{{Op: "add", Path: "/foo/-", Value: []string{"abc"}}},
+			expected: `{"foo": ["bar", ["abc"]]}`,
+		},
+		{
+			name:     "remove object member",
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			ops:      []PatchOp{{Op: "remove", Path: "/baz"}},
+			expected: `{"foo": "bar"}`,
+		},
+		{
+			name:     "remove array element",
+			doc:      `{"foo": ["bar", "qux", "baz"]}`,
+			ops:      []PatchOp{{Op: "remove", Path: "/foo/1"}},
+			expected: `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:     "replace value",
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			ops:      []PatchOp{{Op: "replace", Path: "/baz", Value: "boo"}},
+			expected: `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name: "move value",
+			doc:  `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			ops: []PatchOp{
+				{Op: "move", From: "/foo/waldo", Path: "/qux/thud"},
+			},
+			expected: `{"foo": {"bar": "baz"},` +
+				` "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:     "move array element",
+			doc:      `{"foo": ["all", "grass", "cows", "eat"]}`,
+			ops:      []PatchOp{{Op: "move", From: "/foo/1", Path: "/foo/3"}},
+			expected: `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "copy value",
+			doc:  `{"a": {"b": [1]}}`,
+			ops: []PatchOp{
+				{Op: "copy", From: "/a", Path: "/c"},
+				{Op: "add", Path: "/c/b/-", Value: 2},
+			},
+			expected: `{"a": {"b": [1]}, "c": {"b": [1, 2]}}`,
+		},
+		{
+			name: "test value",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			ops: []PatchOp{
+				{Op: "test", Path: "/baz", Value: "qux"},
+				{Op: "test", Path: "/foo/1", Value: 2},
+			},
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:     "escaped path",
+			doc:      `{"/": 9, "~1": 10}`,
+			ops:      []PatchOp{{Op: "replace", Path: "/~01", Value: 11}},
+			expected: `{"/": 9, "~1": 11}`,
+		},
+		{
+			name:     "replace whole document",
+			doc:      `{"foo": "bar"}`,
+			ops:      []PatchOp{{Op: "replace", Path: "", Value: []int{1}}},
+			expected: `[1]`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := parse(tc.doc)
+			before := parse(tc.doc)
+
+			result, err := ApplyJSONPatch(doc, tc.ops)
+			require.NoError(t, err)
+
+			assert.Equal(t, parse(tc.expected), result)
+			assert.Equal(t, before, doc)
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		type user struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}
+
+		result, err := ApplyJSONPatch(user{Name: "bob", Tags: []string{"a", "b"}},
+			[]PatchOp{
+				{Op: "replace", Path: "/name", Value: "john"},
+				{Op: "remove", Path: "/tags/0"},
+			})
+		require.NoError(t, err)
+
+		NewValue(t, result).Object().
+			ValueEqual("name", "john").
+			ValueEqual("tags", []string{"b"})
+	})
+}
+
+func TestPatchApplyJSONPatchErrors(t *testing.T) {
+	doc := map[string]interface{}{
+		"foo": []interface{}{"bar"},
+		"baz": "qux",
+	}
+
+	cases := []struct {
+		name    string
+		ops     []PatchOp
+		message string
+	}{
+		{
+			name:    "unknown op",
+			ops:     []PatchOp{{Op: "upsert", Path: "/foo"}},
+			message: `operation 0: unknown op "upsert"`,
+		},
+		{
+			name:    "missing from",
+			ops:     []PatchOp{{Op: "move", Path: "/foo"}},
+			message: `operation 0: missing from for "move" operation`,
+		},
+		{
+			name:    "test failed",
+			ops:     []PatchOp{{Op: "test", Path: "/baz", Value: "bar"}},
+			message: `operation 0 (test "/baz"): test failed`,
+		},
+		{
+			name:    "remove missing key",
+			ops:     []PatchOp{{Op: "remove", Path: "/missing"}},
+			message: `operation 0 (remove "/missing"): key "missing" not found`,
+		},
+		{
+			name:    "replace missing key",
+			ops:     []PatchOp{{Op: "replace", Path: "/missing", Value: 1}},
+			message: `operation 0 (replace "/missing"): key "missing" not found`,
+		},
+		{
+			name:    "add out of bounds",
+			ops:     []PatchOp{{Op: "add", Path: "/foo/2", Value: 1}},
+			message: `operation 0 (add "/foo/2"): index "2" out of array bounds`,
+		},
+		{
+			name:    "add missing parent",
+			ops:     []PatchOp{{Op: "add", Path: "/a/b", Value: 1}},
+			message: `operation 0 (add "/a/b"): key "a" not found`,
+		},
+		{
+			name:    "add into scalar",
+			ops:     []PatchOp{{Op: "add", Path: "/baz/x", Value: 1}},
+			message: `operation 0 (add "/baz/x"): can't set "x" in string`,
+		},
+		{
+			name:    "remove whole document",
+			ops:     []PatchOp{{Op: "remove", Path: ""}},
+			message: `operation 0 (remove ""): can't remove whole document`,
+		},
+		{
+			name: "copy missing from",
+			ops:  []PatchOp{{Op: "copy", From: "/missing", Path: "/a"}},
+			message: `operation 0 (copy "/a"): from "/missing":` +
+				` key "missing" not found`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ApplyJSONPatch(doc, tc.ops)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+
+	_, err := ApplyJSONPatch(make(chan int), nil)
+	assert.Error(t, err)
+}
+
+func TestPatchApplyMergePatch(t *testing.T) {
+	parse := func(s string) interface{} {
+		var v interface{}
+		require.NoError(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+
+	// examples from RFC 7396, appendix A
+	cases := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range cases {
+		doc := parse(tc.doc)
+		before := parse(tc.doc)
+
+		result, err := ApplyMergePatch(doc, parse(tc.patch))
+		require.NoError(t, err)
+
+		assert.Equal(t, parse(tc.expected), result, "%s + %s", tc.doc, tc.patch)
+		assert.Equal(t, before, doc)
+	}
+
+	_, err := ApplyMergePatch(map[string]interface{}{}, make(chan int))
+	assert.Error(t, err)
+}
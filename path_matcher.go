@@ -0,0 +1,131 @@
+package httpexpect
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// pathMatcher is a matcher invoked only for responses to requests whose
+// final URL path matches pattern.
+type pathMatcher struct {
+	pattern string
+	re      *regexp.Regexp
+	fn      func(*Response)
+	hits    int64
+}
+
+// MatcherFor returns a copy of Expect instance with given matcher attached
+// to it, which is invoked only for responses to requests whose final URL
+// path (i.e. after following redirects) matches given pattern.
+//
+// If pattern starts with "^", it's a regular expression matched against
+// the path. Otherwise, it's a glob matched against the whole path, where
+// "*" matches any sequence of characters except "/", "**" matches any
+// sequence of characters including "/", and "?" matches any single
+// character except "/".
+//
+// Path matchers are invoked after matchers attached by Matcher. If several
+// patterns match the path, all matching entries are invoked in the order
+// of attachment. If pattern is invalid, failure is reported.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.com").
+//      MatcherFor("/internal/**", func(resp *httpexpect.Response) {
+//          resp.Header("X-Internal").NotEmpty()
+//      }).
+//      MatcherFor(`^/api/v[0-9]+/`, func(resp *httpexpect.Response) {
+//          resp.Header("Access-Control-Allow-Origin").NotEmpty()
+//      })
+func (e *Expect) MatcherFor(pattern string, matcher func(*Response)) *Expect {
+	return e.addPathMatcher("MatcherFor", pattern, matcher, false)
+}
+
+// MatcherForStrict is like MatcherFor, but additionally reports failure at
+// the end of the test if pattern didn't match any request made using
+// returned Expect instance or its copies.
+//
+// The check requires reporter to be testing.TB, or AssertReporter or
+// RequireReporter wrapping it; otherwise, it's not performed.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.com").
+//      MatcherForStrict("/internal/**", func(resp *httpexpect.Response) {
+//          resp.Header("X-Internal").NotEmpty()
+//      })
+func (e *Expect) MatcherForStrict(pattern string, matcher func(*Response)) *Expect {
+	return e.addPathMatcher("MatcherForStrict", pattern, matcher, true)
+}
+
+func (e *Expect) addPathMatcher(
+	where, pattern string, matcher func(*Response), strict bool,
+) *Expect {
+	ret := *e
+
+	re, err := compilePathPattern(pattern)
+	if err != nil {
+		chain := makeChain(e.config.Reporter)
+		chain.failUsage("\nunexpected invalid path pattern in %s:\n %q\n\n%s",
+			where, pattern, err.Error())
+		return &ret
+	}
+
+	m := &pathMatcher{pattern: pattern, re: re, fn: matcher}
+
+	ret.pathMatchers = append(
+		append([]*pathMatcher(nil), e.pathMatchers...), m)
+
+	if strict {
+		if cleanup := reporterCleanup(e.config.Reporter); cleanup != nil {
+			cleanup(func() {
+				if atomic.LoadInt64(&m.hits) == 0 {
+					chain := makeChain(e.config.Reporter)
+					chain.fail("\nexpected at least one request"+
+						" matching path pattern:\n %q\n\nbut got none", pattern)
+				}
+			})
+		}
+	}
+
+	return &ret
+}
+
+// match invokes matcher if response is for a request matching pattern.
+func (m *pathMatcher) match(resp *Response) {
+	if resp.resp == nil || resp.resp.Request == nil ||
+		resp.resp.Request.URL == nil {
+		return
+	}
+	if !m.re.MatchString(resp.resp.Request.URL.Path) {
+		return
+	}
+	atomic.AddInt64(&m.hits, 1)
+	m.fn(resp)
+}
+
+// compilePathPattern converts glob or "^"-prefixed regexp pattern
+// to regexp. See Expect.MatcherFor.
+func compilePathPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "^") {
+		return regexp.Compile(pattern)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			buf.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			buf.WriteString("[^/]*")
+		case pattern[i] == '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	buf.WriteString("$")
+
+	return regexp.Compile(buf.String())
+}
@@ -0,0 +1,173 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createPathMatcherHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/internal/new", http.StatusFound)
+	})
+
+	return mux
+}
+
+func TestPathMatcherOverlapping(t *testing.T) {
+	server := httptest.NewServer(createPathMatcherHandler())
+	defer server.Close()
+
+	var log []string
+
+	record := func(name string) func(*Response) {
+		return func(*Response) {
+			log = append(log, name)
+		}
+	}
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	}).
+		MatcherFor("/internal/**", record("glob-deep")).
+		Matcher(record("global")).
+		MatcherFor("/internal/*", record("glob")).
+		MatcherFor(`^/internal/a`, record("regexp")).
+		MatcherFor("/nothing", record("inert"))
+
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{"/internal/a", []string{"global", "glob-deep", "glob", "regexp"}},
+		{"/internal/a/b", []string{"global", "glob-deep", "regexp"}},
+		{"/internal/b", []string{"global", "glob-deep", "glob"}},
+		{"/internalx", []string{"global"}},
+		{"/public", []string{"global"}},
+		{"/old", []string{"global", "glob-deep", "glob"}},
+	}
+
+	for _, tc := range cases {
+		log = nil
+
+		e.GET(tc.path).Expect().chain.assertOK(t)
+
+		assert.Equal(t, tc.expected, log, tc.path)
+	}
+}
+
+func TestPathMatcherCopies(t *testing.T) {
+	server := httptest.NewServer(createPathMatcherHandler())
+	defer server.Close()
+
+	calls := 0
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	e.MatcherFor("/**", func(*Response) {
+		calls++
+	})
+
+	e.GET("/path").Expect()
+	assert.Equal(t, 0, calls)
+}
+
+func TestPathMatcherStrict(t *testing.T) {
+	server := httptest.NewServer(createPathMatcherHandler())
+	defer server.Close()
+
+	t.Run("matched", func(t *testing.T) {
+		reporter := &cleanupReporter{mockReporter: newMockReporter(t)}
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		}).MatcherForStrict("/internal/*", func(*Response) {})
+
+		e.GET("/internal/a").Expect()
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("inert", func(t *testing.T) {
+		reporter := &cleanupReporter{mockReporter: newMockReporter(t)}
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		}).MatcherForStrict("/nothing", func(*Response) {})
+
+		e.GET("/internal/a").Expect()
+
+		reporter.runCleanups()
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, `"/nothing"`)
+	})
+
+	t.Run("inert non-strict", func(t *testing.T) {
+		reporter := &cleanupReporter{mockReporter: newMockReporter(t)}
+
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: reporter,
+		}).MatcherFor("/nothing", func(*Response) {})
+
+		e.GET("/internal/a").Expect()
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+}
+
+func TestPathMatcherInvalid(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: reporter,
+	})
+
+	e.MatcherFor("^(", func(*Response) {})
+	assert.True(t, reporter.reported)
+}
+
+func TestCompilePathPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"/a/*", "/a/b", true},
+		{"/a/*", "/a/b/c", false},
+		{"/a/*", "/a/", true},
+		{"/a/**", "/a/b/c", true},
+		{"/a/**", "/ab", false},
+		{"/a/?", "/a/b", true},
+		{"/a/?", "/a/bc", false},
+		{"/a.b", "/a.b", true},
+		{"/a.b", "/axb", false},
+		{"/a/*.json", "/a/x.json", true},
+		{`^/a/[0-9]+$`, "/a/123", true},
+		{`^/a/[0-9]+$`, "/a/x", false},
+		{`^/a`, "/a/b/c", true},
+	}
+
+	for _, tc := range cases {
+		re, err := compilePathPattern(tc.pattern)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.match, re.MatchString(tc.path), "%s %s", tc.pattern, tc.path)
+	}
+}
@@ -3,6 +3,7 @@ package httpexpect
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"strings"
@@ -26,6 +27,14 @@ func NewCurlPrinter(logger Logger) CurlPrinter {
 // Request implements Printer.Request.
 func (p CurlPrinter) Request(req *http.Request) {
 	if req != nil {
+		// streaming body may be large and can't be read twice
+		if isStreamBody(req.Body) {
+			body := req.Body
+			req.Body = ioutil.NopCloser(strings.NewReader(streamBodyPlaceholder(req)))
+			defer func() {
+				req.Body = body
+			}()
+		}
 		cmd, err := http2curl.GetCurlCommand(req)
 		if err != nil {
 			panic(err)
@@ -51,7 +60,11 @@ func NewCompactPrinter(logger Logger) CompactPrinter {
 // Request implements Printer.Request.
 func (p CompactPrinter) Request(req *http.Request) {
 	if req != nil {
-		p.logger.Logf("%s %s", req.Method, req.URL)
+		if id := RequestIDFromContext(req.Context()); id != "" {
+			p.logger.Logf("[%s] %s %s", id, req.Method, req.URL)
+		} else {
+			p.logger.Logf("%s %s", req.Method, req.URL)
+		}
 	}
 }
 
@@ -78,11 +91,23 @@ func (p DebugPrinter) Request(req *http.Request) {
 		return
 	}
 
-	dump, err := httputil.DumpRequest(req, p.body)
+	// streaming body may be large and can't be read twice
+	stream := isStreamBody(req.Body)
+
+	dump, err := httputil.DumpRequest(req, p.body && !stream)
 	if err != nil {
 		panic(err)
 	}
-	p.logger.Logf("%s", dump)
+
+	if p.body && stream {
+		dump = append(dump, streamBodyPlaceholder(req)...)
+	}
+
+	if id := RequestIDFromContext(req.Context()); id != "" {
+		p.logger.Logf("[%s] %s", id, dump)
+	} else {
+		p.logger.Logf("%s", dump)
+	}
 }
 
 // Response implements Printer.Response.
@@ -91,7 +116,8 @@ func (p DebugPrinter) Response(resp *http.Response, duration time.Duration) {
 		return
 	}
 
-	dump, err := httputil.DumpResponse(resp, p.body)
+	// event streams may be infinite and are read incrementally
+	dump, err := httputil.DumpResponse(resp, p.body && !isEventStream(resp))
 	if err != nil {
 		panic(err)
 	}
@@ -99,6 +125,13 @@ func (p DebugPrinter) Response(resp *http.Response, duration time.Duration) {
 	text := strings.Replace(string(dump), "\r\n", "\n", -1)
 	lines := strings.SplitN(text, "\n", 2)
 
+	if resp.Request != nil {
+		if id := RequestIDFromContext(resp.Request.Context()); id != "" {
+			p.logger.Logf("[%s] %s %s\n%s", id, lines[0], duration, lines[1])
+			return
+		}
+	}
+
 	p.logger.Logf("%s %s\n%s", lines[0], duration, lines[1])
 }
 
@@ -139,3 +172,11 @@ func (p DebugPrinter) WebsocketRead(typ int, content []byte, closeCode int) {
 	fmt.Fprintf(b, "\n")
 	p.logger.Logf(b.String())
 }
+
+// streamBodyPlaceholder returns text printed instead of streaming body.
+func streamBodyPlaceholder(req *http.Request) string {
+	if req.ContentLength > 0 {
+		return fmt.Sprintf("<streaming body, %d bytes>", req.ContentLength)
+	}
+	return "<streaming body, unknown bytes>"
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCompactPrinter(t *testing.T) {
@@ -42,3 +45,34 @@ func TestDebugPrinter(t *testing.T) {
 	printer.Response(&http.Response{}, 0)
 	printer.Response(nil, 0)
 }
+
+func TestPrinterRequestID(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	req = req.WithContext(withRequestID(req.Context(), "abcd1234"))
+
+	resp := &http.Response{
+		Request: req,
+		Body:    ioutil.NopCloser(bytes.NewBufferString("body")),
+	}
+
+	t.Run("compact", func(t *testing.T) {
+		logger := &mockLogger{}
+
+		printer := NewCompactPrinter(logger)
+		printer.Request(req)
+
+		assert.Equal(t, []string{"[abcd1234] GET http://example.com"}, logger.logs)
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		logger := &mockLogger{}
+
+		printer := NewDebugPrinter(logger, false)
+		printer.Request(req)
+		printer.Response(resp, 0)
+
+		assert.Equal(t, 2, len(logger.logs))
+		assert.True(t, strings.HasPrefix(logger.logs[0], "[abcd1234] GET"))
+		assert.True(t, strings.HasPrefix(logger.logs[1], "[abcd1234] HTTP"))
+	})
+}
@@ -0,0 +1,126 @@
+package httpexpect
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+)
+
+// parseProxyURL parses proxy URL, as accepted by Config.ProxyURL and
+// Request.WithProxy.
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf(
+			"unsupported scheme %q (expected \"http\", \"https\", or \"socks5\")",
+			u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+	return u, nil
+}
+
+// proxyFunc returns function selecting proxy for request, defined by
+// Config.Proxy or Config.ProxyURL, or nil if proxy is not configured.
+func (config Config) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if config.Proxy != nil {
+		return config.Proxy
+	}
+	if config.ProxyURL != "" {
+		u, err := parseProxyURL(config.ProxyURL)
+		if err != nil {
+			return nil
+		}
+		return http.ProxyURL(u)
+	}
+	return nil
+}
+
+// validateProxy returns problems with proxy settings of config.
+func (config Config) validateProxy() []string {
+	var problems []string
+
+	if config.ProxyURL != "" {
+		if config.Proxy != nil {
+			problems = append(problems, "both ProxyURL and Proxy are set")
+		}
+		if _, err := parseProxyURL(config.ProxyURL); err != nil {
+			problems = append(problems,
+				fmt.Sprintf("ProxyURL %q is invalid: %s", config.ProxyURL, err.Error()))
+		}
+	}
+
+	if config.ProxyURL == "" && config.Proxy == nil {
+		return problems
+	}
+
+	if config.Client != nil {
+		if _, _, err := cloneTransport(config.Client); err != nil {
+			problems = append(problems,
+				fmt.Sprintf("proxy is set, but Client is %s", err.Error()))
+		}
+	}
+	if config.WebsocketDialer != nil {
+		if _, ok := config.WebsocketDialer.(*websocket.Dialer); !ok {
+			problems = append(problems,
+				fmt.Sprintf("proxy is set, but WebsocketDialer is of type %T"+
+					" (expected *websocket.Dialer)", config.WebsocketDialer))
+		}
+	}
+
+	return problems
+}
+
+// withProxy returns config with proxy applied to copies of Client and
+// WebsocketDialer. Clients and dialers of unsupported types are kept as is;
+// they are reported by Validate.
+func (config Config) withProxy() Config {
+	proxy := config.proxyFunc()
+	if proxy == nil {
+		return config
+	}
+	if client, transport, err := cloneTransport(config.Client); err == nil {
+		transport.Proxy = proxy
+		config.Client = client
+	}
+	if d, ok := config.WebsocketDialer.(*websocket.Dialer); ok {
+		dialerCopy := *d
+		dialerCopy.Proxy = proxy
+		config.WebsocketDialer = &dialerCopy
+	}
+	return config
+}
+
+// cloneTransport returns shallow copies of client and its transport, so
+// that transport can be modified without affecting other users of client.
+//
+// client should be *http.Client with nil Transport or *http.Transport.
+func cloneTransport(client Client) (*http.Client, *http.Transport, error) {
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		return nil, nil, fmt.Errorf(
+			"client of type %T (expected *http.Client)", client)
+	}
+	var transport *http.Transport
+	switch t := httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, nil, fmt.Errorf(
+			"client with transport of type %T (expected *http.Transport)",
+			httpClient.Transport)
+	}
+	clientCopy := *httpClient
+	clientCopy.Transport = transport
+	return &clientCopy, transport, nil
+}
@@ -0,0 +1,138 @@
+package httpexpect
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// DefaultRedactHeaders is the default value of Config.RedactHeaders.
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization"}
+
+// redactedValue is printed instead of redacted header values.
+const redactedValue = "*****"
+
+// redactHeaders returns list of headers that should be masked in printers
+// and wire capture.
+func redactHeaders(config Config) []string {
+	if config.RedactHeaders == nil {
+		return DefaultRedactHeaders
+	}
+	return config.RedactHeaders
+}
+
+// redactRequest returns shallow copy of request with given headers masked.
+// If there is nothing to mask, request is returned as is.
+func redactRequest(req *http.Request, names []string) *http.Request {
+	header, ok := redactHeader(req.Header, names)
+	if !ok {
+		return req
+	}
+	reqCopy := *req
+	reqCopy.Header = header
+	return &reqCopy
+}
+
+// redactResponse is like redactRequest, but for response.
+func redactResponse(resp *http.Response, names []string) *http.Response {
+	header, ok := redactHeader(resp.Header, names)
+	if !ok {
+		return resp
+	}
+	respCopy := *resp
+	respCopy.Header = header
+	return &respCopy
+}
+
+// redactHeader returns copy of header with given headers masked, and
+// flag indicating whether any header was masked.
+func redactHeader(header http.Header, names []string) (http.Header, bool) {
+	var ret http.Header
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		values, ok := header[key]
+		if !ok {
+			continue
+		}
+		if ret == nil {
+			ret = header.Clone()
+		}
+		masked := make([]string, len(values))
+		for i, v := range values {
+			masked[i] = redactHeaderValue(key, v)
+		}
+		ret[key] = masked
+	}
+	return ret, ret != nil
+}
+
+// redactHeaderValue masks header value. For "Cookie" and "Set-Cookie"
+// headers, only cookie values are masked, and cookie names and attributes
+// are kept.
+func redactHeaderValue(key, value string) string {
+	switch key {
+	case "Cookie":
+		cookies := strings.Split(value, ";")
+		for i, c := range cookies {
+			cookies[i] = redactCookie(c)
+		}
+		return strings.Join(cookies, ";")
+
+	case "Set-Cookie":
+		parts := strings.SplitN(value, ";", 2)
+		parts[0] = redactCookie(parts[0])
+		return strings.Join(parts, ";")
+
+	default:
+		return redactedValue
+	}
+}
+
+func redactCookie(cookie string) string {
+	pos := strings.IndexByte(cookie, '=')
+	if pos < 0 {
+		return cookie
+	}
+	return cookie[:pos+1] + redactedValue
+}
+
+// redactDump masks given headers in serialized HTTP message.
+func redactDump(dump []byte, names []string) []byte {
+	if len(names) == 0 {
+		return dump
+	}
+	end := bytes.Index(dump, []byte("\r\n\r\n"))
+	if end < 0 {
+		end = len(dump)
+	}
+	lines := bytes.Split(dump[:end], []byte("\r\n"))
+	changed := false
+	// first line is request or status line
+	for i := 1; i < len(lines); i++ {
+		pos := bytes.IndexByte(lines[i], ':')
+		if pos < 0 {
+			continue
+		}
+		key := http.CanonicalHeaderKey(string(bytes.TrimSpace(lines[i][:pos])))
+		if !containsHeaderName(names, key) {
+			continue
+		}
+		value := strings.TrimSpace(string(lines[i][pos+1:]))
+		lines[i] = []byte(string(lines[i][:pos]) + ": " + redactHeaderValue(key, value))
+		changed = true
+	}
+	if !changed {
+		return dump
+	}
+	ret := bytes.Join(lines, []byte("\r\n"))
+	return append(ret, dump[end:]...)
+}
+
+func containsHeaderName(names []string, key string) bool {
+	for _, name := range names {
+		if http.CanonicalHeaderKey(name) == key {
+			return true
+		}
+	}
+	return false
+}
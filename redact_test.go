@@ -0,0 +1,64 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{
+		"Authorization": {"Bearer abc"},
+		"Cookie":        {"a=1; b=2"},
+		"Set-Cookie":    {"sid=xyz; Path=/; HttpOnly", "flag"},
+		"Accept":        {"*/*"},
+	}
+
+	redacted, ok := redactHeader(header, []string{"authorization", "Cookie", "Set-Cookie"})
+	assert.True(t, ok)
+
+	assert.Equal(t, http.Header{
+		"Authorization": {"*****"},
+		"Cookie":        {"a=*****; b=*****"},
+		"Set-Cookie":    {"sid=*****; Path=/; HttpOnly", "flag"},
+		"Accept":        {"*/*"},
+	}, redacted)
+
+	assert.Equal(t, "Bearer abc", header.Get("Authorization"))
+
+	_, ok = redactHeader(header, []string{"Proxy-Authorization"})
+	assert.False(t, ok)
+
+	_, ok = redactHeader(header, nil)
+	assert.False(t, ok)
+}
+
+func TestRedactRequest(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	assert.Same(t, req, redactRequest(req, DefaultRedactHeaders))
+
+	req.SetBasicAuth("john", "secret")
+
+	redacted := redactRequest(req, DefaultRedactHeaders)
+	assert.NotSame(t, req, redacted)
+	assert.Equal(t, "*****", redacted.Header.Get("Authorization"))
+	assert.NotEqual(t, "*****", req.Header.Get("Authorization"))
+}
+
+func TestRedactDump(t *testing.T) {
+	dump := []byte("GET / HTTP/1.1\r\nHost: example.com\r\n" +
+		"authorization: Basic abc\r\nCookie: a=1\r\n\r\nAuthorization: body")
+
+	assert.Equal(t, "GET / HTTP/1.1\r\nHost: example.com\r\n"+
+		"authorization: *****\r\nCookie: a=*****\r\n\r\nAuthorization: body",
+		string(redactDump(dump, []string{"Authorization", "Cookie"})))
+
+	assert.Equal(t, string(dump), string(redactDump(dump, nil)))
+	assert.Equal(t, string(dump), string(redactDump(dump, []string{"X-Foo"})))
+
+	assert.Equal(t, "HTTP/1.1 200 OK\r\nSet-Cookie: a=*****; Path=/",
+		string(redactDump([]byte("HTTP/1.1 200 OK\r\nSet-Cookie: a=b; Path=/"),
+			[]string{"Set-Cookie"})))
+}
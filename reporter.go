@@ -2,39 +2,331 @@ package httpexpect
 
 import (
 	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// FailureType defines kind of failure.
+type FailureType int
+
+const (
+	// FailureAssertion is reported when checked value doesn't satisfy
+	// assertion, e.g. when response status doesn't match expected one.
+	FailureAssertion FailureType = iota
+
+	// FailureAssertUsage is reported when assertion itself is used
+	// incorrectly, e.g. when invalid or nil arguments are passed to it,
+	// or when it's called in wrong state.
+	FailureAssertUsage
+
+	// FailureAssertBelongs is reported when checked value unexpectedly
+	// belongs or doesn't belong to given list of values.
+	FailureAssertBelongs
+)
+
+// String returns name of failure type, e.g. "FailureAssertUsage".
+func (t FailureType) String() string {
+	switch t {
+	case FailureAssertion:
+		return "FailureAssertion"
+	case FailureAssertUsage:
+		return "FailureAssertUsage"
+	case FailureAssertBelongs:
+		return "FailureAssertBelongs"
+	default:
+		return "FailureType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Failure contains structured information about failed assertion.
+type Failure struct {
+	// Kind of failure.
+	Type FailureType
+
+	// Failure message, without path, request ID and assertion location.
+	Message string
+
+	// Path of the checked value, e.g. "JSON.items[0]". May be empty.
+	Path string
+
+	// ID of the request, if Config.RequestIDFunc is set. May be empty.
+	RequestID string
+
+	// Location of the failed assertion, "file:line". May be empty, e.g.
+	// if reporter reports location by itself.
+	Assertion string
+
+	// Name of the failed assertion method, e.g. "Number.Equal". May be empty.
+	AssertionName string
+
+	// Expected value, if assertion compares value with expected one.
+	// May be nil.
+	Expected *FailureValue
+
+	// Actual value, if available. May be nil.
+	Actual *FailureValue
+
+	// Range bounds, if assertion checks that value is in range. May be nil.
+	Bounds *FailureBounds
+}
+
+// FailureValue holds expected or actual value of Failure.
+//
+// Value is wrapped into a struct, so that nil Value (e.g. JSON null) can
+// be distinguished from missing value.
+type FailureValue struct {
+	Value interface{}
+}
+
+// FailureBounds holds range bounds of Failure.
+type FailureBounds struct {
+	Min interface{}
+	Max interface{}
+}
+
+// String returns failure message with path, request ID and assertion
+// location appended, as passed to Reporter.Errorf.
+func (f Failure) String() string {
+	var b strings.Builder
+	b.WriteString(f.Message)
+	if f.Assertion != "" {
+		b.WriteString("\n\nassertion:\n ")
+		b.WriteString(f.Assertion)
+	}
+	if f.Path != "" {
+		b.WriteString("\n\npath:\n ")
+		b.WriteString(f.Path)
+	}
+	if f.RequestID != "" {
+		b.WriteString("\n\nrequest id:\n ")
+		b.WriteString(f.RequestID)
+	}
+	return b.String()
+}
+
+// FailureReporter is optional interface that may be implemented by
+// Reporter to receive structured failure information instead of
+// formatted message.
+//
+// If reporter implements FailureReporter, ReportFailure is called
+// instead of Errorf.
+type FailureReporter interface {
+	ReportFailure(Failure)
+}
+
 // AssertReporter implements Reporter interface using `testify/assert'
 // package. Failures are non-fatal with this reporter.
+//
+// Example:
+//  func TestSomething(t *testing.T) {
+//      e := httpexpect.WithConfig(httpexpect.Config{
+//          BaseURL:  "http://example.com/",
+//          Reporter: httpexpect.NewAssertReporter(t),
+//      })
+//  }
 type AssertReporter struct {
+	t       assert.TestingT
 	backend *assert.Assertions
 }
 
 // NewAssertReporter returns a new AssertReporter object.
 func NewAssertReporter(t assert.TestingT) *AssertReporter {
-	return &AssertReporter{assert.New(t)}
+	return &AssertReporter{t, assert.New(t)}
 }
 
 // Errorf implements Reporter.Errorf.
 func (r *AssertReporter) Errorf(message string, args ...interface{}) {
+	if h, ok := r.t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
 	r.backend.Fail(fmt.Sprintf(message, args...))
 }
 
 // RequireReporter implements Reporter interface using `testify/require'
 // package. Failures fatal with this reporter.
+//
+// FailNow stops only the goroutine that calls it, and testing package
+// requires it to be called from the goroutine running the test. Hence, if
+// failure is reported from another goroutine (e.g. from http handler or
+// from a goroutine spawned by the test), RequireReporter degrades to
+// non-fatal failure, and logs a note about it using t.Logf, if available.
+//
+// Goroutine is considered running a test if its call stack contains
+// function of testing package that runs tests. The check is performed
+// only if reporter was created on such goroutine, so that reporters used
+// outside of testing package are not affected.
+//
+// Example:
+//  func TestSomething(t *testing.T) {
+//      e := httpexpect.WithConfig(httpexpect.Config{
+//          BaseURL:  "http://example.com/",
+//          Reporter: httpexpect.NewRequireReporter(t),
+//      })
+//  }
 type RequireReporter struct {
-	backend *require.Assertions
+	t         require.TestingT
+	backend   *require.Assertions
+	checkTest bool
 }
 
 // NewRequireReporter returns a new RequireReporter object.
+//
+// It should be called from the goroutine running the test.
 func NewRequireReporter(t require.TestingT) *RequireReporter {
-	return &RequireReporter{require.New(t)}
+	return &RequireReporter{t, require.New(t), onTestGoroutine()}
 }
 
 // Errorf implements Reporter.Errorf.
 func (r *RequireReporter) Errorf(message string, args ...interface{}) {
+	if h, ok := r.t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if r.checkTest && !onTestGoroutine() {
+		if l, ok := r.t.(interface {
+			Logf(string, ...interface{})
+		}); ok {
+			l.Logf("RequireReporter invoked from non-test goroutine," +
+				" failure is reported as non-fatal")
+		}
+		assert.Fail(r.t, fmt.Sprintf(message, args...))
+		return
+	}
 	r.backend.FailNow(fmt.Sprintf(message, args...))
 }
+
+// MultiReporter implements Reporter interface by forwarding every failure
+// to several reporters, in the order they were given.
+//
+// If some reporter panics or stops the goroutine (e.g. RequireReporter
+// calling t.FailNow), remaining reporters are still invoked, and then
+// the panic or goroutine exit continues.
+//
+// Example:
+//  func TestSomething(t *testing.T) {
+//      e := httpexpect.WithConfig(httpexpect.Config{
+//          BaseURL:  "http://example.com/",
+//          Reporter: httpexpect.NewMultiReporter(
+//              httpexpect.NewRequireReporter(t),
+//              httpexpect.NewWriterReporter(logFile),
+//          ),
+//      })
+//  }
+type MultiReporter struct {
+	reporters []Reporter
+}
+
+// NewMultiReporter returns a new MultiReporter object.
+func NewMultiReporter(reporters ...Reporter) *MultiReporter {
+	return &MultiReporter{append([]Reporter(nil), reporters...)}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *MultiReporter) Errorf(message string, args ...interface{}) {
+	r.report(0, func(reporter Reporter) {
+		reporter.Errorf(message, args...)
+	})
+}
+
+// ReportFailure implements FailureReporter.ReportFailure.
+//
+// Failure is passed to reporters implementing FailureReporter as is, and
+// to other reporters as formatted message.
+func (r *MultiReporter) ReportFailure(failure Failure) {
+	r.report(0, func(reporter Reporter) {
+		if fr, ok := reporter.(FailureReporter); ok {
+			fr.ReportFailure(failure)
+		} else {
+			reporter.Errorf("%s", failure.String())
+		}
+	})
+}
+
+// report invokes fn for reporter i, and then, from a deferred call, for
+// remaining reporters, so that they run even if reporter i panics or calls
+// runtime.Goexit.
+func (r *MultiReporter) report(i int, fn func(Reporter)) {
+	if i >= len(r.reporters) {
+		return
+	}
+	defer r.report(i+1, fn)
+	fn(r.reporters[i])
+}
+
+// WriterReporter implements Reporter interface by writing every failure
+// message, followed by a newline, to io.Writer. It may be used outside
+// of tests, or combined with other reporters using MultiReporter.
+//
+// WriterReporter is safe for concurrent use. Write errors are ignored.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.com/",
+//      Reporter: httpexpect.NewWriterReporter(os.Stderr),
+//  })
+type WriterReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterReporter returns a new WriterReporter object.
+func NewWriterReporter(w io.Writer) *WriterReporter {
+	return &WriterReporter{w: w}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *WriterReporter) Errorf(message string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, _ = fmt.Fprintf(r.w, message+"\n", args...)
+}
+
+// NopReporter implements Reporter interface by ignoring all failures.
+// It may be used for dry runs.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.com/",
+//      Reporter: httpexpect.NopReporter{},
+//  })
+type NopReporter struct{}
+
+// Errorf implements Reporter.Errorf.
+func (NopReporter) Errorf(message string, args ...interface{}) {}
+
+// ReportFailure implements FailureReporter.ReportFailure.
+func (NopReporter) ReportFailure(failure Failure) {}
+
+// testRunnerFunc is the function of testing package that runs tests,
+// benchmarks, and subtests, each on its own goroutine.
+const testRunnerFunc = "testing.tRunner"
+
+// onTestGoroutine returns true if current goroutine runs a test, i.e. if
+// testing.tRunner is found among frames of its call stack.
+func onTestGoroutine() bool {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function == testRunnerFunc {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
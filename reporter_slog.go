@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+package httpexpect
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// SlogReporter implements Reporter and FailureReporter interfaces by
+// logging every failure as a structured record using slog.Logger. It may
+// be used outside of tests, e.g. in smoke-test binaries.
+//
+// Failures are logged with given level. Failure path, request ID and
+// assertion location, if present, are added as "path", "request_id" and
+// "assertion" attributes. Failure type other than FailureAssertion is added
+// as "type" attribute.
+//
+// SlogReporter is available only when built with Go 1.21 or later.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.com/",
+//      Reporter: httpexpect.NewSlogReporter(slog.Default(), slog.LevelError),
+//  })
+type SlogReporter struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// NewSlogReporter returns a new SlogReporter object.
+//
+// If logger is nil, slog.Default() is used.
+func NewSlogReporter(logger *slog.Logger, level slog.Level) *SlogReporter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogReporter{logger, level}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *SlogReporter) Errorf(message string, args ...interface{}) {
+	r.logger.Log(context.Background(), r.level,
+		strings.TrimSpace(fmt.Sprintf(message, args...)))
+}
+
+// ReportFailure implements FailureReporter.ReportFailure.
+func (r *SlogReporter) ReportFailure(failure Failure) {
+	var attrs []interface{}
+	if failure.Type != FailureAssertion {
+		attrs = append(attrs, slog.String("type", failure.Type.String()))
+	}
+	if failure.Path != "" {
+		attrs = append(attrs, slog.String("path", failure.Path))
+	}
+	if failure.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", failure.RequestID))
+	}
+	if failure.Assertion != "" {
+		attrs = append(attrs, slog.String("assertion", failure.Assertion))
+	}
+	r.logger.Log(context.Background(), r.level,
+		strings.TrimSpace(failure.Message), attrs...)
+}
@@ -0,0 +1,84 @@
+//go:build go1.21
+// +build go1.21
+
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogReporter(t *testing.T) {
+	newReporter := func(buf *bytes.Buffer) *SlogReporter {
+		logger := slog.New(slog.NewJSONHandler(buf, nil))
+		return NewSlogReporter(logger, slog.LevelWarn)
+	}
+
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+
+	t.Run("errorf", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		newReporter(buf).Errorf("\nfailure %d", 1)
+
+		record := decode(t, buf)
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "failure 1", record["msg"])
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		chain := makeChain(newReporter(buf))
+		chain.requestID = "req-1"
+		chain = chain.enter("JSON")
+		chain = chain.enterIndex(2)
+
+		chain.fail("\nexpected %s", "something")
+
+		record := decode(t, buf)
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "expected something", record["msg"])
+		assert.Equal(t, "JSON[2]", record["path"])
+		assert.Equal(t, "req-1", record["request_id"])
+		assert.Contains(t, record["assertion"], "reporter_slog_test.go")
+	})
+
+	t.Run("no attributes", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		newReporter(buf).ReportFailure(Failure{Message: "failure"})
+
+		record := decode(t, buf)
+		assert.Equal(t, "failure", record["msg"])
+		assert.NotContains(t, record, "path")
+		assert.NotContains(t, record, "request_id")
+		assert.NotContains(t, record, "assertion")
+	})
+
+	t.Run("multi", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		mockT := &mockTestingT{}
+
+		chain := makeChain(NewMultiReporter(
+			NewAssertReporter(mockT),
+			newReporter(buf),
+		))
+		chain = chain.enter("Body")
+
+		chain.fail("failure")
+
+		record := decode(t, buf)
+		assert.Equal(t, "Body", record["path"])
+		assert.Equal(t, 1, mockT.errors)
+	})
+}
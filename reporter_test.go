@@ -0,0 +1,285 @@
+package httpexpect
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTestingT struct {
+	mu       sync.Mutex
+	errors   int
+	failNows int
+	logs     int
+}
+
+func (t *mockTestingT) Errorf(message string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors++
+}
+
+func (t *mockTestingT) FailNow() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failNows++
+}
+
+func (t *mockTestingT) Logf(message string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.logs++
+}
+
+func TestAssertReporter(t *testing.T) {
+	mockT := &mockTestingT{}
+
+	reporter := NewAssertReporter(mockT)
+	reporter.Errorf("failure %d", 1)
+
+	assert.Equal(t, 1, mockT.errors)
+	assert.Equal(t, 0, mockT.failNows)
+}
+
+func TestRequireReporter(t *testing.T) {
+	t.Run("test goroutine", func(t *testing.T) {
+		mockT := &mockTestingT{}
+
+		reporter := NewRequireReporter(mockT)
+		reporter.Errorf("failure %d", 1)
+
+		assert.Equal(t, 1, mockT.errors)
+		assert.NotZero(t, mockT.failNows)
+		assert.Equal(t, 0, mockT.logs)
+	})
+
+	t.Run("other goroutine", func(t *testing.T) {
+		mockT := &mockTestingT{}
+
+		reporter := NewRequireReporter(mockT)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reporter.Errorf("failure %d", 1)
+		}()
+		wg.Wait()
+
+		assert.Equal(t, 1, mockT.errors)
+		assert.Equal(t, 0, mockT.failNows)
+		assert.Equal(t, 1, mockT.logs)
+	})
+
+	t.Run("created outside of test", func(t *testing.T) {
+		mockT := &mockTestingT{}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reporter := NewRequireReporter(mockT)
+			reporter.Errorf("failure %d", 1)
+		}()
+		wg.Wait()
+
+		assert.Equal(t, 1, mockT.errors)
+		assert.NotZero(t, mockT.failNows)
+		assert.Equal(t, 0, mockT.logs)
+	})
+}
+
+type funcReporter func(message string, args ...interface{})
+
+func (f funcReporter) Errorf(message string, args ...interface{}) {
+	f(message, args...)
+}
+
+func TestMultiReporter(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		var calls []string
+
+		reporter := NewMultiReporter(
+			funcReporter(func(message string, args ...interface{}) {
+				calls = append(calls, "first: "+fmt.Sprintf(message, args...))
+			}),
+			funcReporter(func(message string, args ...interface{}) {
+				calls = append(calls, "second: "+fmt.Sprintf(message, args...))
+			}),
+		)
+		reporter.Errorf("failure %d", 1)
+
+		assert.Equal(t, []string{"first: failure 1", "second: failure 1"}, calls)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var calls []string
+
+		reporter := NewMultiReporter(
+			funcReporter(func(message string, args ...interface{}) {
+				calls = append(calls, "first")
+			}),
+			funcReporter(func(message string, args ...interface{}) {
+				panic("second")
+			}),
+			funcReporter(func(message string, args ...interface{}) {
+				calls = append(calls, "third")
+			}),
+		)
+
+		assert.PanicsWithValue(t, "second", func() {
+			reporter.Errorf("failure")
+		})
+		assert.Equal(t, []string{"first", "third"}, calls)
+	})
+
+	t.Run("goexit", func(t *testing.T) {
+		mockT := &mockTestingT{}
+		writer := &bytes.Buffer{}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reporter := NewMultiReporter(
+				funcReporter(func(message string, args ...interface{}) {
+					runtime.Goexit()
+				}),
+				NewWriterReporter(writer),
+				NewAssertReporter(mockT),
+			)
+			reporter.Errorf("failure")
+		}()
+		wg.Wait()
+
+		assert.Equal(t, "failure\n", writer.String())
+		assert.Equal(t, 1, mockT.errors)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		reporter := NewMultiReporter()
+		assert.NotPanics(t, func() {
+			reporter.Errorf("failure")
+		})
+	})
+}
+
+func TestWriterReporter(t *testing.T) {
+	writer := &bytes.Buffer{}
+
+	reporter := NewWriterReporter(writer)
+	reporter.Errorf("failure %d", 1)
+	reporter.Errorf("failure %s", "two")
+
+	assert.Equal(t, "failure 1\nfailure two\n", writer.String())
+}
+
+type failureRecordingReporter struct {
+	failures []Failure
+	errors   []string
+}
+
+func (r *failureRecordingReporter) Errorf(message string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(message, args...))
+}
+
+func (r *failureRecordingReporter) ReportFailure(failure Failure) {
+	r.failures = append(r.failures, failure)
+}
+
+func TestFailureReporter(t *testing.T) {
+	reporter := &failureRecordingReporter{}
+
+	chain := makeChain(reporter)
+	chain.requestID = "req-1"
+	chain = chain.enter("JSON")
+
+	chain.fail("\nexpected %d", 1)
+
+	assert.Empty(t, reporter.errors)
+	require.Equal(t, 1, len(reporter.failures))
+
+	failure := reporter.failures[0]
+	assert.Equal(t, "\nexpected 1", failure.Message)
+	assert.Equal(t, "JSON", failure.Path)
+	assert.Equal(t, "req-1", failure.RequestID)
+	assert.Contains(t, failure.Assertion, "reporter_test.go")
+
+	assert.Equal(t, "\nexpected 1"+
+		"\n\nassertion:\n "+failure.Assertion+
+		"\n\npath:\n JSON"+
+		"\n\nrequest id:\n req-1", failure.String())
+}
+
+func TestFailureReporterAssertionName(t *testing.T) {
+	reporter := &failureRecordingReporter{}
+
+	NewNumber(reporter, 1).Equal(2)
+	NewObject(reporter, map[string]interface{}{}).ValueEqual("foo", 1)
+
+	require.Equal(t, 2, len(reporter.failures))
+
+	assert.Equal(t, "Number.Equal", reporter.failures[0].AssertionName)
+	assert.Equal(t, "Object.ValueEqual", reporter.failures[1].AssertionName)
+
+	assert.Equal(t, "Number.Equal",
+		assertionName("github.com/gavv/httpexpect/v2.(*Number).Equal"))
+	assert.Equal(t, "NewResponse",
+		assertionName("github.com/gavv/httpexpect/v2.NewResponse"))
+}
+
+func TestFailureReporterRecorder(t *testing.T) {
+	reporter := &failureRecordingReporter{}
+
+	chain := makeChain(reporter)
+	recChain, rec := chain.record()
+
+	recChain.fail("failure")
+
+	assert.True(t, rec.failed)
+	assert.Equal(t, 1, len(reporter.failures))
+}
+
+func TestMultiReporterFailure(t *testing.T) {
+	structured := &failureRecordingReporter{}
+	writer := &bytes.Buffer{}
+
+	reporter := NewMultiReporter(structured, NewWriterReporter(writer))
+	reporter.ReportFailure(Failure{Message: "failure", Path: "Body"})
+
+	assert.Equal(t, []Failure{{Message: "failure", Path: "Body"}}, structured.failures)
+	assert.Equal(t, "failure\n\npath:\n Body\n", writer.String())
+}
+
+func TestNopReporter(t *testing.T) {
+	chain := makeChain(NopReporter{})
+	chain.fail("failure")
+	assert.True(t, chain.failed())
+
+	assert.NotPanics(t, func() {
+		NopReporter{}.Errorf("failure %d", 1)
+	})
+}
+
+func TestOnTestGoroutine(t *testing.T) {
+	assert.True(t, onTestGoroutine())
+
+	t.Run("subtest", func(t *testing.T) {
+		assert.True(t, onTestGoroutine())
+	})
+
+	var other bool
+	done := make(chan struct{})
+	go func() {
+		other = onTestGoroutine()
+		close(done)
+	}()
+	<-done
+
+	assert.False(t, other)
+}
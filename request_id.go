@@ -0,0 +1,62 @@
+package httpexpect
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type requestIDKey struct{}
+
+// NewRequestID returns a new random request ID consisting of 8 hex digits.
+//
+// May be used as Config.RequestIDFunc.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      Reporter:        httpexpect.NewAssertReporter(t),
+//      RequestIDFunc:   httpexpect.NewRequestID,
+//      RequestIDHeader: "X-Request-Id",
+//  })
+func NewRequestID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// newUUID returns a new random (version 4) UUID in canonical form.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// RequestIDFromContext returns request ID stored in request context, or
+// empty string if there is no request ID.
+//
+// Request ID is stored in context of every request if Config.RequestIDFunc
+// is set. It may be used in custom printers and clients.
+//
+// Example:
+//  func (p MyPrinter) Request(req *http.Request) {
+//      id := httpexpect.RequestIDFromContext(req.Context())
+//      ...
+//  }
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
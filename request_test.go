@@ -2,18 +2,27 @@ package httpexpect
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"runtime"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func TestRequestFailed(t *testing.T) {
@@ -34,6 +43,7 @@ func TestRequestFailed(t *testing.T) {
 	}
 
 	req.WithClient(&http.Client{})
+	req.WithTLSConfig(&tls.Config{})
 	req.WithHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
 	req.WithPath("foo", "bar")
 	req.WithPathObject(map[string]interface{}{"foo": "bar"})
@@ -46,11 +56,15 @@ func TestRequestFailed(t *testing.T) {
 	req.WithCookies(map[string]string{"foo": "bar"})
 	req.WithCookie("foo", "bar")
 	req.WithBasicAuth("foo", "bar")
+	req.WithAccept("application/json")
 	req.WithProto("HTTP/1.1")
 	req.WithChunked(strings.NewReader("foo"))
 	req.WithBytes([]byte("foo"))
 	req.WithText("foo")
 	req.WithJSON(map[string]string{"foo": "bar"})
+	req.WithJSONFile("foo")
+	req.WithJSONOverride("foo", "bar")
+	req.WithJSONOverrideStrict("foo", "bar")
 	req.WithForm(map[string]string{"foo": "bar"})
 	req.WithFormField("foo", "bar")
 	req.WithFile("foo", "bar", strings.NewReader("baz"))
@@ -165,6 +179,95 @@ func TestRequestClient(t *testing.T) {
 	req3.chain.assertFailed(t)
 }
 
+func TestRequestTLSConfig(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+
+	t.Run("default transport", func(t *testing.T) {
+		client := &http.Client{}
+
+		config := Config{
+			RequestFactory: factory,
+			Reporter:       newMockReporter(t),
+			Client:         client,
+		}
+
+		req := NewRequest(config, "METHOD", "/")
+		req.WithTLSConfig(tlsConfig)
+		req.chain.assertOK(t)
+
+		reqClient := req.config.Client.(*http.Client)
+		assert.False(t, reqClient == client)
+		assert.Nil(t, client.Transport)
+		assert.Equal(t, tlsConfig,
+			reqClient.Transport.(*http.Transport).TLSClientConfig)
+	})
+
+	t.Run("custom transport", func(t *testing.T) {
+		transport := &http.Transport{MaxIdleConns: 7}
+		jar := NewJar()
+		client := &http.Client{Transport: transport, Jar: jar}
+
+		config := Config{
+			RequestFactory: factory,
+			Reporter:       newMockReporter(t),
+			Client:         client,
+		}
+
+		req := NewRequest(config, "METHOD", "/")
+		req.WithTLSConfig(tlsConfig)
+		req.chain.assertOK(t)
+
+		reqClient := req.config.Client.(*http.Client)
+		reqTransport := reqClient.Transport.(*http.Transport)
+		assert.False(t, reqTransport == transport)
+		assert.Equal(t, 7, reqTransport.MaxIdleConns)
+		assert.Equal(t, tlsConfig, reqTransport.TLSClientConfig)
+		assert.False(t, transport.TLSClientConfig != nil &&
+			transport.TLSClientConfig.InsecureSkipVerify)
+		assert.Equal(t, jar, reqClient.Jar)
+	})
+
+	t.Run("unsupported client", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Reporter:       newMockReporter(t),
+			Client:         &mockClient{},
+		}
+
+		req := NewRequest(config, "METHOD", "/")
+		req.WithTLSConfig(tlsConfig)
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("unsupported transport", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Reporter:       newMockReporter(t),
+			Client: &http.Client{
+				Transport: NewBinder(http.NotFoundHandler()),
+			},
+		}
+
+		req := NewRequest(config, "METHOD", "/")
+		req.WithTLSConfig(tlsConfig)
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("nil config", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Reporter:       newMockReporter(t),
+			Client:         &http.Client{},
+		}
+
+		req := NewRequest(config, "METHOD", "/")
+		req.WithTLSConfig(nil)
+		req.chain.assertFailed(t)
+	})
+}
+
 func TestRequestHandler(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -287,6 +390,103 @@ func TestRequestProto(t *testing.T) {
 	assert.Equal(t, 0, req.http.ProtoMinor)
 }
 
+func TestRequestID(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	t.Run("header", func(t *testing.T) {
+		client := &mockClient{}
+
+		reporter := newMockReporter(t)
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+			RequestIDFunc: func() string {
+				return "abcd1234"
+			},
+			RequestIDHeader: "X-Request-Id",
+		}
+
+		req := NewRequest(config, "GET", "url")
+		assert.Equal(t, "abcd1234", RequestIDFromContext(req.http.Context()))
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "abcd1234", client.req.Header.Get("X-Request-Id"))
+		assert.Equal(t, "abcd1234", RequestIDFromContext(client.req.Context()))
+
+		resp.Status(http.StatusTeapot)
+		assert.Contains(t, reporter.message, "abcd1234")
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       newMockReporter(t),
+			RequestIDFunc:  NewRequestID,
+		}
+
+		req := NewRequest(config, "GET", "url")
+		req.Expect().chain.assertOK(t)
+
+		assert.Equal(t, 8, len(RequestIDFromContext(client.req.Context())))
+		assert.Equal(t, "", client.req.Header.Get("X-Request-Id"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		client := &mockClient{}
+
+		reporter := newMockReporter(t)
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "GET", "url")
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "", RequestIDFromContext(client.req.Context()))
+
+		resp.Status(http.StatusTeapot)
+		assert.NotContains(t, reporter.message, "request id")
+	})
+
+	t.Run("from response", func(t *testing.T) {
+		client := &mockClient{}
+
+		reporter := newMockReporter(t)
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+			RequestIDFunc: func() string {
+				return "abcd1234"
+			},
+			RequestIDHeader:  "X-Request-Id",
+			ResponseIDHeader: "X-Server-Id",
+		}
+
+		req := NewRequest(config, "GET", "url")
+		req.WithHeader("X-Server-Id", "server-id")
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		resp.Status(http.StatusTeapot)
+		assert.Contains(t, reporter.message, "server-id")
+		assert.NotContains(t, reporter.message, "abcd1234")
+	})
+}
+
 func TestRequestURLConcatenate(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -342,6 +542,80 @@ func TestRequestURLConcatenate(t *testing.T) {
 	assert.Equal(t, "http://example.com/", empty3.http.URL.String())
 }
 
+func TestRequestURLConcatenateMatrix(t *testing.T) {
+	cases := []struct {
+		base   string
+		path   string
+		result string
+	}{
+		{"", "", ""},
+		{"", "/path", "/path"},
+		{"", "http://other.com/path", "http://other.com/path"},
+		{"http://example.com", "", "http://example.com"},
+		{"http://example.com", "/", "http://example.com/"},
+		{"http://example.com", "//path", "http://example.com/path"},
+		{"http://example.com/api/v1", "", "http://example.com/api/v1"},
+		{"http://example.com/api/v1", "users", "http://example.com/api/v1/users"},
+		{"http://example.com/api/v1", "/users", "http://example.com/api/v1/users"},
+		{"http://example.com/api/v1/", "", "http://example.com/api/v1/"},
+		{"http://example.com/api/v1/", "users", "http://example.com/api/v1/users"},
+		{"http://example.com/api/v1/", "/users", "http://example.com/api/v1/users"},
+		{"http://example.com/api/v1/", "//users", "http://example.com/api/v1/users"},
+		{"http://example.com/api/v1/", "/users/", "http://example.com/api/v1/users/"},
+		{"http://example.com/api/v1/", "users//1", "http://example.com/api/v1/users/1"},
+		{"http://example.com/api/v1/", "http://other.com/x/", "http://other.com/x/"},
+		{"http://example.com/api?key=1", "", "http://example.com/api?key=1"},
+		{"http://example.com/api?key=1", "/users", "http://example.com/api/users?key=1"},
+		{"http://example.com/api/?key=1", "/users/", "http://example.com/api/users/?key=1"},
+	}
+
+	for _, tc := range cases {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: DefaultRequestFactory{},
+			BaseURL:        tc.base,
+			Client:         client,
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", tc.path)
+		req.Expect().chain.assertOK(t)
+
+		assert.Equal(t, tc.result, req.http.URL.String(),
+			"base %q, path %q", tc.base, tc.path)
+	}
+
+	t.Run("absolute url host", func(t *testing.T) {
+		config := Config{
+			RequestFactory: DefaultRequestFactory{},
+			BaseURL:        "http://example.com",
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "http://other.com/path")
+		req.Expect().chain.assertOK(t)
+
+		assert.Equal(t, "other.com", req.http.Host)
+	})
+
+	t.Run("base url query", func(t *testing.T) {
+		config := Config{
+			RequestFactory: DefaultRequestFactory{},
+			BaseURL:        "http://example.com/api?key=1",
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/users").WithQuery("page", 2)
+		req.Expect().chain.assertOK(t)
+
+		assert.Equal(t, "http://example.com/api/users?key=1&page=2",
+			req.http.URL.String())
+	})
+}
+
 func TestRequestURLOverwrite(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -534,6 +808,298 @@ func TestRequestURLQuery(t *testing.T) {
 		WithQueryString("%").chain.assertFailed(t)
 }
 
+func TestRequestURLQueryEncoding(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+		BaseURL:        "http://example.com",
+	}
+
+	build := func(opts ...QueryEncodeOpts) *Request {
+		req := NewRequest(config, "METHOD", "/path")
+		if len(opts) != 0 {
+			req.WithQueryEncodingOptions(opts[0])
+		}
+		return req.
+			WithQuery("zz", "a b+c").
+			WithQueryString("yy=1,2&xx=10:30").
+			WithQuery("yy", "3")
+	}
+
+	cases := []struct {
+		name string
+		req  *Request
+		url  string
+	}{
+		{
+			name: "default",
+			req:  build(),
+			url:  "http://example.com/path?xx=10%3A30&yy=1%2C2&yy=3&zz=a+b%2Bc",
+		},
+		{
+			name: "zero opts",
+			req:  build(QueryEncodeOpts{}),
+			url:  "http://example.com/path?xx=10%3A30&yy=1%2C2&yy=3&zz=a+b%2Bc",
+		},
+		{
+			name: "space as percent",
+			req:  build(QueryEncodeOpts{SpaceAsPercent: true}),
+			url:  "http://example.com/path?xx=10%3A30&yy=1%2C2&yy=3&zz=a%20b%2Bc",
+		},
+		{
+			name: "keep commas",
+			req:  build(QueryEncodeOpts{KeepCommas: true}),
+			url:  "http://example.com/path?xx=10%3A30&yy=1,2&yy=3&zz=a+b%2Bc",
+		},
+		{
+			name: "keep colons",
+			req:  build(QueryEncodeOpts{KeepColons: true}),
+			url:  "http://example.com/path?xx=10:30&yy=1%2C2&yy=3&zz=a+b%2Bc",
+		},
+		{
+			name: "preserve order",
+			req:  build(QueryEncodeOpts{PreserveOrder: true}),
+			url:  "http://example.com/path?zz=a+b%2Bc&yy=1%2C2&yy=3&xx=10%3A30",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client.req = nil
+			tc.req.Expect()
+			tc.req.chain.assertOK(t)
+			assert.Equal(t, tc.url, client.req.URL.String())
+		})
+	}
+}
+
+func TestRequestIdempotencyKey(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "METHOD", "url")
+	assert.Equal(t, "", req1.IdempotencyKey())
+
+	req1.WithIdempotencyKey()
+	req1.chain.assertOK(t)
+
+	key := req1.IdempotencyKey()
+	assert.Regexp(t,
+		`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, key)
+	assert.Equal(t, key, req1.http.Header.Get("Idempotency-Key"))
+
+	req2 := NewRequest(config, "METHOD", "url").WithIdempotencyKey()
+	assert.NotEqual(t, key, req2.IdempotencyKey())
+
+	req3 := NewRequest(config, "METHOD", "url").WithIdempotencyKey(key)
+	req3.chain.assertOK(t)
+	assert.Equal(t, key, req3.IdempotencyKey())
+
+	resp := req3.Expect()
+	resp.chain.assertOK(t)
+	resp.Header("Idempotency-Key").Equal(key)
+
+	req4 := NewRequest(config, "METHOD", "url").WithIdempotencyKey("a", "b")
+	req4.chain.assertFailed(t)
+}
+
+func TestRequestUserAgent(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		RequestFactory:   DefaultRequestFactory{},
+		Client:           client,
+		Reporter:         newMockReporter(t),
+		DefaultUserAgent: "default/1.0",
+	}
+
+	NewRequest(config, "GET", "url").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, []string{"default/1.0"}, client.req.Header["User-Agent"])
+
+	NewRequest(config, "GET", "url").
+		WithUserAgent("custom/2.0").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, []string{"custom/2.0"}, client.req.Header["User-Agent"])
+
+	NewRequest(config, "GET", "url").
+		WithUserAgent("custom/2.0").
+		WithHeader("User-Agent", "explicit/3.0").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, []string{"explicit/3.0"}, client.req.Header["User-Agent"])
+
+	NewRequest(config, "GET", "url").
+		WithHeader("User-Agent", "explicit/3.0").
+		WithUserAgent("custom/2.0").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, []string{"explicit/3.0"}, client.req.Header["User-Agent"])
+
+	NewRequest(config, "GET", "url").
+		WithUserAgent("").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, "", client.req.Header.Get("User-Agent"))
+
+	config.DefaultUserAgent = NoUserAgent
+
+	NewRequest(config, "GET", "url").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, "", client.req.Header.Get("User-Agent"))
+
+	NewRequest(config, "GET", "url").
+		WithUserAgent("custom/2.0").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, []string{"custom/2.0"}, client.req.Header["User-Agent"])
+
+	config.DefaultUserAgent = ""
+
+	NewRequest(config, "GET", "url").
+		Expect().chain.assertOK(t)
+	assert.Nil(t, client.req.Header["User-Agent"])
+
+	assert.Equal(t, "httpexpect/v2", config.withDefaults().DefaultUserAgent)
+}
+
+func TestRequestWithoutHeader(t *testing.T) {
+	client := &mockClient{}
+
+	e := WithConfig(Config{
+		Client:           client,
+		Reporter:         newMockReporter(t),
+		RequestIDFunc:    func() string { return "req-id" },
+		RequestIDHeader:  "X-Request-Id",
+		DefaultUserAgent: "default/1.0",
+	})
+
+	auth := e.Builder(func(req *Request) {
+		req.WithHeader("Authorization", "Bearer token")
+		req.WithHeader("X-Tenant", "a")
+	})
+
+	auth.GET("/url").Expect().chain.assertOK(t)
+	assert.Equal(t, "Bearer token", client.req.Header.Get("Authorization"))
+	assert.Equal(t, "req-id", client.req.Header.Get("X-Request-Id"))
+	assert.Equal(t, "default/1.0", client.req.Header.Get("User-Agent"))
+
+	auth.GET("/url").
+		WithoutHeader("authorization").
+		WithoutHeader("X-Request-Id").
+		WithoutHeader("User-Agent").
+		WithoutHeader("X-Never-Added").
+		Expect().chain.assertOK(t)
+
+	_, hasAuth := client.req.Header["Authorization"]
+	_, hasID := client.req.Header["X-Request-Id"]
+	assert.False(t, hasAuth)
+	assert.False(t, hasID)
+	assert.Equal(t, "", client.req.Header.Get("User-Agent"))
+	assert.Equal(t, "a", client.req.Header.Get("X-Tenant"))
+
+	// overriding default header
+	auth.GET("/url").
+		WithoutHeader("Authorization").
+		WithHeader("Authorization", "Bearer other").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, []string{"Bearer other"}, client.req.Header["Authorization"])
+
+	auth.GET("/url").
+		WithoutHeader("Content-Type").
+		WithJSON(map[string]interface{}{"a": 1}).
+		WithoutHeader("Content-Type").
+		Expect().chain.assertOK(t)
+	assert.Equal(t, "", client.req.Header.Get("Content-Type"))
+}
+
+func TestRequestWithoutQuery(t *testing.T) {
+	client := &mockClient{}
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com/api?token=secret&v=2",
+		Client:   client,
+		Reporter: newMockReporter(t),
+	})
+
+	e2 := e.Builder(func(req *Request) {
+		req.WithQuery("page", 1).WithQuery("lang", "en")
+	})
+
+	e2.GET("/url").Expect().chain.assertOK(t)
+	assert.Equal(t,
+		"http://example.com/api/url?token=secret&v=2&lang=en&page=1",
+		client.req.URL.String())
+
+	e2.GET("/url").
+		WithoutQuery("token").
+		WithoutQuery("page").
+		WithoutQuery("never-added").
+		Expect().chain.assertOK(t)
+	assert.Equal(t,
+		"http://example.com/api/url?v=2&lang=en",
+		client.req.URL.String())
+
+	e2.GET("/url").
+		WithoutQuery("page").
+		WithQuery("page", 5).
+		Expect().chain.assertOK(t)
+	assert.Equal(t,
+		"http://example.com/api/url?token=secret&v=2&lang=en&page=5",
+		client.req.URL.String())
+}
+
+func TestRequestWithoutCookie(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for _, c := range r.Cookies() {
+			names = append(names, c.Name)
+		}
+		sort.Strings(names)
+		_, _ = w.Write([]byte(strings.Join(names, ",")))
+	})
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &http.Client{
+			Transport: NewBinder(handler),
+			Jar:       NewJar(),
+		},
+		Reporter: newMockReporter(t),
+	})
+
+	u, _ := url.Parse("http://example.com")
+	e.config.Client.(*http.Client).Jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "s"},
+		{Name: "pref", Value: "p"},
+	})
+
+	e2 := e.Builder(func(req *Request) {
+		req.WithCookie("a", "1").WithCookie("b", "2")
+	})
+
+	e2.GET("/").Expect().Body().Equal("a,b,pref,session")
+
+	e2.GET("/").
+		WithoutCookie("a").
+		WithoutCookie("session").
+		WithoutCookie("never-added").
+		Expect().Body().Equal("b,pref")
+
+	e2.GET("/").
+		WithoutCookie("a").
+		WithCookie("a", "3").
+		Expect().Body().Equal("a,b,pref,session")
+
+	e2.GET("/").Expect().Body().Equal("a,b,pref,session")
+}
+
 func TestRequestHeaders(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -574,6 +1140,113 @@ func TestRequestHeaders(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
+func TestRequestHeaderAdd(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req := NewRequest(config, "METHOD", "url").
+		WithHeader("Accept-Language", "en").
+		WithHeaderAdd("accept-language", "fr").
+		WithHeaderAdd("X-Trace", "a").
+		WithoutHeader("X-Trace").
+		WithHeaderAdd("X-Trace", "b")
+
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, []string{"en", "fr"}, client.req.Header["Accept-Language"])
+	assert.Equal(t, []string{"b"}, client.req.Header["X-Trace"])
+
+	req1 := NewRequest(config, "METHOD", "url").WithHeaderAdd("host", "example.com")
+	req1.chain.assertFailed(t)
+
+	req2 := NewRequest(config, "METHOD", "url").WithHeaderAdd("Content-Type", "a")
+	req2.chain.assertFailed(t)
+}
+
+func TestRequestAccept(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	t.Run("preference order", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithAccept("application/json", "application/xml", "*/*")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "application/json, application/xml;q=0.9, */*;q=0.8",
+			req.http.Header.Get("Accept"))
+	})
+
+	t.Run("single", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithAccept("text/html")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "text/html", req.http.Header.Get("Accept"))
+	})
+
+	t.Run("many", func(t *testing.T) {
+		var types []string
+		for i := 0; i < 20; i++ {
+			types = append(types, fmt.Sprintf("type/t%d", i))
+		}
+
+		req := NewRequest(config, "GET", "url")
+
+		req.WithAccept(types...)
+		req.chain.assertOK(t)
+
+		parts := strings.Split(req.http.Header.Get("Accept"), ", ")
+		assert.Equal(t, 20, len(parts))
+		assert.Equal(t, "type/t0", parts[0])
+		assert.Equal(t, "type/t1;q=0.95", parts[1])
+		assert.Equal(t, "type/t19;q=0.05", parts[19])
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithHeader("Accept", "text/plain")
+		req.WithAccept("text/html")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, []string{"text/html"}, req.http.Header.Values("Accept"))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithAccept()
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("empty type", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithAccept("text/html", "")
+		req.chain.assertFailed(t)
+	})
+}
+
 func TestRequestCookies(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -663,7 +1336,7 @@ func TestRequestBodyChunked(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
-func TestRequestBodyChunkedNil(t *testing.T) {
+func TestRequestBodyReader(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
 	client := &mockClient{}
@@ -676,18 +1349,49 @@ func TestRequestBodyChunkedNil(t *testing.T) {
 		Reporter:       reporter,
 	}
 
-	req := NewRequest(config, "METHOD", "url")
+	req1 := NewRequest(config, "METHOD", "url").
+		WithHeader("Content-Type", "application/octet-stream").
+		WithReader(bytes.NewBufferString("body"), 4)
 
-	req.WithChunked(nil)
+	resp1 := req1.Expect()
+	resp1.chain.assertOK(t)
 
-	resp := req.Expect()
-	resp.chain.assertOK(t)
+	assert.Equal(t, int64(4), client.req.ContentLength)
+	assert.Equal(t, "application/octet-stream", client.req.Header.Get("Content-Type"))
+	assert.Equal(t, "body", string(resp1.content))
+
+	req2 := NewRequest(config, "METHOD", "url").
+		WithReader(bytes.NewBufferString("body"), -1)
+
+	resp2 := req2.Expect()
+	resp2.chain.assertOK(t)
+
+	assert.Equal(t, int64(-1), client.req.ContentLength)
+	assert.Equal(t, "body", string(resp2.content))
+
+	req3 := NewRequest(config, "METHOD", "url").WithReader(nil, 0)
+
+	req3.Expect().chain.assertOK(t)
 
 	assert.True(t, client.req.Body == nil)
 	assert.Equal(t, int64(0), client.req.ContentLength)
+
+	req4 := NewRequest(config, "METHOD", "url").
+		WithReader(bytes.NewBufferString("body"), -2)
+	req4.chain.assertFailed(t)
+
+	req5 := NewRequest(config, "METHOD", "url").
+		WithProto("HTTP/1.0").
+		WithReader(bytes.NewBufferString("body"), -1)
+	req5.chain.assertFailed(t)
+
+	req6 := NewRequest(config, "METHOD", "url").
+		WithText("text").
+		WithReader(bytes.NewBufferString("body"), 4)
+	req6.chain.assertFailed(t)
 }
 
-func TestRequestBodyChunkedProto(t *testing.T) {
+func TestRequestBodyChunkedNil(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
 	client := &mockClient{}
@@ -700,14 +1404,38 @@ func TestRequestBodyChunkedProto(t *testing.T) {
 		Reporter:       reporter,
 	}
 
-	req1 := NewRequest(config, "METHOD", "url")
+	req := NewRequest(config, "METHOD", "url")
 
-	req1.WithProto("HTTP/1.0")
-	assert.Equal(t, 1, req1.http.ProtoMajor)
-	assert.Equal(t, 0, req1.http.ProtoMinor)
+	req.WithChunked(nil)
 
-	req1.WithChunked(bytes.NewBufferString("body"))
-	req1.chain.assertFailed(t)
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.True(t, client.req.Body == nil)
+	assert.Equal(t, int64(0), client.req.ContentLength)
+}
+
+func TestRequestBodyChunkedProto(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req1 := NewRequest(config, "METHOD", "url")
+
+	req1.WithProto("HTTP/1.0")
+	assert.Equal(t, 1, req1.http.ProtoMajor)
+	assert.Equal(t, 0, req1.http.ProtoMinor)
+
+	req1.WithChunked(bytes.NewBufferString("body"))
+	req1.chain.assertFailed(t)
 
 	req2 := NewRequest(config, "METHOD", "url")
 
@@ -928,6 +1656,97 @@ func TestRequestBodyFormStruct(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
+func TestRequestBodyFormObject(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	type Address struct {
+		City string `form:"city"`
+		Zip  string `form:"zip,omitempty"`
+	}
+
+	type User struct {
+		Name    string    `form:"name"`
+		Tags    []string  `form:"tags"`
+		Address Address   `form:"address"`
+		Created time.Time `form:"created,omitempty"`
+		Skip    int       `form:"-"`
+	}
+
+	user := User{
+		Name:    "john",
+		Tags:    []string{"a", "b"},
+		Address: Address{City: "Paris"},
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Skip:    1,
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+	req.WithFormObject(user)
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/x-www-form-urlencoded",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t,
+		`address.city=Paris&created=2020-01-02T03%3A04%3A05Z&name=john&tags=a&tags=b`,
+		string(resp.content))
+
+	req = NewRequest(config, "METHOD", "url")
+	req.WithFormObject(User{Name: "bob", Tags: []string{"c"}}, FormEncodeOpts{
+		SliceStyle: FormSliceBrackets,
+		NestStyle:  FormNestBrackets,
+		TimeLayout: "2006-01-02",
+	})
+
+	resp = req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, `address%5Bcity%5D=&name=bob&tags%5B%5D=c`, string(resp.content))
+}
+
+func TestRequestBodyFormObjectUnsupported(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	type Inner struct {
+		Fn func() `form:"fn"`
+	}
+
+	type Outer struct {
+		Inner Inner `form:"inner"`
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+	req.WithFormObject(Outer{Inner{Fn: func() {}}})
+	req.chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, "inner.fn")
+
+	req = NewRequest(config, "METHOD", "url")
+	req.WithFormObject("foo")
+	req.chain.assertFailed(t)
+}
+
 func TestRequestBodyFormCombined(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -1090,6 +1909,109 @@ func TestRequestBodyMultipartFile(t *testing.T) {
 	assert.True(t, eof == nil)
 }
 
+func TestRequestBodyMultipartOrder(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         client,
+		Reporter:       newMockReporter(t),
+	}
+
+	req := NewRequest(config, "POST", "url").
+		WithMultipart().
+		WithFormField("z", 1).
+		WithFileBytes("y", "y.txt", []byte("2")).
+		WithForm(map[string]string{"x": "3", "w": "4"}).
+		WithFormField("a", 5)
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	reader := multipart.NewReader(
+		bytes.NewReader(resp.content), req.MultipartBoundary())
+
+	var names, values []string
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(part)
+		names = append(names, part.FormName())
+		values = append(values, string(b))
+	}
+
+	assert.Equal(t, []string{"z", "y", "w", "x", "a"}, names)
+	assert.Equal(t, []string{"1", "2", "4", "3", "5"}, values)
+}
+
+func TestRequestBodyMultipartBoundary(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         client,
+		Reporter:       newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "POST", "url")
+	assert.Equal(t, "", req1.MultipartBoundary())
+
+	req1.WithMultipart()
+	assert.NotEqual(t, "", req1.MultipartBoundary())
+
+	req1.WithFormField("a", 1)
+	req1.Expect().chain.assertOK(t)
+
+	_, params, _ := mime.ParseMediaType(client.req.Header.Get("Content-Type"))
+	assert.Equal(t, req1.MultipartBoundary(), params["boundary"])
+
+	req2 := NewRequest(config, "POST", "url").
+		WithMultipart().
+		WithMultipartBoundary("golden-boundary").
+		WithFormField("a", 1)
+	req2.chain.assertOK(t)
+	assert.Equal(t, "golden-boundary", req2.MultipartBoundary())
+
+	resp := req2.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t,
+		"multipart/form-data; boundary=golden-boundary",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t, ""+
+		"--golden-boundary\r\n"+
+		"Content-Disposition: form-data; name=\"a\"\r\n"+
+		"\r\n"+
+		"1\r\n"+
+		"--golden-boundary--\r\n",
+		string(resp.content))
+
+	for _, boundary := range []string{
+		"",
+		"bad\"quote",
+		"bad@char",
+		"trailing-space ",
+		strings.Repeat("x", 71),
+	} {
+		req := NewRequest(config, "POST", "url").
+			WithMultipart().
+			WithMultipartBoundary(boundary)
+		req.chain.assertFailed(t)
+	}
+
+	NewRequest(config, "POST", "url").
+		WithMultipartBoundary("boundary").
+		chain.assertFailed(t)
+
+	NewRequest(config, "POST", "url").
+		WithMultipart().
+		WithFormField("a", 1).
+		WithMultipartBoundary("boundary").
+		chain.assertFailed(t)
+}
+
 func TestRequestBodyJSON(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -1127,6 +2049,664 @@ func TestRequestBodyJSON(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
+func TestRequestBodyJSONContentType(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	reporter := newMockReporter(t)
+
+	newReq := func(client *mockClient, contentType string) *Request {
+		return NewRequest(Config{
+			RequestFactory:  factory,
+			Client:          client,
+			Reporter:        reporter,
+			JSONContentType: contentType,
+		}, "POST", "url")
+	}
+
+	t.Run("default", func(t *testing.T) {
+		client := &mockClient{}
+
+		newReq(client, "").WithJSON(123).Expect().chain.assertOK(t)
+
+		assert.Equal(t, "application/json; charset=utf-8",
+			client.req.Header.Get("Content-Type"))
+	})
+
+	t.Run("config", func(t *testing.T) {
+		client := &mockClient{}
+
+		newReq(client, "application/json").WithJSON(123).Expect().chain.assertOK(t)
+
+		assert.Equal(t, "application/json", client.req.Header.Get("Content-Type"))
+	})
+
+	t.Run("before", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client, "application/json").
+			WithJSONContentType("application/vnd.api+json").
+			WithJSON(map[string]interface{}{"a": 1}).
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/vnd.api+json",
+			client.req.Header.Get("Content-Type"))
+		assert.Equal(t, `{"a":1}`, string(resp.content))
+	})
+
+	t.Run("after", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client, "").
+			WithJSON(map[string]interface{}{"a": 1}).
+			WithJSONContentType("application/vnd.api+json").
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/vnd.api+json",
+			client.req.Header.Get("Content-Type"))
+		assert.Equal(t, `{"a":1}`, string(resp.content))
+	})
+
+	t.Run("other setter", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client, "").
+			WithText("hello").
+			WithJSONContentType("application/vnd.api+json").
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "text/plain; charset=utf-8",
+			client.req.Header.Get("Content-Type"))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		req := newReq(&mockClient{}, "").WithJSONContentType("")
+		req.chain.assertFailed(t)
+	})
+}
+
+func TestRequestBodyJSONField(t *testing.T) {
+	newReq := func(client *mockClient) *Request {
+		return NewRequest(Config{
+			RequestFactory: DefaultRequestFactory{},
+			Client:         client,
+			Reporter:       newMockReporter(t),
+		}, "POST", "url")
+	}
+
+	t.Run("empty body", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client).
+			WithJSONField("a", 1).
+			WithJSONField("b", []string{"x"}).
+			WithJSONField("a", "override").
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/json; charset=utf-8",
+			client.req.Header.Get("Content-Type"))
+		assert.JSONEq(t, `{"a":"override","b":["x"]}`, string(resp.content))
+	})
+
+	t.Run("after WithJSON", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client).
+			WithJSON(map[string]interface{}{"a": 1, "n": uint64(12345678901234567890)}).
+			WithJSONField("b", 2).
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, `{"a":1,"b":2,"n":12345678901234567890}`,
+			string(resp.content))
+	})
+
+	t.Run("non-object body", func(t *testing.T) {
+		req := newReq(&mockClient{}).
+			WithJSON([]int{1, 2}).
+			WithJSONField("a", 1)
+		req.chain.assertFailed(t)
+
+		req = newReq(&mockClient{}).
+			WithJSON(nil).
+			WithJSONField("a", 1)
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("unmarshalable value", func(t *testing.T) {
+		req := newReq(&mockClient{}).WithJSONField("a", make(chan int))
+		req.chain.assertFailed(t)
+	})
+}
+
+func TestRequestBodyConflicts(t *testing.T) {
+	type call struct {
+		name string
+		fn   func(req *Request)
+	}
+
+	calls := map[string]call{
+		"json":      {"WithJSON", func(r *Request) { r.WithJSON(map[string]int{"a": 1}) }},
+		"jsonField": {"WithJSONField", func(r *Request) { r.WithJSONField("a", 1) }},
+		"text":      {"WithText", func(r *Request) { r.WithText("hello") }},
+		"bytes":     {"WithBytes", func(r *Request) { r.WithBytes([]byte("hello")) }},
+		"form":      {"WithForm", func(r *Request) { r.WithForm(map[string]int{"a": 1}) }},
+		"formField": {"WithFormField", func(r *Request) { r.WithFormField("a", 1) }},
+		"msgpack":   {"WithMsgPack", func(r *Request) { r.WithMsgPack(1) }},
+	}
+
+	cases := []struct {
+		first  string
+		second string
+		ok     bool
+	}{
+		{"json", "jsonField", true},
+		{"jsonField", "jsonField", true},
+		{"form", "form", true},
+		{"form", "formField", true},
+		{"formField", "form", true},
+
+		{"json", "json", false},
+		{"json", "text", false},
+		{"json", "bytes", false},
+		{"json", "form", false},
+		{"json", "msgpack", false},
+		{"jsonField", "json", false},
+		{"jsonField", "text", false},
+		{"text", "json", false},
+		{"text", "jsonField", false},
+		{"text", "text", false},
+		{"bytes", "jsonField", false},
+		{"bytes", "text", false},
+		{"bytes", "form", false},
+		{"form", "json", false},
+		{"form", "jsonField", false},
+		{"form", "text", false},
+		{"msgpack", "jsonField", false},
+	}
+
+	for _, tc := range cases {
+		first, second := calls[tc.first], calls[tc.second]
+
+		t.Run(first.name+" then "+second.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			req := NewRequest(Config{
+				RequestFactory: DefaultRequestFactory{},
+				Client:         &mockClient{},
+				Reporter:       reporter,
+			}, "POST", "url")
+
+			first.fn(req)
+			req.chain.assertOK(t)
+
+			second.fn(req)
+			resp := req.Expect()
+
+			if tc.ok {
+				resp.chain.assertOK(t)
+			} else {
+				resp.chain.assertFailed(t)
+				assert.Contains(t, reporter.message, first.name)
+				assert.Contains(t, reporter.message, second.name)
+			}
+		})
+	}
+}
+
+func TestRequestBodyFormMerge(t *testing.T) {
+	client := &mockClient{}
+
+	req := NewRequest(Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         client,
+		Reporter:       newMockReporter(t),
+	}, "POST", "url")
+
+	resp := req.
+		WithForm(map[string]interface{}{"a": 1, "b": 2}).
+		WithForm(map[string]interface{}{"b": 3, "c": 4}).
+		WithFormField("d", 5).
+		Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "a=1&b=2&b=3&c=4&d=5", string(resp.content))
+}
+
+func TestRequestConditionalHeaders(t *testing.T) {
+	newReq := func(reporter Reporter) *Request {
+		return NewRequest(Config{
+			RequestFactory: DefaultRequestFactory{},
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}, "GET", "url")
+	}
+
+	ts := time.Date(2020, 1, 2, 15, 4, 5, 0, time.FixedZone("X", 3600))
+
+	t.Run("headers", func(t *testing.T) {
+		req := newReq(newMockReporter(t)).
+			WithIfNoneMatch(`W/"a"`, "b", "*").
+			WithIfMatch(`"c"`).
+			WithIfModifiedSince(ts).
+			WithIfUnmodifiedSince(ts.Add(time.Hour))
+		req.chain.assertOK(t)
+
+		assert.Equal(t, `W/"a", "b", *`, req.http.Header.Get("If-None-Match"))
+		assert.Equal(t, `"c"`, req.http.Header.Get("If-Match"))
+		assert.Equal(t, "Thu, 02 Jan 2020 14:04:05 GMT",
+			req.http.Header.Get("If-Modified-Since"))
+		assert.Equal(t, "Thu, 02 Jan 2020 15:04:05 GMT",
+			req.http.Header.Get("If-Unmodified-Since"))
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		req := newReq(newMockReporter(t)).
+			WithIfNoneMatch("a").
+			WithIfNoneMatch("b")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, []string{`"b"`}, req.http.Header.Values("If-None-Match"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req := newReq(newMockReporter(t)).WithIfNoneMatch()
+		req.chain.assertFailed(t)
+
+		req = newReq(newMockReporter(t)).WithIfMatch(`"a"b"`)
+		req.chain.assertFailed(t)
+	})
+}
+
+func TestRequestBodyJSONNull(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	req := NewRequest(Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         client,
+		Reporter:       reporter,
+	}, "POST", "url")
+
+	resp := req.WithJSON(nil).Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/json; charset=utf-8",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t, int64(4), client.req.ContentLength)
+	assert.Equal(t, "null", string(resp.content))
+}
+
+func TestRequestBodyJSONMarshalError(t *testing.T) {
+	type item struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}
+
+	type order struct {
+		ID    int    `json:"id"`
+		Items []item `json:"items"`
+		Hook  func() `json:"-"`
+	}
+
+	cases := []struct {
+		name   string
+		object interface{}
+		path   string
+	}{
+		{
+			name:   "channel",
+			object: map[string]interface{}{"a": 1, "b": make(chan int)},
+			path:   `"b"`,
+		},
+		{
+			name: "nan in struct",
+			object: &order{
+				ID:    1,
+				Items: []item{{"foo", 1}, {"bar", math.NaN()}},
+			},
+			path: `"items[1].price"`,
+		},
+		{
+			name:   "nested",
+			object: []interface{}{1, map[string]interface{}{"x": math.Inf(1)}},
+			path:   `"[1].x"`,
+		},
+		{
+			name:   "root",
+			object: make(chan int),
+			path:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			req := NewRequest(Config{
+				RequestFactory: DefaultRequestFactory{},
+				Client:         &mockClient{},
+				Reporter:       reporter,
+			}, "POST", "url")
+
+			req.WithJSON(tc.object)
+			req.chain.assertFailed(t)
+
+			if tc.path != "" {
+				assert.Contains(t, reporter.message, tc.path)
+			} else {
+				assert.NotContains(t, reporter.message, "at path")
+			}
+		})
+	}
+}
+
+func TestRequestBodyMsgPack(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	expectedHeaders := map[string][]string{
+		"Content-Type": {"application/msgpack"},
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+
+	req.WithMsgPack(map[string]interface{}{"key": "value"})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
+
+	expectedBody, _ := msgpack.Marshal(map[string]interface{}{"key": "value"})
+	assert.Equal(t, expectedBody, resp.content)
+
+	resp.MsgPack().Object().ValueEqual("key", "value")
+	resp.chain.assertOK(t)
+}
+
+func TestRequestBodyYAML(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	expectedHeaders := map[string][]string{
+		"Content-Type": {"application/yaml"},
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+
+	req.WithYAML(map[string]interface{}{"key": "value", "list": []int{1, 2}})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
+	assert.Equal(t, "key: value\nlist:\n- 1\n- 2\n", string(resp.content))
+
+	resp.YAML().Object().ValueEqual("key", "value")
+	resp.YAML().Object().ValueEqual("list", []interface{}{1, 2})
+	resp.chain.assertOK(t)
+}
+
+func TestRequestBodyJSONPatch(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	t.Run("ops", func(t *testing.T) {
+		client := &mockClient{}
+		reporter := newMockReporter(t)
+
+		req := NewRequest(Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}, "PATCH", "url")
+
+		req.WithJSONPatch([]PatchOp{
+			{Op: "replace", Path: "/name", Value: "john"},
+			{Op: "add", Path: "/nickname", Value: nil},
+			{Op: "move", From: "/old", Path: "/new"},
+			{Op: "remove", Path: "/tags/0"},
+		})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/json-patch+json",
+			client.req.Header.Get("Content-Type"))
+		assert.Equal(t, `[`+
+			`{"op":"replace","path":"/name","value":"john"},`+
+			`{"op":"add","path":"/nickname","value":null},`+
+			`{"from":"/old","op":"move","path":"/new"},`+
+			`{"op":"remove","path":"/tags/0"}]`, string(resp.content))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		client := &mockClient{}
+		reporter := newMockReporter(t)
+
+		req := NewRequest(Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}, "PATCH", "url")
+
+		resp := req.WithJSONPatch(nil).Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, `[]`, string(resp.content))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := []struct {
+			op      PatchOp
+			message string
+		}{
+			{PatchOp{Op: "upsert", Path: "/a"}, `unknown op "upsert"`},
+			{PatchOp{Path: "/a"}, "missing op"},
+			{PatchOp{Op: "move", Path: "/a"}, `missing from for "move" operation`},
+			{PatchOp{Op: "copy", Path: "/a"}, `missing from for "copy" operation`},
+			{PatchOp{Op: "add", Path: "a", Value: 1}, `invalid path "a"`},
+			{PatchOp{Op: "copy", From: "/a~2", Path: "/b"}, `invalid from "/a~2"`},
+			{PatchOp{Op: "remove", Path: "/a", Value: 1},
+				`unexpected value for "remove" operation`},
+			{PatchOp{Op: "replace", Path: "/a", From: "/b"},
+				`unexpected from "/b" for "replace" operation`},
+			{PatchOp{Op: "move", From: "/a", Path: "/a/b"},
+				`can't move "/a" into its own child "/a/b"`},
+		}
+
+		for _, tc := range cases {
+			reporter := &failureRecordingReporter{}
+
+			req := NewRequest(Config{
+				RequestFactory: factory,
+				Client:         &mockClient{},
+				Reporter:       reporter,
+			}, "PATCH", "url")
+
+			req.WithJSONPatch([]PatchOp{{Op: "test", Path: "/x", Value: 1}, tc.op})
+			req.chain.assertFailed(t)
+
+			if assert.Equal(t, 1, len(reporter.failures)) {
+				assert.Equal(t, FailureAssertUsage, reporter.failures[0].Type)
+				assert.Contains(t, reporter.failures[0].Message, "WithJSONPatch")
+				assert.Contains(t, reporter.failures[0].Message,
+					"operation 1: "+tc.message)
+			}
+		}
+	})
+}
+
+func TestRequestBodyMergePatch(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+	reporter := newMockReporter(t)
+
+	req := NewRequest(Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}, "PATCH", "url")
+
+	req.WithMergePatch(map[string]interface{}{
+		"name":     "john",
+		"nickname": nil,
+	})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/merge-patch+json",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t, `{"name":"john","nickname":null}`, string(resp.content))
+
+	req = NewRequest(Config{
+		RequestFactory: factory,
+		Client:         &mockClient{},
+		Reporter:       reporter,
+	}, "PATCH", "url")
+
+	req.WithMergePatch(make(chan int))
+	req.chain.assertFailed(t)
+}
+
+func TestRequestBodyJSONFile(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	reporter := newMockReporter(t)
+
+	fh, _ := ioutil.TempFile("", "httpexpect")
+	filename := fh.Name()
+	_, _ = fh.WriteString(`{"id":12345678901234567890,"user":{"name":"john"},"items":[{"n":1}]}`)
+	fh.Close()
+	defer os.Remove(filename)
+
+	newReq := func(client *mockClient) *Request {
+		return NewRequest(Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}, "POST", "url")
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client).WithJSONFile(filename).Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/json; charset=utf-8",
+			client.req.Header.Get("Content-Type"))
+		assert.JSONEq(t,
+			`{"id":12345678901234567890,"user":{"name":"john"},"items":[{"n":1}]}`,
+			string(resp.content))
+		assert.Contains(t, string(resp.content), "12345678901234567890")
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client).WithJSONFile(filename).
+			WithJSONOverride("user.name", "bob").
+			WithJSONOverride("user.address.city", "Paris").
+			WithJSONOverride("items.0.n", 2).
+			WithJSONOverrideStrict("user.age", 30).
+			WithJSONOverride("extra", map[string]int{"a": 1}).
+			WithJSONOverride("extra.b", 2).
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.JSONEq(t,
+			`{"id":12345678901234567890,`+
+				`"user":{"name":"bob","address":{"city":"Paris"},"age":30},`+
+				`"items":[{"n":2}],"extra":{"a":1,"b":2}}`,
+			string(resp.content))
+	})
+
+	t.Run("strict missing", func(t *testing.T) {
+		client := &mockClient{}
+
+		resp := newReq(client).WithJSONFile(filename).
+			WithJSONOverrideStrict("user.address.city", "Paris").
+			Expect()
+		resp.chain.assertFailed(t)
+
+		assert.Nil(t, client.req)
+	})
+
+	t.Run("bad path", func(t *testing.T) {
+		for _, path := range []string{"user.name.first", "items.1.n", "items.x"} {
+			client := &mockClient{}
+
+			resp := newReq(client).WithJSONFile(filename).
+				WithJSONOverride(path, "x").
+				Expect()
+			resp.chain.assertFailed(t)
+		}
+	})
+
+	t.Run("no file", func(t *testing.T) {
+		req := newReq(&mockClient{}).
+			WithJSONOverride("user.name", "bob")
+		req.chain.assertFailed(t)
+
+		assert.Contains(t, reporter.message,
+			"unexpected WithJSONOverride call without preceding WithJSONFile")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		resp := newReq(&mockClient{}).
+			WithJSONFile(filename + ".missing").
+			Expect()
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		for _, content := range []string{`{"a":`, `{} {}`} {
+			fh, _ := ioutil.TempFile("", "httpexpect")
+			_, _ = fh.WriteString(content)
+			fh.Close()
+
+			resp := newReq(&mockClient{}).
+				WithJSONFile(fh.Name()).
+				Expect()
+			resp.chain.assertFailed(t)
+
+			os.Remove(fh.Name())
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		req := newReq(&mockClient{}).
+			WithJSONFile(filename).
+			WithText("foo")
+		req.chain.assertFailed(t)
+	})
+}
+
 func TestRequestContentLength(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -1453,3 +3033,322 @@ func TestRequestErrorConflictMultipart(t *testing.T) {
 	req3.WithFileBytes("a", "a", []byte("a"))
 	req3.chain.assertFailed(t)
 }
+
+type countingBody struct {
+	reader io.Reader
+	read   int
+	closed bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type countingClient struct {
+	body *countingBody
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	c.body = &countingBody{reader: strings.NewReader(strings.Repeat("x", 1<<20))}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: c.body,
+	}, nil
+}
+
+func TestRequestDiscardBody(t *testing.T) {
+	client := &countingClient{}
+
+	reporter := newMockReporter(t)
+
+	logger := &mockLogger{}
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         client,
+		Reporter:       reporter,
+		Printers: []Printer{
+			NewDebugPrinter(logger, true),
+		},
+	}
+
+	resp := NewRequest(config, "GET", "url").WithDiscardBody().Expect()
+
+	resp.Status(http.StatusOK).
+		ContentType("application/json")
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, 0, client.body.read)
+	assert.True(t, client.body.closed)
+	assert.Nil(t, resp.RawBytes())
+
+	for _, log := range logger.logs {
+		assert.NotContains(t, log, "xxx")
+	}
+
+	for name, fn := range map[string]func(*Response){
+		"Body":      func(r *Response) { r.Body() },
+		"NoContent": func(r *Response) { r.NoContent() },
+		"Text":      func(r *Response) { r.Text() },
+		"Form":      func(r *Response) { r.Form() },
+		"JSON":      func(r *Response) { r.JSON() },
+		"IsJSON":    func(r *Response) { r.IsJSON() },
+		"JSONP":     func(r *Response) { r.JSONP("cb") },
+		"MsgPack":   func(r *Response) { r.MsgPack() },
+		"SSE":       func(r *Response) { r.SSE() },
+		"EqualResponse": func(r *Response) {
+			r.EqualResponse(NewRequest(config, "GET", "url").Expect())
+		},
+	} {
+		resp.chain.reset()
+		fn(resp)
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "discarded by WithDiscardBody", name)
+	}
+
+	resp = NewRequest(config, "GET", "url").Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, 1<<20, client.body.read)
+	assert.Equal(t, 1<<20, len(resp.RawBytes()))
+}
+
+type cleanupReporter struct {
+	*mockReporter
+	cleanups []func()
+}
+
+func (r *cleanupReporter) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *cleanupReporter) runCleanups() {
+	for _, fn := range r.cleanups {
+		fn()
+	}
+	r.cleanups = nil
+}
+
+func TestRequestExpectNeverCalled(t *testing.T) {
+	reporter := &cleanupReporter{mockReporter: newMockReporter(t)}
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       reporter,
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		config := config
+		config.DisableExpectCheck = true
+
+		NewRequest(config, "GET", "/path")
+		assert.Equal(t, 0, len(reporter.cleanups))
+	})
+
+	t.Run("no cleanup", func(t *testing.T) {
+		config := config
+		config.Reporter = newMockReporter(t)
+
+		req := NewRequest(config, "GET", "/path")
+		assert.Nil(t, req.check)
+	})
+
+	t.Run("vacuous", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path")
+		req.chain.assertOK(t)
+
+		reporter.runCleanups()
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "Expect() was never called")
+		assert.Contains(t, reporter.message, "GET /path")
+
+		reporter.reported = false
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path")
+		req.Discard()
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path")
+		req.Expect().chain.assertOK(t)
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("failed request", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path")
+		req.WithClient(nil)
+		req.chain.assertFailed(t)
+
+		reporter.reported = false
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("assert reporter", func(t *testing.T) {
+		ct := &cleanupReporter{mockReporter: newMockReporter(t)}
+
+		config := config
+		config.Reporter = NewAssertReporter(ct)
+
+		NewRequest(config, "GET", "/path")
+		assert.Equal(t, 1, len(ct.cleanups))
+
+		NewRequest(config, "GET", "/path").Discard()
+		assert.Equal(t, 2, len(ct.cleanups))
+	})
+
+	t.Run("no request reference", func(t *testing.T) {
+		collected := make(chan struct{})
+
+		func() {
+			req := NewRequest(config, "GET", "/path")
+			runtime.SetFinalizer(req, func(*Request) {
+				close(collected)
+			})
+		}()
+
+		for i := 0; i < 10; i++ {
+			runtime.GC()
+			select {
+			case <-collected:
+				reporter.runCleanups()
+				assert.True(t, reporter.reported)
+				reporter.reported = false
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		t.Fatal("request is referenced by cleanup function")
+	})
+}
+
+func TestRequestRequireAssertions(t *testing.T) {
+	reporter := &cleanupReporter{mockReporter: newMockReporter(t)}
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client: &mockClient{
+			resp: http.Response{StatusCode: http.StatusOK},
+		},
+		Reporter:          reporter,
+		RequireAssertions: true,
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		config := config
+		config.RequireAssertions = false
+
+		NewRequest(config, "GET", "/path").Expect()
+		assert.Equal(t, 1, len(reporter.cleanups))
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("no assertions", func(t *testing.T) {
+		resp := NewRequest(config, "GET", "/path").Expect()
+		resp.Raw()
+		resp.chain.assertOK(t)
+
+		reporter.runCleanups()
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "no assertions were made")
+		assert.Contains(t, reporter.message, "GET /path")
+		assert.Contains(t, reporter.message, "200 OK")
+
+		reporter.reported = false
+	})
+
+	t.Run("response assertion", func(t *testing.T) {
+		resp := NewRequest(config, "GET", "/path").Expect()
+		resp.Status(http.StatusOK)
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("derived assertion", func(t *testing.T) {
+		resp := NewRequest(config, "GET", "/path").
+			WithText("hello").
+			Expect()
+		resp.Body().Equal("hello")
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("failed assertion", func(t *testing.T) {
+		resp := NewRequest(config, "GET", "/path").Expect()
+		resp.Status(http.StatusTeapot)
+		assert.True(t, reporter.reported)
+
+		reporter.reported = false
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+
+	t.Run("eventually", func(t *testing.T) {
+		e := WithConfig(config)
+
+		e.Eventually(time.Millisecond, time.Second,
+			func() *Request {
+				return e.GET("/path")
+			},
+			func(resp *Response) bool {
+				return resp.Raw() != nil
+			})
+
+		reporter.runCleanups()
+		assert.False(t, reporter.reported)
+	})
+}
+
+func TestRequestWithReporter(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: reporter,
+	})
+
+	terse := &mockCollectingReporter{}
+
+	resp1 := e.GET("/url").WithReporter(terse).Expect()
+	resp1.Header("X-Foo").Equal("foo")
+	resp1.Status(http.StatusTeapot)
+
+	assert.False(t, reporter.reported)
+	assert.Equal(t, []string{
+		"expected string equal to:",
+		"expected status equal to:",
+	}, terse.firstLines())
+
+	resp2 := e.GET("/url").Expect()
+	resp2.Status(http.StatusTeapot)
+
+	assert.True(t, reporter.reported)
+	assert.Contains(t, reporter.message, "418 I'm a teapot")
+	assert.Equal(t, 2, len(terse.messages))
+
+	req := e.GET("/url").WithReporter(nil)
+	req.chain.assertFailed(t)
+}
@@ -2,7 +2,14 @@ package httpexpect
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"mime"
 	"net/http"
@@ -14,6 +21,8 @@ import (
 
 	"github.com/ajg/form"
 	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v2"
 )
 
 // StatusRange is enum for response status ranges.
@@ -45,6 +54,11 @@ type Response struct {
 	cookies   []*http.Cookie
 	websocket *websocket.Conn
 	rtt       *time.Duration
+	timings   *Timings
+	wire      *wireCapture
+	streaming bool
+	discarded bool
+	err       error
 }
 
 // NewResponse returns a new Response given a reporter used to report
@@ -54,16 +68,21 @@ type Response struct {
 // failure is reported.
 //
 // If rtt is given, it defines response round-trip time to be reported
-// by response.RoundTripTime().
+// by response.RoundTripTime(). If more than one rtt is given, failure is
+// reported.
 func NewResponse(
 	reporter Reporter, response *http.Response, rtt ...time.Duration,
 ) *Response {
+	chain := makeChainFor("NewResponse", reporter)
+	if len(rtt) > 1 {
+		chain.failUsage("\nunexpected multiple rtt arguments passed to NewResponse")
+	}
 	var rttPtr *time.Duration
 	if len(rtt) > 0 {
 		rttPtr = &rtt[0]
 	}
 	return makeResponse(responseOpts{
-		chain:    makeChain(reporter),
+		chain:    chain,
 		response: response,
 		rtt:      rttPtr,
 	})
@@ -75,15 +94,23 @@ type responseOpts struct {
 	response  *http.Response
 	websocket *websocket.Conn
 	rtt       *time.Duration
+	timings   *Timings
+	wire      *wireCapture
+	discarded bool
+	err       error
 }
 
 func makeResponse(opts responseOpts) *Response {
 	var content []byte
 	var cookies []*http.Cookie
 	if opts.response != nil {
-		content = getContent(&opts.chain, opts.response)
+		// event streams are read incrementally using SSE(),
+		// discarded bodies are not read at all
+		if !isEventStream(opts.response) && !opts.discarded {
+			content = getContent(&opts.chain, opts.response)
+		}
 		cookies = opts.response.Cookies()
-	} else {
+	} else if opts.err == nil {
 		opts.chain.fail("expected non-nil response")
 	}
 	return &Response{
@@ -94,6 +121,10 @@ func makeResponse(opts responseOpts) *Response {
 		cookies:   cookies,
 		websocket: opts.websocket,
 		rtt:       opts.rtt,
+		timings:   opts.timings,
+		wire:      opts.wire,
+		discarded: opts.discarded,
+		err:       opts.err,
 	}
 }
 
@@ -102,6 +133,13 @@ func getContent(chain *chain, resp *http.Response) []byte {
 		return []byte{}
 	}
 
+	// responses to HEAD requests never have a body, even if Content-Length
+	// is set; don't try to read it to avoid unexpected EOF errors
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		_ = resp.Body.Close()
+		return []byte{}
+	}
+
 	content, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		chain.fail(err.Error())
@@ -113,10 +151,46 @@ func getContent(chain *chain, resp *http.Response) []byte {
 
 // Raw returns underlying http.Response object.
 // This is the value originally passed to NewResponse.
+//
+// Response body is read and buffered when Response is created. To allow
+// both the caller and subsequent assertions to read it, Raw replaces Body
+// of returned object with a new reader of the buffered body on every call.
+//
+// If the body is not buffered because it was taken by SSE, Raw reports
+// failure, and Body of returned object is left as is.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  b, _ := ioutil.ReadAll(resp.Raw().Body)
+//  resp.Body().Equal(string(b))
 func (r *Response) Raw() *http.Response {
+	if r.resp == nil {
+		return nil
+	}
+	if r.streaming {
+		r.chain.failUsage("\nunexpected Raw call for response with body taken by SSE")
+		return r.resp
+	}
+	if r.content != nil {
+		r.resp.Body = ioutil.NopCloser(bytes.NewReader(r.content))
+	}
 	return r.resp
 }
 
+// RawBytes returns buffered response body.
+//
+// Returned slice should not be modified. If the body was not buffered
+// (e.g. it's an event stream or was discarded by WithDiscardBody), nil is
+// returned.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  var payload MyPayload
+//  _ = json.Unmarshal(resp.RawBytes(), &payload)
+func (r *Response) RawBytes() []byte {
+	return r.content
+}
+
 // RoundTripTime returns a new Duration object that may be used to inspect
 // the round-trip time.
 //
@@ -128,11 +202,164 @@ func (r *Response) Raw() *http.Response {
 //  resp := NewResponse(t, response, time.Duration(10000000))
 //  resp.RoundTripTime().Lt(10 * time.Millisecond)
 func (r *Response) RoundTripTime() *Duration {
-	return &Duration{r.chain, r.rtt}
+	defer r.chain.leave(r.chain.failed())
+	return &Duration{r.chain.enter("RoundTripTime"), r.rtt}
+}
+
+// Error returns transport error that prevented receiving response, or nil
+// if response was received.
+//
+// Example:
+//  resp := req.ExpectError()
+//  fmt.Println(resp.Error())
+func (r *Response) Error() error {
+	return r.err
+}
+
+// ErrorClass returns class of transport error returned by Error.
+//
+// If response was received, failure is reported and ErrorOther is returned.
+//
+// Example:
+//  resp := req.ExpectError()
+//  if resp.ErrorClass() == httpexpect.ErrorTimeout {
+//      ...
+//  }
+func (r *Response) ErrorClass() ErrorClass {
+	if r.err == nil {
+		r.chain.failUsage("\nunexpected ErrorClass call for response without transport error")
+		return ErrorOther
+	}
+	return classifyError(r.err)
+}
+
+// ConnectionReused returns a new Boolean object that may be used to inspect
+// whether request was sent over a connection reused from the previous
+// requests, which is useful to catch missing keep-alive.
+//
+// Connection reuse is known only if Request.WithTimingBreakdown() was used;
+// otherwise ConnectionReused reports failure. If request was sent multiple
+// times, it describes the last attempt, like Timings.
+//
+// Example:
+//  e.GET("/first").Expect()
+//
+//  resp := e.GET("/second").WithTimingBreakdown().Expect()
+//  resp.ConnectionReused().True()
+func (r *Response) ConnectionReused() *Boolean {
+	defer r.chain.leave(r.chain.failed())
+	chain := r.chain.enter("ConnectionReused")
+	if chain.failed() {
+		return &Boolean{chain, false}
+	}
+	if r.timings == nil || r.timings.reused == nil {
+		chain.fail("\nexpected response with known connection reuse," +
+			" but it was not collected\n\nuse Request.WithTimingBreakdown()")
+		return &Boolean{chain, false}
+	}
+	return &Boolean{chain, *r.timings.reused}
+}
+
+// Timings returns a new Timings object that may be used to inspect timing
+// breakdown of request.
+//
+// Timings are collected only if Request.WithTimingBreakdown() was used;
+// otherwise all returned durations are not set.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().TimeToFirstByte().Lt(100 * time.Millisecond)
+//  resp.Timings().Total().Lt(time.Second)
+func (r *Response) Timings() *Timings {
+	defer r.chain.leave(r.chain.failed())
+	if r.timings == nil {
+		return &Timings{chain: r.chain.enter("Timings")}
+	}
+	timings := *r.timings
+	timings.chain = r.chain.enter("Timings")
+	return &timings
+}
+
+// WireRequest returns a new String object that may be used to inspect
+// serialized request, as it was written to the wire.
+//
+// Request should be sent with Request.WithWireCapture(), otherwise failure
+// is reported. If request was redirected, the last request is returned.
+//
+// Example:
+//  resp := req.WithWireCapture().Expect()
+//  resp.WireRequest().Contains("Content-Type: application/json")
+func (r *Response) WireRequest() *String {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &String{r.chain, ""}
+	}
+	if !r.checkWire("WireRequest") {
+		return &String{r.chain, ""}
+	}
+	return &String{r.chain.enter("WireRequest"), string(r.wire.request)}
+}
+
+// WireResponse returns a new String object that may be used to inspect
+// serialized response, as it was read from the wire.
+//
+// Request should be sent with Request.WithWireCapture(), otherwise failure
+// is reported.
+//
+// Example:
+//  resp := req.WithWireCapture().Expect()
+//  resp.WireResponse().Match("(?m)^Cache-Control: no-cache\r$")
+func (r *Response) WireResponse() *String {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &String{r.chain, ""}
+	}
+	if !r.checkWire("WireResponse") {
+		return &String{r.chain, ""}
+	}
+	return &String{r.chain.enter("WireResponse"), string(r.wire.response)}
+}
+
+func (r *Response) checkWire(where string) bool {
+	switch {
+	case r.wire == nil:
+		r.chain.failUsage("\nunexpected %s call for response without wire capture"+
+			" (see Request.WithWireCapture)", where)
+		return false
+	case r.wire.err != nil:
+		r.chain.fail("\nunexpected %s call, wire capture failed:\n %s",
+			where, r.wire.err.Error())
+		return false
+	case r.wire.request == nil:
+		r.chain.failUsage("\nunexpected %s call, nothing was captured"+
+			" (websocket requests are not captured)", where)
+		return false
+	}
+	return true
+}
+
+// TLS returns a new TLS object that may be used to inspect TLS connection
+// state of response.
+//
+// If response was not received over TLS, the returned TLS is not set, and
+// its assertions (except NotSet) fail.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().Version(tls.VersionTLS13)
+//  resp.TLS().PeerCertificateSubject(0).Contains("CN=example.com")
+func (r *Response) TLS() *TLS {
+	defer r.chain.leave(r.chain.failed())
+	var value *tls.ConnectionState
+	if !r.chain.failed() {
+		value = r.resp.TLS
+	}
+	return &TLS{r.chain.enter("TLS"), value}
 }
 
 // Deprecated: use RoundTripTime instead.
 func (r *Response) Duration() *Number {
+	defer r.chain.leave(r.chain.failed())
 	if r.rtt == nil {
 		return &Number{r.chain, 0}
 	}
@@ -145,6 +372,7 @@ func (r *Response) Duration() *Number {
 //  resp := NewResponse(t, response)
 //  resp.Status(http.StatusOK)
 func (r *Response) Status(status int) *Response {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
 		return r
 	}
@@ -167,6 +395,7 @@ func (r *Response) Status(status int) *Response {
 //  resp := NewResponse(t, response)
 //  resp.StatusRange(Status2xx)
 func (r *Response) StatusRange(rn StatusRange) *Response {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
 		return r
 	}
@@ -220,11 +449,12 @@ func statusRangeText(code int) string {
 //  resp := NewResponse(t, response)
 //  resp.Headers().Value("Content-Type").String().Equal("application-json")
 func (r *Response) Headers() *Object {
+	defer r.chain.leave(r.chain.failed())
 	var value map[string]interface{}
 	if !r.chain.failed() {
 		value, _ = canonMap(&r.chain, r.resp.Header)
 	}
-	return &Object{r.chain, value}
+	return &Object{r.chain.enter("Headers"), value}
 }
 
 // Header returns a new String object that may be used to inspect given header.
@@ -234,218 +464,1015 @@ func (r *Response) Headers() *Object {
 //  resp.Header("Content-Type").Equal("application-json")
 //  resp.Header("Date").DateTime().Le(time.Now())
 func (r *Response) Header(header string) *String {
+	defer r.chain.leave(r.chain.failed())
 	value := ""
 	if !r.chain.failed() {
 		value = r.resp.Header.Get(header)
 	}
-	return &String{r.chain, value}
+	return &String{r.chain.enter(headerKey(header)), value}
 }
 
-// Cookies returns a new Array object with all cookie names set by this response.
-// Returned Array contains a String value for every cookie name.
+// HeaderValues returns a new Array object that may be used to inspect all
+// values of given header, e.g. "Set-Cookie", "Link" or "Warning", which
+// may be present multiple times.
 //
-// Note that this returns only cookies set by Set-Cookie headers of this response.
-// It doesn't return session cookies from previous responses, which may be stored
-// in a cookie jar.
+// Returned Array contains a String value for every header line, in order.
+// Values are not split by commas. If header is missing, the array is empty.
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Cookies().Contains("session")
-func (r *Response) Cookies() *Array {
+//  resp.HeaderValues("Warning").Length().Equal(2)
+//  resp.HeaderValues("Warning").Contains(`199 - "Miscellaneous warning"`)
+func (r *Response) HeaderValues(header string) *Array {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
 		return &Array{r.chain, nil}
 	}
-	names := []interface{}{}
-	for _, c := range r.cookies {
-		names = append(names, c.Name)
+	key := http.CanonicalHeaderKey(header)
+	values := []interface{}{}
+	for _, v := range r.resp.Header[key] {
+		values = append(values, v)
 	}
-	return &Array{r.chain, names}
+	return &Array{r.chain.enter(fmt.Sprintf("HeaderValues[%q]", key)), values}
 }
 
-// Cookie returns a new Cookie object that may be used to inspect given cookie
-// set by this response.
+// Links returns a new Object that may be used to inspect "Link" header,
+// as defined by RFC 8288.
 //
-// Note that this returns only cookies set by Set-Cookie headers of this response.
-// It doesn't return session cookies from previous responses, which may be stored
-// in a cookie jar.
+// Returned Object maps every link relation type ("rel" parameter) to link
+// URL, as written in the header. Links may be split into multiple header
+// lines and comma-separated lists; a link with multiple space-separated
+// relation types is added for each of them. If header is missing, the
+// object is empty. If header can't be parsed, failure is reported.
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Cookie("session").Domain().Equal("example.com")
-func (r *Response) Cookie(name string) *Cookie {
+//  resp.Links().Value("next").String().Equal("/items?page=3")
+//  resp.Links().NotContainsKey("prev")
+func (r *Response) Links() *Object {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
-		return &Cookie{r.chain, nil}
+		return &Object{r.chain, nil}
 	}
-	names := []string{}
-	for _, c := range r.cookies {
-		if c.Name == name {
-			return &Cookie{r.chain, c}
+	links := map[string]interface{}{}
+	for _, h := range r.resp.Header.Values("Link") {
+		if !parseLinks(h, links) {
+			r.chain.fail("\nexpected \"Link\" header in form of:\n %q\n\nbut got:\n %q",
+				`<url>; rel="name"`, h)
+			return &Object{r.chain, nil}
 		}
-		names = append(names, c.Name)
 	}
-	r.chain.fail("\nexpected response with cookie:\n %q\n\nbut got only cookies:\n%s",
-		name, dumpValue(names))
-	return &Cookie{r.chain, nil}
+	return &Object{r.chain.enter("Links"), links}
 }
 
-// Websocket returns Websocket object that can be used to interact with
-// WebSocket server.
-//
-// May be called only if the WithWebsocketUpgrade was called on the request.
-// That is responsibility of the caller to explicitly close the websocket after use.
-//
-// Example:
-//  req := NewRequest(config, "GET", "/path")
-//  req.WithWebsocketUpgrade()
-//  ws := req.Expect().Websocket()
-//  defer ws.Disconnect()
-func (r *Response) Websocket() *Websocket {
-	if !r.chain.failed() && r.websocket == nil {
-		r.chain.fail("\nunexpected Websocket call for non-WebSocket response")
+// parseLinks parses comma-separated list of links and adds them to
+// links, keyed by relation type.
+func parseLinks(header string, links map[string]interface{}) bool {
+	s := header
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return true
+		}
+		if s[0] != '<' {
+			return false
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return false
+		}
+		target := s[1:end]
+		s = s[end+1:]
+
+		var rels []string
+		for {
+			s = strings.TrimLeft(s, " \t")
+			if s == "" || s[0] == ',' {
+				break
+			}
+			if s[0] != ';' {
+				return false
+			}
+			var name, value string
+			var ok bool
+			if name, value, s, ok = parseLinkParam(s[1:]); !ok {
+				return false
+			}
+			if strings.EqualFold(name, "rel") && rels == nil {
+				rels = strings.Fields(value)
+			}
+		}
+
+		for _, rel := range rels {
+			if _, ok := links[strings.ToLower(rel)]; !ok {
+				links[strings.ToLower(rel)] = target
+			}
+		}
 	}
-	return makeWebsocket(r.config, r.chain, r.websocket)
 }
 
-// Body returns a new String object that may be used to inspect response body.
+// parseLinkParam parses single link parameter in form of name=value or
+// name="quoted value" and returns the rest of input.
+func parseLinkParam(s string) (name, value, rest string, ok bool) {
+	s = strings.TrimLeft(s, " \t")
+	end := strings.IndexAny(s, "=;,")
+	if end < 0 {
+		end = len(s)
+	}
+	name = strings.TrimSpace(s[:end])
+	if name == "" {
+		return "", "", "", false
+	}
+	s = s[end:]
+	if s == "" || s[0] != '=' {
+		return name, "", s, true
+	}
+	s = strings.TrimLeft(s[1:], " \t")
+	if s != "" && s[0] == '"' {
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				if i+1 < len(s) {
+					i++
+					b.WriteByte(s[i])
+				}
+			case '"':
+				return name, b.String(), s[i+1:], true
+			default:
+				b.WriteByte(s[i])
+			}
+		}
+		return "", "", "", false
+	}
+	end = strings.IndexAny(s, ";,")
+	if end < 0 {
+		end = len(s)
+	}
+	return name, strings.TrimSpace(s[:end]), s[end:], true
+}
+
+// Vary succeeds if response Vary header contains exactly given list of
+// header names, in any order.
+//
+// Vary header may be present multiple times and contain comma-separated
+// lists. Header names are compared case-insensitively.
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Body().NotEmpty()
-//  resp.Body().Length().Equal(100)
-func (r *Response) Body() *String {
-	return &String{r.chain, string(r.content)}
-}
-
-// NoContent succeeds if response contains empty Content-Type header and
-// empty body.
-func (r *Response) NoContent() *Response {
+//  resp.Vary("Accept", "Accept-Encoding")
+func (r *Response) Vary(headers ...string) *Response {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
 		return r
 	}
-
-	contentType := r.resp.Header.Get("Content-Type")
-
-	r.checkEqual("\"Content-Type\" header", "", contentType)
-	r.checkEqual("body", "", string(r.content))
-
+	actual := r.getVary()
+	expected := canonHeaderNames(headers)
+	if len(actual) != len(expected) || !containsHeaderNames(actual, expected) {
+		r.chain.fail("\nexpected \"Vary\" header equal to:\n%s\n\nbut got:\n%s",
+			dumpValue(expected), dumpValue(actual))
+	}
 	return r
 }
 
-// ContentType succeeds if response contains Content-Type header with given
-// media type and charset.
+// VaryContains succeeds if response Vary header contains at least given
+// header names.
 //
-// If charset is omitted, and mediaType is non-empty, Content-Type header
-// should contain empty or utf-8 charset.
+// Vary header may be present multiple times and contain comma-separated
+// lists. Header names are compared case-insensitively.
 //
-// If charset is omitted, and mediaType is also empty, Content-Type header
-// should contain no charset.
-func (r *Response) ContentType(mediaType string, charset ...string) *Response {
-	r.checkContentType(mediaType, charset...)
-	return r
-}
-
-// ContentEncoding succeeds if response has exactly given Content-Encoding list.
-// Common values are empty, "gzip", "compress", "deflate", "identity" and "br".
-func (r *Response) ContentEncoding(encoding ...string) *Response {
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.VaryContains("Accept")
+func (r *Response) VaryContains(headers ...string) *Response {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
 		return r
 	}
-	r.checkEqual("\"Content-Encoding\" header", encoding, r.resp.Header["Content-Encoding"])
+	actual := r.getVary()
+	expected := canonHeaderNames(headers)
+	if !containsHeaderNames(actual, expected) {
+		r.chain.fail("\nexpected \"Vary\" header containing:\n%s\n\nbut got:\n%s",
+			dumpValue(expected), dumpValue(actual))
+	}
 	return r
 }
 
-// TransferEncoding succeeds if response contains given Transfer-Encoding list.
-// Common values are empty, "chunked" and "identity".
-func (r *Response) TransferEncoding(encoding ...string) *Response {
-	if r.chain.failed() {
-		return r
+func headerKey(name string) string {
+	return fmt.Sprintf("Header[%q]", http.CanonicalHeaderKey(name))
+}
+
+func (r *Response) getVary() []string {
+	var names []string
+	for _, h := range r.resp.Header.Values("Vary") {
+		for _, name := range strings.Split(h, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
 	}
-	r.checkEqual("\"Transfer-Encoding\" header", encoding, r.resp.TransferEncoding)
-	return r
+	return canonHeaderNames(names)
 }
 
-// ContentOpts define parameters for matching the response content parameters.
-type ContentOpts struct {
-	// The media type Content-Type part, e.g. "application/json"
-	MediaType string
-	// The character set Content-Type part, e.g. "utf-8"
-	Charset string
+func canonHeaderNames(names []string) []string {
+	ret := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if !seen[name] {
+			seen[name] = true
+			ret = append(ret, name)
+		}
+	}
+	return ret
 }
 
-// Text returns a new String object that may be used to inspect response body.
+func containsHeaderNames(outer, inner []string) bool {
+	for _, i := range inner {
+		found := false
+		for _, o := range outer {
+			if i == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DateHeader returns a new DateTime object that may be used to inspect
+// date from given response header.
 //
-// Text succeeds if response contains "text/plain" Content-Type header
-// with empty or "utf-8" charset.
+// The header value is parsed using http.ParseTime, which accepts all three
+// date formats allowed by RFC 7231: IMF-fixdate (RFC 1123), RFC 850, and
+// ANSI C asctime().
+//
+// If the header is missing, DateHeader returns DateTime in "not set" state,
+// see DateTime.NotSet. If the header can't be parsed, failure is reported.
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Text().Equal("hello, world!")
-//  resp.Text(ContentOpts{
-//    MediaType: "text/plain",
-//  }).Equal("hello, world!")
-func (r *Response) Text(opts ...ContentOpts) *String {
-	var content string
-
-	if !r.chain.failed() && r.checkContentOpts(opts, "text/plain") {
-		content = string(r.content)
+//  resp.DateHeader("If-Modified-Since").IsSet()
+func (r *Response) DateHeader(name string) *DateTime {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &DateTime{r.chain, nil}
+	}
+	value := r.resp.Header.Get(name)
+	if value == "" {
+		return &DateTime{r.chain, nil}
 	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		r.chain.fail(
+			"\nexpected %q header containing valid HTTP date, but got:\n %q",
+			name, value)
+		return &DateTime{r.chain, nil}
+	}
+	return &DateTime{r.chain.enter(headerKey(name)), &t}
+}
 
-	return &String{r.chain, content}
+// Date is a shorthand for DateHeader("Date").
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Date().InRange(time.Now().Add(-time.Minute), time.Now())
+func (r *Response) Date() *DateTime {
+	defer r.chain.leave(r.chain.failed())
+	return r.DateHeader("Date")
 }
 
-// Form returns a new Object that may be used to inspect form contents
-// of response.
+// Expires is a shorthand for DateHeader("Expires").
 //
-// Form succeeds if response contains "application/x-www-form-urlencoded"
-// Content-Type header and if form may be decoded from response body.
-// Decoding is performed using https://github.com/ajg/form.
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Expires().Gt(resp.Date().Raw())
+func (r *Response) Expires() *DateTime {
+	defer r.chain.leave(r.chain.failed())
+	return r.DateHeader("Expires")
+}
+
+// LastModified is a shorthand for DateHeader("Last-Modified").
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Form().Value("foo").Equal("bar")
-//  resp.Form(ContentOpts{
-//    MediaType: "application/x-www-form-urlencoded",
-//  }).Value("foo").Equal("bar")
-func (r *Response) Form(opts ...ContentOpts) *Object {
-	object := r.getForm(opts...)
-	return &Object{r.chain, object}
+//  resp.LastModified().Le(resp.Date().Raw())
+func (r *Response) LastModified() *DateTime {
+	defer r.chain.leave(r.chain.failed())
+	return r.DateHeader("Last-Modified")
 }
 
-func (r *Response) getForm(opts ...ContentOpts) map[string]interface{} {
+// Age returns a new Duration object that may be used to inspect "Age"
+// header, which contains number of seconds.
+//
+// If the header is missing, Age returns Duration in "not set" state,
+// see Duration.NotSet. If the header isn't a non-negative integer, failure
+// is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Age().Le(time.Minute)
+func (r *Response) Age() *Duration {
+	defer r.chain.leave(r.chain.failed())
 	if r.chain.failed() {
-		return nil
+		return &Duration{r.chain, nil}
 	}
-
-	if !r.checkContentOpts(opts, "application/x-www-form-urlencoded", "") {
-		return nil
+	value := r.resp.Header.Get("Age")
+	if value == "" {
+		return &Duration{r.chain, nil}
 	}
-
-	decoder := form.NewDecoder(bytes.NewReader(r.content))
-
-	var object map[string]interface{}
-	if err := decoder.Decode(&object); err != nil {
-		r.chain.fail(err.Error())
-		return nil
+	seconds, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		r.chain.fail(
+			"\nexpected \"Age\" header containing non-negative integer, but got:\n %q",
+			value)
+		return &Duration{r.chain, nil}
 	}
-
-	return object
+	d := time.Duration(seconds) * time.Second
+	return &Duration{r.chain.enter(headerKey("Age")), &d}
 }
 
-// JSON returns a new Value object that may be used to inspect JSON contents
-// of response.
+// RetryAfter returns a new Duration object that may be used to inspect
+// "Retry-After" header.
 //
-// JSON succeeds if response contains "application/json" Content-Type header
-// with empty or "utf-8" charset and if JSON may be decoded from response body.
+// The header may contain either number of seconds, or HTTP date. In the
+// latter case, the duration is computed relative to "Date" header, or to
+// current time if "Date" header is missing. Dates in the past give zero
+// duration.
+//
+// If the header is missing, RetryAfter returns Duration in "not set" state,
+// see Duration.NotSet. If the header can't be parsed, failure is reported.
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.JSON().Array().Elements("foo", "bar")
+//  resp.Status(http.StatusTooManyRequests).
+//      RetryAfter().InRange(1*time.Second, 5*time.Second)
+func (r *Response) RetryAfter() *Duration {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &Duration{r.chain, nil}
+	}
+	value := r.resp.Header.Get("Retry-After")
+	if value == "" {
+		return &Duration{r.chain, nil}
+	}
+	var d time.Duration
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else {
+		t, err := http.ParseTime(value)
+		if err != nil {
+			r.chain.fail(
+				"\nexpected \"Retry-After\" header containing"+
+					" number of seconds or HTTP date, but got:\n %q",
+				value)
+			return &Duration{r.chain, nil}
+		}
+		now := time.Now()
+		if date, err := http.ParseTime(r.resp.Header.Get("Date")); err == nil {
+			now = date
+		}
+		if d = t.Sub(now); d < 0 {
+			d = 0
+		}
+	}
+	return &Duration{r.chain.enter(headerKey("Retry-After")), &d}
+}
+
+// CacheControl returns a new CacheControl object that may be used to
+// inspect "Cache-Control" header. Multiple header lines are combined.
+//
+// If header is missing, returned object has no directives. If header can't
+// be parsed, failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  cc := resp.CacheControl()
+//  cc.Public().NotContainsDirective("no-store")
+//  cc.MaxAge().Equal(time.Hour)
+//  cc.SMaxAge().Equal(10 * time.Minute)
+func (r *Response) CacheControl() *CacheControl {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &CacheControl{r.chain, nil}
+	}
+	headers := r.resp.Header.Values("Cache-Control")
+	directives, err := parseCacheControl(headers)
+	if err != nil {
+		r.chain.fail("\nexpected valid \"Cache-Control\" header, but got:\n %q\n\n%s",
+			strings.Join(headers, ", "), err.Error())
+		return &CacheControl{r.chain, nil}
+	}
+	return &CacheControl{r.chain.enter(headerKey("Cache-Control")), directives}
+}
+
+// ETag returns a new ETag object that may be used to inspect "ETag" header.
+//
+// If header is missing or is not a valid entity tag, failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.ETag().IsStrong().Value().Equal("v1")
+func (r *Response) ETag() *ETag {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &ETag{r.chain, "", false}
+	}
+	header := r.resp.Header.Values("ETag")
+	if len(header) == 0 {
+		r.chain.fail("\nexpected \"ETag\" header, but it's missing")
+		return &ETag{r.chain, "", false}
+	}
+	value, weak, ok := parseETag(header[0])
+	if !ok {
+		failETag(&r.chain, header[0])
+		return &ETag{r.chain, "", false}
+	}
+	return &ETag{r.chain.enter(headerKey("ETag")), value, weak}
+}
+
+// KeepAliveTimeout returns a new Duration object that may be used to inspect
+// "timeout" parameter of "Keep-Alive" header, which contains number of
+// seconds.
+//
+// If the header or its "timeout" parameter is missing, KeepAliveTimeout
+// returns Duration in "not set" state, see Duration.NotSet. If the parameter
+// isn't a non-negative integer, failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.KeepAliveTimeout().Ge(5 * time.Second)
+func (r *Response) KeepAliveTimeout() *Duration {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &Duration{r.chain, nil}
+	}
+	value := r.resp.Header.Get("Keep-Alive")
+	for _, param := range strings.Split(value, ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if !strings.EqualFold(strings.TrimSpace(kv[0]), "timeout") {
+			continue
+		}
+		var timeout string
+		if len(kv) == 2 {
+			timeout = strings.TrimSpace(kv[1])
+		}
+		seconds, err := strconv.ParseUint(timeout, 10, 32)
+		if err != nil {
+			r.chain.fail(
+				"\nexpected \"Keep-Alive\" header containing"+
+					" non-negative integer timeout, but got:\n %q",
+				value)
+			return &Duration{r.chain, nil}
+		}
+		d := time.Duration(seconds) * time.Second
+		return &Duration{r.chain.enter(headerKey("Keep-Alive")), &d}
+	}
+	return &Duration{r.chain, nil}
+}
+
+// Cookies returns a new Array object with all cookie names set by this response.
+// Returned Array contains a String value for every cookie name.
+//
+// Note that this returns only cookies set by Set-Cookie headers of this response.
+// It doesn't return session cookies from previous responses, which may be stored
+// in a cookie jar.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Cookies().Contains("session")
+func (r *Response) Cookies() *Array {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &Array{r.chain, nil}
+	}
+	names := []interface{}{}
+	for _, c := range r.cookies {
+		names = append(names, c.Name)
+	}
+	return &Array{r.chain.enter("Cookies"), names}
+}
+
+// Cookie returns a new Cookie object that may be used to inspect given cookie
+// set by this response.
+//
+// Note that this returns only cookies set by Set-Cookie headers of this response.
+// It doesn't return session cookies from previous responses, which may be stored
+// in a cookie jar.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Cookie("session").Domain().Equal("example.com")
+func (r *Response) Cookie(name string) *Cookie {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return &Cookie{r.chain, nil}
+	}
+	names := []string{}
+	for _, c := range r.cookies {
+		if c.Name == name {
+			return &Cookie{r.chain.enter(fmt.Sprintf("Cookie[%q]", name)), c}
+		}
+		names = append(names, c.Name)
+	}
+	r.chain.fail("\nexpected response with cookie:\n %q\n\nbut got only cookies:\n%s",
+		name, dumpValue(names))
+	return &Cookie{r.chain, nil}
+}
+
+// Websocket returns Websocket object that can be used to interact with
+// WebSocket server.
+//
+// May be called only if the WithWebsocketUpgrade was called on the request.
+// That is responsibility of the caller to explicitly close the websocket after use.
+//
+// Example:
+//  req := NewRequest(config, "GET", "/path")
+//  req.WithWebsocketUpgrade()
+//  ws := req.Expect().Websocket()
+//  defer ws.Disconnect()
+func (r *Response) Websocket() *Websocket {
+	defer r.chain.leave(r.chain.failed())
+	if !r.chain.failed() && r.websocket == nil {
+		r.chain.failUsage("\nunexpected Websocket call for non-WebSocket response")
+	}
+	ws := makeWebsocket(r.config, r.chain, r.websocket)
+	if r.resp != nil {
+		ws.compression = wsCompressionNegotiated(r.resp.Header)
+	}
+	return ws
+}
+
+// SSE returns a new SSE object that may be used to read and inspect events
+// from Server-Sent Events stream.
+//
+// SSE succeeds if response contains "text/event-stream" Content-Type header
+// with empty or "utf-8" charset.
+//
+// Body of such responses is not read upfront, so Body() returns empty
+// string for them. Instead, events are read incrementally when requested.
+// The caller should explicitly close the stream after use.
+//
+// Example:
+//  sse := resp.SSE()
+//  defer sse.Close()
+//  sse.Next(time.Second).Event().Equal("update")
+func (r *Response) SSE() *SSE {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return makeSSE(r.chain.enter("SSE"), nil)
+	}
+	if !r.checkBody("SSE") {
+		return makeSSE(r.chain.enter("SSE"), nil)
+	}
+	if !r.checkContentType("text/event-stream") {
+		return makeSSE(r.chain.enter("SSE"), nil)
+	}
+	if r.resp.Body == nil {
+		r.chain.failUsage("\nunexpected SSE call for response without body")
+		return makeSSE(r.chain.enter("SSE"), nil)
+	}
+	r.streaming = true
+	return makeSSE(r.chain.enter("SSE"), r.resp.Body)
+}
+
+// Body returns a new String object that may be used to inspect response body.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Body().NotEmpty()
+//  resp.Body().Length().Equal(100)
+func (r *Response) Body() *String {
+	defer r.chain.leave(r.chain.failed())
+	r.checkBody("Body")
+	return &String{r.chain.enter("Body"), string(r.content)}
+}
+
+// NoContent succeeds if response contains empty Content-Type header and
+// empty body.
+func (r *Response) NoContent() *Response {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return r
+	}
+
+	if !r.checkBody("NoContent") {
+		return r
+	}
+
+	contentType := r.resp.Header.Get("Content-Type")
+
+	r.checkEqual("\"Content-Type\" header", "", contentType)
+	r.checkEqual("body", "", string(r.content))
+
+	return r
+}
+
+// ContentType succeeds if response contains Content-Type header with given
+// media type and charset.
+//
+// If charset is omitted, and mediaType is non-empty, Content-Type header
+// should contain empty or utf-8 charset.
+//
+// If charset is omitted, and mediaType is also empty, Content-Type header
+// should contain no charset.
+func (r *Response) ContentType(mediaType string, charset ...string) *Response {
+	defer r.chain.leave(r.chain.failed())
+	r.checkContentType(mediaType, charset...)
+	return r
+}
+
+// ContentEncoding succeeds if response has exactly given Content-Encoding list.
+// Common values are empty, "gzip", "compress", "deflate", "identity" and "br".
+func (r *Response) ContentEncoding(encoding ...string) *Response {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return r
+	}
+	r.checkEqual("\"Content-Encoding\" header", encoding, r.resp.Header["Content-Encoding"])
+	return r
+}
+
+// BodySize returns a new Number object that may be used to inspect the
+// number of body bytes received on the wire, i.e. size of compressed body
+// if response has Content-Encoding.
+//
+// If the transport transparently decompressed the body (as http.Transport
+// does for gzip when request doesn't set Accept-Encoding), the compressed
+// size is not available and failure is reported. Set Accept-Encoding
+// header explicitly to receive the body as is.
+//
+// Body should be read, i.e. it should not be discarded or taken by SSE.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.ContentEncoding("gzip")
+//  resp.BodySize().Le(50 << 10)
+func (r *Response) BodySize() *Number {
+	defer r.chain.leave(r.chain.failed())
+	if !r.checkBodyRead("BodySize") {
+		return &Number{r.chain, 0}
+	}
+
+	chain := r.chain.enter("BodySize")
+
+	if r.resp.Uncompressed {
+		chain.fail("\nexpected response with known compressed body size," +
+			" but body was decompressed by transport")
+		return &Number{chain, 0}
+	}
+
+	return &Number{chain, float64(len(r.content))}
+}
+
+// UncompressedBodySize returns a new Number object that may be used to
+// inspect size of response body after decoding Content-Encoding.
+//
+// Supported encodings are "gzip", "x-gzip", "deflate" and "identity".
+// If response has other encoding or body can't be decoded, failure is
+// reported. If the transport transparently decompressed the body, its
+// size is used as is.
+//
+// Body should be read, i.e. it should not be discarded or taken by SSE.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.UncompressedBodySize().Gt(resp.BodySize().Raw())
+func (r *Response) UncompressedBodySize() *Number {
+	defer r.chain.leave(r.chain.failed())
+	if !r.checkBodyRead("UncompressedBodySize") {
+		return &Number{r.chain, 0}
+	}
+
+	chain := r.chain.enter("UncompressedBodySize")
+
+	if r.resp.Uncompressed {
+		return &Number{chain, float64(len(r.content))}
+	}
+
+	body, err := decodeContent(r.content, r.resp.Header["Content-Encoding"])
+	if err != nil {
+		chain.fail("\nexpected response body with decodable \"Content-Encoding\"," +
+			" but got error:\n %s", err)
+		return &Number{chain, 0}
+	}
+
+	return &Number{chain, float64(len(body))}
+}
+
+// checkBodyRead reports failure if response body was not buffered.
+func (r *Response) checkBodyRead(where string) bool {
+	if r.chain.failed() {
+		return false
+	}
+	if !r.checkBody(where) {
+		return false
+	}
+	if r.streaming || r.content == nil {
+		r.chain.failUsage("\nunexpected %s call for response with body not read", where)
+		return false
+	}
+	return true
+}
+
+// decodeContent reverts given Content-Encoding list, applied in order.
+func decodeContent(content []byte, encodings []string) ([]byte, error) {
+	var list []string
+	for _, e := range encodings {
+		for _, item := range strings.Split(e, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, strings.ToLower(item))
+			}
+		}
+	}
+
+	for i := len(list) - 1; i >= 0; i-- {
+		var (
+			reader io.Reader
+			err    error
+		)
+		switch list[i] {
+		case "identity":
+			continue
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(content))
+		case "deflate":
+			// "deflate" is zlib format, but some servers send raw deflate
+			reader, err = zlib.NewReader(bytes.NewReader(content))
+			if err != nil {
+				reader, err = flate.NewReader(bytes.NewReader(content)), nil
+			}
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", list[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if content, err = ioutil.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	return content, nil
+}
+
+// TransferEncoding succeeds if response contains given Transfer-Encoding list.
+// Common values are empty, "chunked" and "identity".
+func (r *Response) TransferEncoding(encoding ...string) *Response {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return r
+	}
+	r.checkEqual("\"Transfer-Encoding\" header", encoding, r.resp.TransferEncoding)
+	return r
+}
+
+// ContentOpts define parameters for matching the response content parameters.
+type ContentOpts struct {
+	// The media type Content-Type part, e.g. "application/json"
+	MediaType string
+	// The character set Content-Type part, e.g. "utf-8"
+	Charset string
+}
+
+// Text returns a new String object that may be used to inspect response body.
+//
+// Text succeeds if response contains "text/plain" Content-Type header
+// with empty or "utf-8" charset.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Text().Equal("hello, world!")
+//  resp.Text(ContentOpts{
+//    MediaType: "text/plain",
+//  }).Equal("hello, world!")
+func (r *Response) Text(opts ...ContentOpts) *String {
+	defer r.chain.leave(r.chain.failed())
+	var content string
+
+	if !r.chain.failed() && r.checkBody("Text") &&
+		r.checkContentOpts(opts, "text/plain") {
+		content = string(r.content)
+	}
+
+	return &String{r.chain.enter("Text"), content}
+}
+
+// Form returns a new Object that may be used to inspect form contents
+// of response.
+//
+// Form succeeds if response contains "application/x-www-form-urlencoded"
+// Content-Type header and if form may be decoded from response body.
+// Decoding is performed using https://github.com/ajg/form.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Form().Value("foo").Equal("bar")
+//  resp.Form(ContentOpts{
+//    MediaType: "application/x-www-form-urlencoded",
+//  }).Value("foo").Equal("bar")
+func (r *Response) Form(opts ...ContentOpts) *Object {
+	defer r.chain.leave(r.chain.failed())
+	object := r.getForm(opts...)
+	return &Object{r.chain.enter("Form"), object}
+}
+
+func (r *Response) getForm(opts ...ContentOpts) map[string]interface{} {
+	if r.chain.failed() {
+		return nil
+	}
+
+	if !r.checkBody("Form") {
+		return nil
+	}
+
+	if !r.checkContentOpts(opts, "application/x-www-form-urlencoded", "") {
+		return nil
+	}
+
+	decoder := form.NewDecoder(bytes.NewReader(r.content))
+
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		r.chain.fail(err.Error())
+		return nil
+	}
+
+	return object
+}
+
+// JSON returns a new Value object that may be used to inspect JSON contents
+// of response.
+//
+// JSON succeeds if response contains "application/json" Content-Type header
+// with empty or "utf-8" charset and if JSON may be decoded from response body.
+//
+// Leading UTF-8 byte order mark is stripped before decoding, unless
+// Config.ForbidJSONBOM is set. UTF-16 and UTF-32 encoded bodies are reported
+// as failure.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.JSON().Array().Elements("foo", "bar")
 //  resp.JSON(ContentOpts{
 //    MediaType: "application/json",
 //  }).Array.Elements("foo", "bar")
 func (r *Response) JSON(opts ...ContentOpts) *Value {
+	defer r.chain.leave(r.chain.failed())
+	value := r.getJSON(opts...)
+	return &Value{r.chain.enter("JSON"), value}
+}
+
+// IsJSON succeeds if response body is a valid JSON document.
+//
+// Unlike JSON, IsJSON doesn't check Content-Type header and doesn't decode
+// the body into values, so it doesn't allocate memory for the document
+// structure. Note that response body itself is still read into memory
+// when Response is created, like for other methods. If body is not valid,
+// failure contains syntax error offset and a snippet of body around it.
+// Byte order mark is handled the same way as in JSON.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.IsJSON()
+func (r *Response) IsJSON() *Response {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return r
+	}
+
+	if !r.checkBody("IsJSON") {
+		return r
+	}
+
+	content, ok := r.jsonContent()
+	if !ok {
+		return r
+	}
+
+	if json.Valid(content) {
+		return r
+	}
+
+	// decoding into RawMessage validates input before copying it, so
+	// this reports syntax error without building any values
+	var offset int64
+	var raw json.RawMessage
+	err := json.Unmarshal(content, &raw)
+	if serr, ok := err.(*json.SyntaxError); ok {
+		offset = serr.Offset
+	} else if err == nil {
+		err = errors.New("invalid JSON")
+	}
+
+	r.chain.fail("\nexpected valid JSON body, but got syntax error at offset %d:\n %s"+
+		"\n\nbody around offset:\n %q",
+		offset, err.Error(), snippetAround(content, offset, 32))
+
+	return r
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF32BE = []byte{0x00, 0x00, 0xFE, 0xFF}
+	bomUTF32LE = []byte{0xFF, 0xFE, 0x00, 0x00}
+)
+
+// jsonContent returns body to be decoded as JSON. Leading UTF-8 byte order
+// mark is stripped, unless Config.ForbidJSONBOM is set. Bodies in UTF-16 and
+// UTF-32 are reported as failure, since JSON should be encoded in UTF-8.
+func (r *Response) jsonContent() ([]byte, bool) {
+	content := r.content
+
+	if encoding := jsonNonUTF8Encoding(content); encoding != "" {
+		r.chain.fail("\nexpected UTF-8 encoded JSON body, but got %s encoded JSON",
+			encoding)
+		return nil, false
+	}
+
+	if bytes.HasPrefix(content, bomUTF8) {
+		if r.config.ForbidJSONBOM {
+			r.chain.fail("\nexpected JSON body without byte order mark," +
+				" but got UTF-8 byte order mark")
+			return nil, false
+		}
+		content = content[len(bomUTF8):]
+	}
+
+	return content, true
+}
+
+// jsonNonUTF8Encoding detects UTF-16 and UTF-32 encoded JSON using byte
+// order mark or, if it's missing, pattern of zero bytes in the first
+// characters, which are always ASCII (see RFC 4627, section 3).
+func jsonNonUTF8Encoding(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, bomUTF32BE):
+		return "UTF-32BE"
+	case bytes.HasPrefix(content, bomUTF32LE):
+		return "UTF-32LE"
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return "UTF-16BE"
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return "UTF-16LE"
+	}
+	if len(content) >= 4 {
+		switch {
+		case content[0] == 0 && content[1] == 0 && content[2] == 0 && content[3] != 0:
+			return "UTF-32BE"
+		case content[0] != 0 && content[1] == 0 && content[2] == 0 && content[3] == 0:
+			return "UTF-32LE"
+		}
+	}
+	if len(content) >= 2 {
+		switch {
+		case content[0] == 0 && content[1] != 0:
+			return "UTF-16BE"
+		case content[0] != 0 && content[1] == 0:
+			return "UTF-16LE"
+		}
+	}
+	return ""
+}
+
+// snippetAround returns up to n bytes of buf before and after offset.
+func snippetAround(buf []byte, offset int64, n int) string {
+	begin, end := int(offset)-n, int(offset)+n
+	if begin < 0 {
+		begin = 0
+	}
+	if end > len(buf) {
+		end = len(buf)
+	}
+	if begin > end {
+		begin = end
+	}
+	return string(buf[begin:end])
+}
+
+// JSONStrict succeeds if response contains JSON (see JSON) which is strictly
+// equal to given Go value.
+//
+// Any key present in response but absent in expected value is reported as
+// failure, together with missing keys and mismatched values. Ignore may be
+// used in expected value for volatile fields. See Value.EqualStrict.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.JSONStrict(map[string]interface{}{
+//      "id":   httpexpect.Ignore,
+//      "name": "john",
+//  })
+func (r *Response) JSONStrict(expected interface{}, opts ...ContentOpts) *Response {
+	defer r.chain.leave(r.chain.failed())
 	value := r.getJSON(opts...)
-	return &Value{r.chain, value}
+	checkStrict(&r.chain, value, expected)
+	return r
 }
 
 func (r *Response) getJSON(opts ...ContentOpts) interface{} {
@@ -453,12 +1480,21 @@ func (r *Response) getJSON(opts ...ContentOpts) interface{} {
 		return nil
 	}
 
+	if !r.checkBody("JSON") {
+		return nil
+	}
+
 	if !r.checkContentOpts(opts, "application/json") {
 		return nil
 	}
 
+	content, ok := r.jsonContent()
+	if !ok {
+		return nil
+	}
+
 	var value interface{}
-	if err := json.Unmarshal(r.content, &value); err != nil {
+	if err := json.Unmarshal(content, &value); err != nil {
 		r.chain.fail(err.Error())
 		return nil
 	}
@@ -484,8 +1520,9 @@ func (r *Response) getJSON(opts ...ContentOpts) interface{} {
 //    MediaType: "application/javascript",
 //  }).Array.Elements("foo", "bar")
 func (r *Response) JSONP(callback string, opts ...ContentOpts) *Value {
+	defer r.chain.leave(r.chain.failed())
 	value := r.getJSONP(callback, opts...)
-	return &Value{r.chain, value}
+	return &Value{r.chain.enter("JSONP"), value}
 }
 
 var (
@@ -497,11 +1534,20 @@ func (r *Response) getJSONP(callback string, opts ...ContentOpts) interface{} {
 		return nil
 	}
 
+	if !r.checkBody("JSONP") {
+		return nil
+	}
+
 	if !r.checkContentOpts(opts, "application/javascript") {
 		return nil
 	}
 
-	m := jsonp.FindSubmatch(r.content)
+	content, ok := r.jsonContent()
+	if !ok {
+		return nil
+	}
+
+	m := jsonp.FindSubmatch(content)
 	if len(m) != 3 || string(m[1]) != callback {
 		r.chain.fail(
 			"\nexpected JSONP body in form of:\n \"%s(<valid json>)\"\n\nbut got:\n %q\n",
@@ -519,6 +1565,240 @@ func (r *Response) getJSONP(callback string, opts ...ContentOpts) interface{} {
 	return value
 }
 
+// MsgPack returns a new Value object that may be used to inspect MessagePack
+// contents of response.
+//
+// MsgPack succeeds if response contains "application/msgpack" Content-Type
+// header and if MessagePack may be decoded from response body.
+//
+// If target is given, body is decoded into target (which should be a
+// non-nil pointer), and returned Value holds target converted to canonical
+// form. Otherwise, body is decoded into generic value, which is converted
+// to the same maps, slices and float64 numbers as JSON uses.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.MsgPack().Object().ValueEqual("foo", "bar")
+//
+//  var user User
+//  resp.MsgPack(&user).Object().ValueEqual("name", "john")
+func (r *Response) MsgPack(target ...interface{}) *Value {
+	defer r.chain.leave(r.chain.failed())
+	value := r.getMsgPack(target...)
+	return &Value{r.chain.enter("MsgPack"), value}
+}
+
+func (r *Response) getMsgPack(target ...interface{}) interface{} {
+	if r.chain.failed() {
+		return nil
+	}
+
+	if len(target) > 1 {
+		r.chain.failUsage("\nunexpected multiple targets in MsgPack")
+		return nil
+	}
+
+	if !r.checkBody("MsgPack") {
+		return nil
+	}
+
+	if !r.checkContentType("application/msgpack") {
+		return nil
+	}
+
+	var generic interface{}
+
+	dest := interface{}(&generic)
+	if len(target) != 0 {
+		dest = target[0]
+	}
+
+	reader := bytes.NewReader(r.content)
+
+	if err := msgpack.NewDecoder(reader).Decode(dest); err != nil {
+		r.chain.fail(
+			"\nfailed to decode MessagePack body at offset %d:\n %s",
+			len(r.content)-reader.Len(), err.Error())
+		return nil
+	}
+
+	if len(target) != 0 {
+		generic = target[0]
+	}
+
+	value, ok := canonValue(&r.chain, generic)
+	if !ok {
+		return nil
+	}
+
+	return value
+}
+
+// YAML returns a new Value object that may be used to inspect YAML contents
+// of response.
+//
+// YAML succeeds if response contains "application/yaml" Content-Type header
+// and if YAML may be decoded from response body. Anchors and aliases are
+// resolved. If body contains multiple YAML documents, failure is reported;
+// use YAMLDocuments to inspect them.
+//
+// If target is given, body is decoded into target (which should be a
+// non-nil pointer), and returned Value holds target converted to canonical
+// form. Otherwise, body is decoded into generic value, which is converted
+// to the same maps, slices and float64 numbers as JSON uses. Mapping keys
+// should be strings, otherwise failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.YAML().Object().ValueEqual("foo", "bar")
+//
+//  var config Config
+//  resp.YAML(&config).Object().ValueEqual("name", "john")
+func (r *Response) YAML(target ...interface{}) *Value {
+	defer r.chain.leave(r.chain.failed())
+	value := r.getYAML(target...)
+	return &Value{r.chain.enter("YAML"), value}
+}
+
+// YAMLDocuments returns a new Array object that may be used to inspect
+// multi-document YAML contents of response. Every element of the array
+// holds one document, converted in the same way as by YAML.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.YAMLDocuments().Length().Equal(2)
+func (r *Response) YAMLDocuments() *Array {
+	defer r.chain.leave(r.chain.failed())
+	docs := r.decodeYAML("YAMLDocuments")
+	return &Array{r.chain.enter("YAMLDocuments"), docs}
+}
+
+func (r *Response) getYAML(target ...interface{}) interface{} {
+	if r.chain.failed() {
+		return nil
+	}
+
+	if len(target) > 1 {
+		r.chain.failUsage("\nunexpected multiple targets in YAML")
+		return nil
+	}
+
+	docs := r.decodeYAML("YAML")
+	if docs == nil {
+		return nil
+	}
+
+	if len(docs) != 1 {
+		r.chain.fail(
+			"\nexpected single YAML document in response body,"+
+				" but got %d documents\n\nuse YAMLDocuments to inspect them", len(docs))
+		return nil
+	}
+
+	if len(target) == 0 {
+		return docs[0]
+	}
+
+	if err := yaml.Unmarshal(r.content, target[0]); err != nil {
+		r.chain.fail("\nfailed to decode YAML body:\n %s", err.Error())
+		return nil
+	}
+
+	value, ok := canonValue(&r.chain, target[0])
+	if !ok {
+		return nil
+	}
+
+	return value
+}
+
+func (r *Response) decodeYAML(where string) []interface{} {
+	if r.chain.failed() {
+		return nil
+	}
+
+	if !r.checkBody(where) {
+		return nil
+	}
+
+	if !r.checkContentType("application/yaml") {
+		return nil
+	}
+
+	docs := []interface{}{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(r.content))
+	for {
+		var doc interface{}
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			r.chain.fail("\nfailed to decode YAML document %d:\n %s",
+				len(docs), err.Error())
+			return nil
+		}
+
+		doc, ok := canonYAML(&r.chain, doc, "")
+		if !ok {
+			return nil
+		}
+
+		value, ok := canonValue(&r.chain, doc)
+		if !ok {
+			return nil
+		}
+
+		docs = append(docs, value)
+	}
+
+	return docs
+}
+
+// canonYAML converts maps decoded by yaml.v2 to JSON-compatible maps, and
+// reports failure if some mapping has non-string key.
+func canonYAML(chain *chain, v interface{}, path string) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			key, ok := k.(string)
+			if !ok {
+				chain.fail(
+					"\nexpected YAML mapping with string keys,"+
+						" but got %T key %v at path %q", k, k, path)
+				return nil, false
+			}
+			conv, ok := canonYAML(chain, val, joinPath(path, key))
+			if !ok {
+				return nil, false
+			}
+			m[key] = conv
+		}
+		return m, true
+	case []interface{}:
+		for i := range v {
+			conv, ok := canonYAML(chain, v[i], fmt.Sprintf("%s[%d]", path, i))
+			if !ok {
+				return nil, false
+			}
+			v[i] = conv
+		}
+		return v, true
+	default:
+		return v, true
+	}
+}
+
+// checkBody reports failure if response body was discarded.
+func (r *Response) checkBody(where string) bool {
+	if r.discarded {
+		r.chain.failUsage("\nunexpected %s call for response"+
+			" with body discarded by WithDiscardBody", where)
+		return false
+	}
+	return true
+}
+
 func (r *Response) checkContentOpts(
 	opts []ContentOpts, expectedType string, expectedCharset ...string,
 ) bool {
@@ -580,6 +1860,83 @@ func (r *Response) checkContentType(expectedType string, expectedCharset ...stri
 	return true
 }
 
+// EqualResponse succeeds if response is equal to other response, which is
+// useful to check that replayed request (e.g. with the same idempotency key)
+// returned identical result.
+//
+// Responses are compared by status code, values of given headers, and body.
+// If both bodies are valid JSON, they are compared after canonicalization,
+// so that formatting and key order don't matter; otherwise, bodies are
+// compared byte-to-byte.
+//
+// Only headers listed explicitly are compared, since some headers (like
+// "Date") will always differ between responses.
+//
+// Example:
+//  resp1 := e.POST("/payments").WithIdempotencyKey("abc").Expect()
+//  resp2 := e.POST("/payments").WithIdempotencyKey("abc").Expect()
+//  resp2.EqualResponse(resp1, "Content-Type", "Location")
+func (r *Response) EqualResponse(other *Response, headers ...string) *Response {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return r
+	}
+
+	if other == nil || other.resp == nil {
+		r.chain.failUsage("\nunexpected nil response passed to EqualResponse")
+		return r
+	}
+	if other.chain.failed() {
+		r.chain.failUsage("\nunexpected failed response passed to EqualResponse")
+		return r
+	}
+
+	if r.resp.StatusCode != other.resp.StatusCode {
+		r.chain.fail(
+			"\nexpected status equal to status of other response:\n %s\n\nbut got:\n %s",
+			statusCodeText(other.resp.StatusCode), statusCodeText(r.resp.StatusCode))
+		return r
+	}
+
+	for _, h := range headers {
+		expected := other.resp.Header.Values(h)
+		actual := r.resp.Header.Values(h)
+		if !reflect.DeepEqual(expected, actual) {
+			r.chain.fail(
+				"\nexpected %q header equal to header of other response:\n%s"+
+					"\n\nbut got:\n%s",
+				http.CanonicalHeaderKey(h), dumpValue(expected), dumpValue(actual))
+			return r
+		}
+	}
+
+	if r.discarded || other.discarded {
+		r.chain.failUsage("\nunexpected EqualResponse call for response" +
+			" with body discarded by WithDiscardBody")
+		return r
+	}
+
+	var expected, actual interface{}
+	if json.Unmarshal(other.content, &expected) == nil &&
+		json.Unmarshal(r.content, &actual) == nil {
+		if !reflect.DeepEqual(expected, actual) {
+			r.chain.fail(
+				"\nexpected JSON body equal to body of other response:\n%s"+
+					"\n\nbut got:\n%s\n\ndiff:\n%s",
+				dumpValue(expected), dumpValue(actual), diffValues(expected, actual))
+		}
+		return r
+	}
+
+	if !bytes.Equal(r.content, other.content) {
+		r.chain.fail(
+			"\nexpected body equal to body of other response:\n %q\n\nbut got:\n %q",
+			string(other.content), string(r.content))
+	}
+
+	return r
+}
+
 func (r *Response) checkEqual(what string, expected, actual interface{}) {
 	if !reflect.DeepEqual(expected, actual) {
 		r.chain.fail("\nexpected %s equal to:\n%s\n\nbut got:\n%s", what,
@@ -2,13 +2,19 @@ package httpexpect
 
 import (
 	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func TestResponseFailed(t *testing.T) {
@@ -44,6 +50,77 @@ func TestResponseFailed(t *testing.T) {
 	resp.ContentType("", "")
 	resp.ContentEncoding("")
 	resp.TransferEncoding("")
+	resp.Vary("Accept")
+	resp.VaryContains("Accept")
+	resp.JSONStrict(nil)
+
+	resp.DateHeader("Date").chain.assertFailed(t)
+	resp.Date().chain.assertFailed(t)
+	resp.Expires().chain.assertFailed(t)
+	resp.LastModified().chain.assertFailed(t)
+	resp.Age().chain.assertFailed(t)
+	resp.HeaderValues("Warning").chain.assertFailed(t)
+	resp.Links().chain.assertFailed(t)
+	resp.RetryAfter().chain.assertFailed(t)
+	resp.KeepAliveTimeout().chain.assertFailed(t)
+	resp.YAML().chain.assertFailed(t)
+	resp.YAMLDocuments().chain.assertFailed(t)
+	resp.BodySize().chain.assertFailed(t)
+	resp.UncompressedBodySize().chain.assertFailed(t)
+}
+
+func TestResponseRaw(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`{"foo":123}`)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, []byte(`{"foo":123}`), resp.RawBytes())
+
+	raw := resp.Raw()
+	assert.True(t, raw == httpResp)
+
+	b, err := ioutil.ReadAll(raw.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"foo":123}`, string(b))
+
+	resp.Body().Equal(`{"foo":123}`)
+	resp.JSON().Object().ValueEqual("foo", 123)
+	resp.chain.assertOK(t)
+
+	b, err = ioutil.ReadAll(resp.Raw().Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"foo":123}`, string(b))
+
+	resp.Body().Equal(`{"foo":123}`)
+	resp.chain.assertOK(t)
+
+	assert.Nil(t, NewResponse(reporter, nil).Raw())
+}
+
+func TestResponseRawStreaming(t *testing.T) {
+	resp := newSSEResponse(t, "data: hello\n\n")
+
+	assert.Nil(t, resp.RawBytes())
+
+	sse := resp.SSE()
+	resp.chain.assertOK(t)
+
+	assert.NotNil(t, resp.Raw())
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	sse.Next(time.Second).Data().Equal("hello")
+	sse.chain.assertOK(t)
+	sse.Close()
 }
 
 func TestResponseRoundTripTime(t *testing.T) {
@@ -197,6 +274,511 @@ func TestResponseHeaders(t *testing.T) {
 	resp.Header("Bad-Header").Empty().chain.assertOK(t)
 }
 
+func TestResponseEqualResponse(t *testing.T) {
+	makeResp := func(status int, headers map[string][]string, body string) *Response {
+		return NewResponse(newMockReporter(t), &http.Response{
+			StatusCode: status,
+			Header:     http.Header(headers),
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		})
+	}
+
+	orig := makeResp(http.StatusCreated, map[string][]string{
+		"Content-Type": {"application/json"},
+		"Location":     {"/payments/1"},
+		"Date":         {"Mon, 02 Jan 2006 15:04:05 GMT"},
+	}, `{"id": 1, "amount": 10}`)
+
+	t.Run("equal", func(t *testing.T) {
+		resp := makeResp(http.StatusCreated, map[string][]string{
+			"Content-Type": {"application/json"},
+			"Location":     {"/payments/1"},
+			"Date":         {"Mon, 02 Jan 2006 15:04:06 GMT"},
+		}, `{"amount":10,"id":1}`)
+
+		resp.EqualResponse(orig, "Content-Type", "location")
+		resp.chain.assertOK(t)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		resp := makeResp(http.StatusOK, map[string][]string{
+			"Content-Type": {"application/json"},
+		}, `{"id": 1, "amount": 10}`)
+
+		resp.EqualResponse(orig)
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("header", func(t *testing.T) {
+		resp := makeResp(http.StatusCreated, map[string][]string{
+			"Content-Type": {"application/json"},
+			"Location":     {"/payments/2"},
+		}, `{"id": 1, "amount": 10}`)
+
+		resp.EqualResponse(orig, "Content-Type")
+		resp.chain.assertOK(t)
+
+		resp.EqualResponse(orig, "Content-Type", "Location")
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("date header", func(t *testing.T) {
+		resp := makeResp(http.StatusCreated, map[string][]string{
+			"Date": {"Mon, 02 Jan 2006 15:04:06 GMT"},
+		}, `{"id": 1, "amount": 10}`)
+
+		resp.EqualResponse(orig, "Date")
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("json body", func(t *testing.T) {
+		resp := makeResp(http.StatusCreated, nil, `{"id": 2, "amount": 10}`)
+
+		resp.EqualResponse(orig)
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("text body", func(t *testing.T) {
+		text1 := makeResp(http.StatusOK, nil, "hello")
+		text2 := makeResp(http.StatusOK, nil, "hello")
+		text3 := makeResp(http.StatusOK, nil, "hello!")
+
+		text2.EqualResponse(text1)
+		text2.chain.assertOK(t)
+
+		text3.EqualResponse(text1)
+		text3.chain.assertFailed(t)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		resp := makeResp(http.StatusOK, nil, "")
+
+		resp.EqualResponse(nil)
+		resp.chain.assertFailed(t)
+	})
+}
+
+func TestResponseVary(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Vary": {"accept, Accept-Encoding", "Origin"},
+	}
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       nil,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.Vary("Accept", "Accept-Encoding", "Origin")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.Vary("origin", "ACCEPT", "accept-encoding")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.Vary("Accept", "Accept-Encoding")
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	resp.Vary("Accept", "Accept-Encoding", "Origin", "Cookie")
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	resp.VaryContains("Accept")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.VaryContains("origin", "accept")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.VaryContains("Cookie")
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
+func TestResponseVaryAsterisk(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Vary": {"*"}},
+		Body:       nil,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.Vary("*")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.VaryContains("Accept")
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
+func TestResponseVaryMissing(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       nil,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.Vary()
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.VaryContains("Accept")
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
+func TestResponseDateHeaders(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	ts := time.Date(1994, time.November, 6, 8, 49, 37, 0, time.UTC)
+
+	headers := map[string][]string{
+		"Date":          {"Sun, 06 Nov 1994 08:49:37 GMT"},
+		"Expires":       {"Sunday, 06-Nov-94 08:49:37 GMT"},
+		"Last-Modified": {"Sun Nov  6 08:49:37 1994"},
+		"Bad-Date":      {"yesterday"},
+		"Age":           {"120"},
+	}
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       nil,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+	resp.chain.assertOK(t)
+
+	t.Run("rfc1123", func(t *testing.T) {
+		dt := resp.Date()
+		dt.IsSet().Equal(ts)
+		dt.chain.assertOK(t)
+		assert.True(t, ts.Equal(dt.Raw()))
+	})
+
+	t.Run("rfc850", func(t *testing.T) {
+		dt := resp.Expires()
+		dt.IsSet().Equal(ts)
+		dt.chain.assertOK(t)
+	})
+
+	t.Run("asctime", func(t *testing.T) {
+		dt := resp.LastModified()
+		dt.IsSet().Equal(ts)
+		dt.chain.assertOK(t)
+	})
+
+	t.Run("invariant", func(t *testing.T) {
+		resp.LastModified().Le(resp.Date().Raw()).chain.assertOK(t)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		dt := resp.DateHeader("If-Modified-Since")
+		dt.chain.assertOK(t)
+		dt.NotSet()
+		dt.chain.assertOK(t)
+		dt.IsSet()
+		dt.chain.assertFailed(t)
+
+		dt = resp.DateHeader("If-Modified-Since")
+		dt.Le(ts)
+		dt.chain.assertFailed(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		resp := NewResponse(reporter, httpResp)
+		dt := resp.DateHeader("Bad-Date")
+		dt.chain.assertFailed(t)
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("age", func(t *testing.T) {
+		d := resp.Age()
+		d.IsSet().Equal(2 * time.Minute)
+		d.chain.assertOK(t)
+	})
+}
+
+func TestResponseHeaderValues(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	resp := NewResponse(reporter, &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Warning":  {`199 - "first"`, `299 - "second, with comma"`},
+			"X-Single": {"a, b"},
+		},
+	})
+
+	resp.HeaderValues("warning").Length().Equal(2)
+	resp.HeaderValues("warning").
+		Elements(`199 - "first"`, `299 - "second, with comma"`)
+	resp.HeaderValues("X-Single").Elements("a, b")
+	resp.HeaderValues("X-Missing").Empty()
+	resp.chain.assertOK(t)
+
+	values := resp.HeaderValues("Warning")
+	values.Contains(`199 - "first", 299 - "second, with comma"`)
+	values.chain.assertFailed(t)
+	assert.Contains(t, reporter.message, `HeaderValues["Warning"]`)
+}
+
+func TestResponseLinks(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, tc := range []struct {
+		header   []string
+		ok       bool
+		expected map[string]interface{}
+	}{
+		{
+			header:   nil,
+			ok:       true,
+			expected: map[string]interface{}{},
+		},
+		{
+			header: []string{`</items?page=3>; rel="next"`},
+			ok:     true,
+			expected: map[string]interface{}{
+				"next": "/items?page=3",
+			},
+		},
+		{
+			header: []string{
+				`<https://example.com/items?page=1>; rel="first prev",` +
+					` <https://example.com/items?page=3>;rel=next`,
+				`</items?page=9>; title="a; b, c"; REL="Last"`,
+			},
+			ok: true,
+			expected: map[string]interface{}{
+				"first": "https://example.com/items?page=1",
+				"prev":  "https://example.com/items?page=1",
+				"next":  "https://example.com/items?page=3",
+				"last":  "/items?page=9",
+			},
+		},
+		{
+			header: []string{`</a>; rel="next", </b>; rel="next"`},
+			ok:     true,
+			expected: map[string]interface{}{
+				"next": "/a",
+			},
+		},
+		{
+			header:   []string{`</a>; title="no rel"`},
+			ok:       true,
+			expected: map[string]interface{}{},
+		},
+		{header: []string{`/a; rel="next"`}, ok: false},
+		{header: []string{`</a; rel="next"`}, ok: false},
+		{header: []string{`</a>; rel="next`}, ok: false},
+		{header: []string{`</a> rel="next"`}, ok: false},
+	} {
+		header := http.Header{}
+		if tc.header != nil {
+			header["Link"] = tc.header
+		}
+
+		resp := NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+		})
+
+		links := resp.Links()
+		if !tc.ok {
+			links.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, fmt.Sprintf("%q", tc.header[0]))
+			continue
+		}
+
+		links.chain.assertOK(t)
+		assert.Equal(t, tc.expected, links.Raw())
+	}
+}
+
+func TestResponseAge(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, tc := range []struct {
+		header []string
+		ok     bool
+		set    bool
+	}{
+		{header: nil, ok: true, set: false},
+		{header: []string{"0"}, ok: true, set: true},
+		{header: []string{"3600"}, ok: true, set: true},
+		{header: []string{"-1"}, ok: false},
+		{header: []string{"1.5"}, ok: false},
+		{header: []string{"soon"}, ok: false},
+	} {
+		header := http.Header{}
+		if tc.header != nil {
+			header["Age"] = tc.header
+		}
+
+		resp := NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+		})
+
+		d := resp.Age()
+		if !tc.ok {
+			d.chain.assertFailed(t)
+			continue
+		}
+
+		d.chain.assertOK(t)
+		if tc.set {
+			d.IsSet()
+		} else {
+			d.NotSet()
+		}
+		d.chain.assertOK(t)
+	}
+}
+
+func TestResponseRetryAfter(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	date := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		header   string
+		date     string
+		ok       bool
+		set      bool
+		expected time.Duration
+	}{
+		{header: "", ok: true, set: false},
+		{header: "0", ok: true, set: true, expected: 0},
+		{header: "120", ok: true, set: true, expected: 2 * time.Minute},
+		{
+			header:   date.Add(30 * time.Second).Format(http.TimeFormat),
+			date:     date.Format(http.TimeFormat),
+			ok:       true,
+			set:      true,
+			expected: 30 * time.Second,
+		},
+		{
+			header:   date.Add(-30 * time.Second).Format(http.TimeFormat),
+			date:     date.Format(http.TimeFormat),
+			ok:       true,
+			set:      true,
+			expected: 0,
+		},
+		{header: "-1", ok: false},
+		{header: "1.5", ok: false},
+		{header: "soon", ok: false},
+	} {
+		header := http.Header{}
+		if tc.header != "" {
+			header.Set("Retry-After", tc.header)
+		}
+		if tc.date != "" {
+			header.Set("Date", tc.date)
+		}
+
+		resp := NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     header,
+		})
+
+		d := resp.RetryAfter()
+		if !tc.ok {
+			d.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, tc.header)
+			continue
+		}
+
+		d.chain.assertOK(t)
+		if tc.set {
+			d.IsSet()
+			d.Equal(tc.expected)
+		} else {
+			d.NotSet()
+		}
+		d.chain.assertOK(t)
+	}
+
+	t.Run("relative to now", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Retry-After",
+			time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+
+		resp := NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     header,
+		})
+
+		d := resp.RetryAfter()
+		d.InRange(58*time.Minute, time.Hour)
+		d.chain.assertOK(t)
+	})
+}
+
+func TestResponseKeepAliveTimeout(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, tc := range []struct {
+		header   string
+		ok       bool
+		set      bool
+		expected time.Duration
+	}{
+		{header: "", ok: true, set: false},
+		{header: "max=100", ok: true, set: false},
+		{header: "timeout=5", ok: true, set: true, expected: 5 * time.Second},
+		{header: "timeout=5, max=1000", ok: true, set: true, expected: 5 * time.Second},
+		{header: "max=1000, Timeout = 10", ok: true, set: true, expected: 10 * time.Second},
+		{header: "timeout", ok: false},
+		{header: "timeout=-1", ok: false},
+		{header: "timeout=soon, max=10", ok: false},
+	} {
+		header := http.Header{}
+		if tc.header != "" {
+			header.Set("Keep-Alive", tc.header)
+		}
+
+		resp := NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+		})
+
+		d := resp.KeepAliveTimeout()
+		if !tc.ok {
+			d.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, tc.header)
+			continue
+		}
+
+		d.chain.assertOK(t)
+		if tc.set {
+			d.IsSet()
+			d.Equal(tc.expected)
+		} else {
+			d.NotSet()
+		}
+		d.chain.assertOK(t)
+	}
+}
+
 func TestResponseCookies(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -280,6 +862,42 @@ func TestResponseBody(t *testing.T) {
 	resp.chain.reset()
 }
 
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestResponseHeadNoBody(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Length": {"100"},
+		},
+		ContentLength: 100,
+		Body:          ioutil.NopCloser(failingReader{}),
+		Request: &http.Request{
+			Method: http.MethodHead,
+		},
+	}
+
+	resp := NewResponse(reporter, httpResp)
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "", resp.Body().Raw())
+
+	resp.Body().Empty()
+	resp.chain.assertOK(t)
+
+	resp.NoContent()
+	resp.chain.assertOK(t)
+
+	resp.Header("Content-Length").Equal("100")
+	resp.chain.assertOK(t)
+}
+
 func TestResponseNoContentEmpty(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -481,17 +1099,87 @@ func TestResponseContentTypeInvalid(t *testing.T) {
 	resp1.chain.assertFailed(t)
 	resp1.chain.reset()
 
-	resp1.ContentType("", "")
-	resp1.chain.assertFailed(t)
-	resp1.chain.reset()
+	resp1.ContentType("", "")
+	resp1.chain.assertFailed(t)
+	resp1.chain.reset()
+
+	resp2.ContentType("")
+	resp2.chain.assertFailed(t)
+	resp2.chain.reset()
+
+	resp2.ContentType("", "")
+	resp2.chain.assertFailed(t)
+	resp2.chain.reset()
+}
+
+func TestResponseBodySize(t *testing.T) {
+	payload := strings.Repeat("abc", 100)
+
+	deflated := &bytes.Buffer{}
+	zw := zlib.NewWriter(deflated)
+	_, _ = zw.Write([]byte(payload))
+	_ = zw.Close()
+
+	newResp := func(
+		reporter Reporter, header http.Header, body []byte, uncompressed bool,
+	) *Response {
+		return NewResponse(reporter, &http.Response{
+			Header:       header,
+			Body:         ioutil.NopCloser(bytes.NewReader(body)),
+			Uncompressed: uncompressed,
+		})
+	}
+
+	t.Run("deflate", func(t *testing.T) {
+		resp := newResp(newMockReporter(t), http.Header{
+			"Content-Encoding": {"deflate"},
+		}, deflated.Bytes(), false)
+
+		resp.BodySize().Equal(deflated.Len()).chain.assertOK(t)
+		resp.UncompressedBodySize().Equal(len(payload)).chain.assertOK(t)
+	})
+
+	t.Run("no encoding", func(t *testing.T) {
+		resp := newResp(newMockReporter(t), http.Header{}, []byte(payload), false)
+
+		resp.BodySize().Equal(len(payload)).chain.assertOK(t)
+		resp.UncompressedBodySize().Equal(len(payload)).chain.assertOK(t)
+	})
+
+	t.Run("uncompressed", func(t *testing.T) {
+		resp := newResp(newMockReporter(t), http.Header{}, []byte(payload), true)
+
+		resp.BodySize().chain.assertFailed(t)
+		resp.UncompressedBodySize().Equal(len(payload)).chain.assertOK(t)
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		resp := newResp(newMockReporter(t), http.Header{
+			"Content-Encoding": {"br"},
+		}, []byte(payload), false)
+
+		resp.BodySize().chain.assertOK(t)
+		resp.UncompressedBodySize().chain.assertFailed(t)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		resp := newResp(newMockReporter(t), http.Header{
+			"Content-Encoding": {"gzip"},
+		}, []byte(payload), false)
 
-	resp2.ContentType("")
-	resp2.chain.assertFailed(t)
-	resp2.chain.reset()
+		resp.UncompressedBodySize().chain.assertFailed(t)
+	})
 
-	resp2.ContentType("", "")
-	resp2.chain.assertFailed(t)
-	resp2.chain.reset()
+	t.Run("discarded", func(t *testing.T) {
+		resp := makeResponse(responseOpts{
+			chain:     makeChain(newMockReporter(t)),
+			response:  &http.Response{Header: http.Header{}},
+			discarded: true,
+		})
+
+		resp.BodySize().chain.assertFailed(t)
+		resp.UncompressedBodySize().chain.assertFailed(t)
+	})
 }
 
 func TestResponseContentEncoding(t *testing.T) {
@@ -675,6 +1363,141 @@ func TestResponseFormBadType(t *testing.T) {
 	assert.True(t, resp.Form().Raw() == nil)
 }
 
+func TestResponseJSONStrict(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Content-Type": {"application/json; charset=utf-8"},
+	}
+
+	body := `{"id": 123, "name": "john", "items": [{"n": 1}, {"n": 2}]}`
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.JSONStrict(map[string]interface{}{
+		"id":    Ignore,
+		"name":  "john",
+		"items": []interface{}{map[string]interface{}{"n": 1}, Ignore},
+	})
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.JSONStrict(map[string]interface{}{
+		"id":   Ignore,
+		"name": "john",
+	})
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	resp.JSONStrict(map[string]interface{}{
+		"id":    Ignore,
+		"name":  "john",
+		"items": []interface{}{map[string]interface{}{}, Ignore},
+	})
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	resp.JSONStrict(map[string]interface{}{
+		"id":    Ignore,
+		"name":  "john",
+		"items": Ignore,
+	}, ContentOpts{MediaType: "text/plain"})
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
+func TestResponseIsJSON(t *testing.T) {
+	newResp := func(reporter Reporter, body string) *Response {
+		return NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"text/plain"},
+			},
+			Body: ioutil.NopCloser(bytes.NewBufferString(body)),
+		})
+	}
+
+	reporter := newMockReporter(t)
+
+	for _, body := range []string{
+		`{"key": "value"}`,
+		`[1, 2, 3]`,
+		`"string"`,
+		`null`,
+	} {
+		newResp(reporter, body).IsJSON().chain.assertOK(t)
+	}
+
+	for _, body := range []string{
+		``,
+		`{"key": "value"`,
+		`{"key": value}`,
+		`[1, 2, 3]]`,
+	} {
+		newResp(reporter, body).IsJSON().chain.assertFailed(t)
+	}
+
+	body := `{"items": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10], "key": value, "other": 1}`
+	newResp(reporter, body).IsJSON().chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, "syntax error at offset 51")
+	assert.Contains(t, reporter.message, `10], \"key\": value`)
+}
+
+func TestResponseIsJSONLarge(t *testing.T) {
+	const size = 4 << 20
+
+	var buf bytes.Buffer
+	buf.WriteString(`[`)
+	for i := 0; buf.Len() < size; i++ {
+		if i != 0 {
+			buf.WriteString(`,`)
+		}
+		buf.WriteString(`{"id":`)
+		buf.WriteString(strconv.Itoa(i))
+		buf.WriteString(`,"name":"item","tags":["a","b","c"]}`)
+	}
+	buf.WriteString(`]`)
+
+	resp := NewResponse(newMockReporter(t), &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: ioutil.NopCloser(&buf),
+	})
+
+	measure := func(fn func()) uint64 {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		fn()
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+
+	isJSONAlloc := measure(func() {
+		resp.IsJSON().chain.assertOK(t)
+	})
+
+	jsonAlloc := measure(func() {
+		resp.JSON().chain.assertOK(t)
+	})
+
+	t.Logf("allocated by IsJSON: %d bytes, by JSON: %d bytes", isJSONAlloc, jsonAlloc)
+
+	assert.True(t, isJSONAlloc < size/16)
+	assert.True(t, jsonAlloc > size)
+}
+
 func TestResponseJSON(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -789,6 +1612,108 @@ func TestResponseJSONCharsetBad(t *testing.T) {
 	assert.Equal(t, nil, resp.JSON().Raw())
 }
 
+func TestResponseJSONBOM(t *testing.T) {
+	newResp := func(reporter Reporter, body []byte, forbidBOM bool) *Response {
+		return makeResponse(responseOpts{
+			config: Config{Reporter: reporter, ForbidJSONBOM: forbidBOM},
+			chain:  makeChain(reporter),
+			response: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			},
+		})
+	}
+
+	utf16le := func(s string) []byte {
+		var b []byte
+		for _, c := range s {
+			b = append(b, byte(c), 0)
+		}
+		return b
+	}
+
+	utf16be := func(s string) []byte {
+		var b []byte
+		for _, c := range s {
+			b = append(b, 0, byte(c))
+		}
+		return b
+	}
+
+	body := `{"key": "value"}`
+
+	t.Run("utf-8 bom", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, append([]byte("\xEF\xBB\xBF"), body...), false)
+
+		resp.IsJSON()
+		resp.chain.assertOK(t)
+
+		resp.JSON().Object().ValueEqual("key", "value")
+		resp.chain.assertOK(t)
+	})
+
+	t.Run("utf-8 bom forbidden", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, append([]byte("\xEF\xBB\xBF"), body...), true)
+
+		resp.JSON()
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "UTF-8 byte order mark")
+		resp.chain.reset()
+
+		resp.IsJSON()
+		resp.chain.assertFailed(t)
+
+		resp = newResp(newMockReporter(t), []byte(body), true)
+
+		resp.JSON().Object().ValueEqual("key", "value")
+		resp.chain.assertOK(t)
+	})
+
+	cases := []struct {
+		name     string
+		body     []byte
+		encoding string
+	}{
+		{"utf-16le bom", append([]byte{0xFF, 0xFE}, utf16le(body)...), "UTF-16LE"},
+		{"utf-16be bom", append([]byte{0xFE, 0xFF}, utf16be(body)...), "UTF-16BE"},
+		{"utf-16le", utf16le(body), "UTF-16LE"},
+		{"utf-16be", utf16be(body), "UTF-16BE"},
+		{"utf-32le bom", []byte{0xFF, 0xFE, 0, 0, '{', 0, 0, 0, '}', 0, 0, 0}, "UTF-32LE"},
+		{"utf-32be", []byte{0, 0, 0, '{', 0, 0, 0, '}'}, "UTF-32BE"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			resp := newResp(reporter, tc.body, false)
+
+			resp.JSON()
+			resp.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, tc.encoding+" encoded JSON")
+			resp.chain.reset()
+
+			resp.IsJSON()
+			resp.chain.assertFailed(t)
+			assert.Contains(t, reporter.message, tc.encoding+" encoded JSON")
+		})
+	}
+
+	t.Run("short", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, []byte("1"), false)
+
+		resp.JSON().Number().Equal(1)
+		resp.chain.assertOK(t)
+	})
+}
+
 func TestResponseJSONP(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -920,6 +1845,207 @@ func TestResponseJSONPCharsetBad(t *testing.T) {
 	assert.Equal(t, nil, resp.JSONP("foo").Raw())
 }
 
+func TestResponseMsgPack(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Content-Type": {"application/msgpack"},
+	}
+
+	body, _ := msgpack.Marshal(map[string]interface{}{
+		"name":  "john",
+		"age":   int8(42),
+		"big":   uint64(1 << 40),
+		"items": []interface{}{int16(1), "two", nil, true},
+	})
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.MsgPack()
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	assert.Equal(t, map[string]interface{}{
+		"name":  "john",
+		"age":   42.0,
+		"big":   float64(1 << 40),
+		"items": []interface{}{1.0, "two", nil, true},
+	}, resp.MsgPack().Object().Raw())
+
+	resp.MsgPack().Object().Value("items").Array().Element(1).String().Equal("two")
+	resp.chain.assertOK(t)
+
+	var target struct {
+		Name string `msgpack:"name"`
+		Age  int    `msgpack:"age"`
+	}
+
+	resp.MsgPack(&target).Object().ContainsKey("Name")
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "john", target.Name)
+	assert.Equal(t, 42, target.Age)
+
+	resp.MsgPack(&target, &target)
+	resp.chain.assertFailed(t)
+}
+
+func TestResponseMsgPackBadBody(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Content-Type": {"application/msgpack"},
+	}
+
+	body, _ := msgpack.Marshal([]interface{}{"foo", "bar"})
+	body = body[:len(body)-2]
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	assert.True(t, resp.MsgPack().Raw() == nil)
+	resp.chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, "at offset 7")
+}
+
+func TestResponseMsgPackContentType(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Content-Type": {"application/json"},
+	}
+
+	body, _ := msgpack.Marshal("foo")
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	assert.True(t, resp.MsgPack().Raw() == nil)
+	resp.chain.assertFailed(t)
+}
+
+func TestResponseYAML(t *testing.T) {
+	newResp := func(reporter Reporter, contentType, body string) *Response {
+		return NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		})
+	}
+
+	t.Run("generic", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, "application/yaml", `
+defaults: &defaults
+  timeout: 30
+  retries: 3
+service:
+  <<: *defaults
+  name: john
+  tags: [a, b]
+  owner: ~
+`)
+
+		assert.Equal(t, map[string]interface{}{
+			"defaults": map[string]interface{}{
+				"timeout": 30.0,
+				"retries": 3.0,
+			},
+			"service": map[string]interface{}{
+				"timeout": 30.0,
+				"retries": 3.0,
+				"name":    "john",
+				"tags":    []interface{}{"a", "b"},
+				"owner":   nil,
+			},
+		}, resp.YAML().Object().Raw())
+		resp.chain.assertOK(t)
+
+		resp.YAML().Path("$.service.timeout").Number().Equal(30)
+		resp.chain.assertOK(t)
+	})
+
+	t.Run("target", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, "application/yaml", "name: john\nage: 42\n")
+
+		var target struct {
+			Name string `yaml:"name"`
+			Age  int    `yaml:"age"`
+		}
+
+		resp.YAML(&target).Object().ValueEqual("Name", "john")
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "john", target.Name)
+		assert.Equal(t, 42, target.Age)
+	})
+
+	t.Run("non-string key", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, "application/yaml", "items:\n  1: one\n")
+
+		assert.True(t, resp.YAML().Raw() == nil)
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "string keys")
+		assert.Contains(t, reporter.message, `"items"`)
+	})
+
+	t.Run("multiple documents", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, "application/yaml", "a: 1\n---\nb: 2\n")
+
+		resp.YAMLDocuments().Equal([]interface{}{
+			map[string]interface{}{"a": 1},
+			map[string]interface{}{"b": 2},
+		})
+		resp.chain.assertOK(t)
+
+		assert.True(t, resp.YAML().Raw() == nil)
+		resp.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "got 2 documents")
+	})
+
+	t.Run("bad body", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, "application/yaml", "a: [1, 2\n")
+
+		assert.True(t, resp.YAML().Raw() == nil)
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := newResp(reporter, "application/json", "a: 1\n")
+
+		assert.True(t, resp.YAML().Raw() == nil)
+		resp.chain.assertFailed(t)
+	})
+}
+
 func TestResponseContentOpts(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -1065,3 +2191,116 @@ func TestResponseContentOpts(t *testing.T) {
 			})
 	})
 }
+
+func TestResponseFailurePath(t *testing.T) {
+	body := `{"items": ["a", "b", {"name": "c"}], "count": 3}`
+
+	newResp := func(reporter Reporter) *Response {
+		return NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"application/json"},
+				"Set-Cookie":   {"session=abc"},
+			},
+			Body: ioutil.NopCloser(bytes.NewBufferString(body)),
+		})
+	}
+
+	cases := []struct {
+		name string
+		fn   func(resp *Response)
+		path string
+	}{
+		{
+			name: "element",
+			fn: func(resp *Response) {
+				resp.JSON().Object().Value("items").Array().Element(2).
+					Object().Value("name").String().Equal("x")
+			},
+			path: "JSON.items[2].name",
+		},
+		{
+			name: "negative element",
+			fn: func(resp *Response) {
+				resp.JSON().Object().Value("items").Array().Element(-3).
+					String().Equal("x")
+			},
+			path: "JSON.items[0]",
+		},
+		{
+			name: "last",
+			fn: func(resp *Response) {
+				resp.JSON().Object().Value("items").Array().Last().Null()
+			},
+			path: "JSON.items[2]",
+		},
+		{
+			name: "iter",
+			fn: func(resp *Response) {
+				for _, v := range resp.JSON().Object().Value("items").Array().Iter() {
+					v.String()
+				}
+			},
+			path: "JSON.items[2]",
+		},
+		{
+			name: "missing key",
+			fn: func(resp *Response) {
+				resp.JSON().Object().Value("missing")
+			},
+			path: "JSON",
+		},
+		{
+			name: "header",
+			fn: func(resp *Response) {
+				resp.Header("content-type").Equal("text/plain")
+			},
+			path: `Header["Content-Type"]`,
+		},
+		{
+			name: "cookie",
+			fn: func(resp *Response) {
+				resp.Cookie("session").Value().Equal("xyz")
+			},
+			path: `Cookie["session"].Value`,
+		},
+		{
+			name: "body",
+			fn: func(resp *Response) {
+				resp.Body().Empty()
+			},
+			path: "Body",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			tc.fn(newResp(reporter))
+
+			assert.True(t, reporter.reported)
+			assert.True(t,
+				strings.HasSuffix(reporter.message, "\n\npath:\n "+tc.path),
+				"message %q should end with path %q", reporter.message, tc.path)
+		})
+	}
+
+	t.Run("response", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		newResp(reporter).Status(http.StatusTeapot)
+
+		assert.True(t, reporter.reported)
+		assert.NotContains(t, reporter.message, "path:")
+	})
+
+	t.Run("standalone", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		NewArray(reporter, []interface{}{"a"}).Element(0).String().Equal("b")
+
+		assert.True(t, reporter.reported)
+		assert.Contains(t, reporter.message, "path:\n [0]")
+	})
+}
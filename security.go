@@ -0,0 +1,305 @@
+package httpexpect
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityHeaderOpts defines parameters for Response.HasSecurityHeaders.
+type SecurityHeaderOpts struct {
+	// Disable check of "X-Content-Type-Options" header.
+	SkipContentTypeOptions bool
+
+	// Disable check of "Content-Security-Policy" header.
+	SkipContentSecurityPolicy bool
+
+	// Disable check of "Strict-Transport-Security" header.
+	SkipStrictTransportSecurity bool
+
+	// Disable check of "X-Frame-Options" header and "frame-ancestors"
+	// directive of "Content-Security-Policy" header.
+	SkipFrameOptions bool
+
+	// Disable check of "Referrer-Policy" header.
+	SkipReferrerPolicy bool
+
+	// Minimum max-age of "Strict-Transport-Security" header.
+	// If zero, DefaultHSTSMinMaxAge is used.
+	HSTSMinMaxAge time.Duration
+
+	// Require includeSubDomains directive in "Strict-Transport-Security"
+	// header.
+	HSTSIncludeSubDomains bool
+}
+
+// DefaultHSTSMinMaxAge is the default value of SecurityHeaderOpts.HSTSMinMaxAge.
+const DefaultHSTSMinMaxAge = 365 * 24 * time.Hour
+
+// HasSecurityHeaders succeeds if response contains the common set of
+// security headers:
+//  - "X-Content-Type-Options" equal to "nosniff"
+//  - non-empty "Content-Security-Policy"
+//  - valid "Strict-Transport-Security" with max-age not less than
+//    SecurityHeaderOpts.HSTSMinMaxAge
+//  - "X-Frame-Options" equal to "DENY" or "SAMEORIGIN", or "frame-ancestors"
+//    directive in "Content-Security-Policy"
+//  - "Referrer-Policy" with known policy, other than "unsafe-url"
+//
+// Every check that doesn't pass is reported as a separate failure, naming
+// the header. Individual checks may be disabled using opts.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.HasSecurityHeaders()
+//  resp.HasSecurityHeaders(httpexpect.SecurityHeaderOpts{
+//      SkipContentSecurityPolicy: true,
+//      HSTSMinMaxAge:             180 * 24 * time.Hour,
+//  })
+func (r *Response) HasSecurityHeaders(opts ...SecurityHeaderOpts) *Response {
+	defer r.chain.leave(r.chain.failed())
+	if r.chain.failed() {
+		return r
+	}
+
+	if len(opts) > 1 {
+		r.chain.failUsage("\nunexpected multiple opts arguments in HasSecurityHeaders")
+		return r
+	}
+
+	var opt SecurityHeaderOpts
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+	if opt.HSTSMinMaxAge == 0 {
+		opt.HSTSMinMaxAge = DefaultHSTSMinMaxAge
+	}
+
+	header := r.resp.Header
+
+	var failures []string
+
+	if !opt.SkipContentTypeOptions {
+		failures = appendFailure(failures, checkContentTypeOptions(header))
+	}
+	if !opt.SkipContentSecurityPolicy {
+		failures = appendFailure(failures, checkContentSecurityPolicy(header))
+	}
+	if !opt.SkipStrictTransportSecurity {
+		failures = appendFailure(failures, checkStrictTransportSecurity(header, opt))
+	}
+	if !opt.SkipFrameOptions {
+		failures = appendFailure(failures, checkFrameOptions(header))
+	}
+	if !opt.SkipReferrerPolicy {
+		failures = appendFailure(failures, checkReferrerPolicy(header))
+	}
+
+	for i, f := range failures {
+		chain := &r.chain
+		if i != len(failures)-1 {
+			// report every failure separately, using a copy of the chain
+			c := r.chain
+			chain = &c
+		}
+		chain.fail("%s", f)
+	}
+
+	return r
+}
+
+func appendFailure(failures []string, failure string) []string {
+	if failure == "" {
+		return failures
+	}
+	return append(failures, failure)
+}
+
+func checkContentTypeOptions(header http.Header) string {
+	const name = "X-Content-Type-Options"
+	values := header.Values(name)
+	if len(values) == 0 {
+		return fmt.Sprintf("\nexpected security header %q, but it is missing", name)
+	}
+	if len(values) != 1 || !strings.EqualFold(strings.TrimSpace(values[0]), "nosniff") {
+		return fmt.Sprintf("\nexpected security header %q equal to:\n %q\n\nbut got:\n %q",
+			name, "nosniff", values)
+	}
+	return ""
+}
+
+func checkContentSecurityPolicy(header http.Header) string {
+	const name = "Content-Security-Policy"
+	if strings.TrimSpace(strings.Join(header.Values(name), "")) == "" {
+		return fmt.Sprintf("\nexpected non-empty security header %q,"+
+			" but it is missing", name)
+	}
+	return ""
+}
+
+func checkStrictTransportSecurity(header http.Header, opt SecurityHeaderOpts) string {
+	const name = "Strict-Transport-Security"
+	values := header.Values(name)
+	if len(values) == 0 {
+		return fmt.Sprintf("\nexpected security header %q, but it is missing", name)
+	}
+	if len(values) != 1 {
+		return fmt.Sprintf("\nexpected single security header %q, but got:\n %q",
+			name, values)
+	}
+	hsts, err := parseHSTS(values[0])
+	if err != nil {
+		return fmt.Sprintf("\nexpected valid security header %q:\n %q\n\nbut got error:\n %s",
+			name, values[0], err.Error())
+	}
+	if hsts.maxAge < opt.HSTSMinMaxAge {
+		return fmt.Sprintf("\nexpected security header %q with max-age >= %s"+
+			" (%d seconds)\n\nbut got max-age %s (%d seconds):\n %q",
+			name, opt.HSTSMinMaxAge, int64(opt.HSTSMinMaxAge/time.Second),
+			hsts.maxAge, int64(hsts.maxAge/time.Second), values[0])
+	}
+	if opt.HSTSIncludeSubDomains && !hsts.includeSubDomains {
+		return fmt.Sprintf("\nexpected security header %q with includeSubDomains"+
+			" directive, but got:\n %q", name, values[0])
+	}
+	return ""
+}
+
+func checkFrameOptions(header http.Header) string {
+	const name = "X-Frame-Options"
+	for _, policy := range header.Values("Content-Security-Policy") {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) != 0 && strings.EqualFold(fields[0], "frame-ancestors") {
+				return ""
+			}
+		}
+	}
+	values := header.Values(name)
+	if len(values) == 0 {
+		return fmt.Sprintf("\nexpected security header %q or %q directive in %q,"+
+			" but both are missing", name, "frame-ancestors", "Content-Security-Policy")
+	}
+	if len(values) == 1 {
+		v := strings.TrimSpace(values[0])
+		if strings.EqualFold(v, "DENY") || strings.EqualFold(v, "SAMEORIGIN") {
+			return ""
+		}
+	}
+	return fmt.Sprintf(
+		"\nexpected security header %q equal to one of:\n %q\n\nbut got:\n %q",
+		name, []string{"DENY", "SAMEORIGIN"}, values)
+}
+
+var referrerPolicies = []string{
+	"no-referrer",
+	"no-referrer-when-downgrade",
+	"origin",
+	"origin-when-cross-origin",
+	"same-origin",
+	"strict-origin",
+	"strict-origin-when-cross-origin",
+	"unsafe-url",
+}
+
+func checkReferrerPolicy(header http.Header) string {
+	const name = "Referrer-Policy"
+	values := header.Values(name)
+	if len(values) == 0 {
+		return fmt.Sprintf("\nexpected security header %q, but it is missing", name)
+	}
+	// browsers use the last recognized policy, which allows fallbacks
+	// for policies not supported by older browsers
+	policy := ""
+	for _, v := range values {
+		for _, token := range strings.Split(v, ",") {
+			token = strings.ToLower(strings.TrimSpace(token))
+			if containsString(referrerPolicies, token) {
+				policy = token
+			}
+		}
+	}
+	switch policy {
+	case "":
+		return fmt.Sprintf("\nexpected security header %q with known policy,"+
+			" but got:\n %q", name, values)
+	case "unsafe-url":
+		return fmt.Sprintf("\nexpected security header %q with policy other than"+
+			" %q, but got:\n %q", name, policy, values)
+	}
+	return ""
+}
+
+type hstsHeader struct {
+	maxAge            time.Duration
+	includeSubDomains bool
+	preload           bool
+}
+
+// parseHSTS parses "Strict-Transport-Security" header as defined in
+// RFC 6797: directive names are case-insensitive, max-age is required,
+// and every directive may appear at most once.
+func parseHSTS(value string) (hstsHeader, error) {
+	var (
+		hsts      hstsHeader
+		seen      = map[string]bool{}
+		hasMaxAge bool
+	)
+	for _, directive := range strings.Split(value, ";") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, arg, hasArg := directive, "", false
+		if pos := strings.IndexByte(directive, '='); pos >= 0 {
+			name = strings.TrimSpace(directive[:pos])
+			arg = strings.TrimSpace(directive[pos+1:])
+			hasArg = true
+		}
+		name = strings.ToLower(name)
+		if !isToken(name) {
+			return hsts, fmt.Errorf("invalid directive name %q", name)
+		}
+		if seen[name] {
+			return hsts, fmt.Errorf("duplicate directive %q", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "max-age":
+			if !hasArg {
+				return hsts, fmt.Errorf("directive %q requires value", name)
+			}
+			if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+				arg = arg[1 : len(arg)-1]
+			}
+			seconds, err := strconv.ParseUint(arg, 10, 63)
+			if err != nil {
+				return hsts, fmt.Errorf("invalid %q value %q", name, arg)
+			}
+			if seconds > uint64(maxDurationSeconds) {
+				seconds = uint64(maxDurationSeconds)
+			}
+			hsts.maxAge = time.Duration(seconds) * time.Second
+			hasMaxAge = true
+
+		case "includesubdomains", "preload":
+			if hasArg {
+				return hsts, fmt.Errorf("unexpected value for directive %q", name)
+			}
+			if name == "preload" {
+				hsts.preload = true
+			} else {
+				hsts.includeSubDomains = true
+			}
+		}
+	}
+	if !hasMaxAge {
+		return hsts, fmt.Errorf("missing required %q directive", "max-age")
+	}
+	return hsts, nil
+}
+
+const maxDurationSeconds = int64(1<<63-1) / int64(time.Second)
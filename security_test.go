@@ -0,0 +1,181 @@
+package httpexpect
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func secureHeader() http.Header {
+	return http.Header{
+		"X-Content-Type-Options":    {"nosniff"},
+		"Content-Security-Policy":   {"default-src 'self'"},
+		"Strict-Transport-Security": {"max-age=31536000; includeSubDomains"},
+		"X-Frame-Options":           {"DENY"},
+		"Referrer-Policy":           {"strict-origin-when-cross-origin"},
+	}
+}
+
+func TestResponseSecurityHeaders(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	resp := NewResponse(reporter, &http.Response{Header: secureHeader()})
+
+	resp.HasSecurityHeaders()
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.HasSecurityHeaders(SecurityHeaderOpts{HSTSIncludeSubDomains: true})
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.HasSecurityHeaders(SecurityHeaderOpts{HSTSMinMaxAge: 2 * 365 * 24 * time.Hour})
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	resp.HasSecurityHeaders(SecurityHeaderOpts{}, SecurityHeaderOpts{})
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
+func TestResponseSecurityHeadersMissing(t *testing.T) {
+	headers := []string{
+		"X-Content-Type-Options",
+		"Content-Security-Policy",
+		"Strict-Transport-Security",
+		"X-Frame-Options",
+		"Referrer-Policy",
+	}
+
+	for _, name := range headers {
+		t.Run(name, func(t *testing.T) {
+			header := secureHeader()
+			header.Del(name)
+
+			reporter := &mockCollectingReporter{}
+
+			resp := NewResponse(reporter, &http.Response{Header: header})
+			resp.HasSecurityHeaders()
+			resp.chain.assertFailed(t)
+
+			require.Equal(t, 1, len(reporter.messages))
+			assert.Contains(t, reporter.messages[0], "\""+name+"\"")
+		})
+	}
+
+	t.Run("all", func(t *testing.T) {
+		reporter := &mockCollectingReporter{}
+
+		resp := NewResponse(reporter, &http.Response{Header: http.Header{}})
+		resp.HasSecurityHeaders()
+		resp.chain.assertFailed(t)
+
+		require.Equal(t, len(headers), len(reporter.messages))
+		for i, name := range headers {
+			assert.Contains(t, reporter.messages[i], "\""+name+"\"")
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		resp := NewResponse(reporter, &http.Response{Header: http.Header{}})
+		resp.HasSecurityHeaders(SecurityHeaderOpts{
+			SkipContentTypeOptions:      true,
+			SkipContentSecurityPolicy:   true,
+			SkipStrictTransportSecurity: true,
+			SkipFrameOptions:            true,
+			SkipReferrerPolicy:          true,
+		})
+		resp.chain.assertOK(t)
+	})
+}
+
+func TestResponseSecurityHeadersValues(t *testing.T) {
+	cases := []struct {
+		name   string
+		key    string
+		values []string
+		ok     bool
+	}{
+		{"nosniff case", "X-Content-Type-Options", []string{"NoSniff"}, true},
+		{"nosniff invalid", "X-Content-Type-Options", []string{"sniff"}, false},
+		{"nosniff duplicate", "X-Content-Type-Options",
+			[]string{"nosniff", "nosniff"}, false},
+		{"csp empty", "Content-Security-Policy", []string{" "}, false},
+		{"frame sameorigin", "X-Frame-Options", []string{"sameorigin"}, true},
+		{"frame allow-from", "X-Frame-Options",
+			[]string{"ALLOW-FROM https://example.com"}, false},
+		{"referrer fallback", "Referrer-Policy",
+			[]string{"no-referrer, strict-origin-when-cross-origin"}, true},
+		{"referrer unknown fallback", "Referrer-Policy",
+			[]string{"same-origin, some-future-policy"}, true},
+		{"referrer unknown", "Referrer-Policy", []string{"whatever"}, false},
+		{"referrer unsafe", "Referrer-Policy", []string{"unsafe-url"}, false},
+		{"hsts quoted", "Strict-Transport-Security",
+			[]string{`max-age="31536000"`}, true},
+		{"hsts case", "Strict-Transport-Security",
+			[]string{"Max-Age=31536000; INCLUDESUBDOMAINS; preload"}, true},
+		{"hsts short", "Strict-Transport-Security", []string{"max-age=3600"}, false},
+		{"hsts missing max-age", "Strict-Transport-Security",
+			[]string{"includeSubDomains"}, false},
+		{"hsts duplicate", "Strict-Transport-Security",
+			[]string{"max-age=31536000; max-age=31536000"}, false},
+		{"hsts invalid", "Strict-Transport-Security",
+			[]string{"max-age=-1"}, false},
+		{"hsts multiple headers", "Strict-Transport-Security",
+			[]string{"max-age=31536000", "max-age=31536000"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := secureHeader()
+			header[tc.key] = tc.values
+
+			resp := NewResponse(newMockReporter(t), &http.Response{Header: header})
+			resp.HasSecurityHeaders()
+
+			if tc.ok {
+				resp.chain.assertOK(t)
+			} else {
+				resp.chain.assertFailed(t)
+			}
+		})
+	}
+
+	t.Run("frame ancestors", func(t *testing.T) {
+		header := secureHeader()
+		header.Del("X-Frame-Options")
+		header.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+
+		resp := NewResponse(newMockReporter(t), &http.Response{Header: header})
+		resp.HasSecurityHeaders()
+		resp.chain.assertOK(t)
+	})
+}
+
+func TestParseHSTS(t *testing.T) {
+	hsts, err := parseHSTS("max-age=600; includeSubDomains; preload")
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Minute, hsts.maxAge)
+	assert.True(t, hsts.includeSubDomains)
+	assert.True(t, hsts.preload)
+
+	hsts, err = parseHSTS("max-age=99999999999999999999")
+	assert.Error(t, err)
+
+	hsts, err = parseHSTS("max-age=9999999999999; unknown-ext=1")
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(maxDurationSeconds)*time.Second, hsts.maxAge)
+
+	for _, s := range []string{"", "max-age", "max-age=", "preload=1; max-age=1",
+		"max age=1", strings.Repeat(";", 3)} {
+		_, err := parseHSTS(s)
+		assert.Error(t, err, s)
+	}
+}
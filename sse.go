@@ -0,0 +1,364 @@
+package httpexpect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSE provides methods to read and inspect events from Server-Sent Events
+// (text/event-stream) response.
+//
+// Response body is read incrementally, event by event, as Next or
+// ExpectEvent are called. It's recommended to call Close when SSE usage
+// is over.
+type SSE struct {
+	chain    chain
+	body     io.ReadCloser
+	events   chan sseRead
+	done     chan struct{}
+	isClosed bool
+	isEOF    bool
+}
+
+type sseRead struct {
+	event *sseEvent
+	err   error
+}
+
+type sseEvent struct {
+	event string
+	data  string
+	id    string
+	retry *time.Duration
+}
+
+func makeSSE(chain chain, body io.ReadCloser) *SSE {
+	return &SSE{
+		chain: chain,
+		body:  body,
+	}
+}
+
+// Next reads next event from the stream and returns a new SSEEvent object
+// to inspect it.
+//
+// If no event is received within given timeout, or stream is closed,
+// failure is reported. If timeout is zero, Next waits forever.
+//
+// Example:
+//  sse := resp.SSE()
+//  defer sse.Close()
+//  sse.Next(time.Second).Data().Equal("hello")
+func (s *SSE) Next(timeout time.Duration) *SSEEvent {
+	defer s.chain.leave(s.chain.failed())
+	if s.checkUnusable("Next") {
+		return &SSEEvent{chain: s.chain}
+	}
+	ev, ok := s.waitEvent(timeout)
+	if !ok {
+		return &SSEEvent{chain: s.chain}
+	}
+	return &SSEEvent{s.chain, *ev}
+}
+
+// ExpectEvent reads events from the stream until event with given type is
+// received, and returns a new SSEEvent object to inspect it. Events of other
+// types are skipped.
+//
+// Timeout limits the total time spent in ExpectEvent. If no matching event
+// is received within timeout, or stream is closed, failure is reported.
+//
+// Example:
+//  sse := resp.SSE()
+//  defer sse.Close()
+//  sse.ExpectEvent("update", time.Second).JSONData().Object().
+//      ValueEqual("status", "done")
+func (s *SSE) ExpectEvent(name string, timeout time.Duration) *SSEEvent {
+	defer s.chain.leave(s.chain.failed())
+	if s.checkUnusable("ExpectEvent") {
+		return &SSEEvent{chain: s.chain}
+	}
+	var deadline time.Time
+	if timeout != noDuration {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		remaining := noDuration
+		if !deadline.IsZero() {
+			remaining = time.Until(deadline)
+			if remaining <= 0 {
+				s.chain.fail(
+					"\nexpected SSE event of type %q within %s, but got none",
+					name, timeout)
+				return &SSEEvent{chain: s.chain}
+			}
+		}
+		ev, ok := s.waitEvent(remaining)
+		if !ok {
+			return &SSEEvent{chain: s.chain}
+		}
+		if ev.event == name {
+			return &SSEEvent{s.chain, *ev}
+		}
+	}
+}
+
+// Close closes response body and stops reading events.
+//
+// It's okay to call this function multiple times.
+//
+// Example:
+//  sse := resp.SSE()
+//  defer sse.Close()
+func (s *SSE) Close() *SSE {
+	defer s.chain.leave(s.chain.failed())
+	if s.body == nil || s.isClosed {
+		return s
+	}
+	s.isClosed = true
+	if s.done != nil {
+		close(s.done)
+	}
+	if err := s.body.Close(); err != nil {
+		s.chain.fail("close error when closing SSE stream: " + err.Error())
+	}
+	return s
+}
+
+func (s *SSE) checkUnusable(where string) bool {
+	switch {
+	case s.chain.failed():
+		return true
+	case s.body == nil:
+		s.chain.fail("\nunexpected %s call for failed SSE stream", where)
+		return true
+	case s.isClosed:
+		s.chain.fail("\nunexpected %s call for closed SSE stream", where)
+		return true
+	case s.isEOF:
+		s.chain.fail("\nunexpected %s call for finished SSE stream", where)
+		return true
+	}
+	return false
+}
+
+func (s *SSE) waitEvent(timeout time.Duration) (*sseEvent, bool) {
+	if s.events == nil {
+		s.events = make(chan sseRead)
+		s.done = make(chan struct{})
+		go readSSE(s.body, s.events, s.done)
+	}
+	var timer <-chan time.Time
+	if timeout != noDuration {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+	select {
+	case rd := <-s.events:
+		if rd.err != nil {
+			s.isEOF = true
+			if rd.err == io.EOF {
+				s.chain.fail("\nexpected SSE event, but stream was closed by server")
+			} else {
+				s.chain.fail(
+					"\nexpected SSE event, but got failure: %s", rd.err.Error())
+			}
+			return nil, false
+		}
+		return rd.event, true
+	case <-timer:
+		s.chain.fail("\nexpected SSE event within %s, but got none", timeout)
+		return nil, false
+	}
+}
+
+func readSSE(body io.Reader, events chan<- sseRead, done <-chan struct{}) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, 1<<20)
+	scanner.Split(scanSSELines)
+
+	var (
+		lastID string
+		ev     sseEvent
+		data   strings.Builder
+	)
+
+	send := func(rd sseRead) bool {
+		select {
+		case events <- rd:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if data.Len() != 0 {
+				ev.data = strings.TrimSuffix(data.String(), "\n")
+				ev.id = lastID
+				if ev.event == "" {
+					ev.event = "message"
+				}
+				dispatched := ev
+				if !send(sseRead{event: &dispatched}) {
+					return
+				}
+			}
+			ev = sseEvent{}
+			data.Reset()
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "event":
+			ev.event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				d := time.Duration(ms) * time.Millisecond
+				ev.retry = &d
+			}
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	send(sseRead{err: err})
+}
+
+// scanSSELines is bufio.SplitFunc that splits input into lines terminated
+// by CRLF, LF, or CR, as required by SSE spec.
+func scanSSELines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		// need more data to decide whether CR is followed by LF
+		return 0, nil, nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
+
+// SSEEvent provides methods to inspect event received from Server-Sent
+// Events stream.
+type SSEEvent struct {
+	chain chain
+	value sseEvent
+}
+
+// Event returns a new String object that may be used to inspect event
+// type. If event has no "event" field, type is "message".
+//
+// Example:
+//  ev := sse.Next(time.Second)
+//  ev.Event().Equal("update")
+func (e *SSEEvent) Event() *String {
+	defer e.chain.leave(e.chain.failed())
+	return &String{e.chain.enter("Event"), e.value.event}
+}
+
+// Data returns a new String object that may be used to inspect event data.
+// Multiple "data" fields are joined with newline.
+//
+// Example:
+//  ev := sse.Next(time.Second)
+//  ev.Data().Equal("hello")
+func (e *SSEEvent) Data() *String {
+	defer e.chain.leave(e.chain.failed())
+	return &String{e.chain.enter("Data"), e.value.data}
+}
+
+// JSONData returns a new Value object that may be used to inspect event
+// data decoded as JSON.
+//
+// If data is not a valid JSON, failure is reported.
+//
+// Example:
+//  ev := sse.Next(time.Second)
+//  ev.JSONData().Object().ValueEqual("status", "done")
+func (e *SSEEvent) JSONData() *Value {
+	defer e.chain.leave(e.chain.failed())
+	chain := e.chain.enter("JSONData")
+	if chain.failed() {
+		return &Value{chain, nil}
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(e.value.data), &value); err != nil {
+		chain.fail("\nfailed to decode SSE event data as JSON:\n %q\n\nerror:\n %s",
+			e.value.data, err.Error())
+		return &Value{chain, nil}
+	}
+	return &Value{chain, value}
+}
+
+// ID returns a new String object that may be used to inspect last event
+// ID of the stream, as set by the most recent "id" field.
+//
+// Example:
+//  ev := sse.Next(time.Second)
+//  ev.ID().Equal("42")
+func (e *SSEEvent) ID() *String {
+	defer e.chain.leave(e.chain.failed())
+	return &String{e.chain.enter("ID"), e.value.id}
+}
+
+// Retry returns a new Duration object that may be used to inspect
+// reconnection time from "retry" field of event.
+//
+// The returned Duration is not set if event has no valid "retry" field.
+//
+// Example:
+//  ev := sse.Next(time.Second)
+//  ev.Retry().Equal(3 * time.Second)
+func (e *SSEEvent) Retry() *Duration {
+	defer e.chain.leave(e.chain.failed())
+	return &Duration{e.chain.enter("Retry"), e.value.retry}
+}
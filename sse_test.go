@@ -0,0 +1,162 @@
+package httpexpect
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSSEResponse(t *testing.T, body string) *Response {
+	return NewResponse(newMockReporter(t), &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"text/event-stream"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(body)),
+	})
+}
+
+func TestSSEParse(t *testing.T) {
+	resp := newSSEResponse(t, ""+
+		": comment\n"+
+		"data: first\n"+
+		"\n"+
+		"event: update\r\n"+
+		"id: 1\r\n"+
+		"retry: 3000\r\n"+
+		"data: line1\r\n"+
+		"data:line2\r\n"+
+		"\r\n"+
+		"event: empty\r"+
+		"\r"+
+		"data\n"+
+		"retry: bad\n"+
+		"\n"+
+		"data: {\"a\": 1}\n"+
+		"\n"+
+		"data: incomplete")
+
+	resp.Body().Empty()
+	resp.chain.assertOK(t)
+
+	sse := resp.SSE()
+	defer sse.Close()
+
+	ev := sse.Next(time.Second)
+	ev.Event().Equal("message")
+	ev.Data().Equal("first")
+	ev.ID().Empty()
+	ev.Retry().NotSet()
+	ev.chain.assertOK(t)
+
+	ev = sse.Next(time.Second)
+	ev.Event().Equal("update")
+	ev.Data().Equal("line1\nline2")
+	ev.ID().Equal("1")
+	ev.Retry().Equal(3 * time.Second)
+	ev.chain.assertOK(t)
+
+	ev = sse.Next(time.Second)
+	ev.Event().Equal("message")
+	ev.Data().Equal("")
+	ev.ID().Equal("1")
+	ev.Retry().NotSet()
+	ev.chain.assertOK(t)
+
+	ev = sse.Next(time.Second)
+	ev.JSONData().Object().ValueEqual("a", 1)
+	ev.chain.assertOK(t)
+
+	sse.chain.assertOK(t)
+
+	sse.Next(time.Second)
+	sse.chain.assertFailed(t)
+}
+
+func TestSSEExpectEvent(t *testing.T) {
+	resp := newSSEResponse(t, ""+
+		"event: a\ndata: 1\n\n"+
+		"event: b\ndata: 2\n\n"+
+		"event: a\ndata: 3\n\n")
+
+	sse := resp.SSE()
+	defer sse.Close()
+
+	sse.ExpectEvent("b", time.Second).Data().Equal("2")
+	sse.chain.assertOK(t)
+
+	sse.ExpectEvent("b", time.Second)
+	sse.chain.assertFailed(t)
+}
+
+func TestSSEJSONDataInvalid(t *testing.T) {
+	resp := newSSEResponse(t, "data: {bad\n\n")
+
+	sse := resp.SSE()
+	defer sse.Close()
+
+	ev := sse.Next(time.Second)
+	ev.chain.assertOK(t)
+
+	value := ev.JSONData()
+	value.chain.assertFailed(t)
+	ev.chain.assertOK(t)
+}
+
+func TestSSEContentType(t *testing.T) {
+	resp := NewResponse(newMockReporter(t), &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"text/plain"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString("data: x\n\n")),
+	})
+
+	resp.Body().Equal("data: x\n\n")
+
+	sse := resp.SSE()
+	sse.chain.assertFailed(t)
+
+	sse.Next(time.Second).chain.assertFailed(t)
+	sse.Close()
+}
+
+func TestSSEClosed(t *testing.T) {
+	resp := newSSEResponse(t, "data: x\n\n")
+
+	sse := resp.SSE()
+	sse.Close()
+	sse.Close()
+	sse.chain.assertOK(t)
+
+	sse.Next(time.Second)
+	sse.chain.assertFailed(t)
+}
+
+func TestSSEScanLines(t *testing.T) {
+	cases := []struct {
+		data    string
+		atEOF   bool
+		advance int
+		token   string
+	}{
+		{"abc\ndef", false, 4, "abc"},
+		{"abc\r\ndef", false, 5, "abc"},
+		{"abc\rdef", false, 4, "abc"},
+		{"abc\r", false, 0, ""},
+		{"abc\r", true, 4, "abc"},
+		{"abc", false, 0, ""},
+		{"abc", true, 3, "abc"},
+	}
+
+	for _, tc := range cases {
+		advance, token, err := scanSSELines([]byte(tc.data), tc.atEOF)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.advance, advance, "%q", tc.data)
+		assert.Equal(t, tc.token, string(token), "%q", tc.data)
+	}
+}
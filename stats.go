@@ -0,0 +1,259 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Stats is used to collect statistics about sent requests and reported
+// failures. It may be attached using Config.Stats.
+//
+// Methods may be called concurrently from multiple goroutines if Expect
+// is shared between parallel tests.
+//
+// You can use StatsCollector, or provide custom implementation.
+type Stats interface {
+	// OnRequest is called after request is sent and response is received,
+	// or after sending request failed.
+	OnRequest(RequestStats)
+
+	// OnAssertion is called when assertion on a request or its response
+	// succeeds or fails. Every call of a method of Response, Value and
+	// other objects returned by them counts as assertion, including getters
+	// like Response.Header, which fail if value is missing. Assertions
+	// invoked internally by other assertions are not reported, and neither
+	// are assertions skipped because of earlier failures of the same value.
+	OnAssertion(AssertionStats)
+}
+
+// RequestStats describes sent request. It doesn't include request and
+// response bodies, only their sizes.
+type RequestStats struct {
+	// Method and URL of request.
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	// Request ID, if Config.RequestIDFunc is set.
+	RequestID string `json:"request_id,omitempty"`
+
+	// Response status code, or zero if no response was received.
+	Status int `json:"status"`
+
+	// Round-trip time of request.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Size of request body (-1 if unknown) and response body.
+	RequestSize  int64 `json:"request_size"`
+	ResponseSize int64 `json:"response_size"`
+
+	// Failed is true if sending request or receiving response failed.
+	Failed bool `json:"failed"`
+
+	// Whether request was sent over reused connection. Nil if unknown,
+	// i.e. if Request.WithTimingBreakdown() was not used.
+	ConnectionReused *bool `json:"connection_reused,omitempty"`
+}
+
+// AssertionStats describes reported assertion.
+type AssertionStats struct {
+	// Failed is true if assertion has failed.
+	Failed bool
+
+	// Failure message, without call site, path and request ID.
+	// Empty if assertion succeeded.
+	Message string
+
+	// Path of checked value, e.g. "JSON.items[0]". May be empty.
+	Path string
+
+	// Request ID, if Config.RequestIDFunc is set.
+	RequestID string
+}
+
+// StatsCollector implements Stats. It counts requests and assertions and
+// remembers slowest requests, and can produce printable report at the
+// end of the test suite.
+//
+// StatsCollector is safe for concurrent use.
+//
+// Example:
+//  var stats = httpexpect.NewStatsCollector()
+//
+//  func TestMain(m *testing.M) {
+//      code := m.Run()
+//      fmt.Print(stats.Report())
+//      os.Exit(code)
+//  }
+//
+//  func TestSomething(t *testing.T) {
+//      e := httpexpect.WithConfig(httpexpect.Config{
+//          BaseURL:  "http://example.com",
+//          Reporter: httpexpect.NewAssertReporter(t),
+//          Stats:    stats,
+//      })
+//  }
+type StatsCollector struct {
+	mu sync.Mutex
+
+	maxSlowest int
+
+	requests       int
+	failedRequests int
+	assertions     int
+	failures       int
+	totalDuration  time.Duration
+	bytesSent      int64
+	bytesReceived  int64
+	reusedConns    int
+	newConns       int
+	slowest        []RequestStats
+}
+
+// NewStatsCollector returns a new StatsCollector. Report includes up to
+// maxSlowest slowest requests; if omitted, 10 is used.
+func NewStatsCollector(maxSlowest ...int) *StatsCollector {
+	n := 10
+	if len(maxSlowest) > 0 {
+		n = maxSlowest[0]
+	}
+	return &StatsCollector{maxSlowest: n}
+}
+
+// OnRequest implements Stats.OnRequest.
+func (c *StatsCollector) OnRequest(req RequestStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests++
+	if req.Failed {
+		c.failedRequests++
+	}
+	c.totalDuration += req.Duration
+	if req.RequestSize > 0 {
+		c.bytesSent += req.RequestSize
+	}
+	c.bytesReceived += req.ResponseSize
+	if req.ConnectionReused != nil {
+		if *req.ConnectionReused {
+			c.reusedConns++
+		} else {
+			c.newConns++
+		}
+	}
+
+	if c.maxSlowest <= 0 {
+		return
+	}
+	i := sort.Search(len(c.slowest), func(i int) bool {
+		return c.slowest[i].Duration < req.Duration
+	})
+	if i >= c.maxSlowest {
+		return
+	}
+	c.slowest = append(c.slowest, RequestStats{})
+	copy(c.slowest[i+1:], c.slowest[i:])
+	c.slowest[i] = req
+	if len(c.slowest) > c.maxSlowest {
+		c.slowest = c.slowest[:c.maxSlowest]
+	}
+}
+
+// OnAssertion implements Stats.OnAssertion.
+func (c *StatsCollector) OnAssertion(a AssertionStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.assertions++
+	if a.Failed {
+		c.failures++
+	}
+}
+
+// StatsSummary contains statistics accumulated by StatsCollector.
+//
+// ReusedConnections and NewConnections count only requests for which
+// connection reuse is known, see RequestStats.ConnectionReused.
+type StatsSummary struct {
+	Requests          int            `json:"requests"`
+	FailedRequests    int            `json:"failed_requests"`
+	Assertions        int            `json:"assertions"`
+	Failures          int            `json:"failures"`
+	TotalDuration     time.Duration  `json:"total_duration_ns"`
+	BytesSent         int64          `json:"bytes_sent"`
+	BytesReceived     int64          `json:"bytes_received"`
+	ReusedConnections int            `json:"reused_connections"`
+	NewConnections    int            `json:"new_connections"`
+	Slowest           []RequestStats `json:"slowest"`
+}
+
+// Summary returns a snapshot of accumulated statistics.
+func (c *StatsCollector) Summary() StatsSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return StatsSummary{
+		Requests:          c.requests,
+		FailedRequests:    c.failedRequests,
+		Assertions:        c.assertions,
+		Failures:          c.failures,
+		TotalDuration:     c.totalDuration,
+		BytesSent:         c.bytesSent,
+		BytesReceived:     c.bytesReceived,
+		ReusedConnections: c.reusedConns,
+		NewConnections:    c.newConns,
+		Slowest:           append([]RequestStats(nil), c.slowest...),
+	}
+}
+
+// Report returns printable table with accumulated statistics.
+//
+// Example:
+//  func TestMain(m *testing.M) {
+//      code := m.Run()
+//      fmt.Print(stats.Report())
+//      os.Exit(code)
+//  }
+func (c *StatsCollector) Report() string {
+	s := c.Summary()
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "httpexpect stats:\n")
+	fmt.Fprintf(w, "  requests:\t%d\n", s.Requests)
+	fmt.Fprintf(w, "  failed requests:\t%d\n", s.FailedRequests)
+	fmt.Fprintf(w, "  assertions:\t%d\n", s.Assertions)
+	fmt.Fprintf(w, "  failed assertions:\t%d\n", s.Failures)
+	fmt.Fprintf(w, "  total time:\t%s\n", s.TotalDuration)
+	fmt.Fprintf(w, "  bytes sent:\t%d\n", s.BytesSent)
+	fmt.Fprintf(w, "  bytes received:\t%d\n", s.BytesReceived)
+	if s.ReusedConnections != 0 || s.NewConnections != 0 {
+		fmt.Fprintf(w, "  reused connections:\t%d of %d\n",
+			s.ReusedConnections, s.ReusedConnections+s.NewConnections)
+	}
+
+	if len(s.Slowest) != 0 {
+		fmt.Fprintf(w, "\nslowest requests:\n")
+		fmt.Fprintf(w, "  DURATION\tSTATUS\tMETHOD\tURL\n")
+		for _, r := range s.Slowest {
+			status := "-"
+			if r.Status != 0 {
+				status = fmt.Sprint(r.Status)
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", r.Duration, status, r.Method, r.URL)
+		}
+	}
+
+	_ = w.Flush()
+	return buf.String()
+}
+
+// JSON returns accumulated statistics encoded as JSON.
+func (c *StatsCollector) JSON() ([]byte, error) {
+	return json.MarshalIndent(c.Summary(), "", "  ")
+}
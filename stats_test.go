@@ -0,0 +1,291 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCollectorSlowest(t *testing.T) {
+	c := NewStatsCollector(3)
+
+	for _, ms := range []int{5, 1, 7, 3, 9, 2} {
+		c.OnRequest(RequestStats{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/%d", ms),
+			Duration: time.Duration(ms) * time.Millisecond,
+		})
+	}
+
+	s := c.Summary()
+	assert.Equal(t, 6, s.Requests)
+	assert.Equal(t, 27*time.Millisecond, s.TotalDuration)
+
+	var urls []string
+	for _, r := range s.Slowest {
+		urls = append(urls, r.URL)
+	}
+	assert.Equal(t, []string{"/9", "/7", "/5"}, urls)
+}
+
+func TestStatsCollectorCounters(t *testing.T) {
+	c := NewStatsCollector()
+
+	c.OnRequest(RequestStats{Status: 200, RequestSize: 10, ResponseSize: 100})
+	c.OnRequest(RequestStats{Status: 500, RequestSize: -1, ResponseSize: 5})
+	c.OnRequest(RequestStats{Failed: true})
+
+	c.OnAssertion(AssertionStats{Failed: true})
+	c.OnAssertion(AssertionStats{Failed: true})
+	c.OnAssertion(AssertionStats{Failed: false})
+
+	s := c.Summary()
+	assert.Equal(t, 3, s.Requests)
+	assert.Equal(t, 1, s.FailedRequests)
+	assert.Equal(t, 3, s.Assertions)
+	assert.Equal(t, 2, s.Failures)
+	assert.Equal(t, int64(10), s.BytesSent)
+	assert.Equal(t, int64(105), s.BytesReceived)
+
+	report := c.Report()
+	assert.Contains(t, report, "requests:")
+	assert.Contains(t, report, "assertions:")
+	assert.Contains(t, report, "failed assertions:")
+	assert.Contains(t, report, "slowest requests:")
+
+	b, err := c.JSON()
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, float64(3), decoded["requests"])
+	assert.Equal(t, float64(3), decoded["assertions"])
+	assert.Equal(t, float64(2), decoded["failures"])
+	assert.Equal(t, 3, len(decoded["slowest"].([]interface{})))
+}
+
+func TestStatsCollectorConcurrent(t *testing.T) {
+	c := NewStatsCollector(5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.OnRequest(RequestStats{Duration: time.Duration(i*100 + j)})
+				c.OnAssertion(AssertionStats{Failed: j%2 == 0})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s := c.Summary()
+	assert.Equal(t, 1000, s.Requests)
+	assert.Equal(t, 1000, s.Assertions)
+	assert.Equal(t, 500, s.Failures)
+	assert.Equal(t, 5, len(s.Slowest))
+	assert.Equal(t, time.Duration(999), s.Slowest[0].Duration)
+}
+
+func TestStatsConfig(t *testing.T) {
+	stats := NewStatsCollector()
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+		Stats:          stats,
+	}
+
+	resp := NewRequest(config, "PUT", "http://example.com/path").
+		WithText("hello").
+		Expect()
+	resp.chain.assertOK(t)
+
+	resp.Body().Equal("hello")
+	resp.chain.assertOK(t)
+
+	// skipped after failure, not counted
+	resp.Body().Equal("bye").NotEmpty()
+
+	// Empty is implemented using Equal, counted once
+	arr := NewArray(newMockReporter(t), []interface{}{})
+	arr.chain.stats = stats
+	arr.Empty().chain.assertOK(t)
+
+	s := stats.Summary()
+	assert.Equal(t, 1, s.Requests)
+	assert.Equal(t, 0, s.FailedRequests)
+	// Expect, 2x Body, 2x Equal, and Empty
+	assert.Equal(t, 6, s.Assertions)
+	assert.Equal(t, 1, s.Failures)
+	assert.Equal(t, int64(5), s.BytesSent)
+	assert.Equal(t, int64(5), s.BytesReceived)
+
+	if assert.Equal(t, 1, len(s.Slowest)) {
+		assert.Equal(t, "PUT", s.Slowest[0].Method)
+		assert.Equal(t, "http://example.com/path", s.Slowest[0].URL)
+	}
+}
+
+func TestStatsAssertionsCounted(t *testing.T) {
+	stats := NewStatsCollector()
+
+	cc := NewCacheControl(newMockReporter(t), "no-store, max-age=60")
+	cc.chain.stats = stats
+
+	cc.NoStore()
+	cc.ContainsDirective("max-age")
+	cc.MaxAge().Equal(time.Minute)
+	cc.NoCache()
+
+	s := stats.Summary()
+	assert.Equal(t, 5, s.Assertions)
+	assert.Equal(t, 1, s.Failures)
+}
+
+// TestStatsAssertionsDeferLeave checks that every assertion method defers
+// chain.leave, so that its successes are reported to Stats.
+//
+// Assertion methods are exported methods of types holding chain, except
+// Expect and Environment, which return another object holding chain.
+// Option setters (With*) and Store methods are not assertions.
+func TestStatsAssertionsDeferLeave(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	pkg := pkgs["httpexpect"]
+	require.NotNil(t, pkg)
+
+	chainTypes := map[string]bool{}
+	for _, file := range pkg.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.IsExported() {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					for _, field := range st.Fields.List {
+						if id, ok := field.Type.(*ast.Ident); ok && id.Name == "chain" {
+							chainTypes[ts.Name.Name] = true
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	delete(chainTypes, "Expect")
+	delete(chainTypes, "Environment")
+
+	chainPtr := func(expr ast.Expr) (string, bool) {
+		star, ok := expr.(*ast.StarExpr)
+		if !ok {
+			return "", false
+		}
+		id, ok := star.X.(*ast.Ident)
+		if !ok || !chainTypes[id.Name] {
+			return "", false
+		}
+		return id.Name, true
+	}
+
+	checked := 0
+
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || !fd.Name.IsExported() {
+				continue
+			}
+			typ, ok := chainPtr(fd.Recv.List[0].Type)
+			if !ok {
+				continue
+			}
+			name := fd.Name.Name
+			if strings.HasPrefix(name, "With") || name == "Store" || name == "StoreTo" {
+				continue
+			}
+			res := fd.Type.Results
+			if res == nil || len(res.List) != 1 || len(res.List[0].Names) > 1 {
+				continue
+			}
+			if _, ok := chainPtr(res.List[0].Type); !ok {
+				continue
+			}
+
+			checked++
+
+			deferred := false
+			if len(fd.Body.List) != 0 {
+				if ds, ok := fd.Body.List[0].(*ast.DeferStmt); ok {
+					if sel, ok := ds.Call.Fun.(*ast.SelectorExpr); ok {
+						deferred = sel.Sel.Name == "leave"
+					}
+				}
+			}
+
+			assert.True(t, deferred, "%s.%s (%s) doesn't defer chain.leave",
+				typ, name, fset.Position(fd.Pos()))
+		}
+	}
+
+	assert.Greater(t, checked, 200)
+}
+
+func ExampleStatsCollector() {
+	// Usually stats is a global variable, and report is printed from
+	// TestMain after m.Run():
+	//
+	//  var stats = httpexpect.NewStatsCollector()
+	//
+	//  func TestMain(m *testing.M) {
+	//      code := m.Run()
+	//      fmt.Print(stats.Report())
+	//      os.Exit(code)
+	//  }
+	stats := NewStatsCollector()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: exampleReporter{},
+		Stats:    stats,
+	})
+
+	e.GET("/ping").Expect().Status(http.StatusNoContent)
+
+	e.GET("/ping").Expect().Status(http.StatusOK)
+
+	s := stats.Summary()
+	fmt.Println("requests:", s.Requests)
+	fmt.Println("assertions:", s.Assertions)
+	fmt.Println("failed assertions:", s.Failures)
+
+	// Output:
+	// requests: 2
+	// assertions: 4
+	// failed assertions: 1
+}
+
+type exampleReporter struct{}
+
+func (exampleReporter) Errorf(message string, args ...interface{}) {}
@@ -1,10 +1,14 @@
 package httpexpect
 
 import (
+	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // String provides methods to inspect attached string value
@@ -22,7 +26,7 @@ type String struct {
 // Example:
 //  str := NewString(t, "Hello")
 func NewString(reporter Reporter, value string) *String {
-	return &String{makeChain(reporter), value}
+	return &String{makeChainFor("NewString", reporter), value}
 }
 
 // Raw returns underlying value attached to String.
@@ -35,13 +39,41 @@ func (s *String) Raw() string {
 	return s.value
 }
 
+// Store is similar to Value.Store.
+func (s *String) Store(env *Environment, key string) *String {
+	storeValue(&s.chain, env, key, s.value)
+	return s
+}
+
+// StoreTo saves string value into given variable.
+//
+// If a failure was already reported for this String, the variable is
+// left unchanged.
+//
+// Example:
+//  var id string
+//  resp.JSON().Object().Value("id").String().StoreTo(&id)
+func (s *String) StoreTo(dst *string) *String {
+	if s.chain.failed() {
+		return s
+	}
+	if dst == nil {
+		s.chain.failUsage("\nunexpected nil pointer in StoreTo")
+		return s
+	}
+	*dst = s.value
+	return s
+}
+
 // Path is similar to Value.Path.
 func (s *String) Path(path string) *Value {
+	defer s.chain.leave(s.chain.failed())
 	return getPath(&s.chain, s.value, path)
 }
 
 // Schema is similar to Value.Schema.
 func (s *String) Schema(schema interface{}) *String {
+	defer s.chain.leave(s.chain.failed())
 	checkSchema(&s.chain, s.value, schema)
 	return s
 }
@@ -52,9 +84,49 @@ func (s *String) Schema(schema interface{}) *String {
 //  str := NewString(t, "Hello")
 //  str.Length().Equal(5)
 func (s *String) Length() *Number {
+	defer s.chain.leave(s.chain.failed())
 	return &Number{s.chain, float64(len(s.value))}
 }
 
+// Trimmed returns a new String object with leading and trailing white space
+// removed. White space is defined by Unicode, so non-breaking spaces are
+// removed too. Original String is not modified.
+//
+// Example:
+//  str := NewString(t, "  Hello World\r\n")
+//  str.Trimmed().Equal("Hello World")
+func (s *String) Trimmed() *String {
+	defer s.chain.leave(s.chain.failed())
+	return &String{s.chain.enter("Trimmed"), strings.TrimSpace(s.value)}
+}
+
+// CollapsedWhitespace returns a new String object with every sequence of
+// white space characters replaced with a single space, and leading and
+// trailing white space removed. White space is defined by Unicode, so tabs,
+// line breaks, and non-breaking spaces are collapsed too. Original String
+// is not modified.
+//
+// Example:
+//  str := NewString(t, "Hello\r\n\t World")
+//  str.CollapsedWhitespace().Equal("Hello World")
+func (s *String) CollapsedWhitespace() *String {
+	defer s.chain.leave(s.chain.failed())
+	return &String{s.chain.enter("CollapsedWhitespace"),
+		strings.Join(strings.Fields(s.value), " ")}
+}
+
+// NormalizedNFC returns a new String object with string converted to
+// Unicode Normalization Form C, i.e. with decomposed characters composed.
+// Original String is not modified.
+//
+// Example:
+//  str := NewString(t, "Cafe\u0301")
+//  str.NormalizedNFC().Equal("Caf\u00e9")
+func (s *String) NormalizedNFC() *String {
+	defer s.chain.leave(s.chain.failed())
+	return &String{s.chain.enter("NormalizedNFC"), norm.NFC.String(s.value)}
+}
+
 // DateTime parses date/time from string an returns a new DateTime object.
 //
 // If layout is given, DateTime() uses time.Parse() with given layout.
@@ -68,8 +140,9 @@ func (s *String) Length() *Number {
 //   str := NewString(t, "15 Nov 94 08:12 GMT")
 //   str.DateTime(time.RFC822).Lt(time.Now())
 func (s *String) DateTime(layout ...string) *DateTime {
+	defer s.chain.leave(s.chain.failed())
 	if s.chain.failed() {
-		return &DateTime{s.chain, time.Unix(0, 0)}
+		return newDateTimeEpoch(s.chain)
 	}
 	var (
 		t   time.Time
@@ -82,9 +155,39 @@ func (s *String) DateTime(layout ...string) *DateTime {
 	}
 	if err != nil {
 		s.chain.fail(err.Error())
-		return &DateTime{s.chain, time.Unix(0, 0)}
+		return newDateTimeEpoch(s.chain)
 	}
-	return &DateTime{s.chain, t}
+	return &DateTime{s.chain, &t}
+}
+
+// AsDuration parses duration from string and returns a new Duration object.
+//
+// String should use Go duration syntax accepted by time.ParseDuration,
+// e.g. "250ms" or "2h45m". If parsing error occurred, AsDuration reports
+// failure and returns empty (but non-nil) object.
+//
+// Example:
+//   str := NewString(t, "2h45m")
+//   str.AsDuration().Equal(2*time.Hour + 45*time.Minute)
+func (s *String) AsDuration() *Duration {
+	defer s.chain.leave(s.chain.failed())
+	return parseDuration(&s.chain, s.value)
+}
+
+// AsDecimal parses decimal number from string and returns a new Decimal
+// object, which compares values exactly, e.g. "10.50" is equal to "10.5".
+//
+// String should contain optional sign, digits with optional decimal point,
+// and optional exponent. If parsing error occurred, AsDecimal reports
+// failure, including position of invalid character, and returns empty
+// (but non-nil) object.
+//
+// Example:
+//  str := NewString(t, "10.50")
+//  str.AsDecimal().Equal("10.5").HasScale(2)
+func (s *String) AsDecimal() *Decimal {
+	defer s.chain.leave(s.chain.failed())
+	return parseDecimal(&s.chain, s.value)
 }
 
 // Empty succeeds if string is empty.
@@ -93,7 +196,11 @@ func (s *String) DateTime(layout ...string) *DateTime {
 //  str := NewString(t, "")
 //  str.Empty()
 func (s *String) Empty() *String {
-	return s.Equal("")
+	defer s.chain.leave(s.chain.failed())
+	if !(len(s.value) == 0) {
+		s.chain.fail("\nexpected empty string, but got:\n %q", s.value)
+	}
+	return s
 }
 
 // NotEmpty succeeds if string is non-empty.
@@ -102,7 +209,11 @@ func (s *String) Empty() *String {
 //  str := NewString(t, "Hello")
 //  str.NotEmpty()
 func (s *String) NotEmpty() *String {
-	return s.NotEqual("")
+	defer s.chain.leave(s.chain.failed())
+	if !(len(s.value) != 0) {
+		s.chain.failActual(s.value, "\nexpected non-empty string")
+	}
+	return s
 }
 
 // Equal succeeds if string is equal to given Go string.
@@ -111,8 +222,10 @@ func (s *String) NotEmpty() *String {
 //  str := NewString(t, "Hello")
 //  str.Equal("Hello")
 func (s *String) Equal(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if !(s.value == value) {
-		s.chain.fail("\nexpected string equal to:\n %q\n\nbut got:\n %q",
+		s.chain.failExpected(value, s.value,
+			"\nexpected string equal to:\n %q\n\nbut got:\n %q",
 			value, s.value)
 	}
 	return s
@@ -124,12 +237,83 @@ func (s *String) Equal(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.NotEqual("Goodbye")
 func (s *String) NotEqual(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if !(s.value != value) {
 		s.chain.fail("\nexpected string not equal to:\n %q", value)
 	}
 	return s
 }
 
+// EqualFile succeeds if string is equal to contents of given golden file.
+// Trailing newlines are ignored both in string and file.
+//
+// If UpdateGolden is true, the file is (re)written with the string instead,
+// and EqualFile succeeds. If file doesn't exist and UpdateGolden is false,
+// failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Body().EqualFile("testdata/users_list.txt")
+func (s *String) EqualFile(path string) *String {
+	defer s.chain.leave(s.chain.failed())
+	checkGoldenText(&s.chain, s.value, path)
+	return s
+}
+
+// InList succeeds if string is equal to one of the given Go strings.
+//
+// At least one value should be given, otherwise failure is reported.
+//
+// Example:
+//  str := NewString(t, "active")
+//  str.InList("active", "pending", "blocked")
+func (s *String) InList(values ...string) *String {
+	defer s.chain.leave(s.chain.failed())
+	if s.chain.failed() {
+		return s
+	}
+	if len(values) == 0 {
+		s.chain.failUsage("\nunexpected empty list argument in InList")
+		return s
+	}
+	for _, v := range values {
+		if s.value == v {
+			return s
+		}
+	}
+	s.chain.failBelongs(values, s.value,
+		"\nexpected string equal to one of:\n%s\n\nbut got:\n %q",
+		dumpValue(values), s.value)
+	return s
+}
+
+// NotInList succeeds if string is not equal to any of the given Go strings.
+//
+// At least one value should be given, otherwise failure is reported.
+//
+// Example:
+//  str := NewString(t, "active")
+//  str.NotInList("deleted", "blocked")
+func (s *String) NotInList(values ...string) *String {
+	defer s.chain.leave(s.chain.failed())
+	if s.chain.failed() {
+		return s
+	}
+	if len(values) == 0 {
+		s.chain.failUsage("\nunexpected empty list argument in NotInList")
+		return s
+	}
+	for _, v := range values {
+		if s.value == v {
+			s.chain.failBelongs(values, s.value,
+				"\nexpected string not equal to any of:\n%s\n\nbut got:\n %q",
+				dumpValue(values), s.value)
+			return s
+		}
+	}
+	return s
+}
+
 // EqualFold succeeds if string is equal to given Go string after applying Unicode
 // case-folding (so it's a case-insensitive match).
 //
@@ -137,6 +321,7 @@ func (s *String) NotEqual(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.EqualFold("hELLo")
 func (s *String) EqualFold(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if !strings.EqualFold(s.value, value) {
 		s.chain.fail(
 			"\nexpected string equal to (case-insensitive):\n %q\n\nbut got:\n %q",
@@ -152,6 +337,7 @@ func (s *String) EqualFold(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.NotEqualFold("gOODBYe")
 func (s *String) NotEqualFold(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if strings.EqualFold(s.value, value) {
 		s.chain.fail(
 			"\nexpected string not equal to (case-insensitive):\n %q\n\nbut got:\n %q",
@@ -166,10 +352,12 @@ func (s *String) NotEqualFold(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.Contains("ell")
 func (s *String) Contains(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if !strings.Contains(s.value, value) {
+		pos, size := closestSubstring(s.value, value)
 		s.chain.fail(
-			"\nexpected string containing substring:\n %q\n\nbut got:\n %q",
-			value, s.value)
+			"\nexpected string containing substring:\n %q\n\nbut got:\n %s",
+			value, s.dumpAround(pos, size))
 	}
 	return s
 }
@@ -180,10 +368,11 @@ func (s *String) Contains(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.NotContains("bye")
 func (s *String) NotContains(value string) *String {
-	if strings.Contains(s.value, value) {
+	defer s.chain.leave(s.chain.failed())
+	if pos := strings.Index(s.value, value); pos >= 0 {
 		s.chain.fail(
-			"\nexpected string not containing substring:\n %q\n\nbut got:\n %q",
-			value, s.value)
+			"\nexpected string not containing substring:\n %q\n\nbut got:\n %s",
+			value, s.dumpAround(pos, len(value)))
 	}
 	return s
 }
@@ -195,6 +384,7 @@ func (s *String) NotContains(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.ContainsFold("ELL")
 func (s *String) ContainsFold(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if !strings.Contains(strings.ToLower(s.value), strings.ToLower(value)) {
 		s.chain.fail(
 			"\nexpected string containing substring (case-insensitive):\n %q"+
@@ -210,6 +400,7 @@ func (s *String) ContainsFold(value string) *String {
 //  str := NewString(t, "Hello")
 //  str.NotContainsFold("BYE")
 func (s *String) NotContainsFold(value string) *String {
+	defer s.chain.leave(s.chain.failed())
 	if strings.Contains(strings.ToLower(s.value), strings.ToLower(value)) {
 		s.chain.fail(
 			"\nexpected string not containing substring (case-insensitive):\n %q"+
@@ -239,6 +430,7 @@ func (s *String) NotContainsFold(value string) *String {
 //   m.Name("host").Equal("example.com")
 //   m.Name("user").Equal("john")
 func (s *String) Match(re string) *Match {
+	defer s.chain.leave(s.chain.failed())
 	r, err := regexp.Compile(re)
 	if err != nil {
 		s.chain.fail(err.Error())
@@ -247,8 +439,10 @@ func (s *String) Match(re string) *Match {
 
 	m := r.FindStringSubmatch(s.value)
 	if m == nil {
-		s.chain.fail("\nexpected string matching regexp:\n `%s`\n\nbut got:\n %q",
-			re, s.value)
+		prefix, _ := r.LiteralPrefix()
+		pos, size := closestSubstring(s.value, prefix)
+		s.chain.fail("\nexpected string matching regexp:\n `%s`\n\nbut got:\n %s",
+			re, s.dumpAround(pos, size))
 		return makeMatch(s.chain, nil, nil)
 	}
 
@@ -304,17 +498,102 @@ func (s *String) MatchAll(re string) []Match {
 //   s := NewString(t, "a")
 //   s.NotMatch(`[^a]`)
 func (s *String) NotMatch(re string) *String {
+	defer s.chain.leave(s.chain.failed())
 	r, err := regexp.Compile(re)
 	if err != nil {
 		s.chain.fail(err.Error())
 		return s
 	}
 
-	if r.MatchString(s.value) {
-		s.chain.fail("\nexpected string not matching regexp:\n `%s`\n\nbut got:\n %q",
-			re, s.value)
+	if loc := r.FindStringIndex(s.value); loc != nil {
+		s.chain.fail("\nexpected string not matching regexp:\n `%s`\n\nbut got:\n %s",
+			re, s.dumpAround(loc[0], loc[1]-loc[0]))
 		return s
 	}
 
 	return s
 }
+
+// dumpAround returns quoted string for failure message. If string is long,
+// only an excerpt around given byte range is returned, together with string
+// length and line and column of the range.
+func (s *String) dumpAround(pos, size int) string {
+	context := s.chain.excerpt
+	if context == 0 {
+		context = DefaultStringExcerptSize
+	}
+	if context < 0 || len(s.value) < 2*context+size {
+		return fmt.Sprintf("%q", s.value)
+	}
+
+	begin := pos - context
+	if begin < 0 {
+		begin = 0
+	}
+	end := pos + size + context
+	if end > len(s.value) {
+		end = len(s.value)
+	}
+	for begin > 0 && !utf8.RuneStart(s.value[begin]) {
+		begin--
+	}
+	for end < len(s.value) && !utf8.RuneStart(s.value[end]) {
+		end++
+	}
+
+	excerpt := fmt.Sprintf("%q", s.value[begin:end])
+	if begin > 0 {
+		excerpt = "..." + excerpt
+	}
+	if end < len(s.value) {
+		excerpt += "..."
+	}
+
+	lineStart := strings.LastIndex(s.value[:pos], "\n") + 1
+
+	line := strings.Count(s.value[:pos], "\n") + 1
+	column := utf8.RuneCountInString(s.value[lineStart:pos]) + 1
+
+	return fmt.Sprintf("%s\n\n(string length %d, showing excerpt at line %d, column %d)",
+		excerpt, len(s.value), line, column)
+}
+
+// maxClosestSubstringCost limits the number of steps of closestSubstring.
+const maxClosestSubstringCost = 1 << 24
+
+// closestSubstring finds the longest common substring of haystack and needle
+// and returns its byte position and size in haystack. If needle is too long,
+// only its prefix is considered.
+func closestSubstring(haystack, needle string) (pos, size int) {
+	if len(haystack) == 0 || len(needle) == 0 {
+		return 0, 0
+	}
+	if limit := maxClosestSubstringCost / len(haystack); len(needle) > limit {
+		if limit < 1 {
+			limit = 1
+		}
+		needle = needle[:limit]
+	}
+
+	// prev[j] and curr[j] hold length of common suffix of haystack[:i]
+	// and needle[:j] for previous and current i
+	prev := make([]int, len(needle)+1)
+	curr := make([]int, len(needle)+1)
+
+	for i := 1; i <= len(haystack); i++ {
+		for j := 1; j <= len(needle); j++ {
+			if haystack[i-1] == needle[j-1] {
+				curr[j] = prev[j-1] + 1
+				if curr[j] > size {
+					size = curr[j]
+					pos = i - size
+				}
+			} else {
+				curr[j] = 0
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return pos, size
+}
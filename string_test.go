@@ -1,10 +1,16 @@
 package httpexpect
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStringFailed(t *testing.T) {
@@ -17,11 +23,18 @@ func TestStringFailed(t *testing.T) {
 	value.Path("$").chain.assertFailed(t)
 	value.Schema("")
 
+	value.Trimmed().chain.assertFailed(t)
+	value.CollapsedWhitespace().chain.assertFailed(t)
+	value.NormalizedNFC().chain.assertFailed(t)
 	value.DateTime()
+	value.AsDuration().chain.assertFailed(t)
 	value.Empty()
 	value.NotEmpty()
 	value.Equal("")
 	value.NotEqual("")
+	value.EqualFile("")
+	value.InList("")
+	value.NotInList("")
 	value.EqualFold("")
 	value.NotEqualFold("")
 	value.Contains("")
@@ -100,6 +113,117 @@ func TestStringEqual(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestStringEqualFile(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "httpexpect")
+	require.Nil(t, err)
+	defer os.RemoveAll(tempdir)
+
+	path := filepath.Join(tempdir, "hello.txt")
+
+	require.Nil(t, ioutil.WriteFile(path, []byte("hello, world\n\n"), 0644))
+
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, "hello, world")
+
+	value.EqualFile(path)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	NewString(reporter, "hello, world\n").EqualFile(path).chain.assertOK(t)
+
+	NewString(reporter, "Hello, world").EqualFile(path).chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, path)
+
+	missing := filepath.Join(tempdir, "missing.txt")
+
+	value.EqualFile(missing)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	UpdateGolden = true
+	NewString(reporter, "updated").EqualFile(path).chain.assertOK(t)
+	UpdateGolden = false
+
+	b, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, "updated\n", string(b))
+
+	value.EqualFile(path)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestStringInList(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, "active")
+
+	value.InList("active", "pending")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InList("pending", "blocked")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, `"blocked"`)
+
+	value.InList("ACTIVE")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InList()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotInList("pending", "blocked")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotInList("pending", "active")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotInList()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestStringInListFailureType(t *testing.T) {
+	reporter := &failureRecordingReporter{}
+
+	value := NewString(reporter, "active")
+
+	value.InList("pending", "blocked")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotInList("pending", "active")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InList()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	require.Equal(t, 3, len(reporter.failures))
+
+	assert.Equal(t, FailureAssertBelongs, reporter.failures[0].Type)
+	assert.Equal(t, []string{"pending", "blocked"},
+		reporter.failures[0].Expected.Value)
+	assert.Equal(t, "active", reporter.failures[0].Actual.Value)
+
+	assert.Equal(t, FailureAssertBelongs, reporter.failures[1].Type)
+	assert.Equal(t, FailureAssertUsage, reporter.failures[2].Type)
+
+	value.chain.fail("failed")
+	value.InList("pending")
+	value.NotInList("active")
+	assert.Equal(t, 4, len(reporter.failures))
+}
+
 func TestStringEqualFold(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -152,6 +276,124 @@ func TestStringContains(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestStringExcerpt(t *testing.T) {
+	var buf strings.Builder
+	for i := 0; buf.Len() < 10<<10; i++ {
+		fmt.Fprintf(&buf, "%04d lorem ipsum dolor sit amet\n", i)
+	}
+	haystack := buf.String()
+
+	cases := []struct {
+		name   string
+		check  func(s *String)
+		golden string
+	}{
+		{
+			name:  "Contains",
+			check: func(s *String) { s.Contains("0150 lorem ipsum dolor sit amex") },
+			golden: "\nexpected string containing substring:\n" +
+				" \"0150 lorem ipsum dolor sit amex\"\n\n" +
+				"but got:\n" +
+				" ...\"psum dolor sit amet\\n0150 lorem ipsum dolor sit amet\\n" +
+				"0151 lorem ipsum d\"...\n\n" +
+				"(string length 10240, showing excerpt at line 151, column 1)",
+		},
+		{
+			name:  "NotContains",
+			check: func(s *String) { s.NotContains("0200 lorem") },
+			golden: "\nexpected string not containing substring:\n" +
+				" \"0200 lorem\"\n\n" +
+				"but got:\n" +
+				" ...\"psum dolor sit amet\\n0200 lorem ipsum dolor sit ame\"...\n\n" +
+				"(string length 10240, showing excerpt at line 201, column 1)",
+		},
+		{
+			name:  "Match",
+			check: func(s *String) { s.Match(`0250 lorem ipsum [0-9]+`) },
+			golden: "\nexpected string matching regexp:\n" +
+				" `0250 lorem ipsum [0-9]+`\n\n" +
+				"but got:\n" +
+				" ...\"psum dolor sit amet\\n0250 lorem ipsum dolor sit amet\\n" +
+				"0251 \"...\n\n" +
+				"(string length 10240, showing excerpt at line 251, column 1)",
+		},
+		{
+			name:  "NotMatch",
+			check: func(s *String) { s.NotMatch(`03[0-9]{2} lorem`) },
+			golden: "\nexpected string not matching regexp:\n" +
+				" `03[0-9]{2} lorem`\n\n" +
+				"but got:\n" +
+				" ...\"psum dolor sit amet\\n0300 lorem ipsum dolor sit ame\"...\n\n" +
+				"(string length 10240, showing excerpt at line 301, column 1)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			value := NewString(reporter, haystack)
+			value.chain.excerpt = 20
+
+			tc.check(value)
+			value.chain.assertFailed(t)
+
+			message := strings.SplitN(reporter.message, "\n\nassertion:", 2)[0]
+			assert.Equal(t, tc.golden, message)
+		})
+	}
+
+	t.Run("default size", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		NewString(reporter, haystack).Contains("missing")
+
+		assert.Contains(t, reporter.message, "string length 10240")
+		assert.Less(t, len(reporter.message), 1000)
+	})
+
+	t.Run("whole string", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		value := NewString(reporter, haystack)
+		value.chain.excerpt = -1
+		value.Contains("missing")
+
+		assert.Contains(t, reporter.message, fmt.Sprintf("%q", haystack))
+		assert.NotContains(t, reporter.message, "string length")
+	})
+
+	t.Run("short string", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		NewString(reporter, "hello").Contains("bye")
+
+		assert.Contains(t, reporter.message, "but got:\n \"hello\"")
+		assert.NotContains(t, reporter.message, "string length")
+	})
+}
+
+func TestClosestSubstring(t *testing.T) {
+	cases := []struct {
+		haystack string
+		needle   string
+		pos      int
+		size     int
+	}{
+		{"hello world", "world", 6, 5},
+		{"hello world", "xworx", 6, 3},
+		{"hello world", "zzz", 0, 0},
+		{"", "abc", 0, 0},
+		{"abc", "", 0, 0},
+	}
+
+	for _, tc := range cases {
+		pos, size := closestSubstring(tc.haystack, tc.needle)
+		assert.Equal(t, tc.pos, pos, "%q %q", tc.haystack, tc.needle)
+		assert.Equal(t, tc.size, size, "%q %q", tc.haystack, tc.needle)
+	}
+}
+
 func TestStringContainsFold(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -193,6 +435,39 @@ func TestStringLength(t *testing.T) {
 	assert.Equal(t, 7.0, num.Raw())
 }
 
+func TestStringNormalization(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, "\u00a0 Hello\r\n\t World \u00a0\n")
+
+	value.Trimmed().Equal("Hello\r\n\t World").chain.assertOK(t)
+	value.CollapsedWhitespace().Equal("Hello World").chain.assertOK(t)
+
+	value.Trimmed().Equal("Hello World").chain.assertFailed(t)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, "\u00a0 Hello\r\n\t World \u00a0\n", value.Raw())
+
+	crlf := NewString(reporter, "a\r\nb")
+	lf := NewString(reporter, "a\nb")
+
+	assert.Equal(t,
+		lf.CollapsedWhitespace().Raw(), crlf.CollapsedWhitespace().Raw())
+
+	decomposed := NewString(reporter, "Cafe\u0301")
+
+	decomposed.Equal("Caf\u00e9").chain.assertFailed(t)
+	decomposed.chain.reset()
+
+	nfc := decomposed.NormalizedNFC()
+	nfc.Equal("Caf\u00e9").chain.assertOK(t)
+	nfc.Length().Equal(len("Caf\u00e9")).chain.assertOK(t)
+
+	assert.Equal(t, "Cafe\u0301", decomposed.Raw())
+
+	decomposed.Trimmed().NormalizedNFC().Equal("Caf\u00e9").chain.assertOK(t)
+}
+
 func TestStringDateTime(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -215,6 +490,28 @@ func TestStringDateTime(t *testing.T) {
 	assert.True(t, time.Unix(0, 0).Equal(dt3.Raw()))
 }
 
+func TestStringAsDuration(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewString(reporter, "250ms")
+	d1 := value1.AsDuration()
+	value1.chain.assertOK(t)
+	d1.chain.assertOK(t)
+	assert.Equal(t, 250*time.Millisecond, d1.Raw())
+
+	value2 := NewString(reporter, "2h45m")
+	d2 := value2.AsDuration()
+	value2.chain.assertOK(t)
+	d2.chain.assertOK(t)
+	assert.Equal(t, 2*time.Hour+45*time.Minute, d2.Raw())
+
+	value3 := NewString(reporter, "bad")
+	d3 := value3.AsDuration()
+	value3.chain.assertFailed(t)
+	d3.chain.assertFailed(t)
+	assert.Equal(t, time.Duration(0), d3.Raw())
+}
+
 func TestStringMatchOne(t *testing.T) {
 	reporter := newMockReporter(t)
 
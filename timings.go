@@ -0,0 +1,201 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings provides methods to inspect timing breakdown of request, collected
+// when Request.WithTimingBreakdown() is used.
+//
+// If request was sent multiple times (e.g. when following redirects or when
+// the transport has retried it on a new connection), timings describe the
+// last attempt, and Attempts() reports how many attempts were made.
+type Timings struct {
+	chain    chain
+	dns      *time.Duration
+	connect  *time.Duration
+	tls      *time.Duration
+	ttfb     *time.Duration
+	total    *time.Duration
+	attempts int
+	reused   *bool
+}
+
+// DNS returns a new Duration object that may be used to inspect duration
+// of DNS lookup.
+//
+// The returned Duration is not set if there was no DNS lookup, e.g. if
+// connection was reused or host is an IP address.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().DNS().Lt(100 * time.Millisecond)
+func (t *Timings) DNS() *Duration {
+	defer t.chain.leave(t.chain.failed())
+	return &Duration{t.chain.enter("DNS"), t.dns}
+}
+
+// Connect returns a new Duration object that may be used to inspect duration
+// of establishing TCP connection.
+//
+// The returned Duration is not set if connection was reused.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().Connect().Lt(100 * time.Millisecond)
+func (t *Timings) Connect() *Duration {
+	defer t.chain.leave(t.chain.failed())
+	return &Duration{t.chain.enter("Connect"), t.connect}
+}
+
+// TLSHandshake returns a new Duration object that may be used to inspect
+// duration of TLS handshake.
+//
+// The returned Duration is not set if request was not sent over TLS or if
+// connection was reused.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().TLSHandshake().Lt(100 * time.Millisecond)
+func (t *Timings) TLSHandshake() *Duration {
+	defer t.chain.leave(t.chain.failed())
+	return &Duration{t.chain.enter("TLSHandshake"), t.tls}
+}
+
+// TimeToFirstByte returns a new Duration object that may be used to inspect
+// time interval between the start of attempt and receiving the first byte
+// of response.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().TimeToFirstByte().Lt(time.Second)
+func (t *Timings) TimeToFirstByte() *Duration {
+	defer t.chain.leave(t.chain.failed())
+	return &Duration{t.chain.enter("TimeToFirstByte"), t.ttfb}
+}
+
+// Total returns a new Duration object that may be used to inspect time
+// interval between the start of attempt and receiving response headers.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().Total().Lt(time.Second)
+func (t *Timings) Total() *Duration {
+	defer t.chain.leave(t.chain.failed())
+	return &Duration{t.chain.enter("Total"), t.total}
+}
+
+// Attempts returns a new Number object that may be used to inspect number
+// of attempts made to send request. It is zero if timings were not collected.
+//
+// Example:
+//  resp := req.WithTimingBreakdown().Expect()
+//  resp.Timings().Attempts().Equal(1)
+func (t *Timings) Attempts() *Number {
+	defer t.chain.leave(t.chain.failed())
+	return &Number{t.chain.enter("Attempts"), float64(t.attempts)}
+}
+
+type timingTrace struct {
+	mu sync.Mutex
+
+	attempts int
+	reused   *bool
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func (tt *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.attempts++
+			tt.start = time.Now()
+			tt.dnsStart, tt.dnsDone = time.Time{}, time.Time{}
+			tt.connectStart, tt.connectDone = time.Time{}, time.Time{}
+			tt.tlsStart, tt.tlsDone = time.Time{}, time.Time{}
+			tt.firstByte = time.Time{}
+			tt.reused = nil
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			reused := info.Reused
+			tt.reused = &reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.dnsDone = time.Now()
+		},
+		ConnectStart: func(string, string) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			if tt.connectStart.IsZero() {
+				tt.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(string, string, error) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.connectDone = time.Now()
+		},
+		TLSHandshakeStart: func() {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.tlsDone = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			tt.mu.Lock()
+			defer tt.mu.Unlock()
+			tt.firstByte = time.Now()
+		},
+	}
+}
+
+func (tt *timingTrace) timings(chain chain, end time.Time) *Timings {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	t := &Timings{chain: chain, attempts: tt.attempts, reused: tt.reused}
+	if tt.attempts == 0 {
+		return t
+	}
+
+	t.dns = timingInterval(tt.dnsStart, tt.dnsDone)
+	t.connect = timingInterval(tt.connectStart, tt.connectDone)
+	t.tls = timingInterval(tt.tlsStart, tt.tlsDone)
+	t.ttfb = timingInterval(tt.start, tt.firstByte)
+	t.total = timingInterval(tt.start, end)
+
+	return t
+}
+
+func timingInterval(start, end time.Time) *time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return nil
+	}
+	d := end.Sub(start)
+	return &d
+}
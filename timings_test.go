@@ -0,0 +1,61 @@
+package httpexpect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingsNotCollected(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	timings := &Timings{chain: makeChain(reporter)}
+
+	timings.DNS().NotSet().chain.assertOK(t)
+	timings.Connect().NotSet().chain.assertOK(t)
+	timings.TLSHandshake().NotSet().chain.assertOK(t)
+	timings.TimeToFirstByte().NotSet().chain.assertOK(t)
+	timings.Total().NotSet().chain.assertOK(t)
+	timings.Attempts().Equal(0).chain.assertOK(t)
+}
+
+func TestTimingsTrace(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	tt := &timingTrace{}
+	trace := tt.clientTrace()
+
+	trace.GetConn("example.com:80")
+	trace.ConnectStart("tcp", "1.2.3.4:80")
+	trace.ConnectDone("tcp", "1.2.3.4:80", nil)
+	trace.GotFirstResponseByte()
+
+	timings := tt.timings(makeChain(reporter), time.Now())
+
+	timings.Attempts().Equal(1).chain.assertOK(t)
+	timings.DNS().NotSet().chain.assertOK(t)
+	timings.TLSHandshake().NotSet().chain.assertOK(t)
+	timings.Connect().IsSet().chain.assertOK(t)
+	timings.TimeToFirstByte().IsSet().chain.assertOK(t)
+	timings.Total().Ge(timings.TimeToFirstByte().Raw()).chain.assertOK(t)
+
+	trace.GetConn("example.com:80")
+	trace.GotFirstResponseByte()
+
+	timings = tt.timings(makeChain(reporter), time.Now())
+
+	timings.Attempts().Equal(2).chain.assertOK(t)
+	timings.Connect().NotSet().chain.assertOK(t)
+	timings.TimeToFirstByte().IsSet().chain.assertOK(t)
+}
+
+func TestTimingsPath(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	timings := &Timings{chain: makeChain(reporter)}
+
+	timings.Total().IsSet()
+
+	assert.Contains(t, reporter.message, "Total")
+}
@@ -0,0 +1,201 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// TLS provides methods to inspect attached tls.ConnectionState value.
+type TLS struct {
+	chain chain
+	value *tls.ConnectionState
+}
+
+// NewTLS returns a new TLS object given a reporter used to report failures
+// and TLS connection state to be inspected.
+//
+// reporter should not be nil, but value may be nil. In this case, TLS is
+// not set, and all assertions except NotSet fail.
+//
+// Example:
+//   state := NewTLS(reporter, resp.TLS)
+//   state.Version(tls.VersionTLS12, tls.VersionTLS13)
+func NewTLS(reporter Reporter, value *tls.ConnectionState) *TLS {
+	return &TLS{makeChainFor("NewTLS", reporter), value}
+}
+
+// Raw returns underlying tls.ConnectionState value attached to TLS.
+// This is the value originally passed to NewTLS.
+//
+// If TLS is not set, nil is returned.
+//
+// Example:
+//  state := NewTLS(t, resp.TLS)
+//  assert.Equal(t, resp.TLS, state.Raw())
+func (t *TLS) Raw() *tls.ConnectionState {
+	return t.value
+}
+
+// IsSet succeeds if TLS is set, i.e. if response was received over TLS.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().IsSet()
+func (t *TLS) IsSet() *TLS {
+	defer t.chain.leave(t.chain.failed())
+	t.checkSet()
+	return t
+}
+
+// NotSet succeeds if TLS is not set, i.e. if response was not received
+// over TLS.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().NotSet()
+func (t *TLS) NotSet() *TLS {
+	defer t.chain.leave(t.chain.failed())
+	if t.value != nil {
+		t.chain.fail("expected TLS connection state is not set, but it is")
+	}
+	return t
+}
+
+// Version succeeds if negotiated TLS version is one of the given versions.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().Version(tls.VersionTLS12, tls.VersionTLS13)
+func (t *TLS) Version(versions ...uint16) *TLS {
+	defer t.chain.leave(t.chain.failed())
+	if !t.checkSet() {
+		return t
+	}
+	for _, v := range versions {
+		if t.value.Version == v {
+			return t
+		}
+	}
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		names = append(names, tlsVersionName(v))
+	}
+	t.chain.fail("\nexpected TLS version equal to one of:\n%s\n\nbut got:\n %q",
+		dumpValue(names), tlsVersionName(t.value.Version))
+	return t
+}
+
+// CipherSuite succeeds if negotiated cipher suite is one of the given suites.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().CipherSuite(tls.TLS_AES_128_GCM_SHA256)
+func (t *TLS) CipherSuite(suites ...uint16) *TLS {
+	defer t.chain.leave(t.chain.failed())
+	if !t.checkSet() {
+		return t
+	}
+	for _, s := range suites {
+		if t.value.CipherSuite == s {
+			return t
+		}
+	}
+	names := make([]string, 0, len(suites))
+	for _, s := range suites {
+		names = append(names, tls.CipherSuiteName(s))
+	}
+	t.chain.fail("\nexpected TLS cipher suite equal to one of:\n%s\n\nbut got:\n %q",
+		dumpValue(names), tls.CipherSuiteName(t.value.CipherSuite))
+	return t
+}
+
+// ServerName succeeds if server name requested by client (SNI) is equal
+// to given name.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().ServerName("example.com")
+func (t *TLS) ServerName(name string) *TLS {
+	defer t.chain.leave(t.chain.failed())
+	if !t.checkSet() {
+		return t
+	}
+	if t.value.ServerName != name {
+		t.chain.fail("\nexpected TLS server name equal to:\n %q\n\nbut got:\n %q",
+			name, t.value.ServerName)
+	}
+	return t
+}
+
+// PeerCertificateCount succeeds if peer presented given number of
+// certificates.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().PeerCertificateCount(1)
+func (t *TLS) PeerCertificateCount(n int) *TLS {
+	defer t.chain.leave(t.chain.failed())
+	if !t.checkSet() {
+		return t
+	}
+	if len(t.value.PeerCertificates) != n {
+		t.chain.fail(
+			"\nexpected TLS peer certificate count equal to:\n %d\n\nbut got:\n %d",
+			n, len(t.value.PeerCertificates))
+	}
+	return t
+}
+
+// PeerCertificateSubject returns a new String object that may be used to
+// inspect subject of peer certificate with given index. The first
+// certificate is the leaf certificate.
+//
+// If TLS is not set or index is out of range, failure is reported and
+// empty (but non-nil) object is returned.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.TLS().PeerCertificateSubject(0).Contains("CN=example.com")
+func (t *TLS) PeerCertificateSubject(index int) *String {
+	defer t.chain.leave(t.chain.failed())
+	if !t.checkSet() {
+		return &String{t.chain, ""}
+	}
+	if index < 0 || index >= len(t.value.PeerCertificates) {
+		t.chain.fail(
+			"\nexpected TLS peer certificate index in range:\n [0; %d)\n\nbut got:\n %d",
+			len(t.value.PeerCertificates), index)
+		return &String{t.chain, ""}
+	}
+	chain := t.chain.enter(fmt.Sprintf("PeerCertificates[%d].Subject", index))
+	return &String{chain, t.value.PeerCertificates[index].Subject.String()}
+}
+
+func (t *TLS) checkSet() bool {
+	if t.chain.failed() {
+		return false
+	}
+	if t.value == nil {
+		t.chain.fail("expected TLS connection state is set, but it is not" +
+			" (response was not received over TLS)")
+		return false
+	}
+	return true
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionSSL30:
+		return "SSLv3"
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04X", version)
+	}
+}
@@ -0,0 +1,137 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	value := &TLS{chain, &tls.ConnectionState{}}
+
+	value.IsSet()
+	value.NotSet()
+	value.Version(tls.VersionTLS12)
+	value.CipherSuite(tls.TLS_AES_128_GCM_SHA256)
+	value.ServerName("")
+	value.PeerCertificateCount(0)
+	value.PeerCertificateSubject(0).chain.assertFailed(t)
+}
+
+func TestTLSNotSet(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewTLS(reporter, nil)
+
+	assert.Nil(t, value.Raw())
+
+	value.NotSet()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsSet()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Version(tls.VersionTLS12)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.CipherSuite(tls.TLS_AES_128_GCM_SHA256)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ServerName("")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.PeerCertificateCount(0)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.PeerCertificateSubject(0).chain.assertFailed(t)
+}
+
+func TestTLSState(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	state := &tls.ConnectionState{
+		Version:     tls.VersionTLS12,
+		CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		ServerName:  "example.com",
+		PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: "example.com"}},
+			{Subject: pkix.Name{CommonName: "Example CA"}},
+		},
+	}
+
+	value := NewTLS(reporter, state)
+
+	assert.Equal(t, state, value.Raw())
+
+	value.IsSet()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotSet()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Version(tls.VersionTLS12)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Version(tls.VersionTLS13, tls.VersionTLS12)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Version(tls.VersionTLS13)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, `"TLS 1.3"`)
+	assert.Contains(t, reporter.message, `"TLS 1.2"`)
+
+	value.CipherSuite(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.CipherSuite(tls.TLS_AES_256_GCM_SHA384)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "TLS_AES_256_GCM_SHA384")
+	assert.Contains(t, reporter.message, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+
+	value.ServerName("example.com")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ServerName("example.org")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.PeerCertificateCount(2)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.PeerCertificateCount(1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.PeerCertificateSubject(0).Equal("CN=example.com").chain.assertOK(t)
+	value.PeerCertificateSubject(1).Equal("CN=Example CA").chain.assertOK(t)
+
+	value.PeerCertificateSubject(2).chain.assertFailed(t)
+	value.chain.reset()
+
+	value.PeerCertificateSubject(-1).chain.assertFailed(t)
+	value.chain.reset()
+}
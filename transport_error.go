@@ -0,0 +1,100 @@
+package httpexpect
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrorClass defines category of transport error, i.e. error returned by
+// Client when request could not be sent or response could not be received.
+//
+// See Request.ExpectError and Response.ErrorClass.
+type ErrorClass int
+
+const (
+	// ErrorOther is used for errors not matching any other class.
+	ErrorOther ErrorClass = iota
+
+	// ErrorTimeout is used when request or connection timed out.
+	ErrorTimeout
+
+	// ErrorConnectionRefused is used when server refused connection.
+	ErrorConnectionRefused
+
+	// ErrorDNS is used when server host name could not be resolved.
+	ErrorDNS
+
+	// ErrorTLS is used when TLS handshake or certificate verification failed.
+	ErrorTLS
+
+	// ErrorCanceled is used when request context was canceled.
+	ErrorCanceled
+
+	// ErrorProxy is used when connection to proxy failed, as opposed to
+	// connection to the origin server.
+	ErrorProxy
+)
+
+// String returns name of error class, e.g. "ConnectionRefused".
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorTimeout:
+		return "Timeout"
+	case ErrorConnectionRefused:
+		return "ConnectionRefused"
+	case ErrorDNS:
+		return "DNS"
+	case ErrorTLS:
+		return "TLS"
+	case ErrorCanceled:
+		return "Canceled"
+	case ErrorProxy:
+		return "Proxy"
+	}
+	return "Other"
+}
+
+// classifyError returns class of transport error.
+func classifyError(err error) ErrorClass {
+	var (
+		dnsErr      *net.DNSError
+		recordErr   tls.RecordHeaderError
+		authErr     x509.UnknownAuthorityError
+		certErr     x509.CertificateInvalidError
+		hostnameErr x509.HostnameError
+		netErr      net.Error
+		opErr       *net.OpError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.As(err, &opErr) && opErr.Op == "proxyconnect":
+		// net/http wraps errors of connecting to proxy
+		return ErrorProxy
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.As(err, &recordErr), errors.As(err, &authErr),
+		errors.As(err, &certErr), errors.As(err, &hostnameErr),
+		strings.Contains(err.Error(), "tls: "):
+		return ErrorTLS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorConnectionRefused
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	}
+	return ErrorOther
+}
+
+// isTimeoutError returns true if err is caused by exceeded timeout or
+// deadline.
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,52 @@
+package httpexpect
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestTransportErrorClassify(t *testing.T) {
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "http://example.com", Err: err}
+	}
+
+	cases := []struct {
+		err   error
+		class ErrorClass
+	}{
+		{wrap(errors.New("boom")), ErrorOther},
+		{wrap(timeoutError{}), ErrorTimeout},
+		{wrap(context.DeadlineExceeded), ErrorTimeout},
+		{wrap(context.Canceled), ErrorCanceled},
+		{wrap(&net.DNSError{Err: "no such host", Name: "example"}), ErrorDNS},
+		{wrap(&net.OpError{Op: "dial", Err: &os.SyscallError{
+			Syscall: "connect", Err: syscall.ECONNREFUSED}}), ErrorConnectionRefused},
+		{wrap(x509.UnknownAuthorityError{}), ErrorTLS},
+		{wrap(errors.New("remote error: tls: bad certificate")), ErrorTLS},
+		{wrap(&net.OpError{Op: "proxyconnect", Err: &net.OpError{
+			Op: "dial", Err: &os.SyscallError{
+				Syscall: "connect", Err: syscall.ECONNREFUSED}}}), ErrorProxy},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.class, classifyError(tc.err), tc.err.Error())
+	}
+
+	assert.Equal(t, "ConnectionRefused", ErrorConnectionRefused.String())
+	assert.Equal(t, "Proxy", ErrorProxy.String())
+	assert.Equal(t, "Other", ErrorClass(100).String())
+}
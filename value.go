@@ -36,7 +36,7 @@ type Value struct {
 //  value := NewValue(t, nil)
 //  value.Null()
 func NewValue(reporter Reporter, value interface{}) *Value {
-	chain := makeChain(reporter)
+	chain := makeChainFor("NewValue", reporter)
 	if value != nil {
 		value, _ = canonValue(&chain, value)
 	}
@@ -53,6 +53,20 @@ func (v *Value) Raw() interface{} {
 	return v.value
 }
 
+// Store saves raw value into environment with given key. The value is
+// stored as is, e.g. JSON object is stored as map[string]interface{}.
+//
+// If a failure was already reported for this Value, the value is not
+// stored, so that stale values never propagate to subsequent requests.
+//
+// Example:
+//  env := e.Env()
+//  resp.JSON().Object().Value("user").Store(env, "user")
+func (v *Value) Store(env *Environment, key string) *Value {
+	storeValue(&v.chain, env, key, v.value)
+	return v
+}
+
 // Path returns a new Value object for child object(s) matching given
 // JSONPath expression.
 //
@@ -78,9 +92,32 @@ func (v *Value) Raw() interface{} {
 //      user.String().Equal("john")
 //  }
 func (v *Value) Path(path string) *Value {
+	defer v.chain.leave(v.chain.failed())
 	return getPath(&v.chain, v.value, path)
 }
 
+// Pointer returns a new Value object for child value referenced by given
+// JSON Pointer, as defined by RFC 6901.
+//
+// Empty pointer references the whole value. Otherwise, pointer should
+// start with "/" and consist of "/"-separated tokens, where "~1" and "~0"
+// are unescaped to "/" and "~". Array elements are referenced by decimal
+// index; "-" is rejected since it refers to nonexistent element.
+//
+// If some token can't be resolved, failure is reported with the token and
+// the type of value encountered at that point.
+//
+// Example:
+//  json := `{"users": [{"name": "john"}], "a/b": {"c~d": 1}}`
+//  value := NewValue(t, json)
+//
+//  value.Pointer("/users/0/name").String().Equal("john")
+//  value.Pointer("/a~1b/c~0d").Number().Equal(1)
+func (v *Value) Pointer(pointer string) *Value {
+	defer v.chain.leave(v.chain.failed())
+	return getPointer(&v.chain, v.value, pointer)
+}
+
 // Schema succeeds if value matches given JSON Schema.
 //
 // JSON Schema specifies a JSON-based format to define the structure of
@@ -118,6 +155,7 @@ func (v *Value) Path(path string) *Value {
 //  value := NewValue(t, data)
 //  value.Schema("http://example.com/schema.json")
 func (v *Value) Schema(schema interface{}) *Value {
+	defer v.chain.leave(v.chain.failed())
 	checkSchema(&v.chain, v.value, schema)
 	return v
 }
@@ -131,10 +169,10 @@ func (v *Value) Schema(schema interface{}) *Value {
 //  value := NewValue(t, map[string]interface{}{"foo": 123})
 //  value.Object().ContainsKey("foo")
 func (v *Value) Object() *Object {
+	defer v.chain.leave(v.chain.failed())
 	data, ok := v.value.(map[string]interface{})
 	if !ok {
-		v.chain.fail("\nexpected object value (map or struct), but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "object", v.value)
 	}
 	return &Object{v.chain, data}
 }
@@ -148,10 +186,10 @@ func (v *Value) Object() *Object {
 //  value := NewValue(t, []interface{}{"foo", 123})
 //  value.Array().Elements("foo", 123)
 func (v *Value) Array() *Array {
+	defer v.chain.leave(v.chain.failed())
 	data, ok := v.value.([]interface{})
 	if !ok {
-		v.chain.fail("\nexpected array value, but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "array", v.value)
 	}
 	return &Array{v.chain, data}
 }
@@ -165,10 +203,10 @@ func (v *Value) Array() *Array {
 //  value := NewValue(t, "foo")
 //  value.String().EqualFold("FOO")
 func (v *Value) String() *String {
+	defer v.chain.leave(v.chain.failed())
 	data, ok := v.value.(string)
 	if !ok {
-		v.chain.fail("\nexpected string value, but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "string", v.value)
 	}
 	return &String{v.chain, data}
 }
@@ -182,10 +220,10 @@ func (v *Value) String() *String {
 //  value := NewValue(t, 123)
 //  value.Number().InRange(100, 200)
 func (v *Value) Number() *Number {
+	defer v.chain.leave(v.chain.failed())
 	data, ok := v.value.(float64)
 	if !ok {
-		v.chain.fail("\nexpected numeric value, but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "number", v.value)
 	}
 	return &Number{v.chain, data}
 }
@@ -199,10 +237,10 @@ func (v *Value) Number() *Number {
 //  value := NewValue(t, true)
 //  value.Boolean().True()
 func (v *Value) Boolean() *Boolean {
+	defer v.chain.leave(v.chain.failed())
 	data, ok := v.value.(bool)
 	if !ok {
-		v.chain.fail("\nexpected boolean value, but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "boolean", v.value)
 	}
 	return &Boolean{v.chain, data}
 }
@@ -220,13 +258,23 @@ func (v *Value) Boolean() *Boolean {
 //  value := NewValue(t, []interface{}(nil))
 //  value.Null()
 func (v *Value) Null() *Value {
+	defer v.chain.leave(v.chain.failed())
 	if v.value != nil {
-		v.chain.fail("\nexpected nil value, but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "null", v.value)
 	}
 	return v
 }
 
+// IsNull is an alias for Null.
+//
+// Example:
+//  value := NewValue(t, nil)
+//  value.IsNull()
+func (v *Value) IsNull() *Value {
+	defer v.chain.leave(v.chain.failed())
+	return v.Null()
+}
+
 // NotNull succeeds if value is not nil.
 //
 // Note that non-nil interface{} that points to nil value (e.g. nil slice or map)
@@ -240,9 +288,9 @@ func (v *Value) Null() *Value {
 //  value := NewValue(t, make([]interface{}, 0)
 //  value.Null()
 func (v *Value) NotNull() *Value {
+	defer v.chain.leave(v.chain.failed())
 	if v.value == nil {
-		v.chain.fail("\nexpected non-nil value, but got:\n%s",
-			dumpValue(v.value))
+		failType(&v.chain, "non-null", v.value)
 	}
 	return v
 }
@@ -254,12 +302,14 @@ func (v *Value) NotNull() *Value {
 //  value := NewValue(t, "foo")
 //  value.Equal("foo")
 func (v *Value) Equal(value interface{}) *Value {
+	defer v.chain.leave(v.chain.failed())
 	expected, ok := canonValue(&v.chain, value)
 	if !ok {
 		return v
 	}
 	if !reflect.DeepEqual(expected, v.value) {
-		v.chain.fail("\nexpected value equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+		v.chain.failExpected(expected, v.value,
+			"\nexpected value equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
 			dumpValue(expected),
 			dumpValue(v.value),
 			diffValues(expected, v.value))
@@ -267,6 +317,68 @@ func (v *Value) Equal(value interface{}) *Value {
 	return v
 }
 
+// EqualStrict succeeds if value is deeply equal to given Go value, and reports
+// all mismatches at once: keys present in value but absent in expected,
+// keys absent in value, and differing values. Arrays are compared per-index
+// using the same rules. Before comparison, both values are converted to
+// canonical form.
+//
+// Ignore may be used in expected value to accept any actual value.
+//
+// Example:
+//  value := NewValue(t, map[string]interface{}{"id": 123, "name": "john"})
+//  value.EqualStrict(map[string]interface{}{"id": Ignore, "name": "john"})
+func (v *Value) EqualStrict(value interface{}) *Value {
+	defer v.chain.leave(v.chain.failed())
+	checkStrict(&v.chain, v.value, value)
+	return v
+}
+
+// EqualWith succeeds if value is deeply equal to given Go value, using given
+// Comparator for leaf values. Before comparison, both values are converted
+// to canonical form.
+//
+// For every leaf of expected value, cmp is invoked with the full path to
+// the leaf, e.g. "items[2].created". If cmp doesn't handle the leaf, default
+// equality is used. All mismatches are reported at once, with their paths.
+//
+// See NumericStringEquality for comparator that matches integers encoded
+// as strings.
+//
+// Example:
+//  value := NewValue(t, map[string]interface{}{"price": "10.50"})
+//  value.EqualWith(map[string]interface{}{"price": "10.5"},
+//      func(path string, expected, actual interface{}) (bool, bool) {
+//          if path != "price" {
+//              return false, false
+//          }
+//          e, _ := strconv.ParseFloat(expected.(string), 64)
+//          a, _ := strconv.ParseFloat(actual.(string), 64)
+//          return true, e == a
+//      })
+func (v *Value) EqualWith(value interface{}, cmp Comparator) *Value {
+	defer v.chain.leave(v.chain.failed())
+	checkWith(&v.chain, v.value, value, cmp)
+	return v
+}
+
+// EqualFile succeeds if value is equal to JSON value stored in given golden
+// file. Before comparison, file contents is decoded and both values are
+// converted to canonical form, so formatting and key order don't matter.
+//
+// If UpdateGolden is true, the file is (re)written with indented JSON
+// representation of the value instead, and EqualFile succeeds. If file
+// doesn't exist and UpdateGolden is false, failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.JSON().EqualFile("testdata/users_list.json")
+func (v *Value) EqualFile(path string) *Value {
+	defer v.chain.leave(v.chain.failed())
+	checkGoldenJSON(&v.chain, v.value, path)
+	return v
+}
+
 // NotEqual succeeds if value is not equal to given Go value (e.g. map, slice,
 // string, etc). Before comparison, both values are converted to canonical form.
 //
@@ -274,6 +386,7 @@ func (v *Value) Equal(value interface{}) *Value {
 //  value := NewValue(t, "foo")
 //  value.NorEqual("bar")
 func (v *Value) NotEqual(value interface{}) *Value {
+	defer v.chain.leave(v.chain.failed())
 	expected, ok := canonValue(&v.chain, value)
 	if !ok {
 		return v
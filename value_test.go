@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,6 +23,7 @@ func TestValueFailed(t *testing.T) {
 	value.chain.assertFailed(t)
 
 	value.Path("$").chain.assertFailed(t)
+	value.Pointer("").chain.assertFailed(t)
 	value.Schema("")
 
 	assert.False(t, value.Object() == nil)
@@ -36,10 +40,14 @@ func TestValueFailed(t *testing.T) {
 	value.Boolean().chain.assertFailed(t)
 
 	value.Null()
+	value.IsNull()
 	value.NotNull()
 
 	value.Equal(nil)
 	value.NotEqual(nil)
+	value.EqualStrict(nil)
+	value.EqualWith(nil, nil)
+	value.EqualFile("")
 }
 
 func TestValueCastNull(t *testing.T) {
@@ -154,6 +162,67 @@ func TestValueCastBoolean(t *testing.T) {
 	NewValue(reporter, data).Null().chain.assertFailed(t)
 }
 
+func TestValueCastMessages(t *testing.T) {
+	values := []struct {
+		typ    string
+		value  interface{}
+		actual string
+	}{
+		{"object", map[string]interface{}{"a": 1}, `object ({"a":1})`},
+		{"array", []interface{}{1, "b"}, `array ([1,"b"])`},
+		{"string", "foo", `string ("foo")`},
+		{"number", 42, `number (42)`},
+		{"boolean", true, `boolean (true)`},
+		{"null", nil, `null`},
+	}
+
+	conversions := []struct {
+		typ     string
+		convert func(v *Value) *chain
+	}{
+		{"object", func(v *Value) *chain { return &v.Object().chain }},
+		{"array", func(v *Value) *chain { return &v.Array().chain }},
+		{"string", func(v *Value) *chain { return &v.String().chain }},
+		{"number", func(v *Value) *chain { return &v.Number().chain }},
+		{"boolean", func(v *Value) *chain { return &v.Boolean().chain }},
+		{"null", func(v *Value) *chain { return &v.Null().chain }},
+	}
+
+	for _, conv := range conversions {
+		for _, val := range values {
+			t.Run(conv.typ+" from "+val.typ, func(t *testing.T) {
+				reporter := newMockReporter(t)
+
+				chain := conv.convert(NewValue(reporter, val.value))
+
+				if conv.typ == val.typ {
+					chain.assertOK(t)
+					return
+				}
+
+				chain.assertFailed(t)
+				assert.Contains(t, reporter.message,
+					"expected: "+conv.typ+", actual: "+val.actual)
+			})
+		}
+	}
+
+	t.Run("non-null from null", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		NewValue(reporter, nil).NotNull().chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "expected: non-null, actual: null")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		NewValue(reporter, strings.Repeat("x", 100)).Number().chain.assertFailed(t)
+		assert.Contains(t, reporter.message,
+			`expected: number, actual: string ("`+strings.Repeat("x", 63)+`...)`)
+	})
+}
+
 func TestValueGetObject(t *testing.T) {
 	type (
 		myMap map[string]interface{}
@@ -613,6 +682,83 @@ func TestValuePathIntFloat(t *testing.T) {
 	assert.Equal(t, 123.0, b.Raw())
 }
 
+func TestValuePointer(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	data := map[string]interface{}{
+		"foo":  []interface{}{"bar", "baz"},
+		"":     0,
+		"a/b":  1,
+		"c%d":  2,
+		"e^f":  3,
+		"g|h":  4,
+		"i\\j": 5,
+		"k\"l": 6,
+		" ":    7,
+		"m~n":  8,
+		"~1":   9,
+		"users": []interface{}{
+			map[string]interface{}{"name": "john"},
+		},
+	}
+
+	value := NewValue(reporter, data)
+
+	cases := []struct {
+		pointer  string
+		expected interface{}
+	}{
+		{"", value.Raw()},
+		{"/foo", []interface{}{"bar", "baz"}},
+		{"/foo/0", "bar"},
+		{"/foo/1", "baz"},
+		{"/", 0.0},
+		{"/a~1b", 1.0},
+		{"/c%d", 2.0},
+		{"/e^f", 3.0},
+		{"/g|h", 4.0},
+		{"/i\\j", 5.0},
+		{"/k\"l", 6.0},
+		{"/ ", 7.0},
+		{"/m~0n", 8.0},
+		{"/~01", 9.0},
+		{"/users/0/name", "john"},
+	}
+
+	for _, tc := range cases {
+		result := value.Pointer(tc.pointer)
+		value.chain.assertOK(t)
+		result.chain.assertOK(t)
+		assert.Equal(t, tc.expected, result.Raw(), tc.pointer)
+	}
+
+	failing := []struct {
+		pointer string
+		message string
+	}{
+		{"foo", "should be empty or start with '/'"},
+		{"/missing", `token 0 ("missing") not found in object`},
+		{"/foo/2", `token 1 ("2") out of array bounds`},
+		{"/foo/-", `token 1 ("-") refers to nonexistent element`},
+		{"/foo/01", `token 1 ("01") is not a valid array index`},
+		{"/foo/+1", `token 1 ("+1") is not a valid array index`},
+		{"/foo/0/x", `token 2 ("x") can't be resolved, value at that point is string`},
+		{"/users/0/name/x", `token 3 ("x") can't be resolved`},
+		{"/m~2n", "invalid escape sequence"},
+		{"/m~", "invalid escape sequence"},
+		{"/a/b", `token 0 ("a") not found in object`},
+	}
+
+	for _, tc := range failing {
+		result := value.Pointer(tc.pointer)
+		value.chain.assertFailed(t)
+		result.chain.assertFailed(t)
+		assert.Nil(t, result.Raw())
+		assert.Contains(t, reporter.message, tc.message, tc.pointer)
+		value.chain.reset()
+	}
+}
+
 func TestValueSchema(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -669,3 +815,283 @@ func TestValueSchema(t *testing.T) {
 	NewValue(reporter, data1).Schema("file:///bad/path").chain.assertFailed(t)
 	NewValue(reporter, data1).Schema("{ bad json").chain.assertFailed(t)
 }
+
+func TestValueEqualFile(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "httpexpect")
+	require.Nil(t, err)
+	defer os.RemoveAll(tempdir)
+
+	path := filepath.Join(tempdir, "users.json")
+
+	require.Nil(t, ioutil.WriteFile(path,
+		[]byte(`{ "users": [ {"name": "john", "id": 1} ] }`), 0644))
+
+	reporter := newMockReporter(t)
+
+	value := NewValue(reporter, map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"id": 1, "name": "john"},
+		},
+	})
+
+	value.EqualFile(path)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	NewValue(reporter, map[string]interface{}{"users": []interface{}{}}).
+		EqualFile(path).chain.assertFailed(t)
+
+	assert.Contains(t, reporter.message, path)
+	assert.Contains(t, reporter.message, "--- expected")
+
+	missing := filepath.Join(tempdir, "missing", "users.json")
+
+	value.EqualFile(missing)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, missing)
+
+	UpdateGolden = true
+	value.EqualFile(missing)
+	UpdateGolden = false
+
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualFile(missing)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	b, err := ioutil.ReadFile(missing)
+	require.Nil(t, err)
+	assert.Equal(t,
+		"{\n  \"users\": [\n    {\n      \"id\": 1,\n      \"name\": \"john\"\n    }\n  ]\n}\n",
+		string(b))
+}
+
+func TestValueEqualStrict(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewValue(reporter, map[string]interface{}{
+		"id":   123,
+		"name": "john",
+		"tags": []interface{}{"a", map[string]interface{}{"x": 1, "y": 2}},
+	})
+
+	value.EqualStrict(map[string]interface{}{
+		"id":   123,
+		"name": "john",
+		"tags": []interface{}{"a", map[string]interface{}{"x": 1, "y": 2}},
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualStrict(map[string]interface{}{
+		"id":   Ignore,
+		"name": "john",
+		"tags": []interface{}{Ignore, map[string]interface{}{"x": 1, "y": Ignore}},
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualStrict(map[string]interface{}{
+		"id":   Ignore,
+		"name": "john",
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualStrict(map[string]interface{}{
+		"id":   Ignore,
+		"name": "john",
+		"tags": []interface{}{"a", map[string]interface{}{"x": 1}},
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualStrict(map[string]interface{}{
+		"id":    Ignore,
+		"name":  "john",
+		"tags":  Ignore,
+		"email": Ignore,
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualStrict(map[string]interface{}{
+		"id":   Ignore,
+		"name": "bob",
+		"tags": Ignore,
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualStrict(map[string]interface{}{
+		"id":   Ignore,
+		"name": "john",
+		"tags": []interface{}{"a"},
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestValueEqualStrictStruct(t *testing.T) {
+	type User struct {
+		ID   interface{} `json:"id"`
+		Name string      `json:"name"`
+	}
+
+	reporter := newMockReporter(t)
+
+	value := NewValue(reporter, map[string]interface{}{
+		"id":   123,
+		"name": "john",
+	})
+
+	value.EqualStrict(User{ID: Ignore, Name: "john"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualStrict(User{ID: 321, Name: "john"})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestValueEqualWith(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	// monetary strings are equal if they're numerically equal
+	money := func(path string, expected, actual interface{}) (bool, bool) {
+		if !strings.HasSuffix(path, "price") {
+			return false, false
+		}
+		e, err1 := strconv.ParseFloat(expected.(string), 64)
+		a, err2 := strconv.ParseFloat(actual.(string), 64)
+		return true, err1 == nil && err2 == nil && e == a
+	}
+
+	value := NewValue(reporter, []interface{}{
+		map[string]interface{}{"price": "10.50", "currency": "EUR"},
+	})
+
+	value.EqualWith([]interface{}{
+		map[string]interface{}{"price": "10.5", "currency": "EUR"},
+	}, money)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualWith([]interface{}{
+		map[string]interface{}{"price": "10.51", "currency": "EUR"},
+	}, money)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "[0].price (comparator)")
+
+	value.EqualWith([]interface{}{}, money)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Contains(t, reporter.message, "$ (length mismatch)")
+
+	NewValue(reporter, "10.50").EqualWith("10.5", money).chain.assertFailed(t)
+	NewValue(reporter, "10.50").EqualWith("10.50", nil).chain.assertOK(t)
+}
+
+func TestValueEqualWithNumericString(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	t.Run("string actual", func(t *testing.T) {
+		value := NewValue(reporter, map[string]interface{}{
+			"id":    "9007199254740993",
+			"max":   "18446744073709551615",
+			"neg":   "-9223372036854775808",
+			"small": "42",
+			"name":  "john",
+		})
+
+		value.EqualWith(map[string]interface{}{
+			"id":    int64(9007199254740993),
+			"max":   uint64(18446744073709551615),
+			"neg":   int64(-9223372036854775808),
+			"small": 42,
+			"name":  "john",
+		}, NumericStringEquality)
+		value.chain.assertOK(t)
+
+		value.EqualWith(map[string]interface{}{
+			"id":    int64(9007199254740992),
+			"max":   uint64(18446744073709551615),
+			"neg":   int64(-9223372036854775808),
+			"small": 42,
+			"name":  "john",
+		}, NumericStringEquality)
+		value.chain.assertFailed(t)
+		assert.Contains(t, reporter.message, "id (comparator)")
+		value.chain.reset()
+	})
+
+	t.Run("number actual", func(t *testing.T) {
+		value := NewValue(reporter, []interface{}{9007199254740992, 42})
+
+		value.EqualWith([]interface{}{"9007199254740992", "42"},
+			NumericStringEquality)
+		value.chain.assertOK(t)
+
+		value.EqualWith([]interface{}{"9007199254740993", "42"},
+			NumericStringEquality)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	})
+
+	t.Run("not integers", func(t *testing.T) {
+		for _, tc := range []struct {
+			expected interface{}
+			actual   interface{}
+		}{
+			{"1.5", 1.5},
+			{"1e3", 1000},
+			{" 42", 42},
+			{"0x10", 16},
+			{"", 0},
+			{42, " 42"},
+		} {
+			value := NewValue(reporter, tc.actual)
+			value.EqualWith(tc.expected, NumericStringEquality)
+			value.chain.assertFailed(t)
+		}
+	})
+
+	t.Run("exact without comparator", func(t *testing.T) {
+		value := NewValue(reporter, float64(9007199254740992))
+
+		value.EqualWith(int64(9007199254740992), nil)
+		value.chain.assertOK(t)
+
+		value.EqualWith(int64(9007199254740993), nil)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+
+		value.EqualWith("9007199254740992", nil)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	})
+
+	t.Run("string equal stays strict", func(t *testing.T) {
+		object := NewObject(reporter, map[string]interface{}{
+			"id": "9007199254740993",
+		})
+
+		object.EqualWith(map[string]interface{}{
+			"id": int64(9007199254740993),
+		}, NumericStringEquality)
+		object.chain.assertOK(t)
+
+		str := object.Value("id").String()
+		str.Equal("9007199254740993")
+		str.chain.assertOK(t)
+
+		object.Value("id").Equal(int64(9007199254740993)).chain.assertFailed(t)
+	})
+}
@@ -2,9 +2,13 @@ package httpexpect
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 const noDuration = time.Duration(0)
@@ -19,20 +23,25 @@ type Websocket struct {
 	conn         *websocket.Conn
 	readTimeout  time.Duration
 	writeTimeout time.Duration
+	maxSize      int64
+	compression  bool
 	isClosed     bool
+	pending      chan wsRead
 }
 
 // NewWebsocket returns a new Websocket given a Config with Reporter and
 // Printers, and websocket.Conn to be inspected and handled.
 func NewWebsocket(config Config, conn *websocket.Conn) *Websocket {
-	return makeWebsocket(config, makeChain(config.Reporter), conn)
+	return makeWebsocket(config, makeChainFor("NewWebsocket", config.Reporter), conn)
 }
 
 func makeWebsocket(config Config, chain chain, conn *websocket.Conn) *Websocket {
 	return &Websocket{
-		config: config,
-		chain:  chain,
-		conn:   conn,
+		config:       config,
+		chain:        chain,
+		conn:         conn,
+		readTimeout:  config.Timeouts.WebsocketReadTimeout,
+		writeTimeout: config.Timeouts.WebsocketWriteTimeout,
 	}
 }
 
@@ -44,7 +53,8 @@ func (c *Websocket) Raw() *websocket.Conn {
 
 // WithReadTimeout sets timeout duration for WebSocket connection reads.
 //
-// By default no timeout is used.
+// By default Config.Timeouts.WebsocketReadTimeout is used, which means no
+// timeout if it's zero.
 func (c *Websocket) WithReadTimeout(timeout time.Duration) *Websocket {
 	c.readTimeout = timeout
 	return c
@@ -58,23 +68,94 @@ func (c *Websocket) WithoutReadTimeout() *Websocket {
 
 // WithWriteTimeout sets timeout duration for WebSocket connection writes.
 //
-// By default no timeout is used.
+// By default Config.Timeouts.WebsocketWriteTimeout is used, which means no
+// timeout if it's zero.
 func (c *Websocket) WithWriteTimeout(timeout time.Duration) *Websocket {
 	c.writeTimeout = timeout
 	return c
 }
 
 // WithoutWriteTimeout removes timeout for WebSocket connection writes.
-//
-// If not used then DefaultWebsocketTimeout will be used.
 func (c *Websocket) WithoutWriteTimeout() *Websocket {
 	c.writeTimeout = noDuration
 	return c
 }
 
+// WithMaxMessageSize sets maximum size in bytes for messages read from
+// WebSocket connection.
+//
+// If a message exceeding the limit is received, failure is reported. Note
+// that when the limit is exceeded, the underlying connection sends close
+// message to the peer and becomes unusable for further reads.
+//
+// By default no limit is used.
+//
+// Example:
+//  ws := resp.Websocket().WithMaxMessageSize(1024)
+//  ws.Expect().TextMessage()
+func (c *Websocket) WithMaxMessageSize(bytes int64) *Websocket {
+	if c.chain.failed() {
+		return c
+	}
+	if bytes <= 0 {
+		c.chain.failUsage(
+			"\nunexpected non-positive size %d passed to WithMaxMessageSize", bytes)
+		return c
+	}
+	c.maxSize = bytes
+	if c.conn != nil {
+		c.conn.SetReadLimit(bytes)
+	}
+	return c
+}
+
+// CompressionEnabled succeeds if per message compression extension
+// (permessage-deflate) was negotiated for the connection.
+//
+// Compression may be requested using Request.WithWebsocketCompression().
+// For Websocket created using NewWebsocket, compression is considered
+// not negotiated.
+//
+// Example:
+//  req.WithWebsocketUpgrade().WithWebsocketCompression()
+//  ws := req.Expect().Websocket()
+//  ws.CompressionEnabled()
+func (c *Websocket) CompressionEnabled() *Websocket {
+	defer c.chain.leave(c.chain.failed())
+	if c.chain.failed() {
+		return c
+	}
+	if !c.compression {
+		c.chain.fail(
+			"\nexpected WebSocket compression (permessage-deflate) is negotiated," +
+				" but it is not")
+	}
+	return c
+}
+
+// CompressionDisabled succeeds if per message compression extension
+// (permessage-deflate) was not negotiated for the connection.
+//
+// Example:
+//  ws := req.WithWebsocketUpgrade().Expect().Websocket()
+//  ws.CompressionDisabled()
+func (c *Websocket) CompressionDisabled() *Websocket {
+	defer c.chain.leave(c.chain.failed())
+	if c.chain.failed() {
+		return c
+	}
+	if c.compression {
+		c.chain.fail(
+			"\nexpected WebSocket compression (permessage-deflate) is not negotiated," +
+				" but it is")
+	}
+	return c
+}
+
 // Subprotocol returns a new String object that may be used to inspect
 // negotiated protocol for the connection.
 func (c *Websocket) Subprotocol() *String {
+	defer c.chain.leave(c.chain.failed())
 	s := &String{chain: c.chain}
 	if c.conn != nil {
 		s.value = c.conn.Subprotocol()
@@ -89,37 +170,398 @@ func (c *Websocket) Subprotocol() *String {
 //  msg := conn.Expect()
 //  msg.JSON().Object().ValueEqual("message", "hi")
 func (c *Websocket) Expect() *WebsocketMessage {
+	defer c.chain.leave(c.chain.failed())
 	switch {
 	case c.chain.failed():
 		return makeWebsocketMessage(c.chain)
 	case c.conn == nil:
-		c.chain.fail("\nunexpected read from failed WebSocket connection")
+		c.chain.failUsage("\nunexpected read from failed WebSocket connection")
 		return makeWebsocketMessage(c.chain)
 	case c.isClosed:
-		c.chain.fail("\nunexpected read from closed WebSocket connection")
+		c.chain.failUsage("\nunexpected read from closed WebSocket connection")
 		return makeWebsocketMessage(c.chain)
-	case !c.setReadDeadline():
+	}
+	var rd wsRead
+	if c.pending != nil {
+		var ok bool
+		if rd, ok = c.waitRead(c.readTimeout); !ok {
+			c.chain.fail(
+				"\nexpected read WebSocket connection, "+
+					"but got failure: read timeout after %s", c.readTimeout)
+			return makeWebsocketMessage(c.chain)
+		}
+	} else {
+		if !c.setReadDeadline() {
+			return makeWebsocketMessage(c.chain)
+		}
+		rd.typ, rd.content, rd.err = c.conn.ReadMessage()
+	}
+	m, ok := c.makeMessage(rd)
+	if !ok {
 		return makeWebsocketMessage(c.chain)
 	}
-	var err error
-	m := makeWebsocketMessage(c.chain)
-	m.typ, m.content, err = c.conn.ReadMessage()
+	return m
+}
+
+// ExpectNoMessage succeeds if no text or binary message is received from
+// WebSocket connection during given time window.
+//
+// Control frames are ignored. If the connection is closed by the peer during
+// the window, ExpectNoMessage succeeds, and the close message is returned by
+// the following Expect call.
+//
+// Waiting is done without setting read deadline on the connection, so if
+// a message arrives after the window has expired, the connection is not
+// broken, and the message is returned by the following Expect call.
+//
+// Example:
+//  conn.WriteJSON(Unsubscribe{Topic: "news"})
+//  conn.ExpectNoMessage(time.Second)
+func (c *Websocket) ExpectNoMessage(within time.Duration) *Websocket {
+	defer c.chain.leave(c.chain.failed())
+	switch {
+	case c.checkUnusable("ExpectNoMessage"):
+		return c
+	case within <= 0:
+		c.chain.failUsage(
+			"\nunexpected non-positive duration %s passed to ExpectNoMessage", within)
+		return c
+	}
+	rd, ok := c.waitRead(within)
+	if !ok {
+		return c
+	}
+	if rd.err != nil {
+		// keep close message or read error for the following Expect call
+		c.unread(rd)
+		return c
+	}
+	c.printRead(rd.typ, rd.content, 0)
+	c.chain.fail(
+		"\nexpected no WebSocket message within %s, but got %s message:\n %s",
+		within, wsMessageTypeName(rd.typ), wsMessagePreview(rd.typ, rd.content))
+	return c
+}
+
+// Drain reads messages from WebSocket connection until max messages are
+// received, a close message is received, or timeout expires, and returns
+// a new slice of WebsocketMessage objects to inspect received messages.
+//
+// Timeout limits the total time spent in Drain. Drain doesn't report failure
+// if less than max messages were received; use length of the returned slice
+// to check it.
+//
+// Example:
+//  msgs := conn.Drain(10, time.Second)
+//  for _, msg := range msgs {
+//      msg.TextMessage()
+//  }
+func (c *Websocket) Drain(max int, timeout time.Duration) []WebsocketMessage {
+	if c.checkUnusable("Drain") {
+		return []WebsocketMessage{}
+	}
+	ret := []WebsocketMessage{}
+	deadline := time.Now().Add(timeout)
+	for len(ret) < max {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		rd, ok := c.waitRead(remaining)
+		if !ok {
+			break
+		}
+		m, ok := c.makeMessage(rd)
+		if !ok {
+			break
+		}
+		ret = append(ret, *m)
+		if m.typ == websocket.CloseMessage {
+			break
+		}
+	}
+	return ret
+}
+
+// ExpectSequence reads len(expectations) messages from WebSocket connection
+// and invokes expectations in order, passing i-th received message to i-th
+// expectation.
+//
+// Reading stops at the first message for which expectation fails, and
+// failure is reported with the index at which the sequence diverged. Read
+// timeout (see WithReadTimeout) or closing of the connection before all
+// messages are received is reported the same way.
+//
+// Example:
+//  conn.WriteText("HELLO")
+//  conn.ExpectSequence(
+//      func(m *httpexpect.WebsocketMessage) { m.TextMessage().Body().Equal("HELLO") },
+//      func(m *httpexpect.WebsocketMessage) { m.TextMessage().Body().Equal("READY") },
+//  ).ExpectClosed(websocket.CloseNormalClosure)
+func (c *Websocket) ExpectSequence(
+	expectations ...func(*WebsocketMessage),
+) *Websocket {
+	defer c.chain.leave(c.chain.failed())
+	if c.checkUnusable("ExpectSequence") {
+		return c
+	}
+	seq := c.chain.enter("ExpectSequence")
+	for i, expect := range expectations {
+		rd, ok := c.waitRead(c.readTimeout)
+		if !ok {
+			c.chain.fail(
+				"\nexpected WebSocket message sequence of %d messages,"+
+					" but got read timeout after %s at index %d",
+				len(expectations), c.readTimeout, i)
+			return c
+		}
+		if cls, ok := rd.err.(*websocket.CloseError); ok {
+			// keep close message for the following ExpectClosed call
+			c.unread(rd)
+			c.printRead(websocket.CloseMessage, []byte(cls.Text), cls.Code)
+			c.chain.fail(
+				"\nexpected WebSocket message sequence of %d messages,"+
+					" but connection was closed with code %d at index %d",
+				len(expectations), cls.Code, i)
+			return c
+		}
+		m, ok := c.makeMessage(rd)
+		if !ok {
+			return c
+		}
+		item := seq.enterIndex(i)
+		var rec *failureRecorder
+		m.chain, rec = item.record()
+		expect(m)
+		if rec.failed {
+			c.chain.fail(
+				"\nexpected WebSocket message sequence of %d messages,"+
+					" but it diverged at index %d on %s message:\n %s",
+				len(expectations), i, wsMessageTypeName(m.typ),
+				wsMessagePreview(m.typ, m.content))
+			return c
+		}
+	}
+	return c
+}
+
+// ExpectClosed succeeds if the next message received from WebSocket
+// connection is a close message. If codes are given, close code should be
+// equal to one of them.
+//
+// If other messages are received before close message, or read timeout
+// (see WithReadTimeout) expires, failure is reported. Unexpected messages
+// are reported with preview of their payload.
+//
+// Example:
+//  conn.WriteText("BYE")
+//  conn.ExpectClosed(websocket.CloseNormalClosure)
+func (c *Websocket) ExpectClosed(codes ...int) *Websocket {
+	defer c.chain.leave(c.chain.failed())
+	if c.checkUnusable("ExpectClosed") {
+		return c
+	}
+	rd, ok := c.waitRead(c.readTimeout)
+	if !ok {
+		c.chain.fail(
+			"\nexpected WebSocket connection closed,"+
+				" but got read timeout after %s", c.readTimeout)
+		return c
+	}
+	m, ok := c.makeMessage(rd)
+	if !ok {
+		return c
+	}
+	if m.typ != websocket.CloseMessage {
+		c.chain.fail(
+			"\nexpected WebSocket connection closed,"+
+				" but got unexpected %s message:\n %s",
+			wsMessageTypeName(m.typ), wsMessagePreview(m.typ, m.content))
+		return c
+	}
+	if len(codes) == 0 {
+		return c
+	}
+	for _, code := range codes {
+		if code == m.closeCode {
+			return c
+		}
+	}
+	c.chain.fail(
+		"\nexpected WebSocket connection closed with close code equal to one of:"+
+			"\n %v\n\nbut got:\n %d", codes, m.closeCode)
+	return c
+}
+
+// EverySchema reads messages from WebSocket connection until count messages
+// are received, a close message is received, or timeout expires, and checks
+// that every message is JSON matching given schema.
+//
+// Schema is handled the same way as in Value.Schema. Control frames don't
+// count towards count. If the connection is closed by the peer, the close
+// message is returned by the following Expect call.
+//
+// Failure is reported if less than count messages were received, or if some
+// messages are not valid JSON or don't match the schema. Validation errors
+// of all messages are reported together, with the index of every message.
+//
+// Example:
+//  conn.WriteJSON(Subscribe{Topic: "news"})
+//  conn.EverySchema(`{"type": "object", "required": ["id"]}`, 10, time.Second)
+func (c *Websocket) EverySchema(
+	schema interface{}, count int, timeout time.Duration,
+) *Websocket {
+	defer c.chain.leave(c.chain.failed())
+	if c.checkUnusable("EverySchema") {
+		return c
+	}
+
+	compiled, err := gojsonschema.NewSchema(schemaLoader(schema))
 	if err != nil {
-		if cls, ok := err.(*websocket.CloseError); ok {
+		c.chain.fail("\n%s\n\nschema:\n%s", err.Error(), dumpSchema(schema))
+		return c
+	}
+
+	var (
+		received = 0
+		errors   = ""
+		invalid  = 0
+		deadline = time.Now().Add(timeout)
+	)
+
+	for received < count {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		rd, ok := c.waitRead(remaining)
+		if !ok {
+			break
+		}
+		if _, ok := rd.err.(*websocket.CloseError); ok {
+			// keep close message for the following Expect call
+			c.unread(rd)
+			break
+		}
+		m, ok := c.makeMessage(rd)
+		if !ok {
+			return c
+		}
+
+		index := received
+		received++
+
+		var value interface{}
+		if err := json.Unmarshal(m.content, &value); err != nil {
+			invalid++
+			errors += fmt.Sprintf(" message %d: %s\n", index, err.Error())
+			continue
+		}
+
+		result, err := compiled.Validate(gojsonschema.NewGoLoader(value))
+		if err != nil {
+			invalid++
+			errors += fmt.Sprintf(" message %d: %s\n", index, err.Error())
+			continue
+		}
+		if !result.Valid() {
+			invalid++
+			for _, err := range result.Errors() {
+				errors += fmt.Sprintf(" message %d: %s\n", index, err)
+			}
+		}
+	}
+
+	var message string
+	if received < count {
+		message += fmt.Sprintf(
+			"\nexpected %d WebSocket messages within %s, but got %d\n",
+			count, timeout, received)
+	}
+	if invalid != 0 {
+		message += fmt.Sprintf(
+			"\njson schema validation failed for %d of %d WebSocket messages,"+
+				" schema:\n%s\n\nerrors:\n%s",
+			invalid, received, dumpSchema(schema), errors)
+	}
+	if message != "" {
+		c.chain.fail("%s", message)
+	}
+
+	return c
+}
+
+type wsRead struct {
+	typ     int
+	content []byte
+	err     error
+}
+
+// waitRead waits for the next message read in background up to given
+// timeout, or forever if timeout is zero. If timeout expires, returns
+// false, and the background read remains pending until the next call.
+func (c *Websocket) waitRead(timeout time.Duration) (wsRead, bool) {
+	if c.pending == nil {
+		if err := c.conn.SetReadDeadline(infiniteTime); err != nil {
+			return wsRead{err: err}, true
+		}
+		ch := make(chan wsRead, 1)
+		conn := c.conn
+		go func() {
+			var rd wsRead
+			rd.typ, rd.content, rd.err = conn.ReadMessage()
+			ch <- rd
+		}()
+		c.pending = ch
+	}
+	var timer <-chan time.Time
+	if timeout != noDuration {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+	select {
+	case rd := <-c.pending:
+		c.pending = nil
+		return rd, true
+	case <-timer:
+		return wsRead{}, false
+	}
+}
+
+func (c *Websocket) unread(rd wsRead) {
+	ch := make(chan wsRead, 1)
+	ch <- rd
+	c.pending = ch
+}
+
+func (c *Websocket) makeMessage(rd wsRead) (*WebsocketMessage, bool) {
+	m := makeWebsocketMessage(c.chain)
+	m.typ, m.content = rd.typ, rd.content
+	if rd.err != nil {
+		if cls, ok := rd.err.(*websocket.CloseError); ok {
 			m.typ = websocket.CloseMessage
 			m.closeCode = cls.Code
 			m.content = []byte(cls.Text)
-			c.printRead(m.typ, m.content, m.closeCode)
+		} else if c.readTimeout != noDuration && isTimeoutError(rd.err) {
+			c.chain.fail(
+				"\nexpected read WebSocket connection, "+
+					"but got failure: read timeout after %s", c.readTimeout)
+			return nil, false
+		} else if rd.err == websocket.ErrReadLimit {
+			c.chain.fail(
+				"\nexpected WebSocket message size not exceeding limit:\n %d bytes"+
+					"\n\nbut got message exceeding it", c.maxSize)
+			return nil, false
 		} else {
 			c.chain.fail(
 				"\nexpected read WebSocket connection, "+
-					"but got failure: %s", err.Error())
-			return makeWebsocketMessage(c.chain)
+					"but got failure: %s", rd.err.Error())
+			return nil, false
 		}
-	} else {
-		c.printRead(m.typ, m.content, m.closeCode)
 	}
-	return m
+	c.printRead(m.typ, m.content, m.closeCode)
+	return m, true
 }
 
 func (c *Websocket) setReadDeadline() bool {
@@ -136,6 +578,20 @@ func (c *Websocket) setReadDeadline() bool {
 	return true
 }
 
+func wsCompressionNegotiated(header http.Header) bool {
+	for _, ext := range header.Values("Sec-Websocket-Extensions") {
+		for _, token := range strings.Split(ext, ",") {
+			if i := strings.Index(token, ";"); i >= 0 {
+				token = token[:i]
+			}
+			if strings.TrimSpace(token) == "permessage-deflate" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (c *Websocket) printRead(typ int, content []byte, closeCode int) {
 	for _, printer := range c.config.Printers {
 		if p, ok := printer.(WebsocketPrinter); ok {
@@ -156,6 +612,7 @@ func (c *Websocket) printRead(typ int, content []byte, closeCode int) {
 //  conn := resp.Connection()
 //  defer conn.Disconnect()
 func (c *Websocket) Disconnect() *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	if c.conn == nil || c.isClosed {
 		return c
 	}
@@ -182,11 +639,12 @@ func (c *Websocket) Disconnect() *Websocket {
 //  conn := resp.Connection()
 //  conn.Close(websocket.CloseUnsupportedData)
 func (c *Websocket) Close(code ...int) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	switch {
 	case c.checkUnusable("Close"):
 		return c
 	case len(code) > 1:
-		c.chain.fail("\nunexpected multiple code arguments passed to Close")
+		c.chain.failUsage("\nunexpected multiple code arguments passed to Close")
 		return c
 	}
 	return c.CloseWithBytes(nil, code...)
@@ -208,11 +666,12 @@ func (c *Websocket) Close(code ...int) *Websocket {
 //  conn := resp.Connection()
 //  conn.CloseWithBytes([]byte("bye!"), websocket.CloseGoingAway)
 func (c *Websocket) CloseWithBytes(b []byte, code ...int) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	switch {
 	case c.checkUnusable("CloseWithBytes"):
 		return c
 	case len(code) > 1:
-		c.chain.fail(
+		c.chain.failUsage(
 			"\nunexpected multiple code arguments passed to CloseWithBytes")
 		return c
 	}
@@ -244,11 +703,12 @@ func (c *Websocket) CloseWithBytes(b []byte, code ...int) *Websocket {
 func (c *Websocket) CloseWithJSON(
 	object interface{}, code ...int,
 ) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	switch {
 	case c.checkUnusable("CloseWithJSON"):
 		return c
 	case len(code) > 1:
-		c.chain.fail(
+		c.chain.failUsage(
 			"\nunexpected multiple code arguments passed to CloseWithJSON")
 		return c
 	}
@@ -277,11 +737,12 @@ func (c *Websocket) CloseWithJSON(
 //  conn := resp.Connection()
 //  conn.CloseWithText("bye!")
 func (c *Websocket) CloseWithText(s string, code ...int) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	switch {
 	case c.checkUnusable("CloseWithText"):
 		return c
 	case len(code) > 1:
-		c.chain.fail(
+		c.chain.failUsage(
 			"\nunexpected multiple code arguments passed to CloseWithText")
 		return c
 	}
@@ -304,6 +765,7 @@ func (c *Websocket) CloseWithText(s string, code ...int) *Websocket {
 func (c *Websocket) WriteMessage(
 	typ int, content []byte, closeCode ...int,
 ) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	if c.checkUnusable("WriteMessage") {
 		return c
 	}
@@ -313,7 +775,7 @@ func (c *Websocket) WriteMessage(
 		c.printWrite(typ, content, 0)
 	case websocket.CloseMessage:
 		if len(closeCode) > 1 {
-			c.chain.fail("\nunexpected multiple closeCode arguments " +
+			c.chain.failUsage("\nunexpected multiple closeCode arguments " +
 				"passed to WriteMessage")
 			return c
 		}
@@ -327,7 +789,7 @@ func (c *Websocket) WriteMessage(
 
 		content = websocket.FormatCloseMessage(code, string(content))
 	default:
-		c.chain.fail("\nunexpected WebSocket message type '%s' "+
+		c.chain.failUsage("\nunexpected WebSocket message type '%s' "+
 			"passed to WriteMessage", wsMessageTypeName(typ))
 		return c
 	}
@@ -336,6 +798,12 @@ func (c *Websocket) WriteMessage(
 		return c
 	}
 	if err := c.conn.WriteMessage(typ, content); err != nil {
+		if c.writeTimeout != noDuration && isTimeoutError(err) {
+			c.chain.fail(
+				"\nexpected write into WebSocket connection, "+
+					"but got failure: write timeout after %s", c.writeTimeout)
+			return c
+		}
 		c.chain.fail(
 			"\nexpected write into WebSocket connection, "+
 				"but got failure: %s", err.Error())
@@ -346,6 +814,7 @@ func (c *Websocket) WriteMessage(
 
 // WriteBytesBinary is a shorthand for c.WriteMessage(websocket.BinaryMessage, b).
 func (c *Websocket) WriteBytesBinary(b []byte) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	if c.checkUnusable("WriteBytesBinary") {
 		return c
 	}
@@ -354,6 +823,7 @@ func (c *Websocket) WriteBytesBinary(b []byte) *Websocket {
 
 // WriteBytesText is a shorthand for c.WriteMessage(websocket.TextMessage, b).
 func (c *Websocket) WriteBytesText(b []byte) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	if c.checkUnusable("WriteBytesText") {
 		return c
 	}
@@ -363,6 +833,7 @@ func (c *Websocket) WriteBytesText(b []byte) *Websocket {
 // WriteText is a shorthand for
 // c.WriteMessage(websocket.TextMessage, []byte(s)).
 func (c *Websocket) WriteText(s string) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	if c.checkUnusable("WriteText") {
 		return c
 	}
@@ -372,6 +843,7 @@ func (c *Websocket) WriteText(s string) *Websocket {
 // WriteJSON writes to the underlying WebSocket connection given object,
 // marshaled using json.Marshal().
 func (c *Websocket) WriteJSON(object interface{}) *Websocket {
+	defer c.chain.leave(c.chain.failed())
 	if c.checkUnusable("WriteJSON") {
 		return c
 	}
@@ -390,11 +862,11 @@ func (c *Websocket) checkUnusable(where string) bool {
 	case c.chain.failed():
 		return true
 	case c.conn == nil:
-		c.chain.fail("\nunexpected %s call for failed WebSocket connection",
+		c.chain.failUsage("\nunexpected %s call for failed WebSocket connection",
 			where)
 		return true
 	case c.isClosed:
-		c.chain.fail("\nunexpected %s call for closed WebSocket connection",
+		c.chain.failUsage("\nunexpected %s call for closed WebSocket connection",
 			where)
 		return true
 	}
@@ -63,14 +63,23 @@ func (hc *handlerConn) runHandler(handler http.Handler) {
 	go func() {
 		defer hc.wg.Done()
 
-		recorder := &hijackRecorder{conn: hc.backConn}
+		reader := bufio.NewReader(hc.backConn)
 
 		for {
-			req, err := http.ReadRequest(bufio.NewReader(hc.backConn))
+			req, err := http.ReadRequest(reader)
 			if err != nil {
 				return
 			}
+
+			recorder := newHijackRecorder(hc.backConn, reader)
 			handler.ServeHTTP(recorder, req)
+
+			if recorder.hijacked {
+				return
+			}
+			if err := recorder.flush(); err != nil {
+				return
+			}
 		}
 	}()
 }
@@ -88,24 +97,38 @@ func (hc *handlerConn) runFastHandler(handler fasthttp.RequestHandler) {
 // hijackRecorder it similar to httptest.ResponseRecorder,
 // but with Hijack capabilities.
 //
+// If the handler doesn't hijack the connection (e.g. when the handshake
+// fails), the recorded response, including its body, is written to the
+// connection after the handler returns.
+//
 // Original idea is stolen from https://github.com/posener/wstest
 type hijackRecorder struct {
-	httptest.ResponseRecorder
-	conn net.Conn
+	*httptest.ResponseRecorder
+	conn     net.Conn
+	reader   *bufio.Reader
+	hijacked bool
+}
+
+func newHijackRecorder(conn net.Conn, reader *bufio.Reader) *hijackRecorder {
+	return &hijackRecorder{
+		ResponseRecorder: httptest.NewRecorder(),
+		conn:             conn,
+		reader:           reader,
+	}
 }
 
 // Hijack the connection for caller.
 //
 // Implements http.Hijacker interface.
 func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	rw := bufio.NewReadWriter(bufio.NewReader(r.conn), bufio.NewWriter(r.conn))
+	r.hijacked = true
+	rw := bufio.NewReadWriter(r.reader, bufio.NewWriter(r.conn))
 	return r.conn, rw, nil
 }
 
-// WriteHeader write HTTP header to the client and closes the connection
-//
-// Implements http.ResponseWriter interface.
-func (r *hijackRecorder) WriteHeader(code int) {
-	resp := http.Response{StatusCode: code, Header: r.Header()}
-	_ = resp.Write(r.conn)
+func (r *hijackRecorder) flush() error {
+	resp := r.Result()
+	resp.ContentLength = int64(r.Body.Len())
+	resp.Header.Del("Transfer-Encoding")
+	return resp.Write(r.conn)
 }
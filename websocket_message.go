@@ -2,6 +2,7 @@ package httpexpect
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/gorilla/websocket"
 )
@@ -26,7 +27,7 @@ func NewWebsocketMessage(
 	reporter Reporter, typ int, content []byte, closeCode ...int,
 ) *WebsocketMessage {
 	m := &WebsocketMessage{
-		chain:   makeChain(reporter),
+		chain:   makeChainFor("NewWebsocketMessage", reporter),
 		typ:     typ,
 		content: content,
 	}
@@ -50,31 +51,37 @@ func (m *WebsocketMessage) Raw() (typ int, content []byte, closeCode int) {
 
 // CloseMessage is a shorthand for m.Type(websocket.CloseMessage).
 func (m *WebsocketMessage) CloseMessage() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	return m.Type(websocket.CloseMessage)
 }
 
 // NotCloseMessage is a shorthand for m.NotType(websocket.CloseMessage).
 func (m *WebsocketMessage) NotCloseMessage() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	return m.NotType(websocket.CloseMessage)
 }
 
 // BinaryMessage is a shorthand for m.Type(websocket.BinaryMessage).
 func (m *WebsocketMessage) BinaryMessage() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	return m.Type(websocket.BinaryMessage)
 }
 
 // NotBinaryMessage is a shorthand for m.NotType(websocket.BinaryMessage).
 func (m *WebsocketMessage) NotBinaryMessage() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	return m.NotType(websocket.BinaryMessage)
 }
 
 // TextMessage is a shorthand for m.Type(websocket.TextMessage).
 func (m *WebsocketMessage) TextMessage() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	return m.Type(websocket.TextMessage)
 }
 
 // NotTextMessage is a shorthand for m.NotType(websocket.TextMessage).
 func (m *WebsocketMessage) NotTextMessage() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	return m.NotType(websocket.TextMessage)
 }
 
@@ -87,11 +94,12 @@ func (m *WebsocketMessage) NotTextMessage() *WebsocketMessage {
 //  msg := conn.Expect()
 //  msg.Type(websocket.TextMessage, websocket.BinaryMessage)
 func (m *WebsocketMessage) Type(typ ...int) *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	switch {
 	case m.chain.failed():
 		return m
 	case len(typ) == 0:
-		m.chain.fail("\nunexpected nil argument passed to Type")
+		m.chain.failUsage("\nunexpected nil argument passed to Type")
 		return m
 	}
 	yes := false
@@ -124,11 +132,12 @@ func (m *WebsocketMessage) Type(typ ...int) *WebsocketMessage {
 //  msg := conn.Expect()
 //  msg.NotType(websocket.CloseMessage, websocket.BinaryMessage)
 func (m *WebsocketMessage) NotType(typ ...int) *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	switch {
 	case m.chain.failed():
 		return m
 	case len(typ) == 0:
-		m.chain.fail("\nunexpected nil argument passed to NotType")
+		m.chain.failUsage("\nunexpected nil argument passed to NotType")
 		return m
 	}
 	for _, t := range typ {
@@ -140,7 +149,7 @@ func (m *WebsocketMessage) NotType(typ ...int) *WebsocketMessage {
 			} else {
 				m.chain.fail(
 					"\nexpected message type not equal:\n %d\n\nbut it did",
-					typ[0], m.typ)
+					typ[0])
 			}
 			return m
 		}
@@ -159,11 +168,12 @@ func (m *WebsocketMessage) NotType(typ ...int) *WebsocketMessage {
 //  msg := conn.Expect().Closed()
 //  msg.Code(websocket.CloseNormalClosure, websocket.CloseGoingAway)
 func (m *WebsocketMessage) Code(code ...int) *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	switch {
 	case m.chain.failed():
 		return m
 	case len(code) == 0:
-		m.chain.fail("\nunexpected nil argument passed to Code")
+		m.chain.failUsage("\nunexpected nil argument passed to Code")
 		return m
 	case m.checkClosed("Code"):
 		return m
@@ -200,11 +210,12 @@ func (m *WebsocketMessage) Code(code ...int) *WebsocketMessage {
 //  msg := conn.Expect().Closed()
 //  msg.NotCode(websocket.CloseAbnormalClosure, websocket.CloseNoStatusReceived)
 func (m *WebsocketMessage) NotCode(code ...int) *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	switch {
 	case m.chain.failed():
 		return m
 	case len(code) == 0:
-		m.chain.fail("\nunexpected nil argument passed to CodeNotEqual")
+		m.chain.failUsage("\nunexpected nil argument passed to CodeNotEqual")
 		return m
 	case m.checkClosed("NotCode"):
 		return m
@@ -218,7 +229,7 @@ func (m *WebsocketMessage) NotCode(code ...int) *WebsocketMessage {
 			} else {
 				m.chain.fail(
 					"\nexpected close code not equal:\n %d\n\nbut it did",
-					code[0], m.closeCode)
+					code[0])
 			}
 			return m
 		}
@@ -228,8 +239,8 @@ func (m *WebsocketMessage) NotCode(code ...int) *WebsocketMessage {
 
 func (m *WebsocketMessage) checkClosed(where string) bool {
 	if m.typ != websocket.CloseMessage {
-		m.chain.fail(
-			"\nunexpected %s usage for not '%' WebSocket message type\n\n"+
+		m.chain.failUsage(
+			"\nunexpected %s usage for not '%s' WebSocket message type\n\n"+
 				"got type:\n %s",
 			where,
 			wsMessageTypeName(websocket.CloseMessage),
@@ -247,11 +258,13 @@ func (m *WebsocketMessage) checkClosed(where string) bool {
 //  msg.Body().NotEmpty()
 //  msg.Body().Length().Equal(100)
 func (m *WebsocketMessage) Body() *String {
+	defer m.chain.leave(m.chain.failed())
 	return &String{m.chain, string(m.content)}
 }
 
 // NoContent succeeds if WebSocket message has no content (is empty).
 func (m *WebsocketMessage) NoContent() *WebsocketMessage {
+	defer m.chain.leave(m.chain.failed())
 	switch {
 	case m.chain.failed():
 		return m
@@ -280,6 +293,7 @@ func (m *WebsocketMessage) NoContent() *WebsocketMessage {
 //  msg := conn.Expect()
 //  msg.JSON().Array().Elements("foo", "bar")
 func (m *WebsocketMessage) JSON() *Value {
+	defer m.chain.leave(m.chain.failed())
 	return &Value{m.chain, m.getJSON()}
 }
 
@@ -297,6 +311,17 @@ func (m *WebsocketMessage) getJSON() interface{} {
 	return value
 }
 
+func wsMessagePreview(typ int, content []byte) string {
+	const maxPreview = 100
+	if typ == websocket.BinaryMessage {
+		return fmt.Sprintf("%d bytes", len(content))
+	}
+	if len(content) > maxPreview {
+		return fmt.Sprintf("%q...", content[:maxPreview])
+	}
+	return fmt.Sprintf("%q", content)
+}
+
 func wsMessageTypeName(typ int) string {
 	switch typ {
 	case websocket.TextMessage:
@@ -2,8 +2,10 @@ package httpexpect
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestWebsocketFailed(t *testing.T) {
@@ -25,6 +27,8 @@ func TestWebsocketFailed(t *testing.T) {
 
 	ws.Subprotocol().chain.assertFailed(t)
 	ws.Expect().chain.assertFailed(t)
+	ws.ExpectNoMessage(time.Second)
+	assert.Equal(t, 0, len(ws.Drain(1, time.Second)))
 
 	ws.WriteMessage(websocket.TextMessage, []byte("a"))
 	ws.WriteBytesBinary([]byte("a"))
@@ -52,3 +56,26 @@ func TestWebsocketNil(t *testing.T) {
 
 	ws.chain.assertFailed(t)
 }
+
+func TestWebsocketNilExpectNoMessage(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	ws := NewWebsocket(config, nil)
+
+	ws.ExpectNoMessage(time.Millisecond)
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketNilDrain(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	ws := NewWebsocket(config, nil)
+
+	msgs := ws.Drain(1, time.Millisecond)
+	assert.Equal(t, 0, len(msgs))
+	ws.chain.assertFailed(t)
+}
@@ -0,0 +1,87 @@
+package httpexpect
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+)
+
+// DefaultWireCaptureLimit is the default maximum size of request and
+// response body included in wire capture. See Request.WithWireCapture.
+const DefaultWireCaptureLimit = 64 << 10
+
+// wireCapture holds serialized request and response of the last round
+// trip made by wireTransport.
+type wireCapture struct {
+	limit    int
+	discard  bool
+	redact   []string
+	request  []byte
+	response []byte
+	err      error
+}
+
+// wireTransport is http.RoundTripper that records serialized request and
+// response and delegates actual round trip to underlying transport.
+type wireTransport struct {
+	base    http.RoundTripper
+	capture *wireCapture
+}
+
+func (t wireTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	dumpBody := req.Body == nil || !isStreamBody(req.Body)
+
+	reqDump, err := httputil.DumpRequestOut(req, dumpBody)
+	if err != nil {
+		t.capture.err = err
+		return nil, err
+	}
+	t.capture.request = truncateDump(
+		redactDump(reqDump, t.capture.redact), t.capture.limit)
+	t.capture.response = nil
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respDump, err := httputil.DumpResponse(resp,
+		!isEventStream(resp) && !t.capture.discard)
+	if err != nil {
+		t.capture.err = err
+		return resp, nil
+	}
+	t.capture.response = truncateDump(
+		redactDump(respDump, t.capture.redact), t.capture.limit)
+
+	return resp, nil
+}
+
+// truncateDump truncates body of serialized message to limit bytes and
+// appends a marker with number of truncated bytes.
+func truncateDump(dump []byte, limit int) []byte {
+	pos := bytes.Index(dump, []byte("\r\n\r\n"))
+	if pos < 0 {
+		return dump
+	}
+	body := dump[pos+4:]
+	if limit < 0 || len(body) <= limit {
+		return dump
+	}
+	ret := append([]byte(nil), dump[:pos+4+limit]...)
+	return append(ret, fmt.Sprintf("\n... (%d bytes truncated)",
+		len(body)-limit)...)
+}
+
+// wrapWireClient returns a copy of client with transport wrapped into
+// wireTransport.
+func wrapWireClient(client *http.Client, capture *wireCapture) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	clientCopy := *client
+	clientCopy.Transport = wireTransport{base, capture}
+	return &clientCopy
+}